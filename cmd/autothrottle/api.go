@@ -19,14 +19,17 @@ type APIConfig struct {
 }
 
 var (
-	overrideRateZnode     = "override_rate"
-	overrideRateZnodePath string
-	incorrectMethodError  = errors.New("disallowed method")
+	overrideRateZnode          = "override_rate"
+	overrideRateZnodePath      string
+	reassignmentScopeZnode     = "reassignment_scope"
+	reassignmentScopeZnodePath string
+	incorrectMethodError       = errors.New("disallowed method")
 )
 
 func initAPI(c *APIConfig, zk kafkazk.Handler) {
 	chroot := fmt.Sprintf("/%s", c.ZKPrefix)
 	overrideRateZnodePath = fmt.Sprintf("%s/%s", chroot, overrideRateZnode)
+	reassignmentScopeZnodePath = fmt.Sprintf("%s/%s", chroot, reassignmentScopeZnode)
 
 	m := http.NewServeMux()
 
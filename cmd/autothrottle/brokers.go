@@ -49,8 +49,10 @@ func (bm reassigningBrokers) lists() ([]int, []int, []int) {
 // getReassigningBrokers takes a kafakzk.Reassignments and returns a reassigningBrokers,
 // which includes a broker list for source, destination, and all brokers
 // handling any ongoing reassignments. Additionally, a map of throttled
-// replicas by topic is included.
-func getReassigningBrokers(r kafkazk.Reassignments, zk kafkazk.Handler) (reassigningBrokers, error) {
+// replicas by topic is included. If a non-nil *kafkazk.ReassignmentScope is
+// provided, brokers for topics covered by the scope are limited to those
+// listed in the scope.
+func getReassigningBrokers(r kafkazk.Reassignments, zk kafkazk.Handler, scope *kafkazk.ReassignmentScope) (reassigningBrokers, error) {
 	lb := reassigningBrokers{
 		// Maps of src and dst brokers used as sets.
 		src: map[int]struct{}{},
@@ -64,6 +66,8 @@ func getReassigningBrokers(r kafkazk.Reassignments, zk kafkazk.Handler) (reassig
 	// Get topic data for each topic undergoing a reassignment.
 	for t := range r {
 		topic := topic(t)
+		// Whether this topic's participants were published in the scope.
+		scoped := scope != nil && scope.HasTopic(t)
 		lb.throttledReplicas[topic] = make(throttled)
 		lb.throttledReplicas[topic]["leaders"] = []string{}
 		lb.throttledReplicas[topic]["followers"] = []string{}
@@ -84,7 +88,7 @@ func getReassigningBrokers(r kafkazk.Reassignments, zk kafkazk.Handler) (reassig
 				// Source brokers.
 				leader := tstate[p].Leader
 				// In offline partitions, the leader value is set to -1. Skip.
-				if leader != -1 {
+				if leader != -1 && (!scoped || scope.HasBroker(leader)) {
 					lb.src[leader] = struct{}{}
 					// Append to the throttle list.
 					leaders := lb.throttledReplicas[topic]["leaders"]
@@ -97,7 +101,7 @@ func getReassigningBrokers(r kafkazk.Reassignments, zk kafkazk.Handler) (reassig
 					// XXX(jamie): out of sync but previously existing brokers would
 					// show here as well. May want to consider whether those should
 					// be dynamically throttled as if they're part of a reassignemnt.
-					if b != -1 && !inSlice(b, tstate[p].ISR) {
+					if b != -1 && !inSlice(b, tstate[p].ISR) && (!scoped || scope.HasBroker(b)) {
 						lb.dst[b] = struct{}{}
 						followers := lb.throttledReplicas[topic]["followers"]
 						lb.throttledReplicas[topic]["followers"] = append(followers, fmt.Sprintf("%d:%d", partn, b))
@@ -12,7 +12,7 @@ func TestGetReassigningBrokers(t *testing.T) {
 	zk := &kafkazk.Stub{}
 
 	re := zk.GetReassignments()
-	bmaps, _ := getReassigningBrokers(re, zk)
+	bmaps, _ := getReassigningBrokers(re, zk, nil)
 
 	srcExpected := []int{1000, 1002}
 	dstExpected := []int{1003, 1005, 1010}
@@ -80,6 +80,51 @@ func TestGetReassigningBrokers(t *testing.T) {
 	}
 }
 
+func TestGetReassigningBrokersScoped(t *testing.T) {
+	zk := kafkazk.NewZooKeeperStub()
+	path := "/autothrottle/reassignment_scope"
+
+	// Publish a scope as topicmappr would; only partition 0 is being moved.
+	original, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"reassigning_topic","partition":0,"replicas":[1000,1002]},
+		{"topic":"reassigning_topic","partition":1,"replicas":[1002,1003]}]}`)
+	updated, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"reassigning_topic","partition":0,"replicas":[1003,1000,1002]},
+		{"topic":"reassigning_topic","partition":1,"replicas":[1002,1003]}]}`)
+
+	err := kafkazk.SetReassignmentScope(zk, path, kafkazk.NewReassignmentScope(original, updated))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scope, err := kafkazk.GetReassignmentScope(zk, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	re := zk.GetReassignments()
+	bmaps, _ := getReassigningBrokers(re, zk, scope)
+
+	src, dst, all := bmaps.lists()
+
+	srcExpected := []int{1000, 1002}
+	dstExpected := []int{1003}
+	allExpected := []int{1000, 1002, 1003}
+
+	for _, c := range []struct {
+		got, expected []int
+	}{{src, srcExpected}, {dst, dstExpected}, {all, allExpected}} {
+		if len(c.got) != len(c.expected) {
+			t.Fatalf("Expected %v, got %v", c.expected, c.got)
+		}
+		for n := range c.got {
+			if c.got[n] != c.expected[n] {
+				t.Errorf("Expected ID %d, got %d", c.expected[n], c.got[n])
+			}
+		}
+	}
+}
+
 func TestIncompleteBrokerMetrics(t *testing.T) {
 	bm := stubBrokerMetrics()
 
@@ -2,6 +2,8 @@ package main
 
 import (
 	"fmt"
+	"log"
+	"sort"
 
 	"github.com/DataDog/kafka-kit/v3/kafkametrics"
 )
@@ -54,6 +56,59 @@ func (r replicationCapacityByBroker) reset() {
 	}
 }
 
+// applyRackBudgets takes a map of rack ID to aggregate throttle budget (in
+// MB/s) and a map of broker ID to rack ID. For each rack with a budget, the
+// leader and follower rates of the rack's replicating brokers are summed
+// independently. If a sum exceeds the budget, each broker's rate for that role
+// is scaled down proportionally so that the rates sum to the budget. Brokers
+// without a rate for a role aren't replicating in that role and don't consume
+// any of the budget.
+func (r replicationCapacityByBroker) applyRackBudgets(budgets map[string]float64, racks map[int]string) {
+	// Group brokers by rack.
+	var byRack = map[string][]int{}
+	for id := range r {
+		rack, exists := racks[id]
+		if !exists {
+			continue
+		}
+
+		if _, hasBudget := budgets[rack]; hasBudget {
+			byRack[rack] = append(byRack[rack], id)
+		}
+	}
+
+	for rack, ids := range byRack {
+		sort.Ints(ids)
+		budget := budgets[rack]
+
+		for i, role := range []replicaType{"leader", "follower"} {
+			var sum float64
+			for _, id := range ids {
+				if rate := r[id][i]; rate != nil {
+					sum += *rate
+				}
+			}
+
+			if sum <= budget {
+				continue
+			}
+
+			log.Printf("Aggregate %s throttle rate for rack %s brokers %v (%.2fMB/s) exceeds the rack budget, scaling to %.2fMB/s\n",
+				role, rack, ids, sum, budget)
+
+			ratio := budget / sum
+			for _, id := range ids {
+				a := r[id]
+				if a[i] != nil {
+					scaled := *a[i] * ratio
+					a[i] = &scaled
+					r[id] = a
+				}
+			}
+		}
+	}
+}
+
 // brokerReplicationCapacities traverses the list of all brokers participating
 // in the reassignment. For each broker, it determines whether the broker is
 // a leader (source) or a follower (destination), and calculates a throttle
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"math"
 	"testing"
 
 	"github.com/DataDog/kafka-kit/v3/kafkazk"
@@ -9,7 +10,7 @@ import (
 func TestBrokerReplicationCapacities(t *testing.T) {
 	zk := &kafkazk.Stub{}
 	reassignments := zk.GetReassignments()
-	reassigningBrokers, _ := getReassigningBrokers(reassignments, zk)
+	reassigningBrokers, _ := getReassigningBrokers(reassignments, zk, nil)
 
 	lim, _ := NewLimits(NewLimitsConfig{
 		Minimum:            20,
@@ -54,6 +55,54 @@ func TestBrokerReplicationCapacities(t *testing.T) {
 	}
 }
 
+func TestApplyRackBudgets(t *testing.T) {
+	capacities := replicationCapacityByBroker{
+		// Three brokers sharing rack a.
+		1001: throttleByRole{float64ptr(60), nil},
+		1002: throttleByRole{float64ptr(50), float64ptr(30)},
+		1003: throttleByRole{nil, float64ptr(40)},
+		// Rack b has no budget.
+		1004: throttleByRole{float64ptr(200), nil},
+	}
+
+	racks := map[int]string{1001: "a", 1002: "a", 1003: "a", 1004: "b"}
+	budgets := map[string]float64{"a": 100}
+
+	capacities.applyRackBudgets(budgets, racks)
+
+	// The leader rates for rack a exceeded the budget.
+	var leaderSum float64
+	for _, id := range []int{1001, 1002, 1003} {
+		if rate := capacities[id][0]; rate != nil {
+			leaderSum += *rate
+		}
+	}
+
+	if leaderSum > 100.0001 {
+		t.Errorf("Expected rack a leader rates to sum to at most 100.00, got %.2f", leaderSum)
+	}
+
+	// Rates are scaled proportionally.
+	if r := *capacities[1001][0]; math.Abs(r-54.55) > 0.01 {
+		t.Errorf("Expected broker 1001 leader rate 54.55, got %.2f", r)
+	}
+
+	// The follower rates for rack a were within the budget.
+	if r := *capacities[1002][1]; r != 30 {
+		t.Errorf("Expected broker 1002 follower rate 30.00, got %.2f", r)
+	}
+
+	// Non-replicating roles remain unset.
+	if capacities[1001][1] != nil {
+		t.Error("Expected nil follower rate for broker 1001")
+	}
+
+	// Rack b is unaffected.
+	if r := *capacities[1004][0]; r != 200 {
+		t.Errorf("Expected broker 1004 leader rate 200.00, got %.2f", r)
+	}
+}
+
 func float64ptr(f float64) *float64 {
 	return &f
 }
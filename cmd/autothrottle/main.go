@@ -43,7 +43,12 @@ var (
 		ChangeThreshold    float64
 		FailureThreshold   int
 		CapMap             map[string]float64
+		RackBudgets        map[string]float64
 		CleanupAfter       int64
+		MinThrottleHold    int
+		ScopeTTL           int
+		ChurnWindow        int
+		ChurnThreshold     int
 	}
 
 	// Misc.
@@ -70,7 +75,12 @@ func main() {
 	flag.Float64Var(&Config.ChangeThreshold, "change-threshold", 10, "Required change in replication throttle to trigger an update (percent)")
 	flag.IntVar(&Config.FailureThreshold, "failure-threshold", 1, "Number of iterations that throttle determinations can fail before reverting to the min-rate")
 	m := flag.String("cap-map", "", "JSON map of instance types to network capacity in MB/s")
+	rb := flag.String("rack-budgets", "", "JSON map of rack IDs to aggregate replication throttle budgets in MB/s, shared among each rack's replicating brokers")
 	flag.Int64Var(&Config.CleanupAfter, "cleanup-after", 60, "Number of intervals after which to issue a global throttle unset if no replication is running")
+	flag.IntVar(&Config.MinThrottleHold, "min-throttle-duration", 0, "Time that no reassignments must be observed before throttles are removed (seconds)")
+	flag.IntVar(&Config.ChurnWindow, "broker-churn-window", 600, "Time span over which broker registration changes are counted (seconds)")
+	flag.IntVar(&Config.ChurnThreshold, "broker-churn-threshold", 0, "Number of broker registration changes within the broker churn window at which throttle updates for the broker are skipped (0 to disable)")
+	flag.IntVar(&Config.ScopeTTL, "reassignment-scope-ttl", 86400, "Time after which a published reassignment scope is cleared if no reassignment of its topics has been observed (seconds, 0 to disable)")
 
 	envy.Parse("AUTOTHROTTLE")
 	flag.Parse()
@@ -90,6 +100,23 @@ func main() {
 		}
 	}
 
+	// Deserialize rack budgets map.
+	Config.RackBudgets = map[string]float64{}
+	if len(*rb) > 0 {
+		err := json.Unmarshal([]byte(*rb), &Config.RackBudgets)
+		if err != nil {
+			fmt.Printf("Error parsing rack-budgets flag: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
+	for rack, budget := range Config.RackBudgets {
+		if budget <= 0 {
+			fmt.Printf("Error parsing rack-budgets flag: budget for rack %s must be > 0\n", rack)
+			os.Exit(1)
+		}
+	}
+
 	log.Println("Autothrottle Running")
 	// Lazily prevent a tight restart
 	// loop from thrashing ZK.
@@ -178,9 +205,27 @@ func main() {
 		events:                 events,
 		previouslySetThrottles: make(replicationCapacityByBroker),
 		limits:                 lim,
+		rackBudgets:            Config.RackBudgets,
 		failureThreshold:       Config.FailureThreshold,
 	}
 
+	// Track broker registration churn to avoid acting on flapping brokers.
+	if Config.ChurnThreshold > 0 {
+		throttleMeta.brokerChurn = kafkazk.NewBrokerChurn(time.Duration(Config.ChurnWindow) * time.Second)
+		throttleMeta.churnThreshold = Config.ChurnThreshold
+	}
+
+	// Hold throttle removal until reassignments have been absent for the
+	// minimum throttle duration.
+	removalHold := &throttleRemovalHold{
+		duration: time.Duration(Config.MinThrottleHold) * time.Second,
+	}
+
+	// Track published reassignment scopes to determine when they're cleared.
+	scopeTracker := &reassignmentScopeTracker{
+		ttl: time.Duration(Config.ScopeTTL) * time.Second,
+	}
+
 	// Run.
 	var interval int64
 	var ticker = time.NewTicker(time.Duration(Config.Interval) * time.Second)
@@ -246,6 +291,11 @@ func main() {
 		// for the next check iteration.
 		topicsReplicatingPreviously = topicsReplicatingNow.copy()
 
+		// Record registered brokers for churn tracking.
+		if err := throttleMeta.ObserveBrokers(time.Now()); err != nil {
+			log.Println(err)
+		}
+
 		// Check if a global throttle override was configured.
 		overrideCfg, err := fetchThrottleOverride(zk, overrideRateZnodePath)
 		if err != nil {
@@ -258,8 +308,28 @@ func main() {
 			log.Println(err)
 		}
 
+		// Check if a reassignment scope was published (e.g. by topicmappr).
+		published, err := kafkazk.GetReassignmentScope(zk, reassignmentScopeZnodePath)
+		if err != nil {
+			log.Println(err)
+		}
+
+		// A published scope is cleared once the reassignment of its topics
+		// completes, or if it expires without being observed.
+		scope, clearScope := scopeTracker.update(published, topicsReplicatingNow, time.Now())
+		if clearScope {
+			log.Printf("Clearing reassignment scope for topics %v\n", published.Topics)
+			if err := clearReassignmentScope(zk, reassignmentScopeZnodePath, published.Timestamp); err != nil {
+				log.Printf("Error clearing reassignment scope: %s\n", err)
+			}
+		}
+
+		if scope != nil && len(topicsReplicatingNow) > 0 {
+			log.Printf("Reassignment scope published for topics %v, brokers %v\n", scope.Topics, scope.Brokers)
+		}
+
 		// Get the maps of brokers handling reassignments.
-		throttleMeta.reassigningBrokers, err = getReassigningBrokers(reassignments, zk)
+		throttleMeta.reassigningBrokers, err = getReassigningBrokers(reassignments, zk, scope)
 		if err != nil {
 			log.Println(err)
 		}
@@ -357,7 +427,7 @@ func main() {
 		// Capture all the current conditions:
 
 		// Are there throttles eligible to be cleared?
-		var throttlesToClear = knownThrottles || interval == Config.CleanupAfter
+		var throttlesToClear = knownThrottles || cleanupDue(interval, Config.CleanupAfter)
 
 		// Are any topics being reassigned?
 		var topicsReassigning bool
@@ -371,6 +441,11 @@ func main() {
 			brokerOverridesSet = true
 		}
 
+		// Has the throttle removal hold elapsed?
+		now := time.Now()
+		removalHold.update(topicsReassigning, now)
+		var removalHeld = !removalHold.elapsed(now)
+
 		// Next steps according to the various conditions:
 
 		if !topicsReassigning {
@@ -381,9 +456,13 @@ func main() {
 			log.Println("One or more brokers level override are set; automatic throttle removal will be skipped")
 		}
 
+		if !topicsReassigning && throttlesToClear && !brokerOverridesSet && removalHeld {
+			log.Printf("Throttle removal held for %s\n", removalHold.remaining(now).Round(time.Second))
+		}
+
 		// If there's previously set throttles but no topics reassigning nor
 		// broker overrides set, we can issue a global throttle removal.
-		if throttlesToClear && !topicsReassigning && !brokerOverridesSet {
+		if throttlesToClear && !topicsReassigning && !brokerOverridesSet && !removalHeld {
 			// Reset the interval count.
 			interval = 0
 
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+// reassignmentScopeTracker tracks a published reassignment scope across
+// intervals. Scopes are typically published when a reassignment is planned,
+// well before it's submitted, so a scope is only cleared once a reassignment
+// of its topics has been observed and has since completed. Scopes whose
+// reassignment is never observed are expired after the ttl so that they
+// can't filter a later, unrelated reassignment.
+type reassignmentScopeTracker struct {
+	ttl time.Duration
+	// The timestamp of the scope being tracked.
+	timestamp int64
+	// Whether a reassignment of the scope's topics has been observed.
+	seen bool
+}
+
+// update takes the currently published scope, the topics currently being
+// reassigned and the time now. It returns the scope to apply to the current
+// reassignments, which is nil if there's no applicable scope, and whether the
+// published scope should be cleared.
+func (t *reassignmentScopeTracker) update(scope *kafkazk.ReassignmentScope, reassigning set, now time.Time) (*kafkazk.ReassignmentScope, bool) {
+	if scope == nil {
+		t.timestamp, t.seen = 0, false
+		return nil, false
+	}
+
+	// A newly published scope.
+	if scope.Timestamp != t.timestamp {
+		t.timestamp, t.seen = scope.Timestamp, false
+	}
+
+	var matched bool
+	for topic := range reassigning {
+		if scope.HasTopic(topic) {
+			matched = true
+			break
+		}
+	}
+
+	switch {
+	case matched:
+		t.seen = true
+		return scope, false
+	// The scoped reassignment was observed and has completed.
+	case t.seen:
+		return nil, true
+	// The scoped reassignment was never observed.
+	case t.ttl > 0 && now.Sub(time.Unix(scope.Timestamp, 0)) > t.ttl:
+		return nil, true
+	}
+
+	return scope, false
+}
+
+// clearReassignmentScope clears the reassignment scope at the znode path if
+// it's still the scope with the provided timestamp, avoiding clearing a scope
+// published since it was last read.
+func clearReassignmentScope(zk kafkazk.Handler, path string, timestamp int64) error {
+	scope, err := kafkazk.GetReassignmentScope(zk, path)
+	if err != nil {
+		return err
+	}
+
+	if scope == nil || scope.Timestamp != timestamp {
+		return nil
+	}
+
+	return zk.Set(path, "")
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestReassignmentScopeTracker(t *testing.T) {
+	tracker := &reassignmentScopeTracker{ttl: time.Hour}
+	start := time.Now()
+
+	scope := &kafkazk.ReassignmentScope{
+		Topics:    []string{"test_topic"},
+		Brokers:   []int{1001, 1002},
+		Timestamp: start.Unix(),
+	}
+
+	reassigning := func(topics ...string) set {
+		s := newSet()
+		for _, t := range topics {
+			s.add(t)
+		}
+		return s
+	}
+
+	// The scope is published before the reassignment is submitted; it must
+	// outlive intervals with no reassignments.
+	for i := 1; i <= 3; i++ {
+		s, clear := tracker.update(scope, reassigning(), start.Add(time.Duration(i)*time.Minute))
+		if s != scope || clear {
+			t.Fatalf("[interval %d] Expected the scope to be retained", i)
+		}
+	}
+
+	// An unrelated reassignment doesn't consume the scope.
+	if s, clear := tracker.update(scope, reassigning("other_topic"), start.Add(4*time.Minute)); s != scope || clear {
+		t.Error("Expected the scope to be retained during an unrelated reassignment")
+	}
+
+	// The scoped reassignment is submitted.
+	for i := 5; i <= 6; i++ {
+		s, clear := tracker.update(scope, reassigning("test_topic"), start.Add(time.Duration(i)*time.Minute))
+		if s != scope || clear {
+			t.Fatalf("[interval %d] Expected the scope to be applied", i)
+		}
+	}
+
+	// The scoped reassignment completes.
+	if s, clear := tracker.update(scope, reassigning("other_topic"), start.Add(7*time.Minute)); s != nil || !clear {
+		t.Error("Expected the scope to be cleared")
+	}
+
+	// A newly published scope is tracked independently.
+	next := &kafkazk.ReassignmentScope{
+		Topics:    []string{"test_topic"},
+		Timestamp: start.Add(8 * time.Minute).Unix(),
+	}
+
+	if s, clear := tracker.update(next, reassigning(), start.Add(8*time.Minute)); s != next || clear {
+		t.Error("Expected the new scope to be retained")
+	}
+}
+
+func TestReassignmentScopeTrackerExpiry(t *testing.T) {
+	tracker := &reassignmentScopeTracker{ttl: time.Hour}
+	start := time.Now()
+
+	scope := &kafkazk.ReassignmentScope{
+		Topics:    []string{"test_topic"},
+		Timestamp: start.Unix(),
+	}
+
+	if s, clear := tracker.update(scope, newSet(), start.Add(59*time.Minute)); s != scope || clear {
+		t.Error("Expected the scope to be retained within the ttl")
+	}
+
+	// The scope was never submitted; a later reassignment of the same topic
+	// must not be filtered by it.
+	r := newSet()
+	r.add("other_topic")
+
+	if s, clear := tracker.update(scope, r, start.Add(61*time.Minute)); s != nil || !clear {
+		t.Error("Expected the scope to expire")
+	}
+
+	// With no ttl, unobserved scopes are retained.
+	tracker = &reassignmentScopeTracker{}
+	if s, clear := tracker.update(scope, newSet(), start.Add(48*time.Hour)); s != scope || clear {
+		t.Error("Expected the scope to be retained")
+	}
+}
+
+func TestClearReassignmentScope(t *testing.T) {
+	zk := kafkazk.NewZooKeeperStub()
+	path := "/autothrottle/reassignment_scope"
+
+	scope := kafkazk.ReassignmentScope{Topics: []string{"test_topic"}, Timestamp: 100}
+	if err := kafkazk.SetReassignmentScope(zk, path, scope); err != nil {
+		t.Fatal(err)
+	}
+
+	// A stale timestamp doesn't clear a newly published scope.
+	if err := clearReassignmentScope(zk, path, 50); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, _ := kafkazk.GetReassignmentScope(zk, path); s == nil {
+		t.Fatal("Expected the scope to be retained")
+	}
+
+	if err := clearReassignmentScope(zk, path, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, _ := kafkazk.GetReassignmentScope(zk, path); s != nil {
+		t.Errorf("Expected the scope to be cleared, got %v", s)
+	}
+}
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/DataDog/kafka-kit/v3/kafkametrics"
 	"github.com/DataDog/kafka-kit/v3/kafkazk"
 )
@@ -21,9 +24,67 @@ type ReplicationThrottleConfigs struct {
 	events                   *DDEventWriter
 	previouslySetThrottles   replicationCapacityByBroker
 	limits                   Limits
+	rackBudgets              map[string]float64
 	failureThreshold         int
 	failures                 int
 	skipTopicUpdates         bool
+	// Broker registration churn tracking; throttle updates are skipped for
+	// brokers with at least churnThreshold events. A nil brokerChurn disables
+	// tracking.
+	brokerChurn    *kafkazk.BrokerChurn
+	churnThreshold int
+}
+
+// throttleRemovalHold tracks how long reassignments have been continuously
+// absent. Throttle removal is held until no reassignments have been observed
+// for the configured duration, preventing premature removal when a
+// reassignment briefly appears complete (e.g. a transient ISR flap).
+type throttleRemovalHold struct {
+	duration time.Duration
+	// The time since which no reassignments have been observed. A zero value
+	// means a reassignment was observed in the most recent update.
+	clearSince time.Time
+}
+
+// update records whether any reassignments were observed at time now.
+func (h *throttleRemovalHold) update(reassigning bool, now time.Time) {
+	switch {
+	case reassigning:
+		h.clearSince = time.Time{}
+	case h.clearSince.IsZero():
+		h.clearSince = now
+	}
+}
+
+// elapsed returns whether no reassignments have been observed for at least
+// the hold duration as of time now.
+func (h *throttleRemovalHold) elapsed(now time.Time) bool {
+	if h.clearSince.IsZero() {
+		return false
+	}
+
+	return now.Sub(h.clearSince) >= h.duration
+}
+
+// remaining returns the time left in the hold as of time now.
+func (h *throttleRemovalHold) remaining(now time.Time) time.Duration {
+	if h.elapsed(now) {
+		return 0
+	}
+
+	if h.clearSince.IsZero() {
+		return h.duration
+	}
+
+	return h.duration - now.Sub(h.clearSince)
+}
+
+// cleanupDue returns whether a periodic global throttle cleanup is due at the
+// interval count, which is reset once throttles are removed. The cleanup
+// remains due past cleanupAfter intervals so that a removal held on the
+// cleanupAfter interval is still issued once the hold elapses.
+func cleanupDue(interval, cleanupAfter int64) bool {
+	return interval >= cleanupAfter
 }
 
 // ThrottleOverrideConfig holds throttle override configurations.
@@ -136,6 +197,43 @@ func (r *ReplicationThrottleConfigs) EnableOverrideTopicUpdates() {
 	r.skipOverrideTopicUpdates = false
 }
 
+// ObserveBrokers records the brokers currently registered in ZooKeeper at
+// time now for churn tracking.
+func (r *ReplicationThrottleConfigs) ObserveBrokers(now time.Time) error {
+	if r.brokerChurn == nil {
+		return nil
+	}
+
+	brokers, errs := r.zk.GetAllBrokerMeta(false)
+	if errs != nil {
+		return fmt.Errorf("Error fetching broker metadata for churn tracking: %v", errs)
+	}
+
+	var ids []int
+	for id := range brokers {
+		ids = append(ids, id)
+	}
+
+	r.brokerChurn.Observe(ids, now)
+
+	return nil
+}
+
+// FlappingBrokers returns the IDs of brokers with churn at or above the
+// configured threshold as of time now.
+func (r *ReplicationThrottleConfigs) FlappingBrokers(now time.Time) map[int]struct{} {
+	var flapping = make(map[int]struct{})
+	if r.brokerChurn == nil {
+		return flapping
+	}
+
+	for _, id := range r.brokerChurn.Flapping(r.churnThreshold, now) {
+		flapping[id] = struct{}{}
+	}
+
+	return flapping
+}
+
 // ThrottledBrokers is a list of brokers with a throttle applied
 // for an ongoing reassignment.
 type ThrottledBrokers struct {
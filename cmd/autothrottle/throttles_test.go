@@ -0,0 +1,147 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestThrottleRemovalHold(t *testing.T) {
+	h := &throttleRemovalHold{duration: 10 * time.Minute}
+	start := time.Now()
+
+	// An ongoing reassignment.
+	h.update(true, start)
+	if h.elapsed(start) {
+		t.Error("Expected throttles to be held during a reassignment")
+	}
+
+	// The reassignment briefly appears complete.
+	h.update(false, start.Add(3*time.Minute))
+	if h.elapsed(start.Add(3 * time.Minute)) {
+		t.Error("Expected throttles to be held within the hold window")
+	}
+
+	// The reassignment reappears within the hold window.
+	h.update(true, start.Add(6*time.Minute))
+	if h.elapsed(start.Add(6 * time.Minute)) {
+		t.Error("Expected throttles to be held during a reassignment")
+	}
+
+	// The reassignment completes. The original hold window would have elapsed
+	// at 13m, but the flap restarts the window.
+	h.update(false, start.Add(9*time.Minute))
+	h.update(false, start.Add(15*time.Minute))
+	if h.elapsed(start.Add(15 * time.Minute)) {
+		t.Error("Expected throttles to be held until the window elapses cleanly")
+	}
+
+	if r := h.remaining(start.Add(15 * time.Minute)); r != 4*time.Minute {
+		t.Errorf("Expected 4m remaining, got %s", r)
+	}
+
+	// The window elapses without a reassignment.
+	h.update(false, start.Add(19*time.Minute))
+	if !h.elapsed(start.Add(19 * time.Minute)) {
+		t.Error("Expected throttle hold to have elapsed")
+	}
+}
+
+func TestThrottleRemovalHoldDisabled(t *testing.T) {
+	h := &throttleRemovalHold{}
+	now := time.Now()
+
+	h.update(true, now)
+	if h.elapsed(now) {
+		t.Error("Expected throttles to be held during a reassignment")
+	}
+
+	// With no hold duration, removal is immediate.
+	h.update(false, now)
+	if !h.elapsed(now) {
+		t.Error("Expected throttle hold to have elapsed")
+	}
+}
+
+func TestCleanupDueAfterHold(t *testing.T) {
+	const cleanupAfter = 5
+	h := &throttleRemovalHold{duration: 3 * time.Minute}
+	start := time.Now()
+
+	var interval int64
+	var cleanedAt int64
+
+	// A reassignment completes just before the cleanupAfter interval, so the
+	// hold is active on that interval. One interval per minute.
+	for i := int64(1); i <= 10; i++ {
+		interval++
+		now := start.Add(time.Duration(i) * time.Minute)
+
+		h.update(i < cleanupAfter, now)
+		if cleanupDue(interval, cleanupAfter) && h.elapsed(now) {
+			cleanedAt = i
+			interval = 0
+			break
+		}
+	}
+
+	if cleanedAt != 8 {
+		t.Errorf("Expected cleanup at interval 8, got %d", cleanedAt)
+	}
+}
+
+// churnZK is a kafkazk.Stub where broker 1001 can be deregistered.
+type churnZK struct {
+	*kafkazk.Stub
+	deregistered bool
+}
+
+func (zk *churnZK) GetAllBrokerMeta(withMetrics bool) (kafkazk.BrokerMetaMap, []error) {
+	brokers, errs := zk.Stub.GetAllBrokerMeta(withMetrics)
+	if zk.deregistered {
+		delete(brokers, 1001)
+	}
+
+	return brokers, errs
+}
+
+func TestFlappingBrokers(t *testing.T) {
+	zk := &churnZK{Stub: &kafkazk.Stub{}}
+	rtc := &ReplicationThrottleConfigs{
+		zk:             zk,
+		brokerChurn:    kafkazk.NewBrokerChurn(10 * time.Minute),
+		churnThreshold: 3,
+	}
+
+	start := time.Now()
+
+	// Broker 1001 deregisters and reregisters each interval.
+	for i := 0; i < 4; i++ {
+		now := start.Add(time.Duration(i) * time.Minute)
+		if err := rtc.ObserveBrokers(now); err != nil {
+			t.Fatal(err)
+		}
+		zk.deregistered = !zk.deregistered
+	}
+
+	flapping := rtc.FlappingBrokers(start.Add(3 * time.Minute))
+	if _, exists := flapping[1001]; !exists || len(flapping) != 1 {
+		t.Errorf("Expected flapping brokers [1001], got %v", flapping)
+	}
+
+	// Churn expires once outside the window.
+	flapping = rtc.FlappingBrokers(start.Add(30 * time.Minute))
+	if len(flapping) != 0 {
+		t.Errorf("Expected no flapping brokers, got %v", flapping)
+	}
+
+	// Churn tracking is disabled without a BrokerChurn.
+	rtc = &ReplicationThrottleConfigs{zk: zk}
+	if err := rtc.ObserveBrokers(start); err != nil {
+		t.Fatal(err)
+	}
+	if flapping := rtc.FlappingBrokers(start); len(flapping) != 0 {
+		t.Errorf("Expected no flapping brokers, got %v", flapping)
+	}
+}
@@ -109,6 +109,22 @@ func updateReplicationThrottle(params *ReplicationThrottleConfigs) error {
 		}
 	}
 
+	// Cap the aggregate rates of brokers in racks with a budget set.
+	// Broker-specific overrides are explicit and are merged in after.
+	if len(params.rackBudgets) > 0 {
+		brokerMeta, errs := params.zk.GetAllBrokerMeta(false)
+		if errs != nil {
+			return fmt.Errorf("Error fetching broker metadata for rack budgets: %v", errs)
+		}
+
+		racks := map[int]string{}
+		for id, meta := range brokerMeta {
+			racks[id] = meta.Rack
+		}
+
+		capacities.applyRackBudgets(params.rackBudgets, racks)
+	}
+
 	// Merge in broker-specific overrides if they're part of the reassignment.
 	for id := range params.reassigningBrokers.all {
 		if override, exists := params.brokerOverrides[id]; exists {
@@ -129,8 +145,28 @@ func updateReplicationThrottle(params *ReplicationThrottleConfigs) error {
 		}
 	}
 
+	// Skip throttle updates for flapping brokers; any previously set throttle
+	// is retained until the broker stabilizes.
+	var throttleBrokers = params.reassigningBrokers.all
+	if flapping := params.FlappingBrokers(time.Now()); len(flapping) > 0 {
+		throttleBrokers = make(map[int]struct{})
+		var skipped []int
+		for id := range params.reassigningBrokers.all {
+			if _, exists := flapping[id]; exists {
+				skipped = append(skipped, id)
+				continue
+			}
+			throttleBrokers[id] = struct{}{}
+		}
+
+		if len(skipped) > 0 {
+			sort.Ints(skipped)
+			log.Printf("Skipping throttle updates for flapping brokers: %v\n", skipped)
+		}
+	}
+
 	// Set broker throttle configs.
-	events, errs := applyBrokerThrottles(params.reassigningBrokers.all, capacities, params.previouslySetThrottles, params.limits, params.zk)
+	events, errs := applyBrokerThrottles(throttleBrokers, capacities, params.previouslySetThrottles, params.limits, params.zk)
 	for _, e := range errs {
 		// TODO(jamie): revisit whether we should actually be returning
 		// rather than just logging errors here.
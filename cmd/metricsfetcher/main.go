@@ -1,13 +1,12 @@
 package main
 
 import (
-	"bytes"
-	"compress/gzip"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/DataDog/kafka-kit/v3/kafkazk"
 
@@ -25,11 +24,14 @@ type Config struct {
 	BrokerQuery string
 	BrokerIDTag string
 	Span        int
-	ZKAddr      string
-	ZKPrefix    string
-	Verbose     bool
-	DryRun      bool
-	Compression bool
+	// Seconds of broker storage samples retained for growth rates.
+	GrowthWindow int
+	ZKAddr       string
+	ZKPrefix     string
+	Verbose      bool
+	DryRun       bool
+	Compression  bool
+	Validate     bool
 }
 
 var (
@@ -38,7 +40,9 @@ var (
 	config  = &Config{}
 )
 
-func init() {
+// parseFlags populates the config from flags and env vars. This is done in
+// main rather than init so that the package can be tested.
+func parseFlags() {
 	v := flag.Bool("version", false, "version")
 	flag.StringVar(&config.APIKey, "api-key", "", "Datadog API key")
 	flag.StringVar(&config.AppKey, "app-key", "", "Datadog app key")
@@ -46,11 +50,13 @@ func init() {
 	flag.StringVar(&config.BrokerIDTag, "broker-id-tag", "broker_id", "Datadog host tag for broker ID")
 	pq := flag.String("partition-size-query", "max:kafka.log.partition.size{service:kafka} by {topic,partition}", "Datadog metric query to get partition size by topic, partition")
 	flag.IntVar(&config.Span, "span", 3600, "Query range in seconds (now - span)")
+	flag.IntVar(&config.GrowthWindow, "growth-window", 86400, "Time span of broker storage samples retained across runs to compute storage growth rates (seconds)")
 	flag.StringVar(&config.ZKAddr, "zk-addr", "localhost:2181", "ZooKeeper connect string")
 	flag.StringVar(&config.ZKPrefix, "zk-prefix", "topicmappr", "ZooKeeper namespace prefix")
 	flag.BoolVar(&config.Verbose, "verbose", false, "Verbose output")
 	flag.BoolVar(&config.DryRun, "dry-run", false, "Dry run mode (don't reach Zookeeper)")
 	flag.BoolVar(&config.Compression, "compression", true, "Whether to compress metrics data written to ZooKeeper")
+	flag.BoolVar(&config.Validate, "validate", false, "Validate API access, queries and ZooKeeper connectivity, then exit")
 
 	envy.Parse("METRICSFETCHER")
 	flag.Parse()
@@ -66,8 +72,16 @@ func init() {
 }
 
 func main() {
+	parseFlags()
+
 	// Init, validate dd client.
 	config.Client = dd.NewClient(config.APIKey, config.AppKey)
+
+	// If we're in validate mode, run all checks and exit.
+	if config.Validate {
+		os.Exit(validate(validationChecks(config), os.Stdout))
+	}
+
 	ok, err := config.Client.Validate()
 	exitOnErr(err)
 
@@ -86,8 +100,16 @@ func main() {
 
 	// Ensure znodes exist.
 	paths := zkPaths(config.ZKPrefix)
+	samplesPath := storageSamplesPath(config.ZKPrefix)
+	if !config.DryRun {
+		err = createZNodesIfNotExist(zk, append(paths, samplesPath))
+		exitOnErr(err)
+	}
+
+	// Load the retained broker storage samples.
+	samples := storageSamples{}
 	if !config.DryRun {
-		err = createZNodesIfNotExist(zk, paths)
+		samples, err = loadStorageSamples(zk, samplesPath)
 		exitOnErr(err)
 	}
 
@@ -101,7 +123,7 @@ func main() {
 	exitOnErr(err)
 
 	fmt.Printf("Submitting %s\n", config.BrokerQuery)
-	bm, err := brokerMetrics(config)
+	bm, err := brokerMetrics(config, samples)
 	exitOnErr(err)
 	fmt.Println("success")
 
@@ -121,6 +143,10 @@ func main() {
 			paths[0], config.PartnQuery, partnData)
 	}
 
+	if config.Verbose {
+		printStorageProjections(bm)
+	}
+
 	if config.DryRun {
 		return
 	}
@@ -129,23 +155,43 @@ func main() {
 	for i, data := range [][]byte{partnData, brokerData} {
 		// Optionally compress the data.
 		if config.Compression {
-			var buf bytes.Buffer
-			zw := gzip.NewWriter(&buf)
-
-			_, err := zw.Write(data)
+			data, err = gzipData(data)
 			exitOnErr(err)
-
-			zw.Close()
-			data = buf.Bytes()
 		}
 
 		err = zk.Set(paths[i], string(data))
 		exitOnErr(err)
 	}
 
+	err = storeStorageSamples(zk, samplesPath, samples, config.Compression)
+	exitOnErr(err)
+
 	fmt.Println("\nData written to ZooKeeper")
 }
 
+// printStorageProjections prints the storage growth rate and projected days
+// until storage free is exhausted for each broker with a growth rate.
+func printStorageProjections(bm map[string]map[string]float64) {
+	var ids []string
+	for id := range bm {
+		ids = append(ids, id)
+	}
+
+	sort.Strings(ids)
+
+	fmt.Println("Storage projections:")
+	for _, id := range ids {
+		rate, exists := bm[id]["StorageGrowthRate"]
+		if !exists {
+			continue
+		}
+
+		free := bm[id]["StorageFree"]
+		fmt.Printf("  broker %s: %.2fGB free, %.2fGB/h growth, %.1f days to full\n",
+			id, free/(1<<30), rate/(1<<30), daysToFull(free, rate))
+	}
+}
+
 func zkPaths(p string) []string {
 	paths := []string{}
 
@@ -166,6 +212,17 @@ func zkPaths(p string) []string {
 	return paths
 }
 
+// storageSamplesPath returns the znode path of the retained broker storage
+// samples for the prefix.
+func storageSamplesPath(p string) string {
+	switch p {
+	case "/", "":
+		return "/brokerstoragesamples"
+	default:
+		return fmt.Sprintf("/%s/brokerstoragesamples", p)
+	}
+}
+
 func createZNodesIfNotExist(z kafkazk.Handler, p []string) error {
 	// Check each path.
 	for _, path := range p {
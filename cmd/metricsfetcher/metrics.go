@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -53,9 +54,14 @@ func partitionMetrics(c *Config) (map[string]map[string]map[string]float64, erro
 	return d, nil
 }
 
-func brokerMetrics(c *Config) (map[string]map[string]float64, error) {
-	start := time.Now().Add(-time.Duration(c.Span*2) * time.Second).Unix()
-	o, err := c.Client.QueryMetrics(start, time.Now().Unix(), c.BrokerQuery)
+// brokerMetrics fetches broker storage free metrics. The query datapoints are
+// added to the retained storageSamples, which are expired according to the
+// configured growth window; the storage growth rate of each broker is
+// computed from its retained samples.
+func brokerMetrics(c *Config, samples storageSamples) (map[string]map[string]float64, error) {
+	now := time.Now()
+	start := now.Add(-time.Duration(c.Span*2) * time.Second).Unix()
+	o, err := c.Client.QueryMetrics(start, now.Unix(), c.BrokerQuery)
 	if err != nil {
 		return nil, err
 	}
@@ -83,6 +89,17 @@ func brokerMetrics(c *Config) (map[string]map[string]float64, error) {
 		}
 
 		d[broker]["StorageFree"] = val
+
+		samples.add(broker, samplesFromPoints(ts.Points))
+	}
+
+	samples.expire(now, time.Duration(c.GrowthWindow)*time.Second)
+
+	// Get the storage growth rate if enough samples are available.
+	for broker := range d {
+		if rate, err := storageGrowthRate(samples[broker]); err == nil {
+			d[broker]["StorageGrowthRate"] = rate
+		}
 	}
 
 	return d, nil
@@ -101,6 +118,17 @@ func latestValue(points []dd.DataPoint) (float64, error) {
 	return 0, fmt.Errorf("no value found")
 }
 
+// daysToFull takes storage free in bytes and a storage growth rate in
+// bytes/hour and returns the projected number of days until storage free is
+// exhausted. If storage isn't being consumed, +Inf is returned.
+func daysToFull(free, rate float64) float64 {
+	if rate <= 0 {
+		return math.Inf(1)
+	}
+
+	return free / rate / 24
+}
+
 // tagValFromScope takes a metric scope string and a tag and returns that tag's value.
 func tagValFromScope(scope, tag string) string {
 	ts := strings.Split(scope, ",")
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+func testDataPoint(ts, v float64) dd.DataPoint {
+	return dd.DataPoint{&ts, &v}
+}
+
+func TestStorageGrowthRate(t *testing.T) {
+	gb := float64(1 << 30)
+	start := time.Unix(1600000000, 0)
+	samples := storageSamples{}
+
+	// Two runs two hours apart, each querying a single datapoint.
+	samples.add("1001", samplesFromPoints([]dd.DataPoint{
+		testDataPoint(float64(start.Unix()*1000), 1000*gb),
+		{nil, nil},
+	}))
+
+	samples.expire(start, 24*time.Hour)
+
+	if _, err := storageGrowthRate(samples["1001"]); err == nil {
+		t.Error("Expected non-nil error for a single sample")
+	}
+
+	next := start.Add(2 * time.Hour)
+	samples.add("1001", samplesFromPoints([]dd.DataPoint{
+		testDataPoint(float64(next.Unix()*1000), 990*gb),
+	}))
+
+	samples.expire(next, 24*time.Hour)
+
+	rate, err := storageGrowthRate(samples["1001"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10GB consumed over two hours.
+	if math.Abs(rate-5*gb) > 1 {
+		t.Errorf("Expected growth rate %.2f, got %.2f", 5*gb, rate)
+	}
+
+	// 990GB free at 5GB/h is 198 hours.
+	if d := daysToFull(990*gb, rate); math.Abs(d-8.25) > 0.001 {
+		t.Errorf("Expected 8.25 days to full, got %.3f", d)
+	}
+
+	// Storage free increasing.
+	if d := daysToFull(990*gb, -rate); !math.IsInf(d, 1) {
+		t.Errorf("Expected +Inf days to full, got %.3f", d)
+	}
+}
+
+func TestStorageSamplesExpire(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	samples := storageSamples{}
+
+	for i := 0; i < 5; i++ {
+		ts := now.Add(-time.Duration(i) * time.Hour).Unix()
+		samples.add("1001", []storageSample{{Timestamp: ts, StorageFree: float64(i)}})
+	}
+
+	// A repeated timestamp replaces the retained sample.
+	samples.add("1001", []storageSample{{Timestamp: now.Unix(), StorageFree: 10}})
+
+	// A broker with only expired samples.
+	samples.add("1002", []storageSample{{Timestamp: now.Add(-48 * time.Hour).Unix()}})
+
+	samples.expire(now, 150*time.Minute)
+
+	expected := []storageSample{
+		{Timestamp: now.Add(-2 * time.Hour).Unix(), StorageFree: 2},
+		{Timestamp: now.Add(-1 * time.Hour).Unix(), StorageFree: 1},
+		{Timestamp: now.Unix(), StorageFree: 10},
+	}
+
+	if !reflect.DeepEqual(samples["1001"], expected) {
+		t.Errorf("Expected samples %v, got %v", expected, samples["1001"])
+	}
+
+	if _, exists := samples["1002"]; exists {
+		t.Error("Expected broker 1002 to be removed")
+	}
+
+	// The number of samples is capped.
+	for i := 0; i < maxStorageSamples*2; i++ {
+		samples.add("1001", []storageSample{{Timestamp: now.Unix() + int64(i)}})
+	}
+
+	samples.expire(now, 150*time.Minute)
+
+	if n := len(samples["1001"]); n != maxStorageSamples {
+		t.Errorf("Expected %d samples, got %d", maxStorageSamples, n)
+	}
+}
+
+func TestStorageSamplesStore(t *testing.T) {
+	zk := kafkazk.NewZooKeeperStub()
+	path := storageSamplesPath("topicmappr")
+
+	zk.Create(path, "")
+
+	samples, err := loadStorageSamples(zk, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(samples) != 0 {
+		t.Errorf("Expected no samples, got %v", samples)
+	}
+
+	samples.add("1001", []storageSample{{Timestamp: 1600000000, StorageFree: 1000}})
+
+	for _, compress := range []bool{true, false} {
+		if err := storeStorageSamples(zk, path, samples, compress); err != nil {
+			t.Fatal(err)
+		}
+
+		loaded, err := loadStorageSamples(zk, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(loaded, samples) {
+			t.Errorf("[compress %t] Expected %v, got %v", compress, samples, loaded)
+		}
+	}
+}
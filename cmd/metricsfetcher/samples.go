@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	dd "github.com/zorkian/go-datadog-api"
+)
+
+// maxStorageSamples is the maximum number of samples retained per broker.
+const maxStorageSamples = 100
+
+// storageSample is a broker storage free value at a point in time.
+type storageSample struct {
+	// Unix timestamp in seconds.
+	Timestamp   int64   `json:"timestamp"`
+	StorageFree float64 `json:"storage_free"`
+}
+
+// storageSamples holds the retained storage samples by broker ID, ordered by
+// timestamp. Samples are retained across metricsfetcher runs so that storage
+// growth rates reflect a longer trend than a single query.
+type storageSamples map[string][]storageSample
+
+// samplesFromPoints takes a []dd.DataPoint and returns a []storageSample of
+// the non-nil datapoints.
+func samplesFromPoints(points []dd.DataPoint) []storageSample {
+	var samples []storageSample
+	for _, p := range points {
+		if p[0] == nil || p[1] == nil {
+			continue
+		}
+		// Datapoint timestamps are in milliseconds.
+		samples = append(samples, storageSample{
+			Timestamp:   int64(*p[0] / 1000),
+			StorageFree: *p[1],
+		})
+	}
+
+	return samples
+}
+
+// add merges the samples into the retained samples for the broker. A retained
+// sample with the same timestamp as an added sample is replaced, e.g. where a
+// rollup interval is queried again before it's complete.
+func (s storageSamples) add(broker string, samples []storageSample) {
+	byTimestamp := map[int64]storageSample{}
+	for _, sample := range s[broker] {
+		byTimestamp[sample.Timestamp] = sample
+	}
+
+	for _, sample := range samples {
+		byTimestamp[sample.Timestamp] = sample
+	}
+
+	merged := make([]storageSample, 0, len(byTimestamp))
+	for _, sample := range byTimestamp {
+		merged = append(merged, sample)
+	}
+
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Timestamp < merged[j].Timestamp
+	})
+
+	s[broker] = merged
+}
+
+// expire removes samples older than the window as of now, retaining at most
+// maxStorageSamples of the most recent samples per broker. Brokers left with
+// no samples are removed.
+func (s storageSamples) expire(now time.Time, window time.Duration) {
+	oldest := now.Add(-window).Unix()
+
+	for broker, samples := range s {
+		i := sort.Search(len(samples), func(i int) bool {
+			return samples[i].Timestamp >= oldest
+		})
+
+		if n := len(samples) - maxStorageSamples; i < n {
+			i = n
+		}
+
+		if i == len(samples) {
+			delete(s, broker)
+			continue
+		}
+
+		s[broker] = samples[i:]
+	}
+}
+
+// storageGrowthRate takes a []storageSample and returns the rate of storage
+// consumption in bytes/hour. The rate is the least squares slope of the
+// samples, negated such that a positive rate indicates that storage free is
+// decreasing. An error is returned if there aren't at least two samples at
+// distinct times.
+func storageGrowthRate(samples []storageSample) (float64, error) {
+	if len(samples) < 2 {
+		return 0, fmt.Errorf("insufficient samples")
+	}
+
+	var xMean, yMean float64
+	xs := make([]float64, len(samples))
+
+	for i, sample := range samples {
+		// Use hours relative to the first sample.
+		xs[i] = float64(sample.Timestamp-samples[0].Timestamp) / 3600
+		xMean += xs[i]
+		yMean += sample.StorageFree
+	}
+
+	xMean /= float64(len(samples))
+	yMean /= float64(len(samples))
+
+	var num, den float64
+	for i, sample := range samples {
+		num += (xs[i] - xMean) * (sample.StorageFree - yMean)
+		den += (xs[i] - xMean) * (xs[i] - xMean)
+	}
+
+	if den == 0 {
+		return 0, fmt.Errorf("insufficient samples")
+	}
+
+	return -num / den, nil
+}
+
+// loadStorageSamples returns the storageSamples stored at the znode path,
+// which may be gzip compressed. Empty storageSamples are returned if the
+// znode is empty.
+func loadStorageSamples(zk kafkazk.Handler, path string) (storageSamples, error) {
+	data, err := zk.Get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := storageSamples{}
+	if len(data) == 0 {
+		return samples, nil
+	}
+
+	// Check for the gzip header.
+	if len(data) > 1 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		if data, err = ioutil.ReadAll(zr); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := json.Unmarshal(data, &samples); err != nil {
+		return nil, fmt.Errorf("error unmarshalling storage samples: %s", err)
+	}
+
+	return samples, nil
+}
+
+// storeStorageSamples writes the storageSamples to the znode path, optionally
+// gzip compressed.
+func storeStorageSamples(zk kafkazk.Handler, path string, samples storageSamples, compress bool) error {
+	data, err := json.Marshal(samples)
+	if err != nil {
+		return err
+	}
+
+	if compress {
+		if data, err = gzipData(data); err != nil {
+			return err
+		}
+	}
+
+	return zk.Set(path, string(data))
+}
+
+// gzipData returns the gzip compressed data.
+func gzipData(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+// check is a named validation check.
+type check struct {
+	name string
+	fn   func() error
+}
+
+// validationChecks returns the checks performed in -validate mode. These
+// confirm that the Datadog API credentials are accepted, that the configured
+// queries return data, and that ZooKeeper is reachable.
+func validationChecks(c *Config) []check {
+	checks := []check{
+		{
+			name: "Datadog API/app key access",
+			fn: func() error {
+				ok, err := c.Client.Validate()
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.New("access denied: invalid API or app key")
+				}
+				return nil
+			},
+		},
+		{
+			name: "broker storage query",
+			fn: func() error {
+				bm, err := brokerMetrics(c, storageSamples{})
+				if err != nil {
+					return err
+				}
+				if len(bm) == 0 {
+					return fmt.Errorf("no data returned for %s", c.BrokerQuery)
+				}
+				return nil
+			},
+		},
+		{
+			name: "partition size query",
+			fn: func() error {
+				pm, err := partitionMetrics(c)
+				if err != nil {
+					return err
+				}
+				if len(pm) == 0 {
+					return fmt.Errorf("no data returned for %s", c.PartnQuery)
+				}
+				return nil
+			},
+		},
+	}
+
+	if !c.DryRun {
+		checks = append(checks, check{
+			name: "ZooKeeper connectivity",
+			fn: func() error {
+				zk, err := kafkazk.NewHandler(&kafkazk.Config{Connect: c.ZKAddr})
+				if err != nil {
+					return err
+				}
+				defer zk.Close()
+
+				timeout := 250 * time.Millisecond
+				time.Sleep(timeout)
+				if !zk.Ready() {
+					return fmt.Errorf("failed to connect to %s within %s", c.ZKAddr, timeout)
+				}
+
+				_, err = zk.Exists("/")
+				return err
+			},
+		})
+	}
+
+	return checks
+}
+
+// validate runs each check, writing the results to w. An exit code of 0 is
+// returned if all checks passed, 1 otherwise.
+func validate(checks []check, w io.Writer) int {
+	var failed int
+
+	for _, c := range checks {
+		if err := c.fn(); err != nil {
+			failed++
+			fmt.Fprintf(w, "[FAIL] %s: %s\n", c.name, err)
+			continue
+		}
+		fmt.Fprintf(w, "[OK] %s\n", c.name)
+	}
+
+	if failed > 0 {
+		fmt.Fprintf(w, "\n%d of %d checks failed\n", failed, len(checks))
+		return 1
+	}
+
+	fmt.Fprintf(w, "\nAll %d checks passed\n", len(checks))
+	return 0
+}
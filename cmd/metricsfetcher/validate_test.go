@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	checks := []check{
+		{name: "Datadog API/app key access", fn: func() error { return errors.New("access denied: invalid API or app key") }},
+		{name: "ZooKeeper connectivity", fn: func() error { return nil }},
+	}
+
+	var buf bytes.Buffer
+	code := validate(checks, &buf)
+
+	if code != 1 {
+		t.Errorf("Expected exit code 1, got %d", code)
+	}
+
+	out := buf.String()
+	expected := "[FAIL] Datadog API/app key access: access denied: invalid API or app key"
+	if !strings.Contains(out, expected) {
+		t.Errorf("Expected output to contain '%s', got '%s'", expected, out)
+	}
+
+	if !strings.Contains(out, "[OK] ZooKeeper connectivity") {
+		t.Errorf("Expected passing check in output, got '%s'", out)
+	}
+
+	// All passing.
+	buf.Reset()
+	if code := validate(checks[1:], &buf); code != 0 {
+		t.Errorf("Expected exit code 0, got %d", code)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
@@ -43,6 +44,7 @@ func main() {
 	flag.StringVar(&serverConfig.ZKTagsPrefix, "zk-tags-prefix", "registry", "Tags storage ZooKeeper prefix")
 	flag.StringVar(&zkConfig.Connect, "zk-addr", "localhost:2181", "ZooKeeper connect string")
 	flag.StringVar(&zkConfig.Prefix, "zk-prefix", "", "ZooKeeper prefix (if Kafka is configured with a chroot path prefix)")
+	flag.StringVar(&zkConfig.MetricsPrefix, "zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics")
 	flag.StringVar(&adminConfig.BootstrapServers, "bootstrap-servers", "localhost", "Kafka bootstrap servers")
 	flag.StringVar(&adminConfig.SecurityProtocol, "kafka-security-protocol", "", fmt.Sprintf("Protocol used to communicate with brokers. Supported: %s", strings.Join(securityProtocols, ", ")))
 	flag.StringVar(&adminConfig.SSLCALocation, "kafka-ssl-ca-location", "", "CA certificate path (.pem/.crt) for verifying broker's identity. Needed for SSL and SASL_SSL protocols.")
@@ -51,6 +53,17 @@ func main() {
 	flag.StringVar(&adminConfig.SASLPassword, "kafka-sasl-password", "", "SASL password for use with the PLAIN and SASL-SCRAM-* mechanisms")
 	flag.IntVar(&serverConfig.TagAllowedStalenessMinutes, "tag-allowed-staleness", 60, "Minutes before tags with no associated resource are deleted")
 	flag.IntVar(&serverConfig.TagCleanupFrequencyMinutes, "tag-cleanup-frequency", 20, "Minutes between runs of tag cleanup")
+	flag.IntVar(&serverConfig.UnderReplicationCheckSeconds, "under-replication-check-interval", 0, "Seconds between checks for topics becoming or recovering from being under-replicated; transitions are logged (0 disables)")
+	flag.BoolVar(&serverConfig.ReadOnly, "read-only", false, "Reject all mutating requests, serving reads only")
+	flag.IntVar(&serverConfig.ZKWriteCheckSeconds, "zk-write-check-interval", 0, "Seconds between ZooKeeper write checks; read-only mode is entered while writes fail (0 disables)")
+	flag.IntVar(&serverConfig.SnapshotRetention, "snapshot-retention", 10, "Number of snapshots retained; the oldest snapshots are deleted as new snapshots are created (0 retains all)")
+	flag.StringVar(&serverConfig.PolicyURL, "policy-url", "", "Policy service URL (e.g. an OPA decision endpoint) consulted before topic creation, deletion, replication factor, config and tag changes")
+	flag.BoolVar(&serverConfig.RequestValidation, "request-validation", true, "Reject requests with invalid fields (e.g. empty topic names) with an InvalidArgument error before processing")
+
+	immutableTags := flag.String("immutable-tags", "", "Comma-delimited list of custom tag keys that can't be modified or deleted once set")
+	inventoryTopicTags := flag.String("inventory-topic-tags", "", "Comma-delimited list of topic tag keys to include as labels on /inventory/metrics topic series")
+	inventoryBrokerTags := flag.String("inventory-broker-tags", "", "Comma-delimited list of broker tag keys (e.g. rack) to include as labels on /inventory/metrics broker series")
+	topicConfigRules := flag.String("topic-config-rules", "", `JSON list of rules mapping a topic tag to recommended topic configs, e.g. [{"tag":"workload:log","configs":{"cleanup.policy":"delete"}}]`)
 
 	kafkaVersionString := flag.String("kafka-version", "v0.10.2", "Kafka release (Semantic Versioning)")
 
@@ -62,6 +75,17 @@ func main() {
 		os.Exit(0)
 	}
 
+	serverConfig.ImmutableTagKeys = tagKeys(*immutableTags)
+	serverConfig.InventoryTopicTags = tagKeys(*inventoryTopicTags)
+	serverConfig.InventoryBrokerTags = tagKeys(*inventoryBrokerTags)
+
+	if *topicConfigRules != "" {
+		if err := json.Unmarshal([]byte(*topicConfigRules), &serverConfig.TopicConfigRules); err != nil {
+			fmt.Printf("Invalid topic config rules: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	_, err := semver.NewVersion(*kafkaVersionString)
 	if err != nil {
 		fmt.Printf("Invalid SemVer: %s\n", *kafkaVersionString)
@@ -125,6 +149,16 @@ func main() {
 		log.Fatal(err)
 	}
 
+	// Start the under-replication monitor background thread.
+	if err := srvr.RunUnderReplicationMonitor(ctx, wg, serverConfig); err != nil {
+		log.Fatal(err)
+	}
+
+	// Start the ZooKeeper write check background thread.
+	if err := srvr.RunZKWriteCheck(ctx, wg, serverConfig); err != nil {
+		log.Fatal(err)
+	}
+
 	// Graceful shutdown on SIGINT.
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
@@ -135,3 +169,16 @@ func main() {
 
 	wg.Wait()
 }
+
+// tagKeys takes a comma-delimited list of tag keys and returns the non-empty
+// keys.
+func tagKeys(s string) []string {
+	var keys []string
+	for _, k := range strings.Split(s, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
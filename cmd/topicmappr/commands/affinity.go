@@ -0,0 +1,113 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+// applyTopicAffinity co-locates the partitions of topics grouped by the
+// --topic-affinity flag, if set. A warning is printed for any partitions
+// that couldn't be co-located.
+func applyTopicAffinity(pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap) {
+	if len(Config.topicAffinity) == 0 {
+		return
+	}
+
+	unaffined := colocatePartitions(pm, bm, Config.topicAffinity)
+
+	if len(unaffined) > 0 {
+		fmt.Printf("\n[WARN] %d partitions couldn't be co-located with their affinity group; placement unchanged:\n",
+			len(unaffined))
+		for _, p := range unaffined {
+			fmt.Printf("%s%s p%d: %v\n", indent, p.Topic, p.Partition, p.Replicas)
+		}
+	}
+}
+
+// colocatePartitions takes a *kafkazk.PartitionMap, a kafkazk.BrokerMap and
+// topic affinity groups. The first topic of each group found in the map is
+// the group's anchor. Partition N of every other topic in the group is
+// assigned the replica set of partition N of the anchor, truncated to the
+// topic's replication factor; the partition keeps its number of observers,
+// which are its last replicas. Partitions are left unchanged and returned if
+// the anchor has no corresponding partition, has a lower replication factor,
+// or if any of the anchor's replicas aren't usable target brokers.
+func colocatePartitions(pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap, groups [][]string) []kafkazk.Partition {
+	var unaffined []kafkazk.Partition
+
+	// Index partitions by topic.
+	byTopic := map[string]map[int]int{}
+	for i, p := range pm.Partitions {
+		if byTopic[p.Topic] == nil {
+			byTopic[p.Topic] = map[int]int{}
+		}
+		byTopic[p.Topic][p.Partition] = i
+	}
+
+	for _, group := range groups {
+		var anchor string
+		for _, t := range group {
+			if _, exists := byTopic[t]; exists {
+				anchor = t
+				break
+			}
+		}
+
+		if anchor == "" {
+			continue
+		}
+
+		for _, t := range group {
+			if t == anchor {
+				continue
+			}
+
+			for i, p := range pm.Partitions {
+				if p.Topic != t {
+					continue
+				}
+
+				j, exists := byTopic[anchor][p.Partition]
+				if !exists {
+					unaffined = append(unaffined, p)
+					continue
+				}
+
+				anchorReplicas := pm.Partitions[j].Replicas
+				if len(anchorReplicas) < len(p.Replicas) {
+					unaffined = append(unaffined, p)
+					continue
+				}
+
+				replicas := append([]int{}, anchorReplicas[:len(p.Replicas)]...)
+				if !usableBrokers(replicas, bm) {
+					unaffined = append(unaffined, p)
+					continue
+				}
+
+				var observers []int
+				if n := len(p.Observers); n > 0 {
+					observers = append(observers, replicas[len(replicas)-n:]...)
+				}
+
+				pm.Partitions[i].Replicas = replicas
+				pm.Partitions[i].Observers = observers
+			}
+		}
+	}
+
+	return unaffined
+}
+
+// usableBrokers returns whether all IDs are brokers in the kafkazk.BrokerMap
+// that aren't marked for replacement.
+func usableBrokers(ids []int, bm kafkazk.BrokerMap) bool {
+	for _, id := range ids {
+		if b, exists := bm[id]; !exists || b.Replace {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestColocatePartitions(t *testing.T) {
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, Locality: "a"},
+		1002: &kafkazk.Broker{ID: 1002, Locality: "b"},
+		1003: &kafkazk.Broker{ID: 1003, Locality: "c"},
+		1004: &kafkazk.Broker{ID: 1004, Locality: "a"},
+		1005: &kafkazk.Broker{ID: 1005, Locality: "b", Replace: true},
+	}
+
+	pm := kafkazk.NewPartitionMap()
+	pm.Partitions = []kafkazk.Partition{
+		{Topic: "stream", Partition: 0, Replicas: []int{1001, 1002}},
+		{Topic: "stream", Partition: 1, Replicas: []int{1003, 1004}},
+		{Topic: "stream", Partition: 2, Replicas: []int{1005, 1003}},
+		{Topic: "changelog", Partition: 0, Replicas: []int{1003, 1004}},
+		{Topic: "changelog", Partition: 1, Replicas: []int{1002, 1001, 1003}},
+		{Topic: "changelog", Partition: 2, Replicas: []int{1001, 1002}},
+		{Topic: "changelog", Partition: 3, Replicas: []int{1004, 1002}},
+		{Topic: "other", Partition: 0, Replicas: []int{1002, 1003}},
+	}
+
+	unaffined := colocatePartitions(pm, bm, [][]string{{"stream", "changelog"}})
+
+	expected := map[string][][]int{
+		"stream":    {{1001, 1002}, {1003, 1004}, {1005, 1003}},
+		"changelog": {{1001, 1002}, {1002, 1001, 1003}, {1001, 1002}, {1004, 1002}},
+		"other":     {{1002, 1003}},
+	}
+
+	for _, p := range pm.Partitions {
+		if !replicasEqual(p.Replicas, expected[p.Topic][p.Partition]) {
+			t.Errorf("%s p%d: expected replicas %v, got %v",
+				p.Topic, p.Partition, expected[p.Topic][p.Partition], p.Replicas)
+		}
+	}
+
+	// changelog p1 has a higher replication factor than stream p1, p2 would
+	// be placed on a replaced broker and p3 has no corresponding partition.
+	if len(unaffined) != 3 {
+		t.Fatalf("Expected 3 unaffined partitions, got %d", len(unaffined))
+	}
+
+	for i, n := range []int{1, 2, 3} {
+		if unaffined[i].Topic != "changelog" || unaffined[i].Partition != n {
+			t.Errorf("Expected changelog p%d to be unaffined, got %s p%d",
+				n, unaffined[i].Topic, unaffined[i].Partition)
+		}
+	}
+}
+
+func TestColocatePartitionsObservers(t *testing.T) {
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, Locality: "a"},
+		1002: &kafkazk.Broker{ID: 1002, Locality: "b"},
+		1003: &kafkazk.Broker{ID: 1003, Locality: "c"},
+		1004: &kafkazk.Broker{ID: 1004, Locality: "a"},
+	}
+
+	pm := kafkazk.NewPartitionMap()
+	pm.Partitions = []kafkazk.Partition{
+		{Topic: "stream", Partition: 0, Replicas: []int{1001, 1002, 1003}, Observers: []int{1003}},
+		{Topic: "stream", Partition: 1, Replicas: []int{1002, 1003, 1001}},
+		{Topic: "changelog", Partition: 0, Replicas: []int{1004, 1002}},
+		{Topic: "changelog", Partition: 1, Replicas: []int{1004, 1002, 1003}, Observers: []int{1003}},
+	}
+
+	if unaffined := colocatePartitions(pm, bm, [][]string{{"stream", "changelog"}}); len(unaffined) != 0 {
+		t.Fatalf("Expected no unaffined partitions, got %v", unaffined)
+	}
+
+	// Partitions keep their number of observers, which are their last
+	// replicas.
+	expected := []kafkazk.Partition{
+		{Topic: "changelog", Partition: 0, Replicas: []int{1001, 1002}},
+		{Topic: "changelog", Partition: 1, Replicas: []int{1002, 1003, 1001}, Observers: []int{1001}},
+	}
+
+	for i, p := range pm.Partitions[2:] {
+		if !p.Equal(expected[i]) {
+			t.Errorf("changelog p%d: expected %v, got %v", p.Partition, expected[i], p)
+		}
+	}
+}
+
+func TestTopicAffinityFromString(t *testing.T) {
+	g, err := topicAffinityFromString("stream:stream-changelog, a:b:c")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(g) != 2 || len(g[0]) != 2 || len(g[1]) != 3 || g[0][1] != "stream-changelog" {
+		t.Errorf("Unexpected groups %v", g)
+	}
+
+	for _, s := range []string{"a", "a:", "a:b,b:c", "a:b,"} {
+		if _, err := topicAffinityFromString(s); err == nil {
+			t.Errorf("[%s] Expected non-nil error", s)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// applyState tracks the progress of a phased apply. Original holds the
+// replica assignments prior to the first batch.
+type applyState struct {
+	Original  *kafkazk.PartitionMap   `json:"original"`
+	Batches   []*kafkazk.PartitionMap `json:"batches"`
+	Completed int                     `json:"completed"`
+}
+
+// outputMaps takes the original and final output PartitionMap and an optional
+// phased map and returns the ordered output maps, as written by writeMaps or
+// writeLeaderMoveBatches.
+func outputMaps(cmd *cobra.Command, pm1, pm2, phasedPM *kafkazk.PartitionMap) []*kafkazk.PartitionMap {
+	if n, _ := cmd.Flags().GetInt("max-concurrent-leader-moves"); n > 0 {
+		return leaderMoveBatches(pm1, pm2, n)
+	}
+
+	if phasedPM != nil {
+		return []*kafkazk.PartitionMap{phasedPM, pm2}
+	}
+
+	return []*kafkazk.PartitionMap{pm2}
+}
+
+// applyPhased applies the output maps to the cluster in batches if
+// --phased-apply is set. If --apply-state references an existing state file,
+// the apply it describes is resumed in place of the provided maps. Otherwise,
+// a new apply of the provided maps is started and, if --apply-state is set,
+// tracked in the state file.
+func applyPhased(cmd *cobra.Command, zk kafkazk.Handler, pm1 *kafkazk.PartitionMap, maps []*kafkazk.PartitionMap) {
+	if pa, _ := cmd.Flags().GetBool("phased-apply"); !pa {
+		return
+	}
+
+	scopePath := cmd.Flag("publish-scope").Value.String()
+	statePath := cmd.Flag("apply-state").Value.String()
+	size, _ := cmd.Flags().GetInt("apply-batch-size")
+
+	switch {
+	case zk == nil:
+		fmt.Println("\n[ERROR] --phased-apply requires ZooKeeper")
+		os.Exit(1)
+	case scopePath == "":
+		fmt.Println("\n[ERROR] --phased-apply requires --publish-scope")
+		os.Exit(1)
+	}
+
+	state, err := loadApplyState(statePath)
+	if err != nil {
+		fmt.Printf("\n[ERROR] failed to load apply state: %s\n", err)
+		os.Exit(1)
+	}
+
+	if state != nil {
+		fmt.Printf("\nResuming phased apply from %s at batch %d of %d\n",
+			statePath, state.Completed+1, len(state.Batches))
+	} else {
+		state = &applyState{
+			Original: pm1,
+			Batches:  applyBatches(pm1, maps, size),
+		}
+	}
+
+	save := func(s *applyState) error {
+		if statePath == "" {
+			return nil
+		}
+		return saveApplyState(statePath, s)
+	}
+
+	if err := save(state); err != nil {
+		fmt.Printf("\n[ERROR] failed to save apply state: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := phasedApply(context.Background(), zk, scopePath, state, save); err != nil {
+		fmt.Printf("\n[ERROR] phased apply failed: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// applyBatches takes the original PartitionMap, the ordered output maps and a
+// maximum number of partition moves per batch and returns the batches to
+// apply. Each map contributes the partitions that differ from the state left
+// by the preceding maps, split into batches of at most size partitions. A
+// size of 0 applies each map as a single batch.
+func applyBatches(pm1 *kafkazk.PartitionMap, maps []*kafkazk.PartitionMap, size int) []*kafkazk.PartitionMap {
+	current := partitionIndex(pm1)
+
+	var batches []*kafkazk.PartitionMap
+
+	for _, m := range maps {
+		var changed kafkazk.PartitionList
+		for _, p := range m.Partitions {
+			if prev, exists := current[p.Topic][p.Partition]; !exists || !prev.Equal(p) {
+				changed = append(changed, p)
+			}
+		}
+
+		for len(changed) > 0 {
+			n := len(changed)
+			if size > 0 && size < n {
+				n = size
+			}
+
+			batch := kafkazk.NewPartitionMap()
+			batch.Partitions = append(kafkazk.PartitionList{}, changed[:n]...)
+			batches = append(batches, batch)
+
+			changed = changed[n:]
+		}
+
+		for _, p := range m.Partitions {
+			if current[p.Topic] == nil {
+				current[p.Topic] = map[int]kafkazk.Partition{}
+			}
+			current[p.Topic][p.Partition] = p
+		}
+	}
+
+	return batches
+}
+
+// phasedApply applies each batch of the applyState not yet completed, in
+// order. The reassignment scope of each batch is published to the scopePath
+// znode before it's submitted so that autothrottle targets its throttles at
+// the batch participants. Each batch must complete before the next is
+// submitted; the state is saved with save as each batch completes. Any
+// reassignment in progress, e.g. from an interrupted apply, is waited on
+// before the first batch.
+func phasedApply(ctx context.Context, zk kafkazk.Handler, scopePath string, state *applyState, save func(*applyState) error) error {
+	if state.Completed >= len(state.Batches) {
+		fmt.Println("\nPhased apply complete; no batches remaining")
+		return nil
+	}
+
+	// Replay the completed batches to get the current assignments.
+	current := partitionIndex(state.Original)
+	for _, b := range state.Batches[:state.Completed] {
+		for _, p := range b.Partitions {
+			if current[p.Topic] == nil {
+				current[p.Topic] = map[int]kafkazk.Partition{}
+			}
+			current[p.Topic][p.Partition] = p
+		}
+	}
+
+	if err := zk.WaitReassignmentComplete(ctx); err != nil {
+		return err
+	}
+
+	for i := state.Completed; i < len(state.Batches); i++ {
+		batch := state.Batches[i]
+
+		fmt.Printf("\nApplying batch %d of %d (%d partitions)\n", i+1, len(state.Batches), len(batch.Partitions))
+
+		before := kafkazk.NewPartitionMap()
+		for _, p := range batch.Partitions {
+			if prev, exists := current[p.Topic][p.Partition]; exists {
+				before.Partitions = append(before.Partitions, prev)
+			}
+		}
+
+		// Set throttles.
+		scope := kafkazk.NewReassignmentScope(before, batch)
+		if err := kafkazk.SetReassignmentScope(zk, scopePath, scope); err != nil {
+			return fmt.Errorf("batch %d: failed to publish reassignment scope: %s", i+1, err)
+		}
+
+		fmt.Printf("%sscope published: topics %v, brokers %v\n", indent, scope.Topics, scope.Brokers)
+
+		if err := zk.SubmitReassignment(batch); err != nil {
+			return fmt.Errorf("batch %d: %s", i+1, err)
+		}
+
+		fmt.Printf("%ssubmitted, waiting for completion\n", indent)
+
+		if err := zk.WaitReassignmentComplete(ctx); err != nil {
+			return fmt.Errorf("batch %d: %s", i+1, err)
+		}
+
+		fmt.Printf("%scomplete\n", indent)
+
+		for _, p := range batch.Partitions {
+			if current[p.Topic] == nil {
+				current[p.Topic] = map[int]kafkazk.Partition{}
+			}
+			current[p.Topic][p.Partition] = p
+		}
+
+		state.Completed = i + 1
+		if err := save(state); err != nil {
+			return fmt.Errorf("failed to save apply state: %s", err)
+		}
+	}
+
+	fmt.Println("\nPhased apply complete")
+
+	return nil
+}
+
+// partitionIndex returns a mapping of topic name to partition number to
+// kafkazk.Partition for the partitions in the PartitionMap.
+func partitionIndex(pm *kafkazk.PartitionMap) map[string]map[int]kafkazk.Partition {
+	idx := map[string]map[int]kafkazk.Partition{}
+	for _, p := range pm.Partitions {
+		if idx[p.Topic] == nil {
+			idx[p.Topic] = map[int]kafkazk.Partition{}
+		}
+		idx[p.Topic][p.Partition] = p
+	}
+
+	return idx
+}
+
+// loadApplyState returns the *applyState stored at the path. A nil
+// *applyState is returned if the path is empty or doesn't exist.
+func loadApplyState(path string) (*applyState, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	d, err := ioutil.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	s := &applyState{}
+	if err := json.Unmarshal(d, s); err != nil {
+		return nil, err
+	}
+
+	if s.Original == nil {
+		return nil, fmt.Errorf("%s: missing original partition map", path)
+	}
+
+	return s, nil
+}
+
+// saveApplyState writes the *applyState to the path.
+func saveApplyState(path string, s *applyState) error {
+	d, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, d, 0644)
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+// fakeCluster records reassignment scope publishes, reassignment submissions
+// and completions. Submitted reassignments complete when waited on.
+type fakeCluster struct {
+	kafkazk.Handler
+	events   []string
+	inFlight *kafkazk.PartitionMap
+}
+
+func (f *fakeCluster) Exists(string) (bool, error) { return false, nil }
+
+func (f *fakeCluster) Create(p, d string) error {
+	f.events = append(f.events, "throttle")
+	return nil
+}
+
+func (f *fakeCluster) SubmitReassignment(pm *kafkazk.PartitionMap) error {
+	if f.inFlight != nil {
+		return kafkazk.ErrReassignmentInProgress
+	}
+
+	f.inFlight = pm
+	f.events = append(f.events, fmt.Sprintf("submit %s p%d", pm.Partitions[0].Topic, pm.Partitions[0].Partition))
+
+	return nil
+}
+
+func (f *fakeCluster) WaitReassignmentComplete(context.Context) error {
+	if f.inFlight != nil {
+		f.events = append(f.events, "complete")
+		f.inFlight = nil
+	}
+
+	return nil
+}
+
+func TestPhasedApply(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1002]},
+		{"topic":"test","partition":1,"replicas":[1002,1001]},
+		{"topic":"test","partition":2,"replicas":[1001,1002]}]}`)
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1003,1002]},
+		{"topic":"test","partition":1,"replicas":[1002,1001]},
+		{"topic":"test","partition":2,"replicas":[1001,1004]}]}`)
+
+	// p1 is a no-op; p0 and p2 are applied as two batches.
+	batches := applyBatches(pm1, []*kafkazk.PartitionMap{pm2}, 1)
+	if len(batches) != 2 {
+		t.Fatalf("Expected 2 batches, got %d", len(batches))
+	}
+
+	state := &applyState{Original: pm1, Batches: batches}
+
+	var saved []int
+	save := func(s *applyState) error {
+		saved = append(saved, s.Completed)
+		return nil
+	}
+
+	zk := &fakeCluster{}
+	if err := phasedApply(context.Background(), zk, "/autothrottle/reassignment_scope", state, save); err != nil {
+		t.Fatal(err)
+	}
+
+	// Throttles are set before each batch and batch 2 is only submitted
+	// after batch 1 completes.
+	expected := []string{
+		"throttle", "submit test p0", "complete",
+		"throttle", "submit test p2", "complete",
+	}
+
+	if len(zk.events) != len(expected) {
+		t.Fatalf("Expected events %v, got %v", expected, zk.events)
+	}
+
+	for i := range expected {
+		if zk.events[i] != expected[i] {
+			t.Errorf("Expected event %d '%s', got '%s'", i, expected[i], zk.events[i])
+		}
+	}
+
+	if len(saved) != 2 || saved[0] != 1 || saved[1] != 2 {
+		t.Errorf("Expected state saved after each batch, got %v", saved)
+	}
+
+	// A resumed apply only applies the remaining batches.
+	state.Completed = 1
+	zk = &fakeCluster{}
+	if err := phasedApply(context.Background(), zk, "/autothrottle/reassignment_scope", state, save); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(zk.events) != 3 || zk.events[1] != "submit test p2" {
+		t.Errorf("Expected only batch 2 to be applied, got %v", zk.events)
+	}
+}
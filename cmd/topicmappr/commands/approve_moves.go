@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// errReviewIncomplete is returned when input ends before all partition
+// moves have been reviewed.
+var errReviewIncomplete = fmt.Errorf("input ended before all partition moves were reviewed")
+
+// reviewPlan takes the original and proposed PartitionMap and an optional
+// PartitionMetaMap for move size estimates. If --approve-moves is set, the
+// user is prompted to approve or reject each partition move and a copy of the
+// proposed map with any rejected moves reverted is returned. Otherwise, the
+// proposed map is returned as is. Placement isn't re-run for the approved
+// moves, so rejecting moves can leave the map unbalanced or in violation of
+// placement constraints.
+func reviewPlan(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap, pmm kafkazk.PartitionMetaMap) *kafkazk.PartitionMap {
+	if a, _ := cmd.Flags().GetBool("approve-moves"); !a {
+		return pm2
+	}
+
+	fmt.Println("\nReview partition map changes:")
+	fmt.Printf("%s[y] approve, [n] reject, [a] approve all remaining, [r] reject all remaining\n", indent)
+
+	approved, err := promptMoveApprovals(os.Stdin, os.Stdout, pm1, pm2)
+	if err != nil {
+		fmt.Printf("\n[ERROR] %s\n", err)
+		os.Exit(1)
+	}
+
+	pmApproved := approvedMoves(pm1, pm2, approved)
+
+	var n, total int
+	for i := range pm1.Partitions {
+		if !pm1.Partitions[i].Equal(pm2.Partitions[i]) {
+			total++
+			if approved[i] {
+				n++
+			}
+		}
+	}
+
+	fmt.Printf("\n%d of %d partition moves approved\n", n, total)
+
+	if n < total {
+		fmt.Printf("%s[WARN] placement isn't re-run for the approved moves; the resulting map may be unbalanced or violate placement constraints\n", indent)
+	}
+
+	printMapChanges(pm1, pmApproved, pmm)
+
+	return pmApproved
+}
+
+// promptMoveApprovals takes an io.Reader of user responses, an io.Writer for
+// prompts and the original and proposed PartitionMap. The user is prompted
+// for each partition that differs between the maps. A []bool indicating
+// whether each partition (by index) is approved is returned. Partitions that
+// are unchanged are always approved.
+func promptMoveApprovals(r io.Reader, w io.Writer, pm1, pm2 *kafkazk.PartitionMap) ([]bool, error) {
+	approved := make([]bool, len(pm1.Partitions))
+	in := bufio.NewScanner(r)
+
+	// A response of 'a' or 'r' applies to all remaining moves.
+	var remaining string
+
+	for i := range pm1.Partitions {
+		p1, p2 := pm1.Partitions[i], pm2.Partitions[i]
+		if p1.Equal(p2) {
+			approved[i] = true
+			continue
+		}
+
+		if remaining != "" {
+			approved[i] = remaining == "a"
+			continue
+		}
+
+		for {
+			fmt.Fprintf(w, "%s%s p%d: %v -> %v %s [y/n/a/r]: ",
+				indent, p1.Topic, p1.Partition, p1.Replicas, p2.Replicas,
+				whatChanged(p1.Replicas, p2.Replicas))
+
+			if !in.Scan() {
+				if err := in.Err(); err != nil {
+					return nil, err
+				}
+				return nil, errReviewIncomplete
+			}
+
+			resp := strings.ToLower(strings.TrimSpace(in.Text()))
+			switch resp {
+			case "y", "n":
+				approved[i] = resp == "y"
+			case "a", "r":
+				approved[i] = resp == "a"
+				remaining = resp
+			default:
+				continue
+			}
+
+			break
+		}
+	}
+
+	return approved, nil
+}
+
+// approvedMoves takes the original and proposed PartitionMap and a []bool
+// of approvals by partition index. A copy of the proposed map is returned
+// where every partition that isn't approved retains its original replica
+// and observer assignment.
+func approvedMoves(pm1, pm2 *kafkazk.PartitionMap, approved []bool) *kafkazk.PartitionMap {
+	pm := pm2.Copy()
+
+	for i := range pm.Partitions {
+		if i < len(approved) && approved[i] {
+			continue
+		}
+
+		pm.Partitions[i].Replicas = append([]int{}, pm1.Partitions[i].Replicas...)
+		pm.Partitions[i].Observers = append([]int(nil), pm1.Partitions[i].Observers...)
+	}
+
+	return pm
+}
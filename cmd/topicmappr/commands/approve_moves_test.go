@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func testReviewMaps() (*kafkazk.PartitionMap, *kafkazk.PartitionMap) {
+	pm1 := kafkazk.NewPartitionMap()
+	pm1.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002}},
+		{Topic: "test", Partition: 1, Replicas: []int{1002, 1003}},
+		{Topic: "test", Partition: 2, Replicas: []int{1003, 1001}},
+		{Topic: "test", Partition: 3, Replicas: []int{1001, 1003}},
+	}
+
+	pm2 := pm1.Copy()
+	// p1 is unchanged.
+	pm2.Partitions[0].Replicas = []int{1004, 1002}
+	pm2.Partitions[2].Replicas = []int{1003, 1004}
+	pm2.Partitions[3].Replicas = []int{1004, 1003}
+
+	return pm1, pm2
+}
+
+func TestPromptMoveApprovals(t *testing.T) {
+	pm1, pm2 := testReviewMaps()
+
+	// Invalid responses are re-prompted; no prompt is issued for p1.
+	in := strings.NewReader("y\nx\nn\ny\n")
+	approved, err := promptMoveApprovals(in, ioutil.Discard, pm1, pm2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []bool{true, true, false, true}
+	for i := range expected {
+		if approved[i] != expected[i] {
+			t.Errorf("p%d: expected approval %v, got %v", i, expected[i], approved[i])
+		}
+	}
+
+	// Reject all remaining.
+	approved, err = promptMoveApprovals(strings.NewReader("y\nr\n"), ioutil.Discard, pm1, pm2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected = []bool{true, true, false, false}
+	for i := range expected {
+		if approved[i] != expected[i] {
+			t.Errorf("p%d: expected approval %v, got %v", i, expected[i], approved[i])
+		}
+	}
+
+	// Input ends before the review completes.
+	_, err = promptMoveApprovals(strings.NewReader("y\n"), ioutil.Discard, pm1, pm2)
+	if err != errReviewIncomplete {
+		t.Errorf("Expected error '%s', got '%v'", errReviewIncomplete, err)
+	}
+}
+
+func TestApprovedMoves(t *testing.T) {
+	pm1, pm2 := testReviewMaps()
+
+	// Approve the p0 and p3 moves, reject p2.
+	approved := []bool{true, true, false, true}
+	pm := approvedMoves(pm1, pm2, approved)
+
+	expected := [][]int{
+		{1004, 1002},
+		{1002, 1003},
+		{1003, 1001},
+		{1004, 1003},
+	}
+
+	for i, p := range pm.Partitions {
+		if !replicasEqual(p.Replicas, expected[i]) {
+			t.Errorf("p%d: expected replicas %v, got %v", p.Partition, expected[i], p.Replicas)
+		}
+	}
+
+	// Only approved moves remain once no-ops are skipped.
+	_, out := skipReassignmentNoOps(pm1, pm)
+	if len(out.Partitions) != 2 {
+		t.Fatalf("Expected 2 partition moves, got %d", len(out.Partitions))
+	}
+
+	if out.Partitions[0].Partition != 0 || out.Partitions[1].Partition != 3 {
+		t.Errorf("Expected moves for p0 and p3, got %v", out.Partitions)
+	}
+
+	// The proposed map is unmodified.
+	if !replicasEqual(pm2.Partitions[2].Replicas, []int{1003, 1004}) {
+		t.Errorf("Unexpected modification of proposed map: %v", pm2.Partitions[2].Replicas)
+	}
+}
+
+func TestApprovedMovesObservers(t *testing.T) {
+	pm1 := kafkazk.NewPartitionMap()
+	pm1.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002, 1003}, Observers: []int{1003}},
+		{Topic: "test", Partition: 1, Replicas: []int{1002, 1003}},
+	}
+
+	pm2 := kafkazk.NewPartitionMap()
+	pm2.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002, 1004}, Observers: []int{1004}},
+		{Topic: "test", Partition: 1, Replicas: []int{1002, 1003, 1004}, Observers: []int{1004}},
+	}
+
+	// Reject both moves.
+	pm := approvedMoves(pm1, pm2, []bool{false, false})
+
+	for i, p := range pm.Partitions {
+		if !p.Equal(pm1.Partitions[i]) {
+			t.Errorf("p%d: expected %v, got %v", i, pm1.Partitions[i], p)
+		}
+	}
+
+	// The rejected moves don't change observers.
+	_, out := skipReassignmentNoOps(pm1, pm)
+	if len(out.Partitions) != 0 {
+		t.Errorf("Expected no partition moves, got %v", out.Partitions)
+	}
+}
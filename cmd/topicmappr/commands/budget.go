@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// mb is the number of bytes per megabyte used for replication rates.
+const mb = 1 << 20
+
+// planEstimate holds the estimated replication for a plan.
+type planEstimate struct {
+	moves    int
+	bytes    float64
+	duration time.Duration
+}
+
+// applyTimeBudget takes the original and proposed PartitionMap, the original
+// BrokerMap and a PartitionMetaMap. If --replication-rate is set, the
+// estimated replication duration is printed. If --time-budget is also set
+// and the plan exceeds it, a copy of the proposed map trimmed to fit the
+// budget is returned. Otherwise, the proposed map is returned as is.
+func applyTimeBudget(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap, bm kafkazk.BrokerMap, pmm kafkazk.PartitionMetaMap) *kafkazk.PartitionMap {
+	rate, _ := cmd.Flags().GetFloat64("replication-rate")
+	budget, _ := cmd.Flags().GetDuration("time-budget")
+
+	switch {
+	case rate <= 0 && budget > 0:
+		fmt.Println("\n[ERROR] --time-budget requires --replication-rate")
+		os.Exit(1)
+	case rate <= 0:
+		return pm2
+	}
+
+	trimmed, full, retained, err := trimToTimeBudget(pm1, pm2, bm, pmm, rate*mb, budget)
+	if err != nil {
+		fmt.Printf("\n[ERROR] failed to estimate replication: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\nEstimated replication:")
+	fmt.Printf("%sFull plan: %d partition moves, %.2fGB, %s at %.2fMB/s\n",
+		indent, full.moves, full.bytes/div, full.duration, rate)
+
+	if budget <= 0 || full.duration <= budget {
+		return pm2
+	}
+
+	fmt.Printf("%sTime budget of %s: %d of %d partition moves retained, %.2fGB, %s\n",
+		indent, budget, retained.moves, full.moves, retained.bytes/div, retained.duration)
+
+	printMapChanges(pm1, trimmed, pmm)
+
+	return trimmed
+}
+
+// trimToTimeBudget takes the original and proposed PartitionMap, the original
+// BrokerMap, a PartitionMetaMap, a replication rate in bytes per second and
+// a time budget. The bytes replicated by each partition move are its size
+// multiplied by the number of replicas being added. Moves are visited in
+// descending movePriorities order and are retained if the cumulative
+// estimated duration fits the budget; all other moves are reverted to their
+// original replica and observer assignment. A budget of 0 retains all moves.
+// The trimmed map along with the estimates for the full and trimmed plans are
+// returned.
+func trimToTimeBudget(pm1, pm2 *kafkazk.PartitionMap, bm kafkazk.BrokerMap, pmm kafkazk.PartitionMetaMap, rate float64, budget time.Duration) (*kafkazk.PartitionMap, planEstimate, planEstimate, error) {
+	var full, retained planEstimate
+
+	if pmm == nil {
+		return nil, full, retained, fmt.Errorf("partition metrics are required")
+	}
+
+	duration := func(bytes float64) time.Duration {
+		return time.Duration(bytes / rate * float64(time.Second)).Round(time.Second)
+	}
+
+	// Index the original map; the maps may not share an ordering.
+	original := map[string]map[int]kafkazk.Partition{}
+	for _, p := range pm1.Partitions {
+		if original[p.Topic] == nil {
+			original[p.Topic] = map[int]kafkazk.Partition{}
+		}
+		original[p.Topic][p.Partition] = p
+	}
+
+	reverted := map[string]map[int]bool{}
+
+	for _, m := range movePriorities(pm1, pm2, bm, pmm) {
+		size, err := pmm.Size(original[m.Topic][m.Partition])
+		if err != nil {
+			return nil, full, retained, fmt.Errorf("%s p%d: %s", m.Topic, m.Partition, err)
+		}
+
+		bytes := size * float64(len(m.To))
+
+		full.moves++
+		full.bytes += bytes
+
+		if budget > 0 && duration(retained.bytes+bytes) > budget {
+			if reverted[m.Topic] == nil {
+				reverted[m.Topic] = map[int]bool{}
+			}
+			reverted[m.Topic][m.Partition] = true
+			continue
+		}
+
+		retained.moves++
+		retained.bytes += bytes
+	}
+
+	full.duration = duration(full.bytes)
+	retained.duration = duration(retained.bytes)
+
+	trimmed := pm2.Copy()
+	for i, p := range trimmed.Partitions {
+		if reverted[p.Topic][p.Partition] {
+			orig := original[p.Topic][p.Partition]
+			trimmed.Partitions[i].Replicas = append([]int{}, orig.Replicas...)
+			trimmed.Partitions[i].Observers = append([]int(nil), orig.Observers...)
+		}
+	}
+
+	return trimmed, full, retained, nil
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"testing"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestTrimToTimeBudget(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1002]},
+		{"topic":"test","partition":1,"replicas":[1003,1002]},
+		{"topic":"test","partition":2,"replicas":[1004,1002]}]}`)
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1005,1002]},
+		{"topic":"test","partition":1,"replicas":[1005,1002]},
+		{"topic":"test","partition":2,"replicas":[1005,1002]}]}`)
+
+	// 1001 is the fullest broker, 1003 is near-empty and 1004 is missing;
+	// the move priorities are p2, p0 then p1.
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, StorageFree: 10 * div},
+		1002: &kafkazk.Broker{ID: 1002, StorageFree: 50 * div},
+		1003: &kafkazk.Broker{ID: 1003, StorageFree: 95 * div},
+		1004: &kafkazk.Broker{ID: 1004, Missing: true},
+		1005: &kafkazk.Broker{ID: 1005, StorageFree: 100 * div},
+	}
+
+	// Each move replicates 10GB; at 10MB/s that's 1024s per move.
+	pmm := kafkazk.PartitionMetaMap{
+		"test": {
+			0: &kafkazk.PartitionMeta{Size: 10 * div},
+			1: &kafkazk.PartitionMeta{Size: 10 * div},
+			2: &kafkazk.PartitionMeta{Size: 10 * div},
+		},
+	}
+
+	rate := float64(10 * mb)
+	budget := 40 * time.Minute
+
+	trimmed, full, retained, err := trimToTimeBudget(pm1, pm2, bm, pmm, rate, budget)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if full.moves != 3 || full.duration != 3072*time.Second {
+		t.Errorf("Expected a full plan of 3 moves over 3072s, got %d over %s", full.moves, full.duration)
+	}
+
+	if full.duration <= budget {
+		t.Fatalf("Expected the full plan to exceed the budget")
+	}
+
+	if retained.moves != 2 || retained.duration > budget {
+		t.Errorf("Expected 2 moves within the budget, got %d over %s", retained.moves, retained.duration)
+	}
+
+	// The highest priority moves, p2 and p0, are retained while p1 is
+	// reverted.
+	expected := [][]int{{1005, 1002}, {1003, 1002}, {1005, 1002}}
+	for i, p := range trimmed.Partitions {
+		if !p.Equal(kafkazk.Partition{Topic: "test", Partition: i, Replicas: expected[i]}) {
+			t.Errorf("Expected p%d replicas %v, got %v", i, expected[i], p.Replicas)
+		}
+	}
+
+	// The proposed map isn't modified.
+	if pm2.Partitions[1].Replicas[0] != 1005 {
+		t.Error("Unexpected modification of the proposed map")
+	}
+
+	// No budget retains all moves.
+	_, _, retained, _ = trimToTimeBudget(pm1, pm2, bm, pmm, rate, 0)
+	if retained.moves != 3 {
+		t.Errorf("Expected 3 moves retained, got %d", retained.moves)
+	}
+}
+
+func TestTrimToTimeBudgetObservers(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1002,1003],"observers":[1003]},
+		{"topic":"test","partition":1,"replicas":[1002,1003]}]}`)
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1002,1004],"observers":[1004]},
+		{"topic":"test","partition":1,"replicas":[1002,1003,1004],"observers":[1004]}]}`)
+
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, StorageFree: 50 * div},
+		1002: &kafkazk.Broker{ID: 1002, StorageFree: 50 * div},
+		1003: &kafkazk.Broker{ID: 1003, StorageFree: 50 * div},
+		1004: &kafkazk.Broker{ID: 1004, StorageFree: 100 * div},
+	}
+
+	pmm := kafkazk.PartitionMetaMap{
+		"test": {
+			0: &kafkazk.PartitionMeta{Size: 10 * div},
+			1: &kafkazk.PartitionMeta{Size: 10 * div},
+		},
+	}
+
+	// A budget shorter than any single move reverts all moves.
+	trimmed, _, retained, err := trimToTimeBudget(pm1, pm2, bm, pmm, float64(10*mb), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if retained.moves != 0 {
+		t.Errorf("Expected no moves retained, got %d", retained.moves)
+	}
+
+	for i, p := range trimmed.Partitions {
+		if !p.Equal(pm1.Partitions[i]) {
+			t.Errorf("p%d: expected %v, got %v", i, pm1.Partitions[i], p)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var capacityReportCmd = &cobra.Command{
+	Use:   "capacity-report",
+	Short: "Report cluster storage, partition and leadership capacity",
+	Long: `Report per-broker and per-rack partition counts, leadership counts and storage
+capacity. Storage used is the sum of the sizes of all replicas held by a broker for the
+reported topics; storage total is storage used plus storage free. Headroom is the
+percentage of storage total that is free.`,
+	Run: capacityReport,
+}
+
+func init() {
+	rootCmd.AddCommand(capacityReportCmd)
+
+	capacityReportCmd.Flags().String("topics", ".*", "Report topics (comma delim. list) by lookup in ZooKeeper")
+	capacityReportCmd.Flags().String("topics-exclude", "", "Exclude topics")
+	capacityReportCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics")
+	capacityReportCmd.Flags().Int("metrics-age", 60, "Kafka metrics age tolerance (in minutes)")
+	capacityReportCmd.Flags().String("report-out", "", "If defined, write the capacity report as JSON to this file")
+}
+
+// brokerCapacity is the capacity of a single broker. Storage values are
+// in bytes.
+type brokerCapacity struct {
+	ID           int     `json:"id"`
+	Rack         string  `json:"rack"`
+	Partitions   int     `json:"partitions"`
+	Leaders      int     `json:"leaders"`
+	StorageUsed  float64 `json:"storage_used"`
+	StorageFree  float64 `json:"storage_free"`
+	StorageTotal float64 `json:"storage_total"`
+	Headroom     float64 `json:"headroom_percent"`
+}
+
+// rackCapacity is the aggregate capacity of all brokers in a rack.
+type rackCapacity struct {
+	Rack         string  `json:"rack"`
+	Brokers      int     `json:"brokers"`
+	Partitions   int     `json:"partitions"`
+	Leaders      int     `json:"leaders"`
+	StorageUsed  float64 `json:"storage_used"`
+	StorageFree  float64 `json:"storage_free"`
+	StorageTotal float64 `json:"storage_total"`
+	Headroom     float64 `json:"headroom_percent"`
+}
+
+// clusterCapacity is a cluster capacity report. Totals aggregates all
+// brokers with the rack field left empty.
+type clusterCapacity struct {
+	Brokers []brokerCapacity `json:"brokers"`
+	Racks   []rackCapacity   `json:"racks"`
+	Totals  rackCapacity     `json:"totals"`
+	// Partitions in the report without size metadata.
+	MissingSizes []string `json:"missing_sizes,omitempty"`
+}
+
+func capacityReport(cmd *cobra.Command, _ []string) {
+	Config.topics = topicRegex(cmd.Flag("topics").Value.String())
+	if exclude := cmd.Flag("topics-exclude").Value.String(); exclude != "" {
+		Config.topicsExclude = topicRegex(exclude)
+	}
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	checkMetaAge(cmd, zk)
+	brokerMeta := getBrokerMeta(cmd, zk, true)
+	partitionMeta := getPartitionMeta(cmd, zk)
+
+	partitionMap, err := kafkazk.PartitionMapFromZK(Config.topics, zk)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// Exclude any explicit exclusions.
+	excluded := removeTopics(partitionMap, Config.topicsExclude)
+	printExcludedTopics(nil, excluded)
+
+	report := buildCapacityReport(partitionMap, brokerMeta, partitionMeta)
+
+	printCapacityReport(report, brokerMeta)
+	writeCapacityReport(cmd, report)
+}
+
+// buildCapacityReport takes a *kafkazk.PartitionMap, the kafkazk.BrokerMetaMap
+// of registered brokers and a kafkazk.PartitionMetaMap and returns a
+// clusterCapacity. Only registered brokers are reported.
+func buildCapacityReport(pm *kafkazk.PartitionMap, bmm kafkazk.BrokerMetaMap, pmm kafkazk.PartitionMetaMap) clusterCapacity {
+	var report clusterCapacity
+
+	brokers := map[int]*brokerCapacity{}
+	for id, meta := range bmm {
+		brokers[id] = &brokerCapacity{
+			ID:          id,
+			Rack:        meta.Rack,
+			StorageFree: meta.StorageFree,
+		}
+	}
+
+	for _, p := range pm.Partitions {
+		size, err := pmm.Size(p)
+		if err != nil {
+			report.MissingSizes = append(report.MissingSizes, fmt.Sprintf("%s p%d", p.Topic, p.Partition))
+		}
+
+		for i, id := range p.Replicas {
+			b, exists := brokers[id]
+			if !exists {
+				continue
+			}
+
+			b.Partitions++
+			b.StorageUsed += size
+			if i == 0 {
+				b.Leaders++
+			}
+		}
+	}
+
+	racks := map[string]*rackCapacity{}
+
+	for _, b := range brokers {
+		b.StorageTotal = b.StorageUsed + b.StorageFree
+		b.Headroom = headroom(b.StorageFree, b.StorageTotal)
+
+		if _, exists := racks[b.Rack]; !exists {
+			racks[b.Rack] = &rackCapacity{Rack: b.Rack}
+		}
+
+		for _, r := range []*rackCapacity{racks[b.Rack], &report.Totals} {
+			r.Brokers++
+			r.Partitions += b.Partitions
+			r.Leaders += b.Leaders
+			r.StorageUsed += b.StorageUsed
+			r.StorageFree += b.StorageFree
+			r.StorageTotal += b.StorageTotal
+		}
+
+		report.Brokers = append(report.Brokers, *b)
+	}
+
+	for _, r := range racks {
+		r.Headroom = headroom(r.StorageFree, r.StorageTotal)
+		report.Racks = append(report.Racks, *r)
+	}
+
+	report.Totals.Headroom = headroom(report.Totals.StorageFree, report.Totals.StorageTotal)
+
+	sort.Slice(report.Brokers, func(i, j int) bool {
+		return report.Brokers[i].ID < report.Brokers[j].ID
+	})
+
+	sort.Slice(report.Racks, func(i, j int) bool {
+		return report.Racks[i].Rack < report.Racks[j].Rack
+	})
+
+	return report
+}
+
+// headroom returns free as a percentage of total.
+func headroom(free, total float64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return free / total * 100
+}
+
+// printCapacityReport prints a clusterCapacity as a table.
+func printCapacityReport(r clusterCapacity, bmm kafkazk.BrokerMetaMap) {
+	row := "%s%-8v %-8s %10v %8v %12v %12v %12v %9v\n"
+
+	fmt.Println("\nBroker capacity:")
+	fmt.Printf(row, indent, "broker", "rack", "partitions", "leaders",
+		"used (GB)", "free (GB)", "total (GB)", "headroom")
+
+	for _, b := range r.Brokers {
+		fmt.Printf(row, indent, b.ID, rackName(b.Rack), b.Partitions, b.Leaders,
+			gb(b.StorageUsed), gb(b.StorageFree), gb(b.StorageTotal), pct(b.Headroom))
+	}
+
+	t := r.Totals
+	fmt.Printf(row, indent, "total", "", t.Partitions, t.Leaders,
+		gb(t.StorageUsed), gb(t.StorageFree), gb(t.StorageTotal), pct(t.Headroom))
+
+	fmt.Println("\nRack distribution:")
+	fmt.Printf(row, indent, "rack", "", "partitions", "leaders",
+		"used (GB)", "free (GB)", "total (GB)", "headroom")
+
+	for _, rk := range r.Racks {
+		fmt.Printf(row, indent, rackName(rk.Rack), fmt.Sprintf("(%d)", rk.Brokers), rk.Partitions, rk.Leaders,
+			gb(rk.StorageUsed), gb(rk.StorageFree), gb(rk.StorageTotal), pct(rk.Headroom))
+	}
+
+	var incomplete []int
+	for _, b := range r.Brokers {
+		if bmm[b.ID].MetricsIncomplete {
+			incomplete = append(incomplete, b.ID)
+		}
+	}
+
+	if len(incomplete) > 0 {
+		fmt.Printf("\n%s[WARN] brokers missing storage metrics: %v\n", indent, incomplete)
+	}
+
+	if len(r.MissingSizes) > 0 {
+		fmt.Printf("\n%s[WARN] %d partitions missing size metadata; storage used is understated\n",
+			indent, len(r.MissingSizes))
+	}
+}
+
+// writeCapacityReport writes a clusterCapacity as JSON to the path specified
+// by the report-out flag, if set.
+func writeCapacityReport(cmd *cobra.Command, r clusterCapacity) {
+	p := cmd.Flag("report-out").Value.String()
+	if p == "" {
+		return
+	}
+
+	out, err := json.Marshal(r)
+	if err != nil {
+		fmt.Printf("\n[ERROR] failed to build capacity report: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := ioutil.WriteFile(p, out, 0644); err != nil {
+		fmt.Printf("\n[ERROR] failed to write capacity report: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nCapacity report written to %s\n", p)
+}
+
+func rackName(r string) string {
+	if r == "" {
+		return "[none]"
+	}
+	return r
+}
+
+func gb(b float64) string {
+	return fmt.Sprintf("%.2f", b/div)
+}
+
+func pct(p float64) string {
+	return fmt.Sprintf("%.2f%%", p)
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestBuildCapacityReport(t *testing.T) {
+	zk := &kafkazk.Stub{}
+	bmm, _ := zk.GetAllBrokerMeta(true)
+	pmm, _ := zk.GetAllPartitionMeta()
+	pm, _ := kafkazk.PartitionMapFromZK([]*regexp.Regexp{regexp.MustCompile("test_topic$")}, zk)
+
+	r := buildCapacityReport(pm, bmm, pmm)
+
+	if len(r.Brokers) != len(bmm) {
+		t.Fatalf("Expected %d brokers, got %d", len(bmm), len(r.Brokers))
+	}
+
+	// Totals must match the sum of the fixture brokers.
+	var free float64
+	for _, b := range bmm {
+		free += b.StorageFree
+	}
+
+	var used float64
+	var replicas, leaders int
+	for _, p := range pm.Partitions {
+		size, _ := pmm.Size(p)
+		used += size * float64(len(p.Replicas))
+		replicas += len(p.Replicas)
+		leaders++
+	}
+
+	tot := r.Totals
+	if tot.Brokers != len(bmm) {
+		t.Errorf("Expected %d total brokers, got %d", len(bmm), tot.Brokers)
+	}
+
+	if tot.Partitions != replicas || tot.Leaders != leaders {
+		t.Errorf("Expected %d partitions and %d leaders, got %d and %d",
+			replicas, leaders, tot.Partitions, tot.Leaders)
+	}
+
+	if tot.StorageFree != free || tot.StorageUsed != used || tot.StorageTotal != free+used {
+		t.Errorf("Expected used/free/total %.0f/%.0f/%.0f, got %.0f/%.0f/%.0f",
+			used, free, free+used, tot.StorageUsed, tot.StorageFree, tot.StorageTotal)
+	}
+
+	// Per-broker and per-rack values must also sum to the totals.
+	var bUsed, rUsed float64
+	var bPartitions, rBrokers int
+	for _, b := range r.Brokers {
+		bUsed += b.StorageUsed
+		bPartitions += b.Partitions
+	}
+
+	for _, rk := range r.Racks {
+		rUsed += rk.StorageUsed
+		rBrokers += rk.Brokers
+	}
+
+	if bUsed != used || rUsed != used || bPartitions != replicas || rBrokers != len(bmm) {
+		t.Error("Broker and rack values don't sum to the totals")
+	}
+
+	// 1001 holds test_topic p0, p1 and p2, leading p0.
+	b := r.Brokers[0]
+	if b.ID != 1001 || b.Partitions != 3 || b.Leaders != 1 || b.StorageUsed != 4500 {
+		t.Errorf("Unexpected broker 1001 capacity %+v", b)
+	}
+
+	if b.Headroom != 2000.0/6500*100 {
+		t.Errorf("Expected 1001 headroom %.2f, got %.2f", 2000.0/6500*100, b.Headroom)
+	}
+
+	racks := []string{"", "a", "b"}
+	for i, rk := range r.Racks {
+		if rk.Rack != racks[i] || rk.Brokers != 2 {
+			t.Errorf("Unexpected rack capacity %+v", rk)
+		}
+	}
+
+	if len(r.MissingSizes) != 0 {
+		t.Errorf("Unexpected missing sizes %v", r.MissingSizes)
+	}
+}
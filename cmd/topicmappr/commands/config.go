@@ -29,6 +29,10 @@ var (
 		topics        []*regexp.Regexp
 		topicsExclude []*regexp.Regexp
 		brokers       []int
+		leaderWeights   kafkazk.LeaderWeights
+		capacityWeights kafkazk.CapacityWeights
+		brokerRemap     map[int]int
+		topicAffinity   [][]string
 	}
 )
 
@@ -50,6 +54,46 @@ func bootstrap(cmd *cobra.Command) {
 	if exclude, _ := cmd.Flags().GetString("topics-exclude"); exclude != "" {
 		Config.topicsExclude = topicRegex(exclude)
 	}
+
+	// Populate leadership weights.
+	if lw, _ := cmd.Flags().GetString("leader-weights"); lw != "" {
+		w, err := leaderWeightsFromString(lw)
+		if err != nil {
+			fmt.Printf("Invalid --leader-weights: %s\n", err)
+			os.Exit(1)
+		}
+		Config.leaderWeights = w
+	}
+
+	// Populate broker capacity weights.
+	if cw, _ := cmd.Flags().GetString("capacity-weights"); cw != "" {
+		w, err := leaderWeightsFromString(cw)
+		if err != nil {
+			fmt.Printf("Invalid --capacity-weights: %s\n", err)
+			os.Exit(1)
+		}
+		Config.capacityWeights = kafkazk.CapacityWeights(w)
+	}
+
+	// Populate the broker ID remap table.
+	if br, _ := cmd.Flags().GetString("broker-remap"); br != "" {
+		r, err := brokerRemapFromString(br)
+		if err != nil {
+			fmt.Printf("Invalid --broker-remap: %s\n", err)
+			os.Exit(1)
+		}
+		Config.brokerRemap = r
+	}
+
+	// Populate topic affinity groups.
+	if ta, _ := cmd.Flags().GetString("topic-affinity"); ta != "" {
+		g, err := topicAffinityFromString(ta)
+		if err != nil {
+			fmt.Printf("Invalid --topic-affinity: %s\n", err)
+			os.Exit(1)
+		}
+		Config.topicAffinity = g
+	}
 }
 
 // topicRegex takes a string of csv values and returns a []*regexp.Regexp.
@@ -88,7 +132,18 @@ func topicRegex(s string) []*regexp.Regexp {
 //    topic discovery` via ZooKeeper.
 //  - that the --placement flag was set to 'storage', which expects
 //    metrics metadata to be stored in ZooKeeper.
+// If a --snapshot file is set, a Handler backed by the snapshot is returned
+// instead.
 func initZooKeeper(cmd *cobra.Command) (kafkazk.Handler, error) {
+	// Use a snapshot Handler if configured.
+	if p := cmd.Parent().Flag("snapshot").Value.String(); p != "" {
+		s, err := kafkazk.SnapshotFromFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading snapshot: %s", err)
+		}
+		return s.Handler(), nil
+	}
+
 	// Suppress underlying ZK client noise.
 	log.SetOutput(ioutil.Discard)
 
@@ -163,6 +218,103 @@ func brokerStringToSlice(s string) []int {
 	return is
 }
 
+// leaderWeightsFromString takes a comma delimited list of broker ID:weight
+// pairs (e.g. "1001:2,1002:0.5") and returns a kafkazk.LeaderWeights.
+func leaderWeightsFromString(s string) (kafkazk.LeaderWeights, error) {
+	w := kafkazk.LeaderWeights{}
+
+	for _, p := range strings.Split(s, ",") {
+		kv := strings.Split(strings.TrimSpace(p), ":")
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("'%s' must be formatted as id:weight", p)
+		}
+
+		id, err := strconv.Atoi(kv[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid broker ID '%s'", kv[0])
+		}
+
+		weight, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("invalid weight '%s' for broker %d", kv[1], id)
+		}
+
+		w[id] = weight
+	}
+
+	return w, nil
+}
+
+// brokerRemapFromString takes a comma delimited list of old:new broker ID
+// pairs (e.g. "1001:2001,1002:2002") and returns a map of old to new IDs.
+func brokerRemapFromString(s string) (map[int]int, error) {
+	r := map[int]int{}
+	targets := map[int]struct{}{}
+
+	for _, p := range strings.Split(s, ",") {
+		kv := strings.Split(strings.TrimSpace(p), ":")
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("'%s' must be formatted as old:new", p)
+		}
+
+		var ids [2]int
+		for i := range kv {
+			id, err := strconv.Atoi(kv[i])
+			if err != nil || id <= 0 {
+				return nil, fmt.Errorf("invalid broker ID '%s'", kv[i])
+			}
+			ids[i] = id
+		}
+
+		from, to := ids[0], ids[1]
+
+		switch _, dupe := targets[to]; {
+		case from == to:
+			return nil, fmt.Errorf("broker %d is remapped to itself", from)
+		case r[from] != 0:
+			return nil, fmt.Errorf("broker %d is remapped more than once", from)
+		case dupe:
+			return nil, fmt.Errorf("multiple brokers are remapped to %d", to)
+		}
+
+		r[from] = to
+		targets[to] = struct{}{}
+	}
+
+	return r, nil
+}
+
+// topicAffinityFromString takes a comma delimited list of topic affinity
+// groups, each a colon delimited list of topic names, and returns the groups.
+// Each group must have at least two topics and a topic may only belong to a
+// single group.
+func topicAffinityFromString(s string) ([][]string, error) {
+	var groups [][]string
+	seen := map[string]struct{}{}
+
+	for _, g := range strings.Split(s, ",") {
+		topics := strings.Split(strings.TrimSpace(g), ":")
+		if len(topics) < 2 {
+			return nil, fmt.Errorf("'%s' must be at least two topics formatted as topic1:topic2", g)
+		}
+
+		for _, t := range topics {
+			if t == "" {
+				return nil, fmt.Errorf("'%s' contains an empty topic name", g)
+			}
+
+			if _, dupe := seen[t]; dupe {
+				return nil, fmt.Errorf("topic %s is in more than one affinity group", t)
+			}
+			seen[t] = struct{}{}
+		}
+
+		groups = append(groups, topics)
+	}
+
+	return groups, nil
+}
+
 func defaultsAndExit() {
 	fmt.Println()
 	os.Exit(1)
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// loadConstraintsFile applies the placement constraints file specified by
+// the --constraints-file flag, if set. It's intended to be used as a command
+// PreRun so that file values are applied before any flags are read.
+func loadConstraintsFile(cmd *cobra.Command, _ []string) {
+	p := cmd.Flag("constraints-file").Value.String()
+	if p == "" {
+		return
+	}
+
+	if err := applyConstraintsFile(cmd, p); err != nil {
+		fmt.Printf("\n[ERROR] constraints file %s: %s\n", p, err)
+		os.Exit(1)
+	}
+}
+
+// applyConstraintsFile takes a path to a YAML or JSON constraints file and
+// sets the value of each entry on the command flag of the same name. Entries
+// are keyed by flag name (e.g. min-rack-ids, partition-limit, leader-weights,
+// topics-exclude). Flags that were explicitly set on the command line take
+// precedence over file values. Entries for flags the command doesn't define
+// are ignored with a warning, allowing a file to be shared across commands.
+func applyConstraintsFile(cmd *cobra.Command, p string) error {
+	b, err := ioutil.ReadFile(p)
+	if err != nil {
+		return err
+	}
+
+	constraints, err := constraintsFromBytes(b)
+	if err != nil {
+		return err
+	}
+
+	// Apply in order for consistent output.
+	var keys []string
+	for k := range constraints {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		f := cmd.Flags().Lookup(k)
+		switch {
+		case k == "constraints-file":
+			return fmt.Errorf("constraints-file can't be set from a constraints file")
+		case f == nil:
+			fmt.Printf("[WARN] constraint %s doesn't apply to %s; ignoring\n", k, cmd.Name())
+			continue
+		case f.Changed:
+			continue
+		}
+
+		if err := cmd.Flags().Set(k, constraints[k]); err != nil {
+			return fmt.Errorf("invalid value for %s: %s", k, err)
+		}
+	}
+
+	return nil
+}
+
+// constraintsFromBytes takes YAML or JSON encoded constraints and returns a
+// map of flag names to flag string values. Lists are flattened to comma
+// delimited values and maps to comma delimited key:value pairs (e.g.
+// leader-weights: {1001: 2} becomes "1001:2").
+func constraintsFromBytes(b []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(b, &raw); err != nil {
+		return nil, err
+	}
+
+	constraints := map[string]string{}
+
+	for k, v := range raw {
+		switch val := v.(type) {
+		case []interface{}:
+			var s []string
+			for _, e := range val {
+				s = append(s, fmt.Sprint(e))
+			}
+			constraints[k] = strings.Join(s, ",")
+		case map[string]interface{}:
+			var s []string
+			for mk, mv := range val {
+				s = append(s, fmt.Sprintf("%s:%v", mk, mv))
+			}
+			sort.Strings(s)
+			constraints[k] = strings.Join(s, ",")
+		case map[interface{}]interface{}:
+			var s []string
+			for mk, mv := range val {
+				s = append(s, fmt.Sprintf("%v:%v", mk, mv))
+			}
+			sort.Strings(s)
+			constraints[k] = strings.Join(s, ",")
+		case nil:
+			return nil, fmt.Errorf("no value for %s", k)
+		default:
+			constraints[k] = fmt.Sprint(val)
+		}
+	}
+
+	return constraints, nil
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyConstraintsFile(t *testing.T) {
+	f, err := ioutil.TempFile("", "constraints")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+
+	f.WriteString(`
+min-rack-ids: 2
+partition-limit: 10
+topics-exclude: [test_a, test_b]
+leader-weights:
+  1001: 2
+  1002: 0.5
+not-a-flag: true
+`)
+	f.Close()
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().Int("min-rack-ids", 0, "")
+	cmd.Flags().Int("partition-limit", 30, "")
+	cmd.Flags().String("topics-exclude", "", "")
+	cmd.Flags().String("leader-weights", "", "")
+	cmd.Flags().String("constraints-file", "", "")
+
+	// A command-line value should override the file.
+	if err := cmd.ParseFlags([]string{"--partition-limit=20"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applyConstraintsFile(cmd, f.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"min-rack-ids":    "2",
+		"partition-limit": "20",
+		"topics-exclude":  "test_a,test_b",
+		"leader-weights":  "1001:2,1002:0.5",
+	}
+
+	for k, v := range expected {
+		if got := cmd.Flag(k).Value.String(); got != v {
+			t.Errorf("Expected %s value %s, got %s", k, v, got)
+		}
+	}
+
+	// The parsed leader weights should be usable as-is.
+	w, err := leaderWeightsFromString(cmd.Flag("leader-weights").Value.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if w[1001] != 2 || w[1002] != 0.5 {
+		t.Errorf("Unexpected leader weights %v", w)
+	}
+}
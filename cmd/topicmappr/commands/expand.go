@@ -0,0 +1,239 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var expandCmd = &cobra.Command{
+	Use:   "expand",
+	Short: "Compute placements for partitions added to an existing topic",
+	Long: `expand computes placements for only the partitions being added when increasing
+an existing topic's partition count. The topic is provided via the --topic flag and the
+new total partition count via the --partitions flag. Existing partitions aren't moved;
+new partitions use the topic's current replication factor and the same placement
+constraints as rebuild, accounting for the topic's existing replica distribution. The
+output map covers only the new partitions and can be used as the replica assignment
+when adding partitions to the topic.`,
+	PreRun: loadConstraintsFile,
+	Run:    expand,
+}
+
+func init() {
+	rootCmd.AddCommand(expandCmd)
+
+	expandCmd.Flags().String("topic", "", "Name of the topic to expand")
+	expandCmd.Flags().Int("partitions", 0, "New total topic partition count")
+	expandCmd.Flags().String("constraints-file", "", "Path to a YAML or JSON file of placement constraints keyed by flag name (command-line flags take precedence)")
+	expandCmd.Flags().String("out-path", "", "Path to write output map files to")
+	expandCmd.Flags().String("out-file", "", "If defined, write a combined map of all topics to a file")
+	expandCmd.Flags().Bool("sort-output", false, "Sort output map partitions by topic and partition number for stable, diffable output")
+	expandCmd.Flags().String("placement", "count", "Partition placement strategy: [count, storage]")
+	expandCmd.Flags().Int("min-rack-ids", 0, "Minimum number of required of unique rack IDs per replica set (0 requires that all are unique)")
+	expandCmd.Flags().String("capacity-weights", "", "Broker capacity weights for count placement; brokers are filled toward partition counts proportional to their weight (comma delim. list of id:weight, e.g. 1001:2,1002:1)")
+	expandCmd.Flags().Float64("partition-size", 0, "Estimated partition size in gigabytes (required when using storage placement)")
+	expandCmd.Flags().String("brokers", "", "Broker list to scope all partition placements to ('-2' for all brokers in cluster)")
+	expandCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics (when using storage placement)")
+	expandCmd.Flags().Int("metrics-age", 60, "Kafka metrics age tolerance (in minutes) (when using storage placement)")
+
+	// Required.
+	expandCmd.MarkFlagRequired("topic")
+	expandCmd.MarkFlagRequired("partitions")
+	expandCmd.MarkFlagRequired("brokers")
+}
+
+func expand(cmd *cobra.Command, _ []string) {
+	// Sanity check params.
+	t := cmd.Flag("topic").Value.String()
+	n, _ := cmd.Flags().GetInt("partitions")
+	p := cmd.Flag("placement").Value.String()
+	ps, _ := cmd.Flags().GetFloat64("partition-size")
+
+	switch {
+	case n <= 0:
+		fmt.Println("\n[ERROR] --partitions must be greater than 0")
+		defaultsAndExit()
+	case p != "count" && p != "storage":
+		fmt.Println("\n[ERROR] --placement must be either 'count' or 'storage'")
+		defaultsAndExit()
+	case p == "storage" && ps <= 0:
+		fmt.Println("\n[ERROR] --placement=storage requires --partition-size")
+		defaultsAndExit()
+	}
+
+	bootstrap(cmd)
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer zk.Close()
+
+	// Get the current topic map.
+	partitionMapIn, err := kafkazk.PartitionMapFromZK([]*regexp.Regexp{
+		regexp.MustCompile(fmt.Sprintf("^%s$", regexp.QuoteMeta(t))),
+	}, zk)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	current := len(partitionMapIn.Partitions)
+	if n <= current {
+		fmt.Printf("\n[ERROR] --partitions must be greater than the current partition count of %d\n", current)
+		os.Exit(1)
+	}
+
+	// Fetch broker metadata.
+	var withMetrics bool
+	if p == "storage" {
+		checkMetaAge(cmd, zk)
+		withMetrics = true
+	}
+
+	brokerMeta := getBrokerMeta(cmd, zk, withMetrics)
+
+	fmt.Printf("\nTopic:\n%s%s: %d -> %d partitions\n", indent, t, current, n)
+
+	// Get a BrokerMap of the target brokers.
+	fmt.Printf("\nBroker change summary:\n")
+	brokers := kafkazk.NewBrokerMap()
+	bs, msgs := brokers.Update(Config.brokers, brokerMeta)
+	for msg := range msgs {
+		fmt.Printf("%s%s\n", indent, msg)
+	}
+
+	brokers.SetWeights(Config.capacityWeights)
+	brokersOrig := brokers.Copy()
+
+	ensureBrokerMetrics(cmd, brokers, brokerMeta)
+
+	// Get the usable target broker IDs.
+	var ids []int
+	for id, b := range brokers {
+		if !b.Replace {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Ints(ids)
+
+	if r := partitionMapIn.Partitions[0].Replicas; len(ids) < len(r) {
+		fmt.Printf("\n[ERROR] %d target brokers is less than the replication factor of %d\n",
+			len(ids), len(r))
+		os.Exit(1)
+	}
+
+	var errs errors
+
+	// Build the map.
+	var partitionMeta kafkazk.PartitionMetaMap
+	if p == "storage" {
+		partitionMeta = newTopicPartitionMeta(t, n, ps*div)
+	}
+
+	mrrid, _ := cmd.Flags().GetInt("min-rack-ids")
+	partitionMap, rebuildErrs := expansionMap(partitionMapIn, n, brokers, partitionMeta, p, mrrid)
+	errs = append(errs, rebuildErrs...)
+
+	// Count missing brokers as a warning.
+	if bs.Missing > 0 {
+		errs = append(errs, fmt.Errorf("%d provided brokers not found in ZooKeeper", bs.Missing))
+	}
+
+	if bs.RackMissing > 0 {
+		errs = append(
+			errs, fmt.Errorf("%d provided broker(s) do(es) not have a rack.id defined", bs.RackMissing),
+		)
+	}
+
+	// Print the assignment.
+	fmt.Println("\nNew partitions:")
+	for _, partn := range partitionMap.Partitions {
+		fmt.Printf("%s%s p%d: %v\n", indent, partn.Topic, partn.Partition, partn.Replicas)
+	}
+
+	if p == "storage" {
+		fmt.Println("\nStorage free change estimations:")
+		for _, id := range ids {
+			fmt.Printf("%sBroker %d: %.2f -> %.2f\n",
+				indent, id, brokersOrig[id].StorageFree/div, brokers[id].StorageFree/div)
+		}
+	}
+
+	// Print error/warnings.
+	handleOverridableErrs(cmd, errs)
+
+	writeMaps(cmd, partitionMap, nil)
+}
+
+// expansionMap takes the current *kafkazk.PartitionMap of a single topic, the
+// new total partition count, the target kafkazk.BrokerMap, an optional
+// kafkazk.PartitionMetaMap, a placement strategy and the minimum number of
+// unique rack IDs per replica set. A *kafkazk.PartitionMap holding placements
+// for only the partitions being added is returned. New partitions use the
+// replication factor of the topic's first partition. Replicas of existing
+// partitions held by target brokers are counted toward broker usage so that
+// placements balance the topic as a whole; as with a rebuild, bm is updated
+// in place.
+func expansionMap(pm *kafkazk.PartitionMap, n int, bm kafkazk.BrokerMap, pmm kafkazk.PartitionMetaMap, strategy string, minRackIDs int) (*kafkazk.PartitionMap, errors) {
+	if len(pm.Partitions) == 0 {
+		return nil, errors{fmt.Errorf("no existing partitions to expand from")}
+	}
+
+	current := pm.Copy()
+	sort.Sort(current.Partitions)
+
+	t := current.Partitions[0].Topic
+	r := len(current.Partitions[0].Replicas)
+
+	if n <= len(current.Partitions) {
+		return nil, errors{fmt.Errorf("partition count %d must be greater than the current count of %d",
+			n, len(current.Partitions))}
+	}
+
+	// Account for existing replicas.
+	for _, p := range current.Partitions {
+		for _, id := range p.Replicas {
+			if b, exists := bm[id]; exists && id != kafkazk.StubBrokerID {
+				b.Used++
+			}
+		}
+	}
+
+	// Create a stub map of the new partitions; all replicas are stub
+	// brokers that get replaced in the rebuild.
+	pmNew := kafkazk.NewPartitionMap()
+	for i := len(current.Partitions); i < n; i++ {
+		partn := kafkazk.Partition{
+			Topic:     t,
+			Partition: i,
+			Replicas:  make([]int, r),
+		}
+
+		for j := range partn.Replicas {
+			partn.Replicas[j] = kafkazk.StubBrokerID
+		}
+
+		pmNew.Partitions = append(pmNew.Partitions, partn)
+	}
+
+	rebuildParams := kafkazk.RebuildParams{
+		PMM:              pmm,
+		BM:               bm,
+		Strategy:         strategy,
+		Optimization:     "distribution",
+		PartnSzFactor:    1.0,
+		MinUniqueRackIDs: minRackIDs,
+	}
+
+	return pmNew.Rebuild(rebuildParams)
+}
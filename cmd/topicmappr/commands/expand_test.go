@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestExpansionMap(t *testing.T) {
+	bmm := kafkazk.BrokerMetaMap{
+		1001: &kafkazk.BrokerMeta{Rack: "a"},
+		1002: &kafkazk.BrokerMeta{Rack: "a"},
+		1003: &kafkazk.BrokerMeta{Rack: "b"},
+		1004: &kafkazk.BrokerMeta{Rack: "b"},
+		1005: &kafkazk.BrokerMeta{Rack: "c"},
+		1006: &kafkazk.BrokerMeta{Rack: "c"},
+	}
+
+	bm := kafkazk.NewBrokerMap()
+	bm.Update([]int{1001, 1002, 1003, 1004, 1005, 1006}, bmm)
+
+	// The existing partitions use only 1001, 1003 and 1005.
+	pm := kafkazk.NewPartitionMap()
+	for i := 0; i < 3; i++ {
+		pm.Partitions = append(pm.Partitions, kafkazk.Partition{
+			Topic: "test", Partition: i, Replicas: []int{1001, 1003, 1005},
+		})
+	}
+
+	pmOrig := pm.Copy()
+
+	out, errs := expansionMap(pm, 6, bm, nil, "count", 0)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	// The input map shouldn't be modified.
+	if eq, _ := pm.Equal(pmOrig); !eq {
+		t.Error("Unexpected modification of the input map")
+	}
+
+	if len(out.Partitions) != 3 {
+		t.Fatalf("Expected 3 partitions, got %d", len(out.Partitions))
+	}
+
+	for i, p := range out.Partitions {
+		if p.Topic != "test" {
+			t.Errorf("Expected topic test, got %s", p.Topic)
+		}
+
+		if p.Partition != i+3 {
+			t.Errorf("Expected partition %d, got %d", i+3, p.Partition)
+		}
+
+		if len(p.Replicas) != 3 {
+			t.Errorf("p%d: expected 3 replicas, got %v", p.Partition, p.Replicas)
+			continue
+		}
+
+		racks := map[string]struct{}{}
+		for _, id := range p.Replicas {
+			// The least used brokers should be chosen.
+			switch id {
+			case 1002, 1004, 1006:
+			default:
+				t.Errorf("p%d: unexpected broker %d in %v", p.Partition, id, p.Replicas)
+				continue
+			}
+			racks[bmm[id].Rack] = struct{}{}
+		}
+
+		if len(racks) != 3 {
+			t.Errorf("p%d: expected replicas in 3 racks, got %v", p.Partition, p.Replicas)
+		}
+	}
+
+	// The partition count must increase.
+	if _, errs := expansionMap(pm, 3, bm, nil, "count", 0); errs == nil {
+		t.Error("Expected non-nil errors")
+	}
+}
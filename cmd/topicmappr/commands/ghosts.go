@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var ghostsCmd = &cobra.Command{
+	Use:   "ghosts",
+	Short: "Find partitions referencing unregistered brokers",
+	Long: `Find partitions with replicas assigned to ghost brokers; broker IDs that aren't
+registered in ZooKeeper at all. This differs from offline brokers, which may be down but
+remain registered. Partitions referencing ghost brokers can be repaired with rebuild.`,
+	Run: ghosts,
+}
+
+func init() {
+	rootCmd.AddCommand(ghostsCmd)
+
+	ghostsCmd.Flags().String("topics", ".*", "Scan topics (comma delim. list) by lookup in ZooKeeper")
+	ghostsCmd.Flags().String("topics-exclude", "", "Exclude topics")
+	ghostsCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics")
+}
+
+// ghostReference describes a partition with replicas assigned to
+// unregistered broker IDs.
+type ghostReference struct {
+	topic     string
+	partition int
+	replicas  []int
+	ghosts    []int
+}
+
+func ghosts(cmd *cobra.Command, _ []string) {
+	Config.topics = topicRegex(cmd.Flag("topics").Value.String())
+	if exclude := cmd.Flag("topics-exclude").Value.String(); exclude != "" {
+		Config.topicsExclude = topicRegex(exclude)
+	}
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	brokerMeta := getBrokerMeta(cmd, zk, false)
+
+	partitionMap, err := kafkazk.PartitionMapFromZK(Config.topics, zk)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// Exclude any explicit exclusions.
+	excluded := removeTopics(partitionMap, Config.topicsExclude)
+	printExcludedTopics(nil, excluded)
+
+	refs := ghostReferences(partitionMap, brokerMeta)
+
+	fmt.Println("\nGhost broker references:")
+
+	if len(refs) == 0 {
+		fmt.Printf("%s[none]\n", indent)
+		return
+	}
+
+	var topics = map[string]struct{}{}
+	var ids = map[int]struct{}{}
+
+	for _, r := range refs {
+		fmt.Printf("%s%s p%d: %v (unregistered: %v)\n",
+			indent, r.topic, r.partition, r.replicas, r.ghosts)
+
+		topics[r.topic] = struct{}{}
+		for _, id := range r.ghosts {
+			ids[id] = struct{}{}
+		}
+	}
+
+	fmt.Printf("%s-\n%s[ERROR] %d partitions across %d topics reference %d unregistered brokers\n",
+		indent, indent, len(refs), len(topics), len(ids))
+
+	os.Exit(1)
+}
+
+// ghostReferences takes a *kafkazk.PartitionMap and the kafkazk.BrokerMetaMap
+// of registered brokers and returns a []ghostReference for every partition
+// with replicas assigned to unregistered broker IDs.
+func ghostReferences(pm *kafkazk.PartitionMap, bmm kafkazk.BrokerMetaMap) []ghostReference {
+	var refs []ghostReference
+
+	for _, p := range pm.Partitions {
+		var ghosts []int
+		for _, id := range p.Replicas {
+			if _, registered := bmm[id]; !registered {
+				ghosts = append(ghosts, id)
+			}
+		}
+
+		if len(ghosts) > 0 {
+			refs = append(refs, ghostReference{
+				topic:     p.Topic,
+				partition: p.Partition,
+				replicas:  p.Replicas,
+				ghosts:    ghosts,
+			})
+		}
+	}
+
+	return refs
+}
@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestGhostReferences(t *testing.T) {
+	zk := kafkazk.Stub{}
+	bmm, _ := zk.GetAllBrokerMeta(false)
+
+	pm, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test_topic","partition":0,"replicas":[1001,1002]},
+		{"topic":"test_topic","partition":1,"replicas":[1002,1006]},
+		{"topic":"test_topic2","partition":0,"replicas":[1003,1004]}]}`)
+
+	refs := ghostReferences(pm, bmm)
+
+	if len(refs) != 1 {
+		t.Fatalf("Expected 1 ghost reference, got %d", len(refs))
+	}
+
+	r := refs[0]
+	if r.topic != "test_topic" || r.partition != 1 {
+		t.Errorf("Expected ghost reference for test_topic p1, got %s p%d", r.topic, r.partition)
+	}
+
+	if len(r.ghosts) != 1 || r.ghosts[0] != 1006 {
+		t.Errorf("Expected ghost broker [1006], got %v", r.ghosts)
+	}
+}
@@ -0,0 +1,267 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// applyPreferredLeaderRack pins preferred leaders in the provided
+// *kafkazk.PartitionMap to the rack specified by the --preferred-leader-rack
+// flag, if set. A warning is printed for any partitions that don't have a
+// replica in the rack.
+func applyPreferredLeaderRack(cmd *cobra.Command, pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap) {
+	rack := cmd.Flag("preferred-leader-rack").Value.String()
+	if rack == "" {
+		return
+	}
+
+	unpinned := pinLeadersToRack(pm, bm, rack)
+
+	if len(unpinned) > 0 {
+		fmt.Printf("\n[WARN] %d partitions have no replica in rack %s; leadership unchanged:\n",
+			len(unpinned), rack)
+		for _, p := range unpinned {
+			fmt.Printf("%s%s p%d: %v\n", indent, p.Topic, p.Partition, p.Replicas)
+		}
+	}
+}
+
+// pinLeadersToRack takes a *kafkazk.PartitionMap, a kafkazk.BrokerMap and a
+// rack ID. For each partition that has a (non-observer) replica in the rack,
+// the first such replica is made the preferred leader; the remaining replicas
+// keep their relative order. Partitions without a replica in the rack are left
+// unchanged and returned.
+func pinLeadersToRack(pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap, rack string) []kafkazk.Partition {
+	var unpinned []kafkazk.Partition
+
+	for i, p := range pm.Partitions {
+		idx := -1
+		for j, id := range p.Replicas {
+			// Observers can't lead.
+			if p.IsObserver(id) {
+				continue
+			}
+
+			if b, exists := bm[id]; exists && b.Locality == rack {
+				idx = j
+				break
+			}
+		}
+
+		switch idx {
+		case -1:
+			unpinned = append(unpinned, p)
+		case 0:
+			// Already led by a broker in the rack.
+		default:
+			replicas := []int{p.Replicas[idx]}
+			replicas = append(replicas, p.Replicas[:idx]...)
+			replicas = append(replicas, p.Replicas[idx+1:]...)
+			pm.Partitions[i].Replicas = replicas
+		}
+	}
+
+	return unpinned
+}
+
+// applyKeepLeaders retains the current leader of every partition in the
+// provided *kafkazk.PartitionMap if the --keep-leaders flag is set. If any
+// partition's leader can't be retained, the partitions are printed and
+// topicmappr exits.
+func applyKeepLeaders(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap, bm kafkazk.BrokerMap) {
+	if kl, _ := cmd.Flags().GetBool("keep-leaders"); !kl {
+		return
+	}
+
+	unkept := keepLeaders(pm1, pm2, bm)
+
+	if len(unkept) > 0 {
+		fmt.Printf("\n[ERROR] --keep-leaders: %d partitions require moving the current leader:\n", len(unkept))
+		for _, p := range unkept {
+			fmt.Printf("%s%s p%d: %v\n", indent, p.Topic, p.Partition, p.Replicas)
+		}
+		os.Exit(1)
+	}
+}
+
+// keepLeaders takes the current and rebuilt *kafkazk.PartitionMap and a
+// kafkazk.BrokerMap. The current leader (the first replica in the current
+// map) of each partition is made the preferred leader of the rebuilt replica
+// set. If the rebuild moved the leader, it's substituted back in place of a
+// newly assigned replica, preferring one in the leader's rack and otherwise
+// one that keeps rack IDs unique. Only sync replicas are reordered or
+// substituted, so observers are unchanged. Partitions whose leader is marked
+// for replacement, was rebuilt as an observer or can't be substituted back
+// are left unchanged and returned with their current replicas.
+func keepLeaders(pm1, pm2 *kafkazk.PartitionMap, bm kafkazk.BrokerMap) []kafkazk.Partition {
+	var unkept []kafkazk.Partition
+
+	// Index current partitions.
+	current := map[string]map[int]kafkazk.Partition{}
+	for _, p := range pm1.Partitions {
+		if current[p.Topic] == nil {
+			current[p.Topic] = map[int]kafkazk.Partition{}
+		}
+		current[p.Topic][p.Partition] = p
+	}
+
+	for i, p := range pm2.Partitions {
+		cur, exists := current[p.Topic][p.Partition]
+		if !exists || len(cur.Replicas) == 0 {
+			continue
+		}
+
+		leader := cur.Replicas[0]
+
+		idx := -1
+		for j, id := range p.Replicas {
+			if id == leader {
+				idx = j
+				break
+			}
+		}
+
+		// Observers can't lead.
+		if idx != -1 && p.IsObserver(leader) {
+			unkept = append(unkept, cur)
+			continue
+		}
+
+		if idx == -1 {
+			if b, exists := bm[leader]; !exists || b.Replace {
+				unkept = append(unkept, cur)
+				continue
+			}
+
+			if idx = leaderSubstitute(cur, p, bm); idx == -1 {
+				unkept = append(unkept, cur)
+				continue
+			}
+		}
+
+		replicas := []int{leader}
+		for j, id := range p.Replicas {
+			if j != idx {
+				replicas = append(replicas, id)
+			}
+		}
+
+		pm2.Partitions[i].Replicas = replicas
+	}
+
+	return unkept
+}
+
+// leaderSubstitute takes a partition's current state, its rebuilt state that
+// no longer includes the current leader and a kafkazk.BrokerMap. The index of
+// the rebuilt replica to substitute with the current leader is returned, or
+// -1 if no newly assigned sync replica can be substituted without violating
+// rack uniqueness.
+func leaderSubstitute(cur, p kafkazk.Partition, bm kafkazk.BrokerMap) int {
+	isCurrent := map[int]bool{}
+	for _, id := range cur.Replicas {
+		isCurrent[id] = true
+	}
+
+	rack := bm[cur.Replicas[0]].Locality
+
+	fallback := -1
+	for j, id := range p.Replicas {
+		// Only newly assigned sync replicas are substituted.
+		if isCurrent[id] || p.IsObserver(id) {
+			continue
+		}
+
+		b, exists := bm[id]
+		if rack == "" || (exists && b.Locality == rack) {
+			return j
+		}
+
+		if fallback != -1 {
+			continue
+		}
+
+		// Check whether the leader's rack is held by the other replicas.
+		unique := true
+		for k, other := range p.Replicas {
+			if k != j && bm[other] != nil && bm[other].Locality == rack {
+				unique = false
+				break
+			}
+		}
+
+		if unique {
+			fallback = j
+		}
+	}
+
+	return fallback
+}
+
+// leaderConcentration describes a broker that is the preferred leader for a
+// disproportionate number of partitions.
+type leaderConcentration struct {
+	id      int
+	leaders int
+	// The average preferred leader count across brokers.
+	avg float64
+}
+
+// checkLeaderConcentration prints a warning for any brokers in the provided
+// *kafkazk.PartitionMap whose preferred leader count exceeds the average by
+// more than the --leader-concentration-factor flag value.
+func checkLeaderConcentration(cmd *cobra.Command, pm *kafkazk.PartitionMap) {
+	factor, _ := cmd.Flags().GetFloat64("leader-concentration-factor")
+	if factor <= 0 {
+		return
+	}
+
+	concentrated := leaderConcentrations(pm, factor)
+	if len(concentrated) == 0 {
+		return
+	}
+
+	fmt.Printf("\n[WARN] %d brokers lead more than %.2fx the average number of partitions:\n",
+		len(concentrated), factor)
+	for _, c := range concentrated {
+		fmt.Printf("%sBroker %d - leader: %d (avg: %.2f)\n", indent, c.id, c.leaders, c.avg)
+	}
+	fmt.Printf("%sconsider rebalancing leadership with --optimize-leadership\n", indent)
+}
+
+// leaderConcentrations takes a *kafkazk.PartitionMap and a factor and returns
+// a []leaderConcentration, ordered by broker ID, for every broker whose
+// preferred leader count is greater than the average multiplied by the
+// factor. The average is taken across all brokers holding a replica in the
+// map, including those that lead no partitions.
+func leaderConcentrations(pm *kafkazk.PartitionMap, factor float64) []leaderConcentration {
+	var concentrated []leaderConcentration
+
+	stats := pm.UseStats().List()
+	if len(stats) == 0 {
+		return concentrated
+	}
+
+	var total int
+	for _, s := range stats {
+		total += s.Leader
+	}
+
+	avg := float64(total) / float64(len(stats))
+
+	for _, s := range stats {
+		if float64(s.Leader) > avg*factor {
+			concentrated = append(concentrated, leaderConcentration{
+				id:      s.ID,
+				leaders: s.Leader,
+				avg:     avg,
+			})
+		}
+	}
+
+	return concentrated
+}
@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestPinLeadersToRack(t *testing.T) {
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, Locality: "a"},
+		1002: &kafkazk.Broker{ID: 1002, Locality: "b"},
+		1003: &kafkazk.Broker{ID: 1003, Locality: "c"},
+		1004: &kafkazk.Broker{ID: 1004, Locality: "a"},
+	}
+
+	pm := kafkazk.NewPartitionMap()
+	pm.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002, 1003}},
+		{Topic: "test", Partition: 1, Replicas: []int{1002, 1001, 1003}},
+		{Topic: "test", Partition: 2, Replicas: []int{1003, 1002, 1004}},
+		{Topic: "test", Partition: 3, Replicas: []int{1002, 1003}},
+	}
+
+	expected := [][]int{
+		{1001, 1002, 1003},
+		{1001, 1002, 1003},
+		{1004, 1003, 1002},
+		{1002, 1003},
+	}
+
+	unpinned := pinLeadersToRack(pm, bm, "a")
+
+	for i, p := range pm.Partitions {
+		if !replicasEqual(p.Replicas, expected[i]) {
+			t.Errorf("p%d: expected replicas %v, got %v", p.Partition, expected[i], p.Replicas)
+		}
+	}
+
+	// Partition 3 has no replica in rack a.
+	if len(unpinned) != 1 || unpinned[0].Partition != 3 {
+		t.Errorf("Expected only p3 to be unpinned, got %v", unpinned)
+	}
+}
+
+func TestKeepLeaders(t *testing.T) {
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, Locality: "a"},
+		1002: &kafkazk.Broker{ID: 1002, Locality: "b"},
+		1003: &kafkazk.Broker{ID: 1003, Locality: "c"},
+		1004: &kafkazk.Broker{ID: 1004, Locality: "a"},
+		1005: &kafkazk.Broker{ID: 1005, Locality: "b"},
+		1006: &kafkazk.Broker{ID: 1006, Locality: "c"},
+	}
+
+	pm1 := kafkazk.NewPartitionMap()
+	pm1.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002}},
+		{Topic: "test", Partition: 1, Replicas: []int{1002, 1003}},
+		{Topic: "test", Partition: 2, Replicas: []int{1003, 1001}},
+		{Topic: "test", Partition: 3, Replicas: []int{1001, 1003}},
+	}
+
+	// A rebuilt map, in a different order.
+	pm2 := kafkazk.NewPartitionMap()
+	pm2.Partitions = []kafkazk.Partition{
+		// Leader demoted to follower.
+		{Topic: "test", Partition: 1, Replicas: []int{1003, 1002}},
+		// Leader retained.
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1005}},
+		// Leader moved to a broker in the same rack.
+		{Topic: "test", Partition: 2, Replicas: []int{1006, 1001}},
+		// Leader moved to a broker in another rack.
+		{Topic: "test", Partition: 3, Replicas: []int{1002, 1003}},
+	}
+
+	unkept := keepLeaders(pm1, pm2, bm)
+	if len(unkept) != 0 {
+		t.Fatalf("Expected no unkept partitions, got %v", unkept)
+	}
+
+	expected := map[int][]int{
+		0: {1001, 1005},
+		1: {1002, 1003},
+		2: {1003, 1001},
+		3: {1001, 1003},
+	}
+
+	for _, p := range pm2.Partitions {
+		if p.Replicas[0] != pm1.Partitions[p.Partition].Replicas[0] {
+			t.Errorf("p%d: expected leader %d, got %d",
+				p.Partition, pm1.Partitions[p.Partition].Replicas[0], p.Replicas[0])
+		}
+
+		if !replicasEqual(p.Replicas, expected[p.Partition]) {
+			t.Errorf("p%d: expected replicas %v, got %v", p.Partition, expected[p.Partition], p.Replicas)
+		}
+	}
+}
+
+func TestKeepLeadersUnkept(t *testing.T) {
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, Locality: "a", Replace: true},
+		1002: &kafkazk.Broker{ID: 1002, Locality: "b"},
+		1003: &kafkazk.Broker{ID: 1003, Locality: "c"},
+		1004: &kafkazk.Broker{ID: 1004, Locality: "a"},
+		1005: &kafkazk.Broker{ID: 1005, Locality: "b"},
+	}
+
+	pm1 := kafkazk.NewPartitionMap()
+	pm1.Partitions = []kafkazk.Partition{
+		// The leader is being replaced.
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002}},
+		// Substituting the only new replica would put two replicas in rack b.
+		{Topic: "test", Partition: 1, Replicas: []int{1002, 1005}},
+		// The leader's rack is held by a new replica.
+		{Topic: "test", Partition: 2, Replicas: []int{1002, 1003}},
+	}
+
+	pm2 := kafkazk.NewPartitionMap()
+	pm2.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1004, 1002}},
+		{Topic: "test", Partition: 1, Replicas: []int{1005, 1004}},
+		{Topic: "test", Partition: 2, Replicas: []int{1003, 1005}},
+	}
+
+	unkept := keepLeaders(pm1, pm2, bm)
+	if len(unkept) != 2 || unkept[0].Partition != 0 || unkept[1].Partition != 1 {
+		t.Errorf("Expected p0 and p1 to be unkept, got %v", unkept)
+	}
+
+	if !replicasEqual(pm2.Partitions[2].Replicas, []int{1002, 1003}) {
+		t.Errorf("p2: expected replicas [1002 1003], got %v", pm2.Partitions[2].Replicas)
+	}
+}
+
+func TestKeepLeadersObservers(t *testing.T) {
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, Locality: "a"},
+		1002: &kafkazk.Broker{ID: 1002, Locality: "b"},
+		1003: &kafkazk.Broker{ID: 1003, Locality: "c"},
+		1004: &kafkazk.Broker{ID: 1004, Locality: "a"},
+		1005: &kafkazk.Broker{ID: 1005, Locality: "b"},
+	}
+
+	pm1 := kafkazk.NewPartitionMap()
+	pm1.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002, 1003}, Observers: []int{1003}},
+		{Topic: "test", Partition: 1, Replicas: []int{1001, 1002, 1003}, Observers: []int{1003}},
+		{Topic: "test", Partition: 2, Replicas: []int{1002, 1003, 1001}, Observers: []int{1001}},
+	}
+
+	pm2 := kafkazk.NewPartitionMap()
+	pm2.Partitions = []kafkazk.Partition{
+		// Leader demoted to follower.
+		{Topic: "test", Partition: 0, Replicas: []int{1003, 1001, 1005}, Observers: []int{1005}},
+		// Leader moved; the new observer in the leader's rack isn't
+		// substituted and the new sync replica would share its rack.
+		{Topic: "test", Partition: 1, Replicas: []int{1005, 1003, 1004}, Observers: []int{1004}},
+		// Leader rebuilt as an observer.
+		{Topic: "test", Partition: 2, Replicas: []int{1003, 1001, 1002}, Observers: []int{1002}},
+	}
+
+	unkept := keepLeaders(pm1, pm2, bm)
+	if len(unkept) != 2 || unkept[0].Partition != 1 || unkept[1].Partition != 2 {
+		t.Errorf("Expected p1 and p2 to be unkept, got %v", unkept)
+	}
+
+	expected := []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1003, 1005}, Observers: []int{1005}},
+		{Topic: "test", Partition: 1, Replicas: []int{1005, 1003, 1004}, Observers: []int{1004}},
+		{Topic: "test", Partition: 2, Replicas: []int{1003, 1001, 1002}, Observers: []int{1002}},
+	}
+
+	for i, p := range pm2.Partitions {
+		if !p.Equal(expected[i]) {
+			t.Errorf("p%d: expected %v, got %v", p.Partition, expected[i], p)
+		}
+	}
+}
+
+func TestLeaderConcentrations(t *testing.T) {
+	pm := kafkazk.NewPartitionMap()
+	pm.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002}},
+		{Topic: "test", Partition: 1, Replicas: []int{1001, 1003}},
+		{Topic: "test", Partition: 2, Replicas: []int{1001, 1004}},
+		{Topic: "test", Partition: 3, Replicas: []int{1001, 1002}},
+		{Topic: "test", Partition: 4, Replicas: []int{1001, 1003}},
+		{Topic: "test", Partition: 5, Replicas: []int{1002, 1004}},
+		{Topic: "test", Partition: 6, Replicas: []int{1003, 1001}},
+		{Topic: "test", Partition: 7, Replicas: []int{1004, 1001}},
+	}
+
+	// 8 leaders across 4 brokers is an average of 2; 1001 leads 5.
+	concentrated := leaderConcentrations(pm, 2.0)
+
+	if len(concentrated) != 1 {
+		t.Fatalf("Expected 1 concentrated broker, got %d", len(concentrated))
+	}
+
+	c := concentrated[0]
+	if c.id != 1001 || c.leaders != 5 || c.avg != 2 {
+		t.Errorf("Expected broker 1001 with 5 leaders (avg 2), got broker %d with %d leaders (avg %.2f)",
+			c.id, c.leaders, c.avg)
+	}
+
+	// 1001 doesn't exceed 3x the average.
+	if concentrated = leaderConcentrations(pm, 3.0); len(concentrated) != 0 {
+		t.Errorf("Expected no concentrated brokers, got %v", concentrated)
+	}
+}
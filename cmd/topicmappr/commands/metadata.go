@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"sort"
 	"time"
 
 	"github.com/DataDog/kafka-kit/v3/kafkazk"
@@ -43,9 +44,57 @@ func getBrokerMeta(cmd *cobra.Command, zk kafkazk.Handler, m bool) kafkazk.Broke
 		os.Exit(1)
 	}
 
+	// Optionally assume a storage free value for brokers missing metrics.
+	if asf, _ := cmd.Flags().GetFloat64("assume-storage-free"); m && asf > 0 {
+		if ids := assumeStorageFree(brokerMeta, asf*div); len(ids) > 0 {
+			fmt.Printf("\n[WARN] metrics not found for brokers %v; assuming %.2fGB storage free\n", ids, asf)
+		}
+	}
+
+	// Optionally reserve a percentage of storage free as headroom.
+	if shp, _ := cmd.Flags().GetFloat64("storage-headroom-percent"); m && shp > 0 {
+		if shp >= 100 {
+			fmt.Println("\n[ERROR] --storage-headroom-percent must be less than 100")
+			os.Exit(1)
+		}
+		reserveStorageHeadroom(brokerMeta, shp)
+	}
+
 	return brokerMeta
 }
 
+// reserveStorageHeadroom takes a BrokerMetaMap and a headroom percentage and
+// reduces the StorageFree value of each broker by that percentage, e.g. with
+// 15% headroom, 85% of the reported storage free is usable for placement.
+// Brokers missing metrics are skipped.
+func reserveStorageHeadroom(bmm kafkazk.BrokerMetaMap, pct float64) {
+	for _, b := range bmm {
+		if !b.MetricsIncomplete {
+			b.StorageFree *= 1 - pct/100
+		}
+	}
+}
+
+// assumeStorageFree takes a BrokerMetaMap and a storage free value in bytes.
+// Any brokers marked with incomplete metrics are assigned the storage free
+// value and are no longer considered as missing metrics. The IDs of the brokers
+// that were assigned the value are returned.
+func assumeStorageFree(bmm kafkazk.BrokerMetaMap, v float64) []int {
+	var ids []int
+
+	for id, b := range bmm {
+		if b.MetricsIncomplete {
+			b.StorageFree = v
+			b.MetricsIncomplete = false
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Ints(ids)
+
+	return ids
+}
+
 // ensureBrokerMetrics takes a map of reference brokers and a map of discovered
 // broker metadata. Any non-missing brokers in the broker map must be present
 // in the broker metadata map and have a non-true MetricsIncomplete value.
@@ -102,6 +151,28 @@ func stripPendingDeletes(pm *kafkazk.PartitionMap, zk kafkazk.Handler) []string
 	return removeTopics(pm, re)
 }
 
+// stripReassigning takes a partition map and zk handler. It looks up any
+// topics with an in-progress reassignment and removes them from the provided
+// partition map, returning a list of topics removed. Plans for these topics
+// would conflict with the in-progress reassignment.
+func stripReassigning(pm *kafkazk.PartitionMap, zk kafkazk.Handler) []string {
+	reassigning := zk.GetReassignments()
+
+	if len(reassigning) == 0 {
+		return []string{}
+	}
+
+	// Convert to a series of literal regex.
+	var re []*regexp.Regexp
+	for topic := range reassigning {
+		r := regexp.MustCompile(fmt.Sprintf(`^%s$`, regexp.QuoteMeta(topic)))
+		re = append(re, r)
+	}
+
+	// Update the PartitionMap and return a list of removed topic names.
+	return removeTopics(pm, re)
+}
+
 // removeTopics takes a PartitionMap and []*regexp.Regexp of topic name patters.
 // Any topic names that match any provided pattern will be removed from the
 // PartitionMap and a []string of topics that were found and removed is returned.
@@ -60,3 +60,103 @@ func mergePartitionMaps(pms ...*kafkazk.PartitionMap) *kafkazk.PartitionMap {
 
 	return pm
 }
+
+func TestAssumeStorageFree(t *testing.T) {
+	zk := kafkazk.NewZooKeeperStub()
+	bmm, _ := zk.GetAllBrokerMeta(true)
+
+	// Simulate a newly added broker without metrics.
+	bmm[1007].StorageFree = 0
+	bmm[1007].MetricsIncomplete = true
+
+	ids := assumeStorageFree(bmm, 20000.00)
+
+	if len(ids) != 1 || ids[0] != 1007 {
+		t.Fatalf("Expected assumed brokers [1007], got %v", ids)
+	}
+
+	if bmm[1007].MetricsIncomplete {
+		t.Error("Expected MetricsIncomplete to be false")
+	}
+
+	// The broker should participate in placement with the assumed value.
+	bm := kafkazk.NewBrokerMap()
+	bm.Update([]int{1001, 1002, 1003, 1004, 1005, 1007}, bmm)
+
+	if bm[1007].StorageFree != 20000.00 {
+		t.Errorf("Expected StorageFree 20000.00, got %.2f", bm[1007].StorageFree)
+	}
+
+	pm, _ := zk.GetPartitionMap("test_topic")
+	pmm, _ := zk.GetAllPartitionMeta()
+
+	params := kafkazk.RebuildParams{
+		PMM:          pmm,
+		BM:           bm,
+		Strategy:     "storage",
+		Optimization: "storage",
+	}
+
+	// Replace broker 1003; its partitions should be placed on the broker
+	// with the most storage free.
+	bm[1003].Replace = true
+
+	out, errs := pm.Rebuild(params)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	var placed bool
+	for _, p := range out.Partitions {
+		for _, id := range p.Replicas {
+			if id == 1007 {
+				placed = true
+			}
+		}
+	}
+
+	if !placed {
+		t.Error("Expected broker 1007 to be assigned partitions")
+	}
+}
+
+func TestStripReassigning(t *testing.T) {
+	zk := kafkazk.NewZooKeeperStub()
+
+	// The stub reports an in-progress reassignment for reassigning_topic.
+	pm, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test_topic","partition":0,"replicas":[1001,1002]},
+		{"topic":"reassigning_topic","partition":0,"replicas":[1003,1000]},
+		{"topic":"reassigning_topic","partition":1,"replicas":[1005,1010]}]}`)
+
+	removed := stripReassigning(pm, zk)
+
+	if len(removed) != 1 || removed[0] != "reassigning_topic" {
+		t.Errorf("Expected reassigning_topic to be removed, got %v", removed)
+	}
+
+	if len(pm.Partitions) != 1 || pm.Partitions[0].Topic != "test_topic" {
+		t.Errorf("Expected only test_topic to remain, got %v", pm.Partitions)
+	}
+}
+
+func TestReserveStorageHeadroom(t *testing.T) {
+	zk := kafkazk.NewZooKeeperStub()
+	bmm, _ := zk.GetAllBrokerMeta(true)
+
+	reported := bmm[1001].StorageFree
+
+	reserveStorageHeadroom(bmm, 15)
+
+	if expected := reported * 0.85; bmm[1001].StorageFree != expected {
+		t.Errorf("Expected StorageFree %.2f, got %.2f", expected, bmm[1001].StorageFree)
+	}
+
+	// Placement uses the reduced value.
+	bm := kafkazk.NewBrokerMap()
+	bm.Update([]int{1001}, bmm)
+
+	if bm[1001].StorageFree != reported*0.85 {
+		t.Errorf("Expected placement StorageFree %.2f, got %.2f", reported*0.85, bm[1001].StorageFree)
+	}
+}
@@ -0,0 +1,241 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var newTopicCmd = &cobra.Command{
+	Use:   "new-topic",
+	Short: "Compute an initial partition map for a new topic",
+	Long: `new-topic computes an initial partition map for a topic that doesn't yet exist.
+The topic name, partition count and replication factor are provided via the --topic,
+--partitions and --replication flags, and target broker IDs via the --brokers flag.
+Partitions are placed using the same placement constraints as rebuild. The output map
+can be used as the replica assignment when creating the topic.`,
+	PreRun: loadConstraintsFile,
+	Run:    newTopic,
+}
+
+func init() {
+	rootCmd.AddCommand(newTopicCmd)
+
+	newTopicCmd.Flags().String("topic", "", "Name of the topic to create a map for")
+	newTopicCmd.Flags().Int("partitions", 0, "Topic partition count")
+	newTopicCmd.Flags().Int("replication", 0, "Topic replication factor")
+	newTopicCmd.Flags().String("constraints-file", "", "Path to a YAML or JSON file of placement constraints keyed by flag name (command-line flags take precedence)")
+	newTopicCmd.Flags().Bool("use-meta", true, "Use broker metadata in placement constraints")
+	newTopicCmd.Flags().String("out-path", "", "Path to write output map files to")
+	newTopicCmd.Flags().String("out-file", "", "If defined, write a combined map of all topics to a file")
+	newTopicCmd.Flags().Bool("sort-output", false, "Sort output map partitions by topic and partition number for stable, diffable output")
+	newTopicCmd.Flags().String("placement", "count", "Partition placement strategy: [count, storage]")
+	newTopicCmd.Flags().Int("min-rack-ids", 0, "Minimum number of required of unique rack IDs per replica set (0 requires that all are unique)")
+	newTopicCmd.Flags().String("capacity-weights", "", "Broker capacity weights for count placement; brokers are filled toward partition counts proportional to their weight (comma delim. list of id:weight, e.g. 1001:2,1002:1)")
+	newTopicCmd.Flags().Float64("partition-size", 0, "Estimated partition size in gigabytes (required when using storage placement)")
+	newTopicCmd.Flags().String("brokers", "", "Broker list to scope all partition placements to ('-2' for all brokers in cluster)")
+	newTopicCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics (when using storage placement)")
+	newTopicCmd.Flags().Int("metrics-age", 60, "Kafka metrics age tolerance (in minutes) (when using storage placement)")
+	newTopicCmd.Flags().Bool("optimize-leadership", false, "Balance broker leader/follower ratios")
+
+	// Required.
+	newTopicCmd.MarkFlagRequired("topic")
+	newTopicCmd.MarkFlagRequired("partitions")
+	newTopicCmd.MarkFlagRequired("replication")
+	newTopicCmd.MarkFlagRequired("brokers")
+}
+
+func newTopic(cmd *cobra.Command, _ []string) {
+	// Sanity check params.
+	t := cmd.Flag("topic").Value.String()
+	n, _ := cmd.Flags().GetInt("partitions")
+	r, _ := cmd.Flags().GetInt("replication")
+	p := cmd.Flag("placement").Value.String()
+	m, _ := cmd.Flags().GetBool("use-meta")
+	ps, _ := cmd.Flags().GetFloat64("partition-size")
+
+	switch {
+	case n <= 0:
+		fmt.Println("\n[ERROR] --partitions must be greater than 0")
+		defaultsAndExit()
+	case r <= 0:
+		fmt.Println("\n[ERROR] --replication must be greater than 0")
+		defaultsAndExit()
+	case p != "count" && p != "storage":
+		fmt.Println("\n[ERROR] --placement must be either 'count' or 'storage'")
+		defaultsAndExit()
+	case !m && p == "storage":
+		fmt.Println("\n[ERROR] --placement=storage requires --use-meta=true")
+		defaultsAndExit()
+	case p == "storage" && ps <= 0:
+		fmt.Println("\n[ERROR] --placement=storage requires --partition-size")
+		defaultsAndExit()
+	}
+
+	bootstrap(cmd)
+
+	// ZooKeeper init.
+	var zk kafkazk.Handler
+	if m {
+		var err error
+		zk, err = initZooKeeper(cmd)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		defer zk.Close()
+
+		// Ensure that the topic doesn't already exist.
+		existing, err := zk.GetTopics([]*regexp.Regexp{
+			regexp.MustCompile(fmt.Sprintf("^%s$", regexp.QuoteMeta(t))),
+		})
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if len(existing) > 0 {
+			fmt.Printf("\n[ERROR] topic %s already exists\n", t)
+			os.Exit(1)
+		}
+	}
+
+	// Fetch broker metadata.
+	var withMetrics bool
+	if p == "storage" {
+		checkMetaAge(cmd, zk)
+		withMetrics = true
+	}
+
+	var brokerMeta kafkazk.BrokerMetaMap
+	if m {
+		brokerMeta = getBrokerMeta(cmd, zk, withMetrics)
+	}
+
+	fmt.Printf("\nTopic:\n%s%s: %d partitions, replication factor %d\n", indent, t, n, r)
+
+	// Get a BrokerMap of the target brokers.
+	fmt.Printf("\nBroker change summary:\n")
+	brokers := kafkazk.NewBrokerMap()
+	bs, msgs := brokers.Update(Config.brokers, brokerMeta)
+	for msg := range msgs {
+		fmt.Printf("%s%s\n", indent, msg)
+	}
+
+	brokers.SetWeights(Config.capacityWeights)
+	brokersOrig := brokers.Copy()
+
+	if m {
+		ensureBrokerMetrics(cmd, brokers, brokerMeta)
+	}
+
+	// Get the usable target broker IDs.
+	var ids []int
+	for id, b := range brokers {
+		if !b.Replace {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Ints(ids)
+
+	if len(ids) < r {
+		fmt.Printf("\n[ERROR] %d target brokers is less than the replication factor of %d\n",
+			len(ids), r)
+		os.Exit(1)
+	}
+
+	var errs errors
+
+	// Build the map.
+	var partitionMeta kafkazk.PartitionMetaMap
+	if p == "storage" {
+		partitionMeta = newTopicPartitionMeta(t, n, ps*div)
+	}
+
+	mrrid, _ := cmd.Flags().GetInt("min-rack-ids")
+	partitionMap, rebuildErrs := newTopicMap(t, n, r, brokers, partitionMeta, p, mrrid)
+	errs = append(errs, rebuildErrs...)
+
+	// Optimize leaders.
+	if ol, _ := cmd.Flags().GetBool("optimize-leadership"); ol {
+		partitionMap.OptimizeLeaderFollower()
+	}
+
+	// Count missing brokers as a warning.
+	if bs.Missing > 0 {
+		errs = append(errs, fmt.Errorf("%d provided brokers not found in ZooKeeper", bs.Missing))
+	}
+
+	if bs.RackMissing > 0 {
+		errs = append(
+			errs, fmt.Errorf("%d provided broker(s) do(es) not have a rack.id defined", bs.RackMissing),
+		)
+	}
+
+	// Print the assignment.
+	fmt.Println("\nPartition map:")
+	for _, partn := range partitionMap.Partitions {
+		fmt.Printf("%s%s p%d: %v\n", indent, partn.Topic, partn.Partition, partn.Replicas)
+	}
+
+	fmt.Println("\nBroker distribution:")
+	for _, use := range partitionMap.UseStats().List() {
+		fmt.Printf("%sBroker %d - leader: %d, follower: %d, total: %d\n",
+			indent, use.ID, use.Leader, use.Follower, use.Leader+use.Follower)
+	}
+
+	printCapacityTargets(partitionMap, brokers)
+
+	if p == "storage" {
+		fmt.Println("\nStorage free change estimations:")
+		for _, id := range ids {
+			fmt.Printf("%sBroker %d: %.2f -> %.2f\n",
+				indent, id, brokersOrig[id].StorageFree/div, brokers[id].StorageFree/div)
+		}
+	}
+
+	// Print error/warnings.
+	handleOverridableErrs(cmd, errs)
+
+	writeMaps(cmd, partitionMap, nil)
+}
+
+// newTopicMap takes a topic name, partition count, replication factor, the
+// target kafkazk.BrokerMap, an optional kafkazk.PartitionMetaMap, a placement
+// strategy and the minimum number of unique rack IDs per replica set. An
+// initial *kafkazk.PartitionMap for the topic is returned.
+func newTopicMap(t string, n, r int, bm kafkazk.BrokerMap, pmm kafkazk.PartitionMetaMap, strategy string, minRackIDs int) (*kafkazk.PartitionMap, errors) {
+	// Create a stub map with the requested dimensions; all replicas
+	// are stub brokers that get replaced in the rebuild.
+	pm := kafkazk.NewPartitionMap(kafkazk.Populate(t, n, r))
+
+	rebuildParams := kafkazk.RebuildParams{
+		PMM:              pmm,
+		BM:               bm,
+		Strategy:         strategy,
+		Optimization:     "distribution",
+		PartnSzFactor:    1.0,
+		MinUniqueRackIDs: minRackIDs,
+	}
+
+	return pm.Rebuild(rebuildParams)
+}
+
+// newTopicPartitionMeta returns a kafkazk.PartitionMetaMap for a new topic t
+// with n partitions of an estimated size s in bytes.
+func newTopicPartitionMeta(t string, n int, s float64) kafkazk.PartitionMetaMap {
+	pmm := kafkazk.NewPartitionMetaMap()
+	pmm[t] = map[int]*kafkazk.PartitionMeta{}
+
+	for i := 0; i < n; i++ {
+		pmm[t][i] = &kafkazk.PartitionMeta{Size: s}
+	}
+
+	return pmm
+}
@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestNewTopicMap(t *testing.T) {
+	bmm := kafkazk.BrokerMetaMap{
+		1001: &kafkazk.BrokerMeta{Rack: "a"},
+		1002: &kafkazk.BrokerMeta{Rack: "a"},
+		1003: &kafkazk.BrokerMeta{Rack: "b"},
+		1004: &kafkazk.BrokerMeta{Rack: "b"},
+		1005: &kafkazk.BrokerMeta{Rack: "c"},
+		1006: &kafkazk.BrokerMeta{Rack: "c"},
+	}
+
+	bm := kafkazk.NewBrokerMap()
+	bm.Update([]int{1001, 1002, 1003, 1004, 1005, 1006}, bmm)
+
+	pm, errs := newTopicMap("test", 6, 3, bm, nil, "count", 0)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	if len(pm.Partitions) != 6 {
+		t.Fatalf("Expected 6 partitions, got %d", len(pm.Partitions))
+	}
+
+	for _, p := range pm.Partitions {
+		if len(p.Replicas) != 3 {
+			t.Errorf("p%d: expected 3 replicas, got %v", p.Partition, p.Replicas)
+			continue
+		}
+
+		racks := map[string]struct{}{}
+		for _, id := range p.Replicas {
+			if id == kafkazk.StubBrokerID {
+				t.Errorf("p%d: unexpected stub broker in %v", p.Partition, p.Replicas)
+			}
+			racks[bmm[id].Rack] = struct{}{}
+		}
+
+		if len(racks) != 3 {
+			t.Errorf("p%d: expected replicas in 3 racks, got %v", p.Partition, p.Replicas)
+		}
+	}
+
+	// Partitions should be spread evenly across brokers.
+	for _, use := range pm.UseStats().List() {
+		if use.Leader+use.Follower != 3 {
+			t.Errorf("Expected broker %d to hold 3 replicas, got %d", use.ID, use.Leader+use.Follower)
+		}
+	}
+}
+
+func TestNewTopicPartitionMeta(t *testing.T) {
+	pmm := newTopicPartitionMeta("test", 3, 1024)
+
+	for i := 0; i < 3; i++ {
+		if s := pmm["test"][i].Size; s != 1024 {
+			t.Errorf("p%d: expected size 1024, got %f", i, s)
+		}
+	}
+}
+
+func TestNewTopicMapCapacityWeights(t *testing.T) {
+	bmm := kafkazk.BrokerMetaMap{
+		1001: &kafkazk.BrokerMeta{Rack: "a"},
+		1002: &kafkazk.BrokerMeta{Rack: "b"},
+		1003: &kafkazk.BrokerMeta{Rack: "c"},
+		1004: &kafkazk.BrokerMeta{Rack: "d"},
+	}
+
+	bm := kafkazk.NewBrokerMap()
+	bm.Update([]int{1001, 1002, 1003, 1004}, bmm)
+
+	// 1001 has double the capacity of the other brokers.
+	bm.SetWeights(kafkazk.CapacityWeights{1001: 2})
+
+	pm, errs := newTopicMap("test", 30, 2, bm, nil, "count", 0)
+	if errs != nil {
+		t.Fatal(errs)
+	}
+
+	// 60 replicas over a total weight of 5.
+	targets := bm.TargetCounts(60)
+	expected := map[int]float64{1001: 24, 1002: 12, 1003: 12, 1004: 12}
+
+	for id, n := range expected {
+		if targets[id] != n {
+			t.Errorf("Expected broker %d target %.1f, got %.1f", id, n, targets[id])
+		}
+	}
+
+	counts := map[int]int{}
+	for _, use := range pm.UseStats().List() {
+		counts[use.ID] = use.Leader + use.Follower
+	}
+
+	// Each broker should land near its target; replica sets can't repeat a
+	// broker, so the 2x broker can fall slightly short.
+	for id, n := range expected {
+		if d := float64(counts[id]) - n; d < -2 || d > 2 {
+			t.Errorf("Expected broker %d to hold ~%.0f replicas, got %d", id, n, counts[id])
+		}
+	}
+
+	// The 2x broker should hold roughly double the replicas of each 1x broker.
+	for _, id := range []int{1002, 1003, 1004} {
+		ratio := float64(counts[1001]) / float64(counts[id])
+		if ratio < 1.5 || ratio > 2.5 {
+			t.Errorf("Expected broker 1001 to hold ~2x the replicas of broker %d, got %d and %d",
+				id, counts[1001], counts[id])
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// applyObserverRacks converts replicas to and from observers if the
+// --observer-racks flag is set: replicas in the specified racks become
+// observers and all others become sync replicas. An empty value promotes all
+// observers. If any partition would have fewer sync replicas than the topic's
+// min.insync.replicas, the partitions are printed and topicmappr exits. The
+// --min-isr flag provides the min.insync.replicas of topics without a value
+// in ZooKeeper.
+func applyObserverRacks(cmd *cobra.Command, zk kafkazk.Handler, pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap) {
+	if !cmd.Flags().Changed("observer-racks") {
+		return
+	}
+
+	racks := map[string]struct{}{}
+	for _, r := range strings.Split(cmd.Flag("observer-racks").Value.String(), ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			racks[r] = struct{}{}
+		}
+	}
+
+	setObserverRacks(pm, bm, racks)
+
+	// Static broker configs (server.properties) aren't stored in ZooKeeper.
+	var static map[string]string
+	if n, _ := cmd.Flags().GetInt("min-isr"); n > 0 {
+		static = map[string]string{"min.insync.replicas": strconv.Itoa(n)}
+	}
+
+	minISR, err := getMinISR(zk, pm.Topics(), static)
+	if err != nil {
+		fmt.Printf("\n[ERROR] --observer-racks: %s\n", err)
+		os.Exit(1)
+	}
+
+	violations := minISRViolations(pm, minISR)
+
+	if len(violations) > 0 {
+		fmt.Printf("\n[ERROR] --observer-racks: %d partitions would have fewer sync replicas than min.insync.replicas:\n",
+			len(violations))
+		for _, p := range violations {
+			fmt.Printf("%s%s p%d: %v (observers: %v, min.insync.replicas: %d)\n",
+				indent, p.Topic, p.Partition, p.Replicas, p.Observers, minISR[p.Topic])
+		}
+		os.Exit(1)
+	}
+}
+
+// setObserverRacks takes a *kafkazk.PartitionMap, a kafkazk.BrokerMap and a
+// set of rack IDs. The replicas of each partition on brokers in the racks are
+// made observers and all other replicas are made sync replicas. Observers are
+// moved after all sync replicas, with both keeping their relative order; the
+// preferred leader therefore changes only if it becomes an observer.
+func setObserverRacks(pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap, racks map[string]struct{}) {
+	for i, p := range pm.Partitions {
+		var sync, observers []int
+
+		for _, id := range p.Replicas {
+			if b, exists := bm[id]; exists {
+				if _, observer := racks[b.Locality]; observer {
+					observers = append(observers, id)
+					continue
+				}
+			}
+			sync = append(sync, id)
+		}
+
+		pm.Partitions[i].Replicas = append(sync, observers...)
+		pm.Partitions[i].Observers = observers
+	}
+}
+
+// getMinISR returns a mapping of topic name to the effective
+// min.insync.replicas for each of the topics, taking the static broker
+// configs as the lowest precedence default. The value set on the brokers
+// can't otherwise be known, so an error is returned for topics without a
+// topic override, dynamic cluster default or static config rather than
+// assuming the Kafka default of 1.
+func getMinISR(zk kafkazk.Handler, topics []string, static map[string]string) (map[string]int, error) {
+	minISR := map[string]int{}
+	var unknown []string
+
+	for _, t := range topics {
+		tc, err := zk.GetEffectiveTopicConfig(t, static)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get min.insync.replicas for %s: %s", t, err)
+		}
+
+		v, exists := tc.Config["min.insync.replicas"]
+		if !exists {
+			unknown = append(unknown, t)
+			continue
+		}
+
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min.insync.replicas for %s: %s", t, v)
+		}
+		minISR[t] = n
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("no min.insync.replicas found in ZooKeeper for topics %s; set --min-isr to the brokers' configured value",
+			strings.Join(unknown, ", "))
+	}
+
+	return minISR, nil
+}
+
+// minISRViolations takes a *kafkazk.PartitionMap and a mapping of topic name
+// to min.insync.replicas and returns all partitions with fewer sync replicas
+// than the topic's min.insync.replicas.
+func minISRViolations(pm *kafkazk.PartitionMap, minISR map[string]int) []kafkazk.Partition {
+	var violations []kafkazk.Partition
+
+	for _, p := range pm.Partitions {
+		if len(p.SyncReplicas()) < minISR[p.Topic] {
+			violations = append(violations, p)
+		}
+	}
+
+	return violations
+}
+
+// observerChanges takes a before and after kafkazk.Partition and returns a
+// description of replicas promoted from or demoted to observers.
+func observerChanges(p1, p2 kafkazk.Partition) string {
+	var promoted, demoted []string
+
+	for _, id := range p1.Observers {
+		if p2.IsReplica(id) && !p2.IsObserver(id) {
+			promoted = append(promoted, strconv.Itoa(id))
+		}
+	}
+
+	for _, id := range p2.Observers {
+		if p1.IsReplica(id) && !p1.IsObserver(id) {
+			demoted = append(demoted, strconv.Itoa(id))
+		}
+	}
+
+	var changes []string
+	if len(promoted) > 0 {
+		changes = append(changes, fmt.Sprintf("promoted observer %s", strings.Join(promoted, ",")))
+	}
+	if len(demoted) > 0 {
+		changes = append(changes, fmt.Sprintf("demoted %s to observer", strings.Join(demoted, ",")))
+	}
+
+	return strings.Join(changes, ", ")
+}
@@ -0,0 +1,98 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestSetObserverRacks(t *testing.T) {
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, Locality: "a"},
+		1002: &kafkazk.Broker{ID: 1002, Locality: "b"},
+		1003: &kafkazk.Broker{ID: 1003, Locality: "c"},
+		1004: &kafkazk.Broker{ID: 1004, Locality: "c"},
+	}
+
+	pm := kafkazk.NewPartitionMap()
+	pm.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002, 1003}, Observers: []int{1003}},
+		{Topic: "test", Partition: 1, Replicas: []int{1002, 1004, 1001}, Observers: []int{1001}},
+	}
+
+	original := pm.Copy()
+
+	// Promote all observers in rack a; 1003 and 1004 in rack c remain or
+	// become observers.
+	setObserverRacks(pm, bm, map[string]struct{}{"c": {}})
+
+	expected := []struct {
+		replicas, observers []int
+	}{
+		{[]int{1001, 1002, 1003}, []int{1003}},
+		{[]int{1002, 1001, 1004}, []int{1004}},
+	}
+
+	for i, p := range pm.Partitions {
+		if !replicasEqual(p.Replicas, expected[i].replicas) {
+			t.Errorf("p%d: expected replicas %v, got %v", i, expected[i].replicas, p.Replicas)
+		}
+		if !replicasEqual(p.Observers, expected[i].observers) {
+			t.Errorf("p%d: expected observers %v, got %v", i, expected[i].observers, p.Observers)
+		}
+	}
+
+	if c := observerChanges(original.Partitions[0], pm.Partitions[0]); c != "" {
+		t.Errorf("Expected no p0 observer changes, got '%s'", c)
+	}
+
+	if c := observerChanges(original.Partitions[1], pm.Partitions[1]); c != "promoted observer 1001, demoted 1004 to observer" {
+		t.Errorf("Unexpected p1 observer changes '%s'", c)
+	}
+}
+
+func TestMinISRViolations(t *testing.T) {
+	pm := kafkazk.NewPartitionMap()
+	pm.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002, 1003}, Observers: []int{1003}},
+		{Topic: "test", Partition: 1, Replicas: []int{1002, 1003, 1001}, Observers: []int{1003, 1001}},
+		{Topic: "other", Partition: 0, Replicas: []int{1001, 1002}, Observers: []int{1002}},
+	}
+
+	violations := minISRViolations(pm, map[string]int{"test": 2, "other": 1})
+
+	if len(violations) != 1 || violations[0].Topic != "test" || violations[0].Partition != 1 {
+		t.Errorf("Expected only test p1 to violate min.insync.replicas, got %v", violations)
+	}
+}
+
+func TestGetMinISR(t *testing.T) {
+	zk := kafkazk.NewZooKeeperStub()
+
+	// No min.insync.replicas in ZooKeeper.
+	if _, err := getMinISR(zk, []string{"test_topic"}, nil); err == nil {
+		t.Error("Expected an error for a topic without min.insync.replicas")
+	}
+
+	// The static broker config is used as the default.
+	minISR, err := getMinISR(zk, []string{"test_topic"}, map[string]string{"min.insync.replicas": "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if minISR["test_topic"] != 2 {
+		t.Errorf("Expected min.insync.replicas 2, got %d", minISR["test_topic"])
+	}
+
+	// The dynamic cluster default takes precedence.
+	zk.Set("/config/brokers/<default>", `{"version":1,"config":{"min.insync.replicas":"3"}}`)
+
+	minISR, err = getMinISR(zk, []string{"test_topic"}, map[string]string{"min.insync.replicas": "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if minISR["test_topic"] != 3 {
+		t.Errorf("Expected min.insync.replicas 3, got %d", minISR["test_topic"])
+	}
+}
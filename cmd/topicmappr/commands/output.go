@@ -2,7 +2,9 @@ package commands
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"math"
 	"os"
 	"sort"
@@ -49,9 +51,25 @@ func printExcludedTopics(p []string, e []string) {
 	}
 }
 
-// printMapChanges takes the original input PartitionMap and the final output
-// PartitionMap and prints what's changed.
-func printMapChanges(pm1, pm2 *kafkazk.PartitionMap) {
+// printReassigningTopics takes a []string of topics excluded due to
+// in-progress reassignments and prints a warning listing them.
+func printReassigningTopics(r []string) {
+	if len(r) == 0 {
+		return
+	}
+
+	sort.Strings(r)
+	fmt.Printf("\n[WARN] topics excluded due to in-progress reassignments:\n")
+	for _, t := range r {
+		fmt.Printf("%s%s\n", indent, t)
+	}
+}
+
+// printMapChanges takes the original input PartitionMap, the final output
+// PartitionMap and an optional PartitionMetaMap and prints what's changed.
+// Partitions gaining replicas are annotated with the estimated move size, or
+// as unknown if the partition size isn't available.
+func printMapChanges(pm1, pm2 *kafkazk.PartitionMap, pmm kafkazk.PartitionMetaMap) {
 	// Ensure the topic name and partition order match.
 	for i := range pm1.Partitions {
 		t1, t2 := pm1.Partitions[i].Topic, pm2.Partitions[i].Topic
@@ -68,6 +86,22 @@ func printMapChanges(pm1, pm2 *kafkazk.PartitionMap) {
 		change := whatChanged(pm1.Partitions[i].Replicas,
 			pm2.Partitions[i].Replicas)
 
+		// Describe observer conversions.
+		if oc := observerChanges(pm1.Partitions[i], pm2.Partitions[i]); oc != "" {
+			if change == "no-op" {
+				change = oc
+			} else {
+				change += ", " + oc
+			}
+		}
+
+		// Annotate the estimated move size.
+		if b, ok := moveBytes(pm1.Partitions[i], pm2.Partitions[i], pmm); !ok {
+			change += ", move size unknown"
+		} else if b > 0 {
+			change += fmt.Sprintf(", move size %.2fGB", b/div)
+		}
+
 		fmt.Printf("%s%s p%d: %v -> %v %s\n",
 			indent,
 			pm1.Partitions[i].Topic,
@@ -107,7 +141,7 @@ func printBrokerAssignmentStats(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionM
 	case
 		cmd.Name() == "scale",
 		cmd.Name() == "rebalance",
-		cmd.Flag("placement").Value.String() == "storage":
+		storagePlacement(cmd.Flag("placement").Value.String()):
 
 		fmt.Println("\nStorage free change estimations:")
 		if psf != 1.0 && cmd.Name() != "rebalance" {
@@ -232,30 +266,58 @@ func writeMaps(cmd *cobra.Command, pm *kafkazk.PartitionMap, phasedPM *kafkazk.P
 	}
 
 	// If we've been provided a phased output map.
-	var phaseSuffix [2]string
 	if phasedPM != nil {
-		phaseSuffix[0] = "-phase1"
-		phaseSuffix[1] = "-phase2"
+		writeMapSet(cmd, []*kafkazk.PartitionMap{phasedPM, pm}, []string{"-phase1", "-phase2"})
+		return
 	}
 
+	writeMapSet(cmd, []*kafkazk.PartitionMap{pm}, []string{""})
+}
+
+// writeLeaderMoveBatches takes the original and updated PartitionMap and
+// writes out the updated map split into batches of at most max preferred
+// leader changes. Batches are written with a -batchN suffix and are intended
+// to be applied in order.
+func writeLeaderMoveBatches(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap, max int) {
+	if len(pm2.Partitions) == 0 {
+		fmt.Println("\nNo partition reassignments, skipping map generation")
+		return
+	}
+
+	batches := leaderMoveBatches(pm1, pm2, max)
+
+	var suffixes []string
+	for i := range batches {
+		suffixes = append(suffixes, fmt.Sprintf("-batch%d", i+1))
+	}
+
+	writeMapSet(cmd, batches, suffixes)
+}
+
+// writeMapSet takes a []*kafkazk.PartitionMap and a file name suffix for
+// each, and writes out per-topic and, if configured, combined map files.
+func writeMapSet(cmd *cobra.Command, maps []*kafkazk.PartitionMap, suffixes []string) {
 	outPath := cmd.Flag("out-path").Value.String()
 	outFile := cmd.Flag("out-file").Value.String()
 
+	// Sort output if configured.
+	if so, _ := cmd.Flags().GetBool("sort-output"); so {
+		sorted := make([]*kafkazk.PartitionMap, len(maps))
+		for i := range maps {
+			sorted[i] = sortedPartitionMap(maps[i])
+		}
+		maps = sorted
+	}
+
 	// Break map up by topic.
 	tm := map[string]*kafkazk.PartitionMap{}
 
-	outputMaps := []*kafkazk.PartitionMap{phasedPM, pm}
-
 	// For each map type, create per-topic maps.
-	for i, m := range outputMaps {
-		// We may not have a phasedPM.
-		if m == nil {
-			continue
-		}
+	for i, m := range maps {
 		// Populate each partition in the parent map keyed
-		// by topic name and possible phase suffix.
+		// by topic name and possible suffix.
 		for _, p := range m.Partitions {
-			mapName := fmt.Sprintf("%s%s", p.Topic, phaseSuffix[i])
+			mapName := fmt.Sprintf("%s%s", p.Topic, suffixes[i])
 			if tm[mapName] == nil {
 				tm[mapName] = kafkazk.NewPartitionMap()
 			}
@@ -267,12 +329,8 @@ func writeMaps(cmd *cobra.Command, pm *kafkazk.PartitionMap, phasedPM *kafkazk.P
 
 	// Write global map if set.
 	if outFile != "" {
-		for i, m := range outputMaps {
-			if m == nil {
-				continue
-			}
-
-			fullPath := fmt.Sprintf("%s%s%s", outPath, outFile, phaseSuffix[i])
+		for i, m := range maps {
+			fullPath := fmt.Sprintf("%s%s%s", outPath, outFile, suffixes[i])
 			err := kafkazk.WriteMap(m, fullPath)
 			if err != nil {
 				fmt.Printf("%s%s", indent, err)
@@ -282,8 +340,15 @@ func writeMaps(cmd *cobra.Command, pm *kafkazk.PartitionMap, phasedPM *kafkazk.P
 		}
 	}
 
-	// Write per-topic maps.
+	// Write per-topic maps in name order.
+	var names []string
 	for t := range tm {
+		names = append(names, t)
+	}
+
+	sort.Strings(names)
+
+	for _, t := range names {
 		err := kafkazk.WriteMap(tm[t], outPath+t)
 		if err != nil {
 			fmt.Printf("%s%s", indent, err)
@@ -293,6 +358,50 @@ func writeMaps(cmd *cobra.Command, pm *kafkazk.PartitionMap, phasedPM *kafkazk.P
 	}
 }
 
+// sortedPartitionMap returns a copy of the provided *kafkazk.PartitionMap with
+// partitions sorted by topic name then partition number.
+func sortedPartitionMap(pm *kafkazk.PartitionMap) *kafkazk.PartitionMap {
+	sorted := pm.Copy()
+	sort.Sort(sorted.Partitions)
+
+	return sorted
+}
+
+// leaderMoveBatches takes the original and updated PartitionMap and splits
+// the updated map into batches that each include at most max preferred leader
+// changes. All partition changes that don't change the preferred leader are
+// included in the first batch. Applying all batches in order converges to the
+// updated PartitionMap.
+func leaderMoveBatches(pm1, pm2 *kafkazk.PartitionMap, max int) []*kafkazk.PartitionMap {
+	batches := []*kafkazk.PartitionMap{kafkazk.NewPartitionMap()}
+	var leaderMoves kafkazk.PartitionList
+
+	original := partitionIndex(pm1)
+
+	for _, p2 := range pm2.Partitions {
+		p1, exists := original[p2.Topic][p2.Partition]
+		if exists && len(p1.Replicas) > 0 && len(p2.Replicas) > 0 && p1.Replicas[0] != p2.Replicas[0] {
+			leaderMoves = append(leaderMoves, p2)
+			continue
+		}
+		batches[0].Partitions = append(batches[0].Partitions, p2)
+	}
+
+	for i, p := range leaderMoves {
+		if i > 0 && i%max == 0 {
+			batches = append(batches, kafkazk.NewPartitionMap())
+		}
+		b := batches[len(batches)-1]
+		b.Partitions = append(b.Partitions, p)
+	}
+
+	for _, b := range batches {
+		sort.Sort(b.Partitions)
+	}
+
+	return batches
+}
+
 func printReassignmentParams(cmd *cobra.Command, results []reassignmentBundle, brokers kafkazk.BrokerMap, tol float64) {
 	subCmd := cmd.Name()
 
@@ -348,6 +457,47 @@ func printPlannedRelocations(targets []int, relos map[int][]relocation, pmm kafk
 	fmt.Printf("%sTotal relocation volume: %.2fGB\n", indent, total)
 }
 
+// printCapacityTargets takes a PartitionMap and BrokerMap and, if broker
+// capacity weights are configured, prints each broker's replica count
+// alongside its weighted target count.
+func printCapacityTargets(pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap) {
+	if Config.capacityWeights == nil {
+		return
+	}
+
+	use := pm.UseStats()
+
+	var total int
+	for _, u := range use {
+		total += u.Leader + u.Follower
+	}
+
+	targets := bm.TargetCounts(total)
+
+	var ids []int
+	for id := range targets {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	fmt.Println("\nBroker capacity targets:")
+	for _, id := range ids {
+		var n int
+		if u, ok := use[id]; ok {
+			n = u.Leader + u.Follower
+		}
+
+		w := bm[id].Weight
+		if w == 0 {
+			w = 1
+		}
+
+		fmt.Printf("%sBroker %d (weight %.2f): %d partitions, target %.1f\n",
+			indent, id, w, n, targets[id])
+	}
+}
+
 // handleOverridableErrs handles errors that can be optionally ignored by the
 // user (hence being referred to as 'WARN' in the CLI). If --ignore-warns is
 // false (default), any errors passed here will cause an exit(1).
@@ -454,3 +604,393 @@ func whatChanged(s1 []int, s2 []int) string {
 
 	return buf.String()
 }
+
+// publishReassignmentScope takes an original and updated PartitionMap and, if
+// the --publish-scope flag is set, writes the topics and brokers participating
+// in the reassignment to the specified znode. This allows autothrottle to
+// target throttles at the published reassignment participants.
+func publishReassignmentScope(cmd *cobra.Command, zk kafkazk.Handler, pm1, pm2 *kafkazk.PartitionMap) {
+	p := cmd.Flag("publish-scope").Value.String()
+	if p == "" || len(pm2.Partitions) == 0 {
+		return
+	}
+
+	scope := kafkazk.NewReassignmentScope(pm1, pm2)
+
+	if err := kafkazk.SetReassignmentScope(zk, p, scope); err != nil {
+		fmt.Printf("\n[ERROR] failed to publish reassignment scope: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nReassignment scope published to %s:\n", p)
+	fmt.Printf("%stopics: %v\n", indent, scope.Topics)
+	fmt.Printf("%sbrokers: %v\n", indent, scope.Brokers)
+}
+
+// brokerSummary holds before/after metrics for a broker in a plan.
+type brokerSummary struct {
+	BrokerID            int     `json:"broker_id"`
+	PartitionsBefore    int     `json:"partitions_before"`
+	PartitionsAfter     int     `json:"partitions_after"`
+	LeadersBefore       int     `json:"leaders_before"`
+	LeadersAfter        int     `json:"leaders_after"`
+	StorageFreeBeforeGB float64 `json:"storage_free_before_gb"`
+	StorageFreeAfterGB  float64 `json:"storage_free_after_gb"`
+}
+
+// planSummary takes the original and updated PartitionMap and BrokerMap and
+// returns a []brokerSummary with one entry per broker, sorted by ID.
+func planSummary(pm1, pm2 *kafkazk.PartitionMap, bm1, bm2 kafkazk.BrokerMap) []brokerSummary {
+	use1, use2 := pm1.UseStats(), pm2.UseStats()
+
+	// Get all referenced broker IDs.
+	ids := map[int]struct{}{}
+	for _, u := range []kafkazk.BrokerUseStatsMap{use1, use2} {
+		for id := range u {
+			ids[id] = struct{}{}
+		}
+	}
+
+	for _, bm := range []kafkazk.BrokerMap{bm1, bm2} {
+		for id := range bm {
+			ids[id] = struct{}{}
+		}
+	}
+
+	delete(ids, kafkazk.StubBrokerID)
+
+	summary := []brokerSummary{}
+	for id := range ids {
+		s := brokerSummary{BrokerID: id}
+
+		if u, ok := use1[id]; ok {
+			s.PartitionsBefore = u.Leader + u.Follower
+			s.LeadersBefore = u.Leader
+		}
+
+		if u, ok := use2[id]; ok {
+			s.PartitionsAfter = u.Leader + u.Follower
+			s.LeadersAfter = u.Leader
+		}
+
+		if b, ok := bm1[id]; ok {
+			s.StorageFreeBeforeGB = b.StorageFree / div
+		}
+
+		if b, ok := bm2[id]; ok {
+			s.StorageFreeAfterGB = b.StorageFree / div
+		}
+
+		summary = append(summary, s)
+	}
+
+	sort.Slice(summary, func(i, j int) bool {
+		return summary[i].BrokerID < summary[j].BrokerID
+	})
+
+	return summary
+}
+
+// writePlanSummary writes the planSummary as a flat JSON array to the file
+// specified by --summary-out, if set. The format is intended for direct
+// ingestion into Grafana table and timeseries panels.
+func writePlanSummary(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap, bm1, bm2 kafkazk.BrokerMap) {
+	p := cmd.Flag("summary-out").Value.String()
+	if p == "" {
+		return
+	}
+
+	out, err := json.Marshal(planSummary(pm1, pm2, bm1, bm2))
+	if err != nil {
+		fmt.Printf("\n[ERROR] failed to build plan summary: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := ioutil.WriteFile(p, out, 0644); err != nil {
+		fmt.Printf("\n[ERROR] failed to write plan summary: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nPlan summary written to %s\n", p)
+}
+
+// movePriority holds the priority score for a partition move.
+type movePriority struct {
+	Topic     string `json:"topic"`
+	Partition int    `json:"partition"`
+	// The brokers being removed from and added to the replica set.
+	From []int `json:"from"`
+	To   []int `json:"to"`
+	// StorageRelief scores the relief provided to full brokers being removed
+	// from the replica set.
+	StorageRelief float64 `json:"storage_relief"`
+	// ReplicaRepair is the number of replicas moved off of brokers that are
+	// missing from ZooKeeper or offline.
+	ReplicaRepair int     `json:"replica_repair"`
+	Priority      float64 `json:"priority"`
+	// MoveBytes is the estimated bytes replicated by the move, or nil if the
+	// partition size isn't available.
+	MoveBytes *float64 `json:"move_bytes"`
+}
+
+// movePriorities takes the original and updated PartitionMap, the original
+// BrokerMap and an optional PartitionMetaMap and returns a []movePriority for
+// each partition with replica set membership changes, sorted by descending
+// priority. Leadership-only changes aren't included.
+//
+// Each broker removed from a replica set contributes its fullness to the
+// storage relief score, where fullness is 1 minus the ratio of its free
+// storage to the highest free storage among all brokers. If partition sizes
+// are available, the contribution is weighted by the partition size relative
+// to the largest moved partition. Storage relief for a single replica is at
+// most 1, while each replica repaired adds 1 to the priority so that moves
+// restoring replicas generally rank above those that only relieve storage.
+func movePriorities(pm1, pm2 *kafkazk.PartitionMap, bm kafkazk.BrokerMap, pmm kafkazk.PartitionMetaMap) []movePriority {
+	var maxFree float64
+	for id, b := range bm {
+		if id != kafkazk.StubBrokerID && b.StorageFree > maxFree {
+			maxFree = b.StorageFree
+		}
+	}
+
+	fullness := func(id int) float64 {
+		b, exists := bm[id]
+		if !exists || maxFree <= 0 || b.StorageFree <= 0 {
+			return 0
+		}
+		return 1 - b.StorageFree/maxFree
+	}
+
+	// Index the updated map; the maps may not share an ordering.
+	updated := map[string]map[int]kafkazk.Partition{}
+	for _, p := range pm2.Partitions {
+		if updated[p.Topic] == nil {
+			updated[p.Topic] = map[int]kafkazk.Partition{}
+		}
+		updated[p.Topic][p.Partition] = p
+	}
+
+	var moves []movePriority
+	var sizes []float64
+	var maxSize float64
+
+	for _, p1 := range pm1.Partitions {
+		p2, exists := updated[p1.Topic][p1.Partition]
+		if !exists {
+			continue
+		}
+
+		m := movePriority{
+			Topic:     p1.Topic,
+			Partition: p1.Partition,
+			From:      []int{},
+			To:        []int{},
+		}
+
+		for _, id := range p1.Replicas {
+			if !inReplicas(id, p2.Replicas) {
+				m.From = append(m.From, id)
+			}
+		}
+
+		for _, id := range p2.Replicas {
+			if !inReplicas(id, p1.Replicas) {
+				m.To = append(m.To, id)
+			}
+		}
+
+		if len(m.From) == 0 && len(m.To) == 0 {
+			continue
+		}
+
+		for _, id := range m.From {
+			if b, exists := bm[id]; !exists || b.Missing {
+				m.ReplicaRepair++
+			}
+		}
+
+		var size float64
+		if pmm != nil {
+			if s, err := pmm.Size(p1); err == nil {
+				size = s
+				b := size * float64(len(m.To))
+				m.MoveBytes = &b
+			}
+		}
+
+		if size > maxSize {
+			maxSize = size
+		}
+
+		moves = append(moves, m)
+		sizes = append(sizes, size)
+	}
+
+	for i := range moves {
+		weight := 1.0
+		if maxSize > 0 {
+			weight = sizes[i] / maxSize
+		}
+
+		for _, id := range moves[i].From {
+			moves[i].StorageRelief += fullness(id) * weight
+		}
+
+		moves[i].Priority = moves[i].StorageRelief + float64(moves[i].ReplicaRepair)
+	}
+
+	sort.SliceStable(moves, func(i, j int) bool {
+		if moves[i].Priority != moves[j].Priority {
+			return moves[i].Priority > moves[j].Priority
+		}
+		if moves[i].Topic != moves[j].Topic {
+			return moves[i].Topic < moves[j].Topic
+		}
+		return moves[i].Partition < moves[j].Partition
+	})
+
+	return moves
+}
+
+// moveBytes takes the original and updated kafkazk.Partition and an optional
+// PartitionMetaMap and returns the estimated bytes replicated by the move: the
+// partition size multiplied by the number of replicas being added. False is
+// returned if replicas are being added and the partition size isn't
+// available.
+func moveBytes(p1, p2 kafkazk.Partition, pmm kafkazk.PartitionMetaMap) (float64, bool) {
+	var added int
+	for _, id := range p2.Replicas {
+		if !inReplicas(id, p1.Replicas) {
+			added++
+		}
+	}
+
+	if added == 0 {
+		return 0, true
+	}
+
+	if pmm == nil {
+		return 0, false
+	}
+
+	size, err := pmm.Size(p1)
+	if err != nil {
+		return 0, false
+	}
+
+	return size * float64(added), true
+}
+
+// inReplicas returns whether the broker ID is in the replica set.
+func inReplicas(id int, replicas []int) bool {
+	for _, r := range replicas {
+		if r == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// writeMovePriorities writes the movePriorities as a JSON array to the file
+// specified by --priority-out, if set.
+func writeMovePriorities(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap, bm kafkazk.BrokerMap, pmm kafkazk.PartitionMetaMap) {
+	p := cmd.Flag("priority-out").Value.String()
+	if p == "" {
+		return
+	}
+
+	out, err := json.Marshal(movePriorities(pm1, pm2, bm, pmm))
+	if err != nil {
+		fmt.Printf("\n[ERROR] failed to build move priorities: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := ioutil.WriteFile(p, out, 0644); err != nil {
+		fmt.Printf("\n[ERROR] failed to write move priorities: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nMove priorities written to %s\n", p)
+}
+
+// topicThrottles holds the throttled replica lists for a topic in the format
+// used by the Kafka topic configs of the same names.
+type topicThrottles struct {
+	Leaders   string `json:"leader.replication.throttled.replicas"`
+	Followers string `json:"follower.replication.throttled.replicas"`
+}
+
+// throttledReplicas takes the original and updated PartitionMap and returns
+// the topicThrottles implied by the reassignment for each topic with replicas
+// being added. As with the Kafka reassignment tooling, every existing replica
+// of a moved partition is leader throttled since any may be the leader
+// sourcing data, and each replica being added is follower throttled.
+func throttledReplicas(pm1, pm2 *kafkazk.PartitionMap) map[string]topicThrottles {
+	// Index the original map; the maps may not share an ordering.
+	original := map[string]map[int][]int{}
+	for _, p := range pm1.Partitions {
+		if original[p.Topic] == nil {
+			original[p.Topic] = map[int][]int{}
+		}
+		original[p.Topic][p.Partition] = p.Replicas
+	}
+
+	leaders, followers := map[string][]string{}, map[string][]string{}
+
+	for _, p := range pm2.Partitions {
+		replicas, exists := original[p.Topic][p.Partition]
+		if !exists {
+			continue
+		}
+
+		var added []string
+		for _, id := range p.Replicas {
+			if !inReplicas(id, replicas) {
+				added = append(added, fmt.Sprintf("%d:%d", p.Partition, id))
+			}
+		}
+
+		// Partitions without new replicas don't transfer any data.
+		if len(added) == 0 {
+			continue
+		}
+
+		for _, id := range replicas {
+			leaders[p.Topic] = append(leaders[p.Topic], fmt.Sprintf("%d:%d", p.Partition, id))
+		}
+		followers[p.Topic] = append(followers[p.Topic], added...)
+	}
+
+	throttles := map[string]topicThrottles{}
+	for t := range leaders {
+		throttles[t] = topicThrottles{
+			Leaders:   strings.Join(leaders[t], ","),
+			Followers: strings.Join(followers[t], ","),
+		}
+	}
+
+	return throttles
+}
+
+// writeThrottledReplicas writes the throttledReplicas as a JSON object keyed
+// by topic to the file specified by --throttles-out, if set.
+func writeThrottledReplicas(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap) {
+	p := cmd.Flag("throttles-out").Value.String()
+	if p == "" {
+		return
+	}
+
+	out, err := json.Marshal(throttledReplicas(pm1, pm2))
+	if err != nil {
+		fmt.Printf("\n[ERROR] failed to build throttled replica lists: %s\n", err)
+		os.Exit(1)
+	}
+
+	if err := ioutil.WriteFile(p, out, 0644); err != nil {
+		fmt.Printf("\n[ERROR] failed to write throttled replica lists: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nThrottled replica lists written to %s\n", p)
+}
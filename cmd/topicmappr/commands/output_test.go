@@ -1,7 +1,11 @@
 package commands
 
 import (
+	"encoding/json"
+	"reflect"
 	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
 )
 
 func TestWhatChanged(t *testing.T) {
@@ -32,3 +36,353 @@ func TestWhatChanged(t *testing.T) {
 		}
 	}
 }
+
+func TestPlanSummary(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1002]},
+		{"topic":"test","partition":1,"replicas":[1002,1001]}]}`)
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1003]},
+		{"topic":"test","partition":1,"replicas":[1003,1001]}]}`)
+
+	bm1 := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, StorageFree: 100 * div},
+		1002: &kafkazk.Broker{ID: 1002, StorageFree: 50 * div},
+	}
+	bm2 := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, StorageFree: 100 * div},
+		1002: &kafkazk.Broker{ID: 1002, StorageFree: 60 * div},
+		1003: &kafkazk.Broker{ID: 1003, StorageFree: 90 * div},
+	}
+
+	out, err := json.Marshal(planSummary(pm1, pm2, bm1, bm2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var summary []map[string]float64
+	if err := json.Unmarshal(out, &summary); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []map[string]float64{
+		{"broker_id": 1001, "partitions_before": 2, "partitions_after": 2, "leaders_before": 1,
+			"leaders_after": 1, "storage_free_before_gb": 100, "storage_free_after_gb": 100},
+		{"broker_id": 1002, "partitions_before": 2, "partitions_after": 0, "leaders_before": 1,
+			"leaders_after": 0, "storage_free_before_gb": 50, "storage_free_after_gb": 60},
+		{"broker_id": 1003, "partitions_before": 0, "partitions_after": 2, "leaders_before": 0,
+			"leaders_after": 1, "storage_free_before_gb": 0, "storage_free_after_gb": 90},
+	}
+
+	if len(summary) != len(expected) {
+		t.Fatalf("Expected %d entries, got %d", len(expected), len(summary))
+	}
+
+	for i, e := range expected {
+		if len(summary[i]) != len(e) {
+			t.Errorf("Expected %d fields, got %d", len(e), len(summary[i]))
+		}
+
+		for k, v := range e {
+			got, ok := summary[i][k]
+			if !ok {
+				t.Errorf("Expected field %s in entry %d", k, i)
+				continue
+			}
+
+			if got != v {
+				t.Errorf("[broker %v] Expected %s %v, got %v", e["broker_id"], k, v, got)
+			}
+		}
+	}
+}
+
+func TestMovePriorities(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1002]},
+		{"topic":"test","partition":1,"replicas":[1003,1002]},
+		{"topic":"test","partition":2,"replicas":[1004,1002]},
+		{"topic":"test","partition":3,"replicas":[1002,1003]}]}`)
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1005,1002]},
+		{"topic":"test","partition":1,"replicas":[1005,1002]},
+		{"topic":"test","partition":2,"replicas":[1005,1002]},
+		{"topic":"test","partition":3,"replicas":[1003,1002]}]}`)
+
+	// 1001 is the fullest broker, 1003 is near-empty and 1004 is missing.
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, StorageFree: 10 * div},
+		1002: &kafkazk.Broker{ID: 1002, StorageFree: 50 * div},
+		1003: &kafkazk.Broker{ID: 1003, StorageFree: 95 * div},
+		1004: &kafkazk.Broker{ID: 1004, Missing: true},
+		1005: &kafkazk.Broker{ID: 1005, StorageFree: 100 * div},
+	}
+
+	moves := movePriorities(pm1, pm2, bm, nil)
+
+	// p3 only changes leadership.
+	if len(moves) != 3 {
+		t.Fatalf("Expected 3 moves, got %d", len(moves))
+	}
+
+	// The replica repair ranks first, followed by the move off of the
+	// fullest broker.
+	expected := []int{2, 0, 1}
+	for i, m := range moves {
+		if m.Partition != expected[i] {
+			t.Errorf("Expected partition %d at position %d, got %d", expected[i], i, m.Partition)
+		}
+	}
+
+	p0, p1 := moves[1], moves[2]
+	if p0.Priority <= p1.Priority {
+		t.Errorf("Expected p0 priority %f to exceed p1 priority %f", p0.Priority, p1.Priority)
+	}
+
+	if p0.From[0] != 1001 || p0.To[0] != 1005 {
+		t.Errorf("Unexpected p0 move %v -> %v", p0.From, p0.To)
+	}
+
+	if moves[0].ReplicaRepair != 1 {
+		t.Errorf("Expected p2 replica repair of 1, got %d", moves[0].ReplicaRepair)
+	}
+
+	// Partition sizes weight storage relief.
+	pmm := kafkazk.NewPartitionMetaMap()
+	pmm["test"] = map[int]*kafkazk.PartitionMeta{
+		0: {Size: 10},
+		1: {Size: 1000},
+		2: {Size: 10},
+		3: {Size: 10},
+	}
+
+	moves = movePriorities(pm1, pm2, bm, pmm)
+	if moves[1].Partition != 1 {
+		t.Errorf("Expected partition 1 at position 1, got %d", moves[1].Partition)
+	}
+}
+
+func TestMoveBytes(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1002]},
+		{"topic":"test","partition":1,"replicas":[1001,1002]},
+		{"topic":"test","partition":2,"replicas":[1001,1002]}]}`)
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1002,1003]},
+		{"topic":"test","partition":1,"replicas":[1002,1001]},
+		{"topic":"test","partition":2,"replicas":[1003,1004]}]}`)
+
+	pmm := kafkazk.NewPartitionMetaMap()
+	pmm["test"] = map[int]*kafkazk.PartitionMeta{
+		0: {Size: 100},
+		1: {Size: 200},
+	}
+
+	// p0 gains one replica, p1 only changes leadership and p2's size is
+	// unknown.
+	expected := []struct {
+		bytes float64
+		known bool
+	}{
+		{100, true},
+		{0, true},
+		{0, false},
+	}
+
+	for i := range pm1.Partitions {
+		b, ok := moveBytes(pm1.Partitions[i], pm2.Partitions[i], pmm)
+		if b != expected[i].bytes || ok != expected[i].known {
+			t.Errorf("p%d: expected %f, %t, got %f, %t", i, expected[i].bytes, expected[i].known, b, ok)
+		}
+	}
+
+	// Sizes are unknown without partition metrics.
+	if _, ok := moveBytes(pm1.Partitions[0], pm2.Partitions[0], nil); ok {
+		t.Error("Expected unknown move bytes without partition metrics")
+	}
+
+	// Move priorities are annotated with the same estimates.
+	for _, m := range movePriorities(pm1, pm2, kafkazk.BrokerMap{}, pmm) {
+		switch m.Partition {
+		case 0:
+			if m.MoveBytes == nil || *m.MoveBytes != 100 {
+				t.Errorf("Expected p0 move bytes of 100, got %v", m.MoveBytes)
+			}
+		case 2:
+			if m.MoveBytes != nil {
+				t.Errorf("Expected unknown p2 move bytes, got %f", *m.MoveBytes)
+			}
+		}
+	}
+}
+
+func TestLeaderMoveBatches(t *testing.T) {
+	pm1, pm2 := kafkazk.NewPartitionMap(), kafkazk.NewPartitionMap()
+
+	// Leadership changes on partitions 0-9 and a follower
+	// change on partition 10.
+	for i := 0; i < 10; i++ {
+		pm1.Partitions = append(pm1.Partitions, kafkazk.Partition{Topic: "test", Partition: i, Replicas: []int{1001, 1002}})
+		pm2.Partitions = append(pm2.Partitions, kafkazk.Partition{Topic: "test", Partition: i, Replicas: []int{1002, 1001}})
+	}
+
+	pm1.Partitions = append(pm1.Partitions, kafkazk.Partition{Topic: "test", Partition: 10, Replicas: []int{1001, 1002}})
+	pm2.Partitions = append(pm2.Partitions, kafkazk.Partition{Topic: "test", Partition: 10, Replicas: []int{1001, 1003}})
+
+	batches := leaderMoveBatches(pm1, pm2, 3)
+
+	if len(batches) != 4 {
+		t.Fatalf("Expected 4 batches, got %d", len(batches))
+	}
+
+	// Check that no batch exceeds the limit and that all
+	// partitions are included exactly once.
+	seen := map[int]kafkazk.Partition{}
+
+	for n, b := range batches {
+		var leaderMoves int
+		for _, p := range b.Partitions {
+			if _, exists := seen[p.Partition]; exists {
+				t.Errorf("Partition %d included in multiple batches", p.Partition)
+			}
+			seen[p.Partition] = p
+
+			if pm1.Partitions[p.Partition].Replicas[0] != p.Replicas[0] {
+				leaderMoves++
+			}
+		}
+
+		if leaderMoves > 3 {
+			t.Errorf("Batch %d: expected at most 3 leadership changes, got %d", n+1, leaderMoves)
+		}
+	}
+
+	// The batches should converge to the updated map.
+	for _, p := range pm2.Partitions {
+		if !p.Equal(seen[p.Partition]) {
+			t.Errorf("Expected partition %d replicas %v, got %v", p.Partition, p.Replicas, seen[p.Partition].Replicas)
+		}
+	}
+
+	// The follower change is applied in the first batch.
+	expectedSizes := []int{4, 3, 3, 1}
+	for n, b := range batches {
+		if len(b.Partitions) != expectedSizes[n] {
+			t.Errorf("Batch %d: expected %d partitions, got %d", n+1, expectedSizes[n], len(b.Partitions))
+		}
+	}
+}
+
+func TestLeaderMoveBatchesUnordered(t *testing.T) {
+	pm1, pm2 := kafkazk.NewPartitionMap(), kafkazk.NewPartitionMap()
+
+	pm1.Partitions = kafkazk.PartitionList{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1002}},
+		{Topic: "test", Partition: 1, Replicas: []int{1002, 1001}},
+		{Topic: "test", Partition: 2, Replicas: []int{1003, 1001}},
+	}
+
+	// The same partitions in a different order; partitions 0 and 2 change
+	// leadership.
+	pm2.Partitions = kafkazk.PartitionList{
+		{Topic: "test", Partition: 2, Replicas: []int{1001, 1003}},
+		{Topic: "test", Partition: 1, Replicas: []int{1002, 1001}},
+		{Topic: "test", Partition: 0, Replicas: []int{1002, 1001}},
+	}
+
+	batches := leaderMoveBatches(pm1, pm2, 1)
+
+	if len(batches) != 2 {
+		t.Fatalf("Expected 2 batches, got %d", len(batches))
+	}
+
+	expected := [][]int{{1, 2}, {0}}
+	for n, b := range batches {
+		var got []int
+		for _, p := range b.Partitions {
+			got = append(got, p.Partition)
+		}
+
+		if !reflect.DeepEqual(got, expected[n]) {
+			t.Errorf("Batch %d: expected partitions %v, got %v", n+1, expected[n], got)
+		}
+	}
+}
+
+func TestSortedPartitionMap(t *testing.T) {
+	pm := kafkazk.NewPartitionMap()
+	pm.Partitions = kafkazk.PartitionList{
+		{Topic: "test_b", Partition: 1, Replicas: []int{1001}},
+		{Topic: "test_a", Partition: 2, Replicas: []int{1002}},
+		{Topic: "test_b", Partition: 0, Replicas: []int{1003}},
+		{Topic: "test_a", Partition: 10, Replicas: []int{1004}},
+		{Topic: "test_a", Partition: 0, Replicas: []int{1005}},
+	}
+
+	sorted := sortedPartitionMap(pm)
+
+	expected := []struct {
+		topic     string
+		partition int
+	}{
+		{"test_a", 0},
+		{"test_a", 2},
+		{"test_a", 10},
+		{"test_b", 0},
+		{"test_b", 1},
+	}
+
+	for i, p := range sorted.Partitions {
+		if p.Topic != expected[i].topic || p.Partition != expected[i].partition {
+			t.Errorf("Expected %s p%d at position %d, got %s p%d",
+				expected[i].topic, expected[i].partition, i, p.Topic, p.Partition)
+		}
+	}
+
+	// Replica sets move with their partitions.
+	if sorted.Partitions[0].Replicas[0] != 1005 {
+		t.Errorf("Expected replicas [1005] for test_a p0, got %v", sorted.Partitions[0].Replicas)
+	}
+
+	// The input map is unmodified.
+	if pm.Partitions[0].Topic != "test_b" || pm.Partitions[0].Partition != 1 {
+		t.Error("Unexpected modification of the input map")
+	}
+}
+
+func TestThrottledReplicas(t *testing.T) {
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1002]},
+		{"topic":"test","partition":1,"replicas":[1002,1003]},
+		{"topic":"test","partition":2,"replicas":[1003,1001]},
+		{"topic":"test2","partition":0,"replicas":[1001,1002,1003]},
+		{"topic":"test3","partition":0,"replicas":[1001,1002]}]}`)
+	// test p0 replaces 1001 with 1004, p1 replaces both replicas and p2 only
+	// changes leadership. test2 p0 reduces the replication factor and test3
+	// is unchanged.
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1004,1002]},
+		{"topic":"test","partition":1,"replicas":[1004,1001]},
+		{"topic":"test","partition":2,"replicas":[1001,1003]},
+		{"topic":"test2","partition":0,"replicas":[1001,1002]},
+		{"topic":"test3","partition":0,"replicas":[1001,1002]}]}`)
+
+	throttles := throttledReplicas(pm1, pm2)
+
+	expected := map[string]topicThrottles{
+		"test": {
+			Leaders:   "0:1001,0:1002,1:1002,1:1003",
+			Followers: "0:1004,1:1004,1:1001",
+		},
+	}
+
+	if len(throttles) != len(expected) {
+		t.Fatalf("Expected throttles for %d topics, got %v", len(expected), throttles)
+	}
+
+	for topic, e := range expected {
+		if throttles[topic] != e {
+			t.Errorf("Expected %s throttles %v, got %v", topic, e, throttles[topic])
+		}
+	}
+}
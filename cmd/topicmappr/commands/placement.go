@@ -0,0 +1,297 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+// placementConstraintsConfig is the topic config holding Confluent replica
+// placement constraints.
+const placementConstraintsConfig = "confluent.placement.constraints"
+
+// placementConstraints is a Confluent replica placement constraints document,
+// e.g. {"version":1,"replicas":[{"count":2,"constraints":{"rack":"a"}}],
+// "observers":[{"count":1,"constraints":{"rack":"b"}}]}.
+type placementConstraints struct {
+	Version   int              `json:"version"`
+	Replicas  []placementGroup `json:"replicas"`
+	Observers []placementGroup `json:"observers"`
+}
+
+// placementGroup requires Count replicas on brokers matching the
+// Constraints. The only supported constraint is rack.
+type placementGroup struct {
+	Count       int               `json:"count"`
+	Constraints map[string]string `json:"constraints"`
+}
+
+// parsePlacementConstraints parses a Confluent replica placement constraints
+// document.
+func parsePlacementConstraints(s string) (*placementConstraints, error) {
+	pc := &placementConstraints{}
+	if err := json.Unmarshal([]byte(s), pc); err != nil {
+		return nil, err
+	}
+
+	if pc.Version != 1 && pc.Version != 2 {
+		return nil, fmt.Errorf("unsupported version %d", pc.Version)
+	}
+
+	if len(pc.Replicas) == 0 {
+		return nil, fmt.Errorf("no replicas constraints specified")
+	}
+
+	for _, g := range pc.groups() {
+		if g.Count < 1 {
+			return nil, fmt.Errorf("invalid count %d", g.Count)
+		}
+
+		for k := range g.Constraints {
+			if k != "rack" {
+				return nil, fmt.Errorf("unsupported constraint %s", k)
+			}
+		}
+
+		if g.Constraints["rack"] == "" {
+			return nil, fmt.Errorf("rack constraint required")
+		}
+	}
+
+	return pc, nil
+}
+
+// groups returns the replicas groups followed by the observers groups.
+func (pc *placementConstraints) groups() []placementGroup {
+	var groups []placementGroup
+	groups = append(groups, pc.Replicas...)
+	groups = append(groups, pc.Observers...)
+
+	return groups
+}
+
+// applyPlacementConstraints fetches the Confluent replica placement
+// constraints of all topics in the provided *kafkazk.PartitionMap and places
+// the replicas of constrained topics to satisfy them. If any partition's
+// constraints can't be satisfied, the partitions are printed and topicmappr
+// exits.
+func applyPlacementConstraints(zk kafkazk.Handler, pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap) {
+	// Rack IDs are required to evaluate constraints.
+	if zk == nil {
+		return
+	}
+
+	constraints, errs := getPlacementConstraints(zk, pm)
+	for _, e := range errs {
+		fmt.Printf("\n[WARN] %s\n", e)
+	}
+
+	if len(constraints) == 0 {
+		return
+	}
+
+	unplaced := placeByConstraints(pm, bm, constraints)
+
+	if len(unplaced) > 0 {
+		fmt.Printf("\n[ERROR] %d partitions can't satisfy their %s:\n",
+			len(unplaced), placementConstraintsConfig)
+		for _, p := range unplaced {
+			fmt.Printf("%s%s p%d: %v\n", indent, p.Topic, p.Partition, p.Replicas)
+		}
+		os.Exit(1)
+	}
+}
+
+// getPlacementConstraints returns a mapping of topic name to the parsed
+// placement constraints for all topics in the *kafkazk.PartitionMap that have
+// them. Topics whose config can't be fetched or parsed are returned as errors.
+func getPlacementConstraints(zk kafkazk.Handler, pm *kafkazk.PartitionMap) (map[string]*placementConstraints, errors) {
+	var errs errors
+	constraints := map[string]*placementConstraints{}
+
+	seen := map[string]struct{}{}
+	for _, p := range pm.Partitions {
+		if _, ok := seen[p.Topic]; ok {
+			continue
+		}
+		seen[p.Topic] = struct{}{}
+
+		tc, err := zk.GetTopicConfig(p.Topic)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to check %s for %s: %s", placementConstraintsConfig, p.Topic, err))
+			continue
+		}
+
+		s, exists := tc.Config[placementConstraintsConfig]
+		if !exists || s == "" {
+			continue
+		}
+
+		pc, err := parsePlacementConstraints(s)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("ignoring invalid %s for %s: %s", placementConstraintsConfig, p.Topic, err))
+			continue
+		}
+
+		constraints[p.Topic] = pc
+	}
+
+	return constraints, errs
+}
+
+// placeByConstraints takes a *kafkazk.PartitionMap, a kafkazk.BrokerMap and a
+// mapping of topic name to placement constraints. The replica set of each
+// partition of a constrained topic is made to satisfy the constraints: for
+// each group, current replicas in the group's rack are kept and the remaining
+// count is filled with the brokers in the rack holding the fewest replicas.
+// Brokers marked for replacement aren't used. The kept replicas retain their
+// relative order so that the leader is unchanged where possible, and observers
+// are placed after all other replicas, as Kafka requires, and are set as the
+// partition's Observers. Partitions that can't be satisfied are left
+// unchanged and returned.
+func placeByConstraints(pm *kafkazk.PartitionMap, bm kafkazk.BrokerMap, constraints map[string]*placementConstraints) []kafkazk.Partition {
+	var unplaced []kafkazk.Partition
+
+	// Track replica counts as placements are made.
+	used := map[int]int{}
+	for id, s := range pm.UseStats() {
+		used[id] = s.Leader + s.Follower
+	}
+
+	// Index usable brokers by rack.
+	byRack := map[string][]int{}
+	for id, b := range bm {
+		if !b.Replace && b.Locality != "" {
+			byRack[b.Locality] = append(byRack[b.Locality], id)
+		}
+	}
+
+	for i, p := range pm.Partitions {
+		pc, exists := constraints[p.Topic]
+		if !exists {
+			continue
+		}
+
+		replicas, observers, ok := placePartition(p, pc, bm, byRack, used)
+		if !ok {
+			unplaced = append(unplaced, p)
+			continue
+		}
+
+		for _, id := range p.Replicas {
+			used[id]--
+		}
+		for _, id := range replicas {
+			used[id]++
+		}
+
+		pm.Partitions[i].Replicas = replicas
+		pm.Partitions[i].Observers = observers
+	}
+
+	return unplaced
+}
+
+// placePartition returns the replica set, including observers, and the
+// observers satisfying the placement constraints for the partition, or false
+// if the constraints can't be satisfied.
+func placePartition(p kafkazk.Partition, pc *placementConstraints, bm kafkazk.BrokerMap, byRack map[string][]int, used map[int]int) ([]int, []int, bool) {
+	// The position of each current replica.
+	position := map[int]int{}
+	for i, id := range p.Replicas {
+		position[id] = i
+	}
+
+	selected := map[int]bool{}
+
+	// place selects the brokers for a group, returning them ordered with
+	// current replicas first, in their current order.
+	place := func(g placementGroup) ([]int, bool) {
+		rack := g.Constraints["rack"]
+
+		var kept, added []int
+		for _, id := range p.Replicas {
+			if len(kept) == g.Count {
+				break
+			}
+
+			b, exists := bm[id]
+			if !exists || b.Replace || b.Locality != rack || selected[id] {
+				continue
+			}
+
+			kept = append(kept, id)
+			selected[id] = true
+		}
+
+		// Fill the remaining count with the least used brokers in the rack.
+		candidates := append([]int{}, byRack[rack]...)
+		sort.Slice(candidates, func(i, j int) bool {
+			if used[candidates[i]] != used[candidates[j]] {
+				return used[candidates[i]] < used[candidates[j]]
+			}
+			return candidates[i] < candidates[j]
+		})
+
+		for _, id := range candidates {
+			if len(kept)+len(added) == g.Count {
+				break
+			}
+
+			if selected[id] {
+				continue
+			}
+
+			added = append(added, id)
+			selected[id] = true
+		}
+
+		if len(kept)+len(added) < g.Count {
+			return nil, false
+		}
+
+		return append(kept, added...), true
+	}
+
+	var replicas, observers []int
+
+	for _, g := range pc.Replicas {
+		ids, ok := place(g)
+		if !ok {
+			return nil, nil, false
+		}
+		replicas = append(replicas, ids...)
+	}
+
+	for _, g := range pc.Observers {
+		ids, ok := place(g)
+		if !ok {
+			return nil, nil, false
+		}
+		observers = append(observers, ids...)
+	}
+
+	// Order current replicas by their current position, ahead of new
+	// replicas, so that the current leader is retained if it's still a
+	// (non-observer) replica.
+	byPosition := func(ids []int) {
+		sort.SliceStable(ids, func(i, j int) bool {
+			pi, iCurrent := position[ids[i]]
+			pj, jCurrent := position[ids[j]]
+			switch {
+			case iCurrent && jCurrent:
+				return pi < pj
+			default:
+				return iCurrent && !jCurrent
+			}
+		})
+	}
+
+	byPosition(replicas)
+	byPosition(observers)
+
+	return append(replicas, observers...), observers, true
+}
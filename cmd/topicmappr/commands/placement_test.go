@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestParsePlacementConstraints(t *testing.T) {
+	valid := `{"version":1,"replicas":[{"count":2,"constraints":{"rack":"a"}}],"observers":[{"count":1,"constraints":{"rack":"b"}}]}`
+
+	pc, err := parsePlacementConstraints(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pc.Replicas) != 1 || pc.Replicas[0].Count != 2 || len(pc.Observers) != 1 {
+		t.Errorf("Unexpected placement constraints %+v", pc)
+	}
+
+	invalid := []string{
+		`{"version":3,"replicas":[{"count":1,"constraints":{"rack":"a"}}]}`,
+		`{"version":1}`,
+		`{"version":1,"replicas":[{"count":0,"constraints":{"rack":"a"}}]}`,
+		`{"version":1,"replicas":[{"count":1,"constraints":{"zone":"a"}}]}`,
+		`{"version":1,"replicas":[{"count":1}]}`,
+	}
+
+	for _, s := range invalid {
+		if _, err := parsePlacementConstraints(s); err == nil {
+			t.Errorf("Expected error for %s", s)
+		}
+	}
+}
+
+func TestPlaceByConstraints(t *testing.T) {
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, Locality: "a"},
+		1002: &kafkazk.Broker{ID: 1002, Locality: "a"},
+		1003: &kafkazk.Broker{ID: 1003, Locality: "a"},
+		1004: &kafkazk.Broker{ID: 1004, Locality: "b"},
+		1005: &kafkazk.Broker{ID: 1005, Locality: "b"},
+		1006: &kafkazk.Broker{ID: 1006, Locality: "c"},
+	}
+
+	// Rack unique placements, as produced by a rebuild.
+	pm := kafkazk.NewPartitionMap()
+	pm.Partitions = []kafkazk.Partition{
+		{Topic: "test", Partition: 0, Replicas: []int{1001, 1004, 1006}},
+		{Topic: "test", Partition: 1, Replicas: []int{1004, 1002, 1006}},
+		{Topic: "test", Partition: 2, Replicas: []int{1006, 1005, 1003}},
+		{Topic: "other", Partition: 0, Replicas: []int{1006, 1005}},
+	}
+
+	pc, _ := parsePlacementConstraints(`{"version":1,"replicas":[{"count":2,"constraints":{"rack":"a"}}],"observers":[{"count":1,"constraints":{"rack":"b"}}]}`)
+
+	unplaced := placeByConstraints(pm, bm, map[string]*placementConstraints{"test": pc})
+	if len(unplaced) != 0 {
+		t.Fatalf("Expected all partitions placed, got unplaced %v", unplaced)
+	}
+
+	for _, p := range pm.Partitions {
+		if p.Topic != "test" {
+			continue
+		}
+
+		if len(p.Replicas) != 3 {
+			t.Errorf("p%d: expected 3 replicas, got %v", p.Partition, p.Replicas)
+			continue
+		}
+
+		// 2 replicas in rack a followed by an observer in rack b.
+		for i, id := range p.Replicas {
+			rack := "a"
+			if i == 2 {
+				rack = "b"
+			}
+
+			if bm[id].Locality != rack {
+				t.Errorf("p%d: expected replica %d in rack %s, got %v", p.Partition, i, rack, p.Replicas)
+			}
+		}
+
+		if !replicasEqual(p.Observers, p.Replicas[2:]) {
+			t.Errorf("p%d: expected observers %v, got %v", p.Partition, p.Replicas[2:], p.Observers)
+		}
+
+		if p.Replicas[0] == p.Replicas[1] {
+			t.Errorf("p%d: duplicate replicas %v", p.Partition, p.Replicas)
+		}
+	}
+
+	// Current replicas satisfying the constraints are kept, retaining the
+	// leader.
+	if p := pm.Partitions[0]; p.Replicas[0] != 1001 || p.Replicas[2] != 1004 {
+		t.Errorf("Expected p0 to retain 1001 as leader and 1004 as observer, got %v", p.Replicas)
+	}
+
+	// Unconstrained topics are unchanged.
+	if !replicasEqual(pm.Partitions[3].Replicas, []int{1006, 1005}) {
+		t.Errorf("Expected unconstrained topic unchanged, got %v", pm.Partitions[3].Replicas)
+	}
+
+	// Unsatisfiable constraints.
+	pc, _ = parsePlacementConstraints(`{"version":1,"replicas":[{"count":2,"constraints":{"rack":"c"}}]}`)
+
+	unplaced = placeByConstraints(pm, bm, map[string]*placementConstraints{"other": pc})
+	if len(unplaced) != 1 || !replicasEqual(pm.Partitions[3].Replicas, []int{1006, 1005}) {
+		t.Errorf("Expected unsatisfiable partition unchanged and returned, got %v", unplaced)
+	}
+}
+
+// constrainedZK is a kafkazk.Handler where test_topic has placement
+// constraints.
+type constrainedZK struct {
+	kafkazk.Handler
+	constraints string
+}
+
+func (zk constrainedZK) GetTopicConfig(t string) (*kafkazk.TopicConfig, error) {
+	tc, err := zk.Handler.GetTopicConfig(t)
+	if err != nil {
+		return nil, err
+	}
+
+	if t == "test_topic" {
+		tc.Config[placementConstraintsConfig] = zk.constraints
+	}
+
+	return tc, nil
+}
+
+func TestPlacementConstraintsFromSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	live := constrainedZK{
+		Handler:     &kafkazk.Stub{},
+		constraints: `{"version":1,"replicas":[{"count":1,"constraints":{"rack":"a"}}],"observers":[{"count":1,"constraints":{"rack":"b"}}]}`,
+	}
+
+	// Capture a snapshot to a file and read it back.
+	s, err := kafkazk.NewSnapshot(live)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "snapshot.json")
+	if err := s.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, err = kafkazk.SnapshotFromFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	offline := s.Handler()
+
+	// Rebuild test_topic, replacing 1003, and apply its constraints.
+	rebuild := func(zk kafkazk.Handler) *kafkazk.PartitionMap {
+		bm, errs := zk.GetAllBrokerMeta(false)
+		if errs != nil {
+			t.Fatal(errs)
+		}
+
+		pm, err := kafkazk.PartitionMapFromZK([]*regexp.Regexp{regexp.MustCompile("test_topic$")}, zk)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		brokers := kafkazk.BrokerMapFromPartitionMap(pm, bm, false)
+		brokers.Update([]int{1001, 1002, 1004, 1005, 1007}, bm)
+
+		params := kafkazk.NewRebuildParams()
+		params.BM = brokers
+		params.Strategy = "count"
+
+		out, errs := pm.Rebuild(params)
+		if errs != nil {
+			t.Fatal(errs)
+		}
+
+		constraints, errs := getPlacementConstraints(zk, out)
+		if errs != nil {
+			t.Fatalf("Unexpected error(s): %v", errs)
+		}
+
+		if constraints["test_topic"] == nil {
+			t.Fatal("Expected placement constraints for test_topic")
+		}
+
+		if unplaced := placeByConstraints(out, brokers, constraints); len(unplaced) != 0 {
+			t.Fatalf("Expected all partitions placed, got unplaced %v", unplaced)
+		}
+
+		return out
+	}
+
+	expected, got := rebuild(live), rebuild(offline)
+
+	if same, err := expected.Equal(got); !same {
+		t.Errorf("Expected the snapshot rebuild to match the live rebuild: %s", err)
+	}
+
+	bm, _ := offline.GetAllBrokerMeta(false)
+	for _, p := range got.Partitions {
+		if len(p.Replicas) != 2 || len(p.Observers) != 1 || bm[p.Replicas[0]].Rack != "a" || bm[p.Observers[0]].Rack != "b" {
+			t.Errorf("p%d: expected a replica in rack a and an observer in rack b, got %v (observers: %v)",
+				p.Partition, p.Replicas, p.Observers)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+// rackRegression describes a partition whose replica set spans fewer racks
+// after a plan than before.
+type rackRegression struct {
+	topic     string
+	partition int
+	before    []int
+	after     []int
+	// The number of distinct racks spanned before and after.
+	racksBefore int
+	racksAfter  int
+}
+
+// checkRackRegressions prints any partitions with reduced rack diversity in
+// pm2 compared to pm1. If the strict-rack-awareness flag is set and any
+// regressions are found, an error is printed and topicmappr exits.
+func checkRackRegressions(cmd *cobra.Command, pm1, pm2 *kafkazk.PartitionMap, bm kafkazk.BrokerMap) {
+	regressions := rackRegressions(pm1, pm2, bm)
+	if len(regressions) == 0 {
+		return
+	}
+
+	fmt.Println("\nRack diversity regressions:")
+	for _, r := range regressions {
+		fmt.Printf("%s%s p%d: %v -> %v (racks: %d -> %d)\n",
+			indent, r.topic, r.partition, r.before, r.after, r.racksBefore, r.racksAfter)
+	}
+
+	if strict, _ := cmd.Flags().GetBool("strict-rack-awareness"); strict {
+		fmt.Printf("\n[ERROR] %d partitions have reduced rack diversity; partition map not created\n", len(regressions))
+		os.Exit(1)
+	}
+}
+
+// rackRegressions takes a before and after *kafkazk.PartitionMap and a
+// kafkazk.BrokerMap and returns a []rackRegression for every partition that
+// spans fewer racks in pm2 than in pm1. Brokers without a rack ID aren't
+// counted.
+func rackRegressions(pm1, pm2 *kafkazk.PartitionMap, bm kafkazk.BrokerMap) []rackRegression {
+	var regressions []rackRegression
+
+	before := map[string]map[int][]int{}
+	for _, p := range pm1.Partitions {
+		if _, exists := before[p.Topic]; !exists {
+			before[p.Topic] = map[int][]int{}
+		}
+		before[p.Topic][p.Partition] = p.Replicas
+	}
+
+	for _, p := range pm2.Partitions {
+		replicas, exists := before[p.Topic][p.Partition]
+		if !exists {
+			continue
+		}
+
+		rb, ra := rackCount(replicas, bm), rackCount(p.Replicas, bm)
+		if ra < rb {
+			regressions = append(regressions, rackRegression{
+				topic:       p.Topic,
+				partition:   p.Partition,
+				before:      replicas,
+				after:       p.Replicas,
+				racksBefore: rb,
+				racksAfter:  ra,
+			})
+		}
+	}
+
+	return regressions
+}
+
+// rackCount returns the number of distinct racks spanned by the replica set.
+func rackCount(replicas []int, bm kafkazk.BrokerMap) int {
+	racks := map[string]struct{}{}
+
+	for _, id := range replicas {
+		if b, exists := bm[id]; exists && b.Locality != "" {
+			racks[b.Locality] = struct{}{}
+		}
+	}
+
+	return len(racks)
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestRackRegressions(t *testing.T) {
+	bm := kafkazk.BrokerMap{
+		1001: &kafkazk.Broker{ID: 1001, Locality: "a"},
+		1002: &kafkazk.Broker{ID: 1002, Locality: "b"},
+		1003: &kafkazk.Broker{ID: 1003, Locality: "a"},
+		1004: &kafkazk.Broker{ID: 1004, Locality: "c"},
+		1005: &kafkazk.Broker{ID: 1005},
+	}
+
+	pm1, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test_topic","partition":0,"replicas":[1001,1002]},
+		{"topic":"test_topic","partition":1,"replicas":[1002,1001]},
+		{"topic":"test_topic","partition":2,"replicas":[1001,1005]}]}`)
+
+	// p0 moves 1002 to 1003, co-locating both replicas in rack a. p1 moves
+	// 1001 to 1004, retaining two racks. p2 moves from a broker without a
+	// rack ID.
+	pm2, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test_topic","partition":0,"replicas":[1001,1003]},
+		{"topic":"test_topic","partition":1,"replicas":[1002,1004]},
+		{"topic":"test_topic","partition":2,"replicas":[1001,1004]}]}`)
+
+	regressions := rackRegressions(pm1, pm2, bm)
+
+	if len(regressions) != 1 {
+		t.Fatalf("Expected 1 regression, got %d", len(regressions))
+	}
+
+	r := regressions[0]
+	if r.topic != "test_topic" || r.partition != 0 {
+		t.Errorf("Expected a regression for test_topic p0, got %s p%d", r.topic, r.partition)
+	}
+
+	if r.racksBefore != 2 || r.racksAfter != 1 {
+		t.Errorf("Expected racks 2 -> 1, got %d -> %d", r.racksBefore, r.racksAfter)
+	}
+
+	// No regressions comparing a map to itself.
+	if regressions := rackRegressions(pm1, pm1, bm); len(regressions) != 0 {
+		t.Errorf("Unexpected regressions %v", regressions)
+	}
+}
@@ -12,10 +12,11 @@ import (
 )
 
 var rebalanceCmd = &cobra.Command{
-	Use:   "rebalance",
-	Short: "Rebalance partition allotments among a set of topics and brokers",
-	Long:  `Rebalance partition allotments among a set of topics and brokers`,
-	Run:   rebalance,
+	Use:    "rebalance",
+	Short:  "Rebalance partition allotments among a set of topics and brokers",
+	Long:   `Rebalance partition allotments among a set of topics and brokers`,
+	PreRun: loadConstraintsFile,
+	Run:    rebalance,
 }
 
 func init() {
@@ -23,8 +24,17 @@ func init() {
 
 	rebalanceCmd.Flags().String("topics", "", "Rebuild topics (comma delim. list) by lookup in ZooKeeper")
 	rebalanceCmd.Flags().String("topics-exclude", "", "Exclude topics")
+	rebalanceCmd.Flags().String("constraints-file", "", "Path to a YAML or JSON file of placement constraints keyed by flag name (command-line flags take precedence)")
 	rebalanceCmd.Flags().String("out-path", "", "Path to write output map files to")
 	rebalanceCmd.Flags().String("out-file", "", "If defined, write a combined map of all topics to a file")
+	rebalanceCmd.Flags().Bool("sort-output", false, "Sort output map partitions by topic and partition number for stable, diffable output")
+	rebalanceCmd.Flags().Bool("approve-moves", false, "Prompt to approve or reject each partition move before writing maps; placement isn't re-run for the approved moves")
+	rebalanceCmd.Flags().String("summary-out", "", "If defined, write a Grafana-ready JSON summary of per-broker before/after metrics to the file")
+	rebalanceCmd.Flags().String("priority-out", "", "If defined, write a JSON list of partition moves ordered by priority (storage relief, replica repair) to the file")
+	rebalanceCmd.Flags().String("throttles-out", "", "If defined, write the leader and follower throttled replica lists implied by the plan, per topic, to the file")
+	rebalanceCmd.Flags().Float64("replication-rate", 0, "Estimated aggregate replication rate in MB/s used to estimate the plan duration (0 disables)")
+	rebalanceCmd.Flags().Duration("time-budget", 0, "Trim the plan to the highest priority partition moves estimated to complete within the duration, e.g. 2h (requires --replication-rate)")
+	rebalanceCmd.Flags().Bool("strict-rack-awareness", false, "Fail if the plan reduces the number of racks spanned by any partition's replica set")
 	rebalanceCmd.Flags().String("brokers", "", "Broker list to scope all partition placements to ('-1' for all currently mapped brokers, '-2' for all brokers in cluster)")
 	rebalanceCmd.Flags().Float64("storage-threshold", 0.20, "Percent below the harmonic mean storage free to target for partition offload (0 targets a brokers)")
 	rebalanceCmd.Flags().Float64("storage-threshold-gb", 0.00, "Storage free in gigabytes to target for partition offload (those below the specified value); 0 [default] defers target selection to --storage-threshold")
@@ -35,7 +45,17 @@ func init() {
 	rebalanceCmd.Flags().Bool("verbose", false, "Verbose output")
 	rebalanceCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics")
 	rebalanceCmd.Flags().Int("metrics-age", 60, "Kafka metrics age tolerance (in minutes)")
+	rebalanceCmd.Flags().Float64("assume-storage-free", 0, "Storage free in gigabytes to assume for brokers missing metrics (0 disables)")
+	rebalanceCmd.Flags().Float64("storage-headroom-percent", 0, "Percentage of each broker's storage free to reserve as headroom; reserved storage is treated as unavailable for placement (0 disables)")
 	rebalanceCmd.Flags().Bool("optimize-leadership", false, "Rebalance all broker leader/follower ratios")
+	rebalanceCmd.Flags().String("leader-weights", "", "Broker leadership weights used with --optimize-leadership (comma delim. list of id:weight, e.g. 1001:2,1002:0.5)")
+	rebalanceCmd.Flags().String("preferred-leader-rack", "", "Make a replica in this rack the preferred leader for all partitions that have one (partitions without are left unchanged)")
+	rebalanceCmd.Flags().Float64("leader-concentration-factor", 2.0, "Warn about brokers leading more than this factor times the average number of partitions in the output map (0 disables)")
+	rebalanceCmd.Flags().Int("max-concurrent-leader-moves", 0, "Limit the number of preferred leader changes per output map; maps are split into ordered batches (0 disables)")
+	rebalanceCmd.Flags().String("publish-scope", "", "ZooKeeper znode path to publish the reassignment scope to (e.g. /autothrottle/reassignment_scope)")
+	rebalanceCmd.Flags().Bool("phased-apply", false, "Apply the plan in batches, publishing each batch's reassignment scope for autothrottle and waiting for each batch to complete before the next (requires --publish-scope)")
+	rebalanceCmd.Flags().Int("apply-batch-size", 0, "Maximum partition moves per --phased-apply batch (0 applies each output map as a single batch)")
+	rebalanceCmd.Flags().String("apply-state", "", "Path to a file tracking --phased-apply progress; an interrupted apply is resumed from the file")
 
 	// Required.
 	rebalanceCmd.MarkFlagRequired("brokers")
@@ -69,6 +89,9 @@ func rebalance(cmd *cobra.Command, _ []string) {
 	// Exclude any topics that are pending deletion.
 	pending := stripPendingDeletes(partitionMapIn, zk)
 
+	// Exclude any topics undergoing a reassignment.
+	reassigning := stripReassigning(partitionMapIn, zk)
+
 	// Exclude any explicit exclusions.
 	excluded := removeTopics(partitionMapIn, Config.topicsExclude)
 
@@ -78,6 +101,9 @@ func rebalance(cmd *cobra.Command, _ []string) {
 	// Print if any topics were excluded due to pending deletion.
 	printExcludedTopics(pending, excluded)
 
+	// Warn if any topics were excluded due to in-progress reassignments.
+	printReassigningTopics(reassigning)
+
 	// Get a broker map.
 	brokersIn := kafkazk.BrokerMapFromPartitionMap(partitionMapIn, brokerMeta, false)
 
@@ -136,27 +162,61 @@ func rebalance(cmd *cobra.Command, _ []string) {
 
 	// Optimize leaders.
 	if t, _ := cmd.Flags().GetBool("optimize-leadership"); t {
-		partitionMapOut.OptimizeLeaderFollower()
+		partitionMapOut.OptimizeLeaderFollowerWeighted(Config.leaderWeights)
 	}
 
+	// Pin preferred leaders to a rack if configured.
+	applyPreferredLeaderRack(cmd, partitionMapOut, brokersOut)
+
 	// Print planned relocations.
 	printPlannedRelocations(offloadTargets, relos, partitionMeta)
 
 	// Print map change results.
-	printMapChanges(partitionMapIn, partitionMapOut)
+	printMapChanges(partitionMapIn, partitionMapOut, partitionMeta)
 
 	// Print broker assignment statistics.
 	errs := printBrokerAssignmentStats(cmd, partitionMapIn, partitionMapOut, brokersIn, brokersOut)
 
+	// Check for rack diversity regressions.
+	checkRackRegressions(cmd, partitionMapIn, partitionMapOut, brokersOut)
+
+	// Warn about concentrated leadership.
+	checkLeaderConcentration(cmd, partitionMapOut)
+
 	// Handle errors that are possible to be overridden by the user (aka 'WARN'
 	// in topicmappr console output).
 	handleOverridableErrs(cmd, errs)
 
+	// Trim the plan to the time budget if configured.
+	partitionMapOut = applyTimeBudget(cmd, partitionMapIn, partitionMapOut, brokersIn, partitionMeta)
+
+	// Interactively review moves if configured.
+	partitionMapOut = reviewPlan(cmd, partitionMapIn, partitionMapOut, partitionMeta)
+
+	// Write the plan summary if configured.
+	writePlanSummary(cmd, partitionMapIn, partitionMapOut, brokersIn, brokersOut)
+
+	// Write move priorities if configured.
+	writeMovePriorities(cmd, partitionMapIn, partitionMapOut, brokersIn, partitionMeta)
+
+	// Write throttled replica lists if configured.
+	writeThrottledReplicas(cmd, partitionMapIn, partitionMapOut)
+
 	// Ignore no-ops; rebalances will naturally have a high percentage of these.
 	partitionMapIn, partitionMapOut = skipReassignmentNoOps(partitionMapIn, partitionMapOut)
 
-	// Write maps.
-	writeMaps(cmd, partitionMapOut, nil)
+	// Write maps, batching leadership changes if configured.
+	if n, _ := cmd.Flags().GetInt("max-concurrent-leader-moves"); n > 0 {
+		writeLeaderMoveBatches(cmd, partitionMapIn, partitionMapOut, n)
+	} else {
+		writeMaps(cmd, partitionMapOut, nil)
+	}
+
+	// Publish the reassignment scope if configured.
+	publishReassignmentScope(cmd, zk, partitionMapIn, partitionMapOut)
+
+	// Apply the plan in batches if configured.
+	applyPhased(cmd, zk, partitionMapIn, outputMaps(cmd, partitionMapIn, partitionMapOut, nil))
 }
 
 func validateBrokersForRebalance(cmd *cobra.Command, brokers kafkazk.BrokerMap, bm kafkazk.BrokerMetaMap) []int {
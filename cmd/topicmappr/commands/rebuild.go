@@ -18,7 +18,8 @@ Target topics are provided as a comma delimited list of topic names and/or regex
 via the --topics parameter, which discovers matching topics in ZooKeeper (additionally,
 the --zk-addr and --zk-prefix global flags should be set). Alternatively, a JSON map can be
 provided via the --map-string flag. Target broker IDs are provided via the --broker flag.`,
-	Run: rebuild,
+	PreRun: loadConstraintsFile,
+	Run:    rebuild,
 }
 
 func init() {
@@ -26,23 +27,50 @@ func init() {
 
 	rebuildCmd.Flags().String("topics", "", "Rebuild topics (comma delim. list) by lookup in ZooKeeper")
 	rebuildCmd.Flags().String("topics-exclude", "", "Exclude topics")
+	rebuildCmd.Flags().String("constraints-file", "", "Path to a YAML or JSON file of placement constraints keyed by flag name (command-line flags take precedence)")
 	rebuildCmd.Flags().String("map-string", "", "Rebuild a partition map provided as a string literal")
 	rebuildCmd.Flags().Bool("use-meta", true, "Use broker metadata in placement constraints")
 	rebuildCmd.Flags().String("out-path", "", "Path to write output map files to")
 	rebuildCmd.Flags().String("out-file", "", "If defined, write a combined map of all topics to a file")
+	rebuildCmd.Flags().Bool("sort-output", false, "Sort output map partitions by topic and partition number for stable, diffable output")
+	rebuildCmd.Flags().Bool("approve-moves", false, "Prompt to approve or reject each partition move before writing maps; placement isn't re-run for the approved moves")
+	rebuildCmd.Flags().String("summary-out", "", "If defined, write a Grafana-ready JSON summary of per-broker before/after metrics to the file")
+	rebuildCmd.Flags().String("priority-out", "", "If defined, write a JSON list of partition moves ordered by priority (storage relief, replica repair) to the file")
+	rebuildCmd.Flags().String("throttles-out", "", "If defined, write the leader and follower throttled replica lists implied by the plan, per topic, to the file")
+	rebuildCmd.Flags().Float64("replication-rate", 0, "Estimated aggregate replication rate in MB/s used to estimate the plan duration (0 disables)")
+	rebuildCmd.Flags().Duration("time-budget", 0, "Trim the plan to the highest priority partition moves estimated to complete within the duration, e.g. 2h (requires --replication-rate)")
+	rebuildCmd.Flags().Bool("strict-rack-awareness", false, "Fail if the plan reduces the number of racks spanned by any partition's replica set")
 	rebuildCmd.Flags().Bool("force-rebuild", false, "Forces a complete map rebuild")
 	rebuildCmd.Flags().Int("replication", 0, "Normalize the topic replication factor across all replica sets (0 results in a no-op)")
+	rebuildCmd.Flags().String("broker-remap", "", "Rewrite broker IDs in the current map before rebuilding, e.g. when new brokers take over old broker IDs (comma delim. list of old:new, e.g. 1001:2001,1002:2002)")
 	rebuildCmd.Flags().Bool("sub-affinity", false, "Replacement broker substitution affinity")
-	rebuildCmd.Flags().String("placement", "count", "Partition placement strategy: [count, storage]")
+	rebuildCmd.Flags().String("placement", "count", "Partition placement strategy: [count, storage, hybrid]")
+	rebuildCmd.Flags().Float64("hybrid-weight", 0.5, "Weight given to storage free evenness over partition count evenness for hybrid placement (0 is pure count, 1 is pure storage)")
+	rebuildCmd.Flags().Bool("prefer-leader-balance", false, "Place replacement leaders on the brokers leading the fewest partitions, subject to rack and storage constraints")
 	rebuildCmd.Flags().Int("min-rack-ids", 0, "Minimum number of required of unique rack IDs per replica set (0 requires that all are unique)")
 	rebuildCmd.Flags().String("optimize", "distribution", "Optimization priority for the storage placement strategy: [distribution, storage]")
 	rebuildCmd.Flags().Float64("partition-size-factor", 1.0, "Factor by which to multiply partition sizes when using storage placement")
 	rebuildCmd.Flags().String("brokers", "", "Broker list to scope all partition placements to ('-1' for all currently mapped brokers, '-2' for all brokers in cluster)")
 	rebuildCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics (when using storage placement)")
 	rebuildCmd.Flags().Int("metrics-age", 60, "Kafka metrics age tolerance (in minutes) (when using storage placement)")
+	rebuildCmd.Flags().Float64("assume-storage-free", 0, "Storage free in gigabytes to assume for brokers missing metrics (0 disables)")
+	rebuildCmd.Flags().Float64("storage-headroom-percent", 0, "Percentage of each broker's storage free to reserve as headroom; reserved storage is treated as unavailable for placement (0 disables)")
 	rebuildCmd.Flags().Bool("skip-no-ops", false, "Skip no-op partition assigments")
 	rebuildCmd.Flags().Bool("optimize-leadership", false, "Rebalance all broker leader/follower ratios")
+	rebuildCmd.Flags().String("leader-weights", "", "Broker leadership weights used with --optimize-leadership (comma delim. list of id:weight, e.g. 1001:2,1002:0.5)")
+	rebuildCmd.Flags().String("capacity-weights", "", "Broker capacity weights for count placement; brokers are filled toward partition counts proportional to their weight (comma delim. list of id:weight, e.g. 1001:2,1002:1)")
+	rebuildCmd.Flags().String("topic-affinity", "", "Co-locate corresponding partitions of related topics; partition N of each topic in a group takes the brokers of partition N of the group's first topic (comma delim. list of groups, each colon delim. topics, e.g. stream:stream-changelog)")
+	rebuildCmd.Flags().Bool("keep-leaders", false, "Keep the current leader (first replica) of every partition as its preferred leader, moving only non-leader replicas")
+	rebuildCmd.Flags().String("preferred-leader-rack", "", "Make a replica in this rack the preferred leader for all partitions that have one (partitions without are left unchanged)")
+	rebuildCmd.Flags().Float64("leader-concentration-factor", 2.0, "Warn about brokers leading more than this factor times the average number of partitions in the output map (0 disables)")
+	rebuildCmd.Flags().String("observer-racks", "", "Make replicas in these racks observers and all other replicas sync replicas; an empty value promotes all observers (comma delim. list)")
+	rebuildCmd.Flags().Int("min-isr", 0, "min.insync.replicas assumed by --observer-racks for topics without a value in ZooKeeper, e.g. where set in server.properties (0 requires a value in ZooKeeper)")
+	rebuildCmd.Flags().Int("max-concurrent-leader-moves", 0, "Limit the number of preferred leader changes per output map; maps are split into ordered batches (0 disables)")
 	rebuildCmd.Flags().Bool("phased-reassignment", false, "Create two-phase output maps")
+	rebuildCmd.Flags().String("publish-scope", "", "ZooKeeper znode path to publish the reassignment scope to (e.g. /autothrottle/reassignment_scope)")
+	rebuildCmd.Flags().Bool("phased-apply", false, "Apply the plan in batches, publishing each batch's reassignment scope for autothrottle and waiting for each batch to complete before the next (requires --publish-scope)")
+	rebuildCmd.Flags().Int("apply-batch-size", 0, "Maximum partition moves per --phased-apply batch (0 applies each output map as a single batch)")
+	rebuildCmd.Flags().String("apply-state", "", "Path to a file tracking --phased-apply progress; an interrupted apply is resumed from the file")
 
 	// Required.
 	rebuildCmd.MarkFlagRequired("brokers")
@@ -53,23 +81,52 @@ func rebuild(cmd *cobra.Command, _ []string) {
 	t, _ := cmd.Flags().GetString("topics")
 	ms, _ := cmd.Flags().GetString("map-string")
 	p := cmd.Flag("placement").Value.String()
+	hw, _ := cmd.Flags().GetFloat64("hybrid-weight")
+	rr, _ := cmd.Flags().GetFloat64("replication-rate")
 	o := cmd.Flag("optimize").Value.String()
 	fr, _ := cmd.Flags().GetBool("force-rebuild")
 	sa, _ := cmd.Flags().GetBool("sub-affinity")
 	m, _ := cmd.Flags().GetBool("use-meta")
+	ps := cmd.Flag("publish-scope").Value.String()
+	pr, _ := cmd.Flags().GetBool("phased-reassignment")
+	mlm, _ := cmd.Flags().GetInt("max-concurrent-leader-moves")
+	plr := cmd.Flag("preferred-leader-rack").Value.String()
+	kl, _ := cmd.Flags().GetBool("keep-leaders")
+	ol, _ := cmd.Flags().GetBool("optimize-leadership")
+
+	// Hybrid placement with no weight given to storage is count placement.
+	if p == "hybrid" && hw == 0 {
+		p = "count"
+		cmd.Flags().Set("placement", p)
+	}
 
 	switch {
 	case ms == "" && t == "":
 		fmt.Println("\n[ERROR] must specify either --topics or --map-string")
 		defaultsAndExit()
-	case p != "count" && p != "storage":
-		fmt.Println("\n[ERROR] --placement must be either 'count' or 'storage'")
+	case p != "count" && p != "storage" && p != "hybrid":
+		fmt.Println("\n[ERROR] --placement must be one of 'count', 'storage' or 'hybrid'")
+		defaultsAndExit()
+	case hw < 0 || hw > 1:
+		fmt.Println("\n[ERROR] --hybrid-weight must be between 0 and 1")
 		defaultsAndExit()
 	case o != "distribution" && o != "storage":
 		fmt.Println("\n[ERROR] --optimize must be either 'distribution' or 'storage'")
 		defaultsAndExit()
-	case !m && p == "storage":
-		fmt.Println("\n[ERROR] --placement=storage requires --use-meta=true")
+	case !m && storagePlacement(p):
+		fmt.Printf("\n[ERROR] --placement=%s requires --use-meta=true\n", p)
+		defaultsAndExit()
+	case !m && plr != "":
+		fmt.Println("\n[ERROR] --preferred-leader-rack requires --use-meta=true")
+		defaultsAndExit()
+	case !m && cmd.Flags().Changed("observer-racks"):
+		fmt.Println("\n[ERROR] --observer-racks requires --use-meta=true")
+		defaultsAndExit()
+	case kl && (ol || plr != ""):
+		fmt.Println("\n[ERROR] --keep-leaders is mutually exclusive with --optimize-leadership and --preferred-leader-rack")
+		defaultsAndExit()
+	case pr && mlm > 0:
+		fmt.Println("\n[ERROR] --phased-reassignment and --max-concurrent-leader-moves are mutually exclusive")
 		defaultsAndExit()
 	case fr && sa:
 		fmt.Println("\n[INFO] --force-rebuild disables --sub-affinity")
@@ -79,7 +136,7 @@ func rebuild(cmd *cobra.Command, _ []string) {
 
 	// ZooKeeper init.
 	var zk kafkazk.Handler
-	if m || len(Config.topics) > 0 || p == "storage" {
+	if m || len(Config.topics) > 0 || storagePlacement(p) || ps != "" || rr > 0 {
 		var err error
 		zk, err = initZooKeeper(cmd)
 		if err != nil {
@@ -105,7 +162,7 @@ func rebuild(cmd *cobra.Command, _ []string) {
 
 	// Fetch broker metadata.
 	var withMetrics bool
-	if cmd.Flag("placement").Value.String() == "storage" {
+	if storagePlacement(p) {
 		checkMetaAge(cmd, zk)
 		withMetrics = true
 	}
@@ -117,22 +174,34 @@ func rebuild(cmd *cobra.Command, _ []string) {
 
 	// Fetch partition metadata.
 	var partitionMeta kafkazk.PartitionMetaMap
-	if cmd.Flag("placement").Value.String() == "storage" {
+	if storagePlacement(p) || rr > 0 {
 		partitionMeta = getPartitionMeta(cmd, zk)
 	}
 
 	// Build a partition map either from literal map text input or by fetching the
 	// map data from ZooKeeper. Store a copy of the original.
-	partitionMapIn, pending, excluded := getPartitionMap(cmd, zk)
+	partitionMapIn, pending, reassigning, excluded := getPartitionMap(cmd, zk)
 	originalMap := partitionMapIn.Copy()
 
 	// Get a list of affected topics.
 	printTopics(partitionMapIn)
 
+	// Apply any broker ID remapping to the input map. The original map is
+	// retained so that the remapped replicas are reflected as changes.
+	if Config.brokerRemap != nil {
+		if err := remapBrokers(partitionMapIn, Config.brokerRemap); err != nil {
+			fmt.Printf("\n[ERROR] --broker-remap: %s\n", err)
+			os.Exit(1)
+		}
+	}
+
 	// Print if any topics were excluded due to pending deletion or explicit
 	// exclusion.
 	printExcludedTopics(pending, excluded)
 
+	// Warn if any topics were excluded due to in-progress reassignments.
+	printReassigningTopics(reassigning)
+
 	brokers, bs := getBrokers(cmd, partitionMapIn, brokerMeta)
 	brokersOrig := brokers.Copy()
 
@@ -159,15 +228,33 @@ func rebuild(cmd *cobra.Command, _ []string) {
 	// Apply any replication factor settings.
 	updateReplicationFactor(cmd, partitionMapIn)
 
+	// Capture the current leaders; the rebuild reorders the input map.
+	leadersMap := partitionMapIn.Copy()
+
 	// Build a new map using the provided list of brokers. This is OK to run even
 	// when a no-op is intended.
 	partitionMapOut, errs := buildMap(cmd, partitionMapIn, partitionMeta, brokers, affinities)
 
 	// Optimize leaders.
 	if t, _ := cmd.Flags().GetBool("optimize-leadership"); t {
-		partitionMapOut.OptimizeLeaderFollower()
+		partitionMapOut.OptimizeLeaderFollowerWeighted(Config.leaderWeights)
 	}
 
+	// Co-locate affined topics if configured.
+	applyTopicAffinity(partitionMapOut, brokers)
+
+	// Pin preferred leaders to a rack if configured.
+	applyPreferredLeaderRack(cmd, partitionMapOut, brokers)
+
+	// Retain current leaders if configured.
+	applyKeepLeaders(cmd, leadersMap, partitionMapOut, brokers)
+
+	// Convert replicas to and from observers if configured.
+	applyObserverRacks(cmd, zk, partitionMapOut, brokers)
+
+	// Satisfy any Confluent replica placement constraints.
+	applyPlacementConstraints(zk, partitionMapOut, brokers)
+
 	// Count missing brokers as a warning.
 	if bs.Missing > 0 {
 		errs = append(errs, fmt.Errorf("%d provided brokers not found in ZooKeeper", bs.Missing))
@@ -187,18 +274,65 @@ func rebuild(cmd *cobra.Command, _ []string) {
 	}
 
 	// Print map change results.
-	printMapChanges(originalMap, partitionMapOut)
+	printMapChanges(originalMap, partitionMapOut, partitionMeta)
 
 	// Print broker assignment statistics.
 	printBrokerAssignmentStats(cmd, originalMap, partitionMapOut, brokersOrig, brokers)
 
+	// Print weighted broker targets if configured.
+	printCapacityTargets(partitionMapOut, brokers)
+
+	// Check for rack diversity regressions.
+	checkRackRegressions(cmd, originalMap, partitionMapOut, brokers)
+
+	// Warn about concentrated leadership.
+	checkLeaderConcentration(cmd, partitionMapOut)
+
 	// Print error/warnings.
 	handleOverridableErrs(cmd, errs)
 
+	// Trim the plan to the time budget if configured.
+	if trimmed := applyTimeBudget(cmd, originalMap, partitionMapOut, brokersOrig, partitionMeta); trimmed != partitionMapOut {
+		partitionMapOut = trimmed
+		// Regenerate the phased map from the retained moves.
+		if phasedMap != nil {
+			phasedMap = phasedReassignment(originalMap, partitionMapOut)
+		}
+	}
+
+	// Review moves for approval if configured.
+	if a, _ := cmd.Flags().GetBool("approve-moves"); a {
+		partitionMapOut = reviewPlan(cmd, originalMap, partitionMapOut, partitionMeta)
+		// Regenerate the phased map from the approved moves.
+		if phasedMap != nil {
+			phasedMap = phasedReassignment(originalMap, partitionMapOut)
+		}
+	}
+
+	// Write the plan summary if configured.
+	writePlanSummary(cmd, originalMap, partitionMapOut, brokersOrig, brokers)
+
+	// Write move priorities if configured.
+	writeMovePriorities(cmd, originalMap, partitionMapOut, brokersOrig, partitionMeta)
+
+	// Write throttled replica lists if configured.
+	writeThrottledReplicas(cmd, originalMap, partitionMapOut)
+
 	// Skip no-ops if configured.
 	if sno, _ := cmd.Flags().GetBool("skip-no-ops"); sno {
 		originalMap, partitionMapOut = skipReassignmentNoOps(originalMap, partitionMapOut)
 	}
 
-	writeMaps(cmd, partitionMapOut, phasedMap)
+	// Write maps, batching leadership changes if configured.
+	if n, _ := cmd.Flags().GetInt("max-concurrent-leader-moves"); n > 0 {
+		writeLeaderMoveBatches(cmd, originalMap, partitionMapOut, n)
+	} else {
+		writeMaps(cmd, partitionMapOut, phasedMap)
+	}
+
+	// Publish the reassignment scope if configured.
+	publishReassignmentScope(cmd, zk, originalMap, partitionMapOut)
+
+	// Apply the plan in batches if configured.
+	applyPhased(cmd, zk, originalMap, outputMaps(cmd, originalMap, partitionMapOut, phasedMap))
 }
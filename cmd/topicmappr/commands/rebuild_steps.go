@@ -17,10 +17,11 @@ import (
 // is either built from a string literal input (json from off-the-shelf Kafka
 // tools output) provided via the ---map-string flag, or, by building a map based
 // on topic config found in ZooKeeper for all topics matching input provided
-// via the --topics flag. Two []string are returned; topics excluded due to
-// pending deletion and topics explicitly excluded (via the --topics-exclude
-// flag), respectively.
-func getPartitionMap(cmd *cobra.Command, zk kafkazk.Handler) (*kafkazk.PartitionMap, []string, []string) {
+// via the --topics flag. Three []string are returned; topics excluded due to
+// pending deletion, topics excluded due to in-progress reassignments (when
+// fetched via ZooKeeper) and topics explicitly excluded (via the
+// --topics-exclude flag), respectively.
+func getPartitionMap(cmd *cobra.Command, zk kafkazk.Handler) (*kafkazk.PartitionMap, []string, []string, []string) {
 	ms := cmd.Flag("map-string").Value.String()
 
 	switch {
@@ -34,7 +35,7 @@ func getPartitionMap(cmd *cobra.Command, zk kafkazk.Handler) (*kafkazk.Partition
 		}
 		// Exclude topics explicitly listed.
 		et := removeTopics(pm, Config.topicsExclude)
-		return pm, []string{}, et
+		return pm, []string{}, []string{}, et
 	// The map needs to be fetched via ZooKeeper metadata for all specified topics.
 	case len(Config.topics) > 0:
 		pm, err := kafkazk.PartitionMapFromZK(Config.topics, zk)
@@ -46,13 +47,16 @@ func getPartitionMap(cmd *cobra.Command, zk kafkazk.Handler) (*kafkazk.Partition
 		// Exclude any topics that are pending deletion.
 		pd := stripPendingDeletes(pm, zk)
 
+		// Exclude any topics undergoing a reassignment.
+		rt := stripReassigning(pm, zk)
+
 		// Exclude topics explicitly listed.
 		et := removeTopics(pm, Config.topicsExclude)
 
-		return pm, pd, et
+		return pm, pd, rt, et
 	}
 
-	return nil, nil, nil
+	return nil, nil, nil, nil
 }
 
 // getSubAffinities, if enabled via --sub-affinity, takes reference broker maps
@@ -108,6 +112,8 @@ func getBrokers(cmd *cobra.Command, pm *kafkazk.PartitionMap, bm kafkazk.BrokerM
 		fmt.Printf("%s%s\n", indent, m)
 	}
 
+	brokers.SetWeights(Config.capacityWeights)
+
 	return brokers, bs
 }
 
@@ -177,6 +183,42 @@ func updateReplicationFactor(cmd *cobra.Command, pm *kafkazk.PartitionMap) {
 	}
 }
 
+// remapBrokers takes a PartitionMap and a map of old to new broker IDs and
+// rewrites all replica references of each old ID to the new ID. Remapping is
+// applied against the original IDs; chained entries (e.g. 1001:1002 and
+// 1002:1003) aren't followed. An error is returned, and the PartitionMap left
+// unmodified, if a remap would result in a duplicate replica in any partition.
+func remapBrokers(pm *kafkazk.PartitionMap, remap map[int]int) error {
+	remapped := make([][]int, len(pm.Partitions))
+
+	for i, p := range pm.Partitions {
+		replicas := make([]int, len(p.Replicas))
+		seen := map[int]struct{}{}
+
+		for j, id := range p.Replicas {
+			if to, ok := remap[id]; ok {
+				id = to
+			}
+
+			if _, dupe := seen[id]; dupe {
+				return fmt.Errorf("remapping %s p%d %v results in duplicate replica %d",
+					p.Topic, p.Partition, p.Replicas, id)
+			}
+
+			seen[id] = struct{}{}
+			replicas[j] = id
+		}
+
+		remapped[i] = replicas
+	}
+
+	for i := range pm.Partitions {
+		pm.Partitions[i].Replicas = remapped[i]
+	}
+
+	return nil
+}
+
 // buildMap takes an input PartitionMap, rebuild parameters, and all partition/broker
 // metadata structures required to generate the output PartitionMap. A []string of
 // warnings / advisories is returned if any are encountered.
@@ -184,6 +226,7 @@ func buildMap(cmd *cobra.Command, pm *kafkazk.PartitionMap, pmm kafkazk.Partitio
 	placement := cmd.Flag("placement").Value.String()
 	psf, _ := cmd.Flags().GetFloat64("partition-size-factor")
 	mrrid, _ := cmd.Flags().GetInt("min-rack-ids")
+	hw, _ := cmd.Flags().GetFloat64("hybrid-weight")
 
 	rebuildParams := kafkazk.RebuildParams{
 		PMM:              pmm,
@@ -192,12 +235,17 @@ func buildMap(cmd *cobra.Command, pm *kafkazk.PartitionMap, pmm kafkazk.Partitio
 		Optimization:     cmd.Flag("optimize").Value.String(),
 		PartnSzFactor:    psf,
 		MinUniqueRackIDs: mrrid,
+		HybridWeight:     hw,
 	}
 
 	if af != nil {
 		rebuildParams.Affinities = af
 	}
 
+	if plb, _ := cmd.Flags().GetBool("prefer-leader-balance"); plb {
+		rebuildParams.SoftConstraints = []kafkazk.SoftConstraint{kafkazk.NewLeaderBalance(1)}
+	}
+
 	// If we're doing a force rebuild, the input map must have all brokers stripped out.
 	// A few notes about doing force rebuilds:
 	// - Map rebuilds should always be called on a stripped PartitionMap copy.
@@ -214,9 +262,9 @@ func buildMap(cmd *cobra.Command, pm *kafkazk.PartitionMap, pmm kafkazk.Partitio
 	if fr, _ := cmd.Flags().GetBool("force-rebuild"); fr {
 		// Get a stripped map that we'll call rebuild on.
 		partitionMapInStripped := pm.Strip()
-		// If the storage placement strategy is being used,
+		// If a storage based placement strategy is being used,
 		// update the broker StorageFree values.
-		if placement == "storage" {
+		if storagePlacement(placement) {
 			allBrokers := func(b *kafkazk.Broker) bool { return true }
 			err := rebuildParams.BM.SubStorage(pm, pmm, allBrokers)
 			if err != nil {
@@ -230,7 +278,7 @@ func buildMap(cmd *cobra.Command, pm *kafkazk.PartitionMap, pmm kafkazk.Partitio
 	}
 
 	// Update the StorageFree only on brokers marked for replacement.
-	if placement == "storage" {
+	if storagePlacement(placement) {
 		replacedBrokers := func(b *kafkazk.Broker) bool { return b.Replace }
 		err := rebuildParams.BM.SubStorage(pm, pmm, replacedBrokers)
 		if err != nil {
@@ -243,6 +291,12 @@ func buildMap(cmd *cobra.Command, pm *kafkazk.PartitionMap, pmm kafkazk.Partitio
 	return pm.Rebuild(rebuildParams)
 }
 
+// storagePlacement returns whether the placement strategy accounts for
+// partition sizes, requiring broker and partition metrics.
+func storagePlacement(placement string) bool {
+	return placement == "storage" || placement == "hybrid"
+}
+
 // phasedReassignment takes the input map (the current ISR states) and the
 // output map (the results of the topicmappr input parameters / computation)
 // and prepends the current leaders as the leaders of the output map.
@@ -53,3 +53,77 @@ func TestPhasedReassignment(t *testing.T) {
 		}
 	}
 }
+
+func TestRemapBrokers(t *testing.T) {
+	zk := kafkazk.Stub{}
+	pm, _ := zk.GetPartitionMap("test_topic")
+
+	remap := map[int]int{1001: 2001, 1002: 2002}
+
+	if err := remapBrokers(pm, remap); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]int{
+		{2001, 2002},
+		{2002, 2001},
+		{1003, 1004, 2001},
+		{1004, 1003, 2002},
+	}
+
+	for i, p := range pm.Partitions {
+		seen := map[int]struct{}{}
+		for j, id := range p.Replicas {
+			if _, old := remap[id]; old {
+				t.Errorf("p%d: old broker ID %d not remapped", p.Partition, id)
+			}
+
+			if _, dupe := seen[id]; dupe {
+				t.Errorf("p%d: duplicate replica %d", p.Partition, id)
+			}
+			seen[id] = struct{}{}
+
+			if id != expected[i][j] {
+				t.Errorf("p%d: expected replicas %v, got %v", p.Partition, expected[i], p.Replicas)
+				break
+			}
+		}
+	}
+
+	// Remapping to a broker already in the replica set is an error.
+	pm, _ = zk.GetPartitionMap("test_topic")
+	original := pm.Copy()
+
+	if err := remapBrokers(pm, map[int]int{1001: 1002}); err == nil {
+		t.Error("Expected duplicate replica error")
+	}
+
+	if eq, _ := pm.Equal(original); !eq {
+		t.Error("Expected the PartitionMap to be unmodified on error")
+	}
+}
+
+func TestBrokerRemapFromString(t *testing.T) {
+	r, err := brokerRemapFromString("1001:2001, 1002:2002")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r) != 2 || r[1001] != 2001 || r[1002] != 2002 {
+		t.Errorf("Unexpected remap table %v", r)
+	}
+
+	invalid := []string{
+		"1001",
+		"1001:a",
+		"1001:1001",
+		"1001:2001,1001:2002",
+		"1001:2001,1002:2001",
+	}
+
+	for _, s := range invalid {
+		if _, err := brokerRemapFromString(s); err == nil {
+			t.Errorf("Expected error for '%s'", s)
+		}
+	}
+}
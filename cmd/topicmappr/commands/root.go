@@ -26,4 +26,5 @@ func init() {
 	rootCmd.PersistentFlags().String("zk-addr", "localhost:2181", "ZooKeeper connect string")
 	rootCmd.PersistentFlags().String("zk-prefix", "", "ZooKeeper prefix (if Kafka is configured with a chroot path prefix)")
 	rootCmd.PersistentFlags().Bool("ignore-warns", false, "Produce a map even if warnings are encountered")
+	rootCmd.PersistentFlags().String("snapshot", "", "Read cluster metadata from a snapshot file rather than ZooKeeper (see the snapshot command)")
 }
@@ -0,0 +1,64 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Write a snapshot of cluster metadata for offline planning",
+	Long: `Write the cluster metadata used by topicmappr (brokers, topics, partition maps and
+metrics) to a snapshot file. Any command can then be run against the snapshot rather than
+ZooKeeper with the global --snapshot flag.`,
+	Run: snapshot,
+}
+
+func init() {
+	rootCmd.AddCommand(snapshotCmd)
+
+	snapshotCmd.Flags().String("out-file", "", "Snapshot output file")
+	snapshotCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics")
+
+	snapshotCmd.MarkFlagRequired("out-file")
+}
+
+func snapshot(cmd *cobra.Command, _ []string) {
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	s, err := kafkazk.NewSnapshot(zk)
+	if err != nil {
+		fmt.Printf("\n[ERROR] failed to capture snapshot: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("\nSnapshot:")
+	fmt.Printf("%sbrokers: %d\n", indent, len(s.Brokers))
+	fmt.Printf("%stopics: %d\n", indent, len(s.Topics))
+
+	if s.Metrics {
+		fmt.Printf("%smetrics age: %s\n", indent, s.MetricsAge.Round(time.Second))
+	} else {
+		fmt.Printf("%s[WARN] metrics unavailable; storage placement can't be used with this snapshot\n", indent)
+	}
+
+	p := cmd.Flag("out-file").Value.String()
+	if err := s.WriteFile(p); err != nil {
+		fmt.Printf("\n[ERROR] failed to write snapshot: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nSnapshot written to %s\n", p)
+}
@@ -0,0 +1,307 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+
+	"github.com/spf13/cobra"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Verify that the cluster converged to an applied partition map",
+	Long: `Verify that the cluster converged to an applied partition map. The live state
+of each topic in the map is read from ZooKeeper and any partitions whose replica
+sets don't match the intended map are reported. With --constraints, the map is
+instead checked for placement constraint violations before being applied.`,
+	Run: verify,
+}
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+
+	verifyCmd.Flags().String("map-string", "", "Partition map to verify provided as a string literal")
+	verifyCmd.Flags().String("map-file", "", "Partition map to verify provided as a file path (e.g. a topicmappr output file)")
+	verifyCmd.Flags().String("zk-metrics-prefix", "topicmappr", "ZooKeeper namespace prefix for Kafka metrics")
+	verifyCmd.Flags().Bool("constraints", false, "Check the map for rack-awareness, duplicate replica, replication factor and broker liveness violations rather than convergence")
+}
+
+// partitionDivergence describes a partition whose live replica set
+// doesn't match the intended replica set.
+type partitionDivergence struct {
+	topic     string
+	partition int
+	current   []int
+	target    []int
+}
+
+func verify(cmd *cobra.Command, _ []string) {
+	ms := cmd.Flag("map-string").Value.String()
+	mf := cmd.Flag("map-file").Value.String()
+
+	switch {
+	case ms == "" && mf == "":
+		fmt.Println("\n[ERROR] must specify either --map-string or --map-file")
+		defaultsAndExit()
+	case ms != "" && mf != "":
+		fmt.Println("\n[ERROR] --map-string and --map-file are mutually exclusive")
+		defaultsAndExit()
+	}
+
+	if mf != "" {
+		b, err := ioutil.ReadFile(mf)
+		if err != nil {
+			fmt.Printf("\n[ERROR] %s\n", err)
+			os.Exit(1)
+		}
+		ms = string(b)
+	}
+
+	target, err := kafkazk.PartitionMapFromString(ms)
+	if err != nil {
+		fmt.Printf("\n[ERROR] %s\n", err)
+		os.Exit(1)
+	}
+
+	// ZooKeeper init.
+	zk, err := initZooKeeper(cmd)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	defer zk.Close()
+
+	// Print topics being verified.
+	printTopics(target)
+
+	if c, _ := cmd.Flags().GetBool("constraints"); c {
+		verifyConstraints(cmd, zk, target)
+		return
+	}
+
+	diverged, err := unconvergedPartitions(zk, target)
+	if err != nil {
+		fmt.Printf("\n[ERROR] %s\n", err)
+		os.Exit(1)
+	}
+
+	// Note any topics still undergoing a reassignment; these
+	// are expected to not have converged yet.
+	reassigning := zk.GetReassignments()
+
+	fmt.Println("\nVerification:")
+
+	if len(diverged) == 0 {
+		fmt.Printf("%sOK: all %d partitions converged\n", indent, len(target.Partitions))
+		return
+	}
+
+	for _, d := range diverged {
+		var note string
+		if _, ok := reassigning[d.topic][d.partition]; ok {
+			note = " (reassignment in progress)"
+		}
+
+		fmt.Printf("%s%s p%d: current %v, target %v%s\n",
+			indent, d.topic, d.partition, d.current, d.target, note)
+	}
+
+	fmt.Printf("%s-\n%s[ERROR] %d of %d partitions did not converge\n",
+		indent, indent, len(diverged), len(target.Partitions))
+
+	os.Exit(1)
+}
+
+// unconvergedPartitions takes a kafkazk.Handler and the intended
+// *kafkazk.PartitionMap. The live replica sets for all partitions in the
+// map are fetched and a []partitionDivergence is returned for any partitions
+// where the live replica set doesn't match the intended replica set.
+func unconvergedPartitions(zk kafkazk.Handler, target *kafkazk.PartitionMap) ([]partitionDivergence, error) {
+	var diverged []partitionDivergence
+	var states = map[string]*kafkazk.TopicState{}
+
+	for _, p := range target.Partitions {
+		// Fetch the live topic state if we haven't already.
+		if _, ok := states[p.Topic]; !ok {
+			ts, err := zk.GetTopicState(p.Topic)
+			if err != nil {
+				return nil, fmt.Errorf("error fetching state for topic %s: %s", p.Topic, err)
+			}
+			states[p.Topic] = ts
+		}
+
+		current := states[p.Topic].Partitions[strconv.Itoa(p.Partition)]
+
+		if !replicasEqual(current, p.Replicas) {
+			diverged = append(diverged, partitionDivergence{
+				topic:     p.Topic,
+				partition: p.Partition,
+				current:   current,
+				target:    p.Replicas,
+			})
+		}
+	}
+
+	return diverged, nil
+}
+
+func replicasEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// planViolation describes a partition in a partition map that violates a
+// placement constraint.
+type planViolation struct {
+	topic     string
+	partition int
+	replicas  []int
+	reason    string
+}
+
+// verifyConstraints prints any placement constraint violations in the target
+// map, exiting with an error if any are found.
+func verifyConstraints(cmd *cobra.Command, zk kafkazk.Handler, target *kafkazk.PartitionMap) {
+	brokerMeta := getBrokerMeta(cmd, zk, false)
+
+	live, err := liveReplicaSets(zk, target)
+	if err != nil {
+		fmt.Printf("\n[ERROR] %s\n", err)
+		os.Exit(1)
+	}
+
+	violations := planViolations(target, brokerMeta, live)
+
+	fmt.Println("\nConstraint violations:")
+
+	if len(violations) == 0 {
+		fmt.Printf("%sOK: no violations in %d partitions\n", indent, len(target.Partitions))
+		return
+	}
+
+	for _, v := range violations {
+		fmt.Printf("%s%s p%d %v: %s\n", indent, v.topic, v.partition, v.replicas, v.reason)
+	}
+
+	fmt.Printf("%s-\n%s[ERROR] %d constraint violations found\n", indent, indent, len(violations))
+
+	os.Exit(1)
+}
+
+// liveReplicaSets takes a kafkazk.Handler and a *kafkazk.PartitionMap and
+// returns the live replica sets for all partitions of the topics in the map,
+// keyed by topic name then partition number.
+func liveReplicaSets(zk kafkazk.Handler, pm *kafkazk.PartitionMap) (map[string]map[int][]int, error) {
+	live := map[string]map[int][]int{}
+
+	for _, p := range pm.Partitions {
+		if _, ok := live[p.Topic]; ok {
+			continue
+		}
+
+		ts, err := zk.GetTopicState(p.Topic)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching state for topic %s: %s", p.Topic, err)
+		}
+
+		live[p.Topic] = map[int][]int{}
+		for n, replicas := range ts.Partitions {
+			i, _ := strconv.Atoi(n)
+			live[p.Topic][i] = replicas
+		}
+	}
+
+	return live, nil
+}
+
+// planViolations takes a *kafkazk.PartitionMap, the kafkazk.BrokerMetaMap of
+// all registered brokers and the live replica sets of the topics in the map
+// (see liveReplicaSets). A planViolation is returned for each partition that
+// doesn't exist, has a replica count differing from its live replication
+// factor, lists a broker more than once or is assigned to an unregistered
+// broker. Replicas sharing a rack are also violations if the cluster has at
+// least as many racks as replicas; brokers without a rack ID aren't
+// considered for rack-awareness.
+func planViolations(pm *kafkazk.PartitionMap, bm kafkazk.BrokerMetaMap, live map[string]map[int][]int) []planViolation {
+	var violations []planViolation
+
+	racks := map[string]struct{}{}
+	for _, b := range bm {
+		if b.Rack != "" {
+			racks[b.Rack] = struct{}{}
+		}
+	}
+
+	for _, p := range pm.Partitions {
+		violation := func(format string, a ...interface{}) {
+			violations = append(violations, planViolation{
+				topic:     p.Topic,
+				partition: p.Partition,
+				replicas:  p.Replicas,
+				reason:    fmt.Sprintf(format, a...),
+			})
+		}
+
+		if current, exists := live[p.Topic][p.Partition]; !exists {
+			violation("partition doesn't exist")
+		} else if len(p.Replicas) != len(current) {
+			violation("replication factor %d, expected %d", len(p.Replicas), len(current))
+		}
+
+		seen := map[int]struct{}{}
+		inRack := map[string]int{}
+
+		for _, id := range p.Replicas {
+			if _, dupe := seen[id]; dupe {
+				violation("duplicate replica %d", id)
+				continue
+			}
+			seen[id] = struct{}{}
+
+			meta, exists := bm[id]
+			if !exists {
+				violation("broker %d not registered", id)
+				continue
+			}
+
+			if meta.Rack != "" {
+				inRack[meta.Rack]++
+			}
+		}
+
+		// Rack-awareness is only enforceable with at least as many racks as
+		// replicas.
+		if len(racks) < len(seen) {
+			continue
+		}
+
+		var shared []string
+		for r, n := range inRack {
+			if n > 1 {
+				shared = append(shared, r)
+			}
+		}
+
+		sort.Strings(shared)
+		for _, r := range shared {
+			violation("multiple replicas in rack %s", r)
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+)
+
+func TestUnconvergedPartitions(t *testing.T) {
+	zk := &kafkazk.Stub{}
+
+	// The stub topic state has partition 2 on [1004 1005].
+	target, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1000,1001]},
+		{"topic":"test","partition":1,"replicas":[1002,1003]},
+		{"topic":"test","partition":2,"replicas":[1004,1006]},
+		{"topic":"test","partition":3,"replicas":[1006,1007]},
+		{"topic":"test","partition":4,"replicas":[1008,1009]}]}`)
+
+	diverged, err := unconvergedPartitions(zk, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diverged) != 1 {
+		t.Fatalf("Expected 1 unconverged partition, got %d", len(diverged))
+	}
+
+	d := diverged[0]
+
+	if d.topic != "test" || d.partition != 2 {
+		t.Errorf("Expected test p2, got %s p%d", d.topic, d.partition)
+	}
+
+	if !replicasEqual(d.current, []int{1004, 1005}) {
+		t.Errorf("Expected current replicas [1004 1005], got %v", d.current)
+	}
+
+	if !replicasEqual(d.target, []int{1004, 1006}) {
+		t.Errorf("Expected target replicas [1004 1006], got %v", d.target)
+	}
+}
+
+func TestPlanViolations(t *testing.T) {
+	zk := &kafkazk.Stub{}
+	bm, _ := zk.GetAllBrokerMeta(false)
+
+	// The stub topic state has 2 replicas per partition. Brokers 1001 and
+	// 1004 are in rack a, 1002 and 1005 in rack b and 1006 isn't registered.
+	target, _ := kafkazk.PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1001,1002]},
+		{"topic":"test","partition":1,"replicas":[1001,1004]},
+		{"topic":"test","partition":2,"replicas":[1005,1005]},
+		{"topic":"test","partition":3,"replicas":[1002,1006]},
+		{"topic":"test","partition":4,"replicas":[1004,1005,1003]},
+		{"topic":"test","partition":5,"replicas":[1004,1005]}]}`)
+
+	live, err := liveReplicaSets(zk, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	violations := planViolations(target, bm, live)
+
+	expected := map[int]string{
+		// The hand-edited rack violation.
+		1: "multiple replicas in rack a",
+		2: "duplicate replica 1005",
+		3: "broker 1006 not registered",
+		4: "replication factor 3, expected 2",
+		5: "partition doesn't exist",
+	}
+
+	if len(violations) != len(expected) {
+		t.Fatalf("Expected %d violations, got %d: %v", len(expected), len(violations), violations)
+	}
+
+	for _, v := range violations {
+		if v.reason != expected[v.partition] {
+			t.Errorf("Expected p%d violation '%s', got '%s'", v.partition, expected[v.partition], v.reason)
+		}
+	}
+}
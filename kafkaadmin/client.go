@@ -3,6 +3,7 @@ package kafkaadmin
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
@@ -19,7 +20,10 @@ type FactoryFunc func(conf *kafka.ConfigMap) (*kafka.AdminClient, error)
 
 // Client implements a KafkaAdmin.
 type Client struct {
-	c *kafka.AdminClient
+	c     *kafka.AdminClient
+	cache *metadataCache
+	// The client configuration, used for auxiliary clients.
+	cfg *kafka.ConfigMap
 }
 
 // Config holds Client configuration parameters.
@@ -31,6 +35,10 @@ type Config struct {
 	SASLMechanism    string
 	SASLUsername     string
 	SASLPassword     string
+	// If non-zero, cluster metadata is cached for this duration. Cached
+	// metadata is invalidated by topic creates and deletes and can be
+	// refreshed with RefreshMetadata.
+	MetadataCacheTTL time.Duration
 }
 
 // Close closes the Client.
@@ -94,6 +102,11 @@ func newClient(cfg Config, factory FactoryFunc) (*Client, error) {
 
 	k, err := factory(kafkaCfg)
 	c.c = k
+	c.cfg = kafkaCfg
+
+	if cfg.MetadataCacheTTL > 0 {
+		c.cache = newMetadataCache(cfg.MetadataCacheTTL, c.requestMetadata)
+	}
 
 	if err != nil {
 		err = fmt.Errorf("[librdkafka] %s", err)
@@ -12,6 +12,11 @@ type KafkaAdmin interface {
 	Close()
 	CreateTopic(context.Context, CreateTopicConfig) error
 	DeleteTopic(context.Context, string) error
+	TopicExists(context.Context, string) (bool, error)
+	WaitForTopic(context.Context, string) error
+	DescribeTopics(context.Context, []string) (TopicStates, error)
+	GetLogEndOffsets(context.Context, string) (PartitionOffsets, error)
+	RefreshMetadata(context.Context) error
 }
 
 // NewClient returns a KafkaAdmin.
@@ -0,0 +1,229 @@
+// Package kafkaadmintest provides a fake kafkaadmin.KafkaAdmin for use in
+// tests.
+package kafkaadmintest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/DataDog/kafka-kit/v3/kafkaadmin"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// Call is a method call recorded by a FakeAdmin.
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// FakeAdmin is a kafkaadmin.KafkaAdmin that holds cluster state in memory.
+// Responses can be programmed through the exported fields and all calls are
+// recorded. The exported fields should be set before the FakeAdmin is used.
+type FakeAdmin struct {
+	// Topics is the cluster state. Topics created without error are added
+	// and deleted topics are removed.
+	Topics kafkaadmin.TopicStates
+	// Offsets is a mapping of topic name to the offsets returned by
+	// GetLogEndOffsets.
+	Offsets map[string]kafkaadmin.PartitionOffsets
+	// CreateTopicErrors and DeleteTopicErrors are mappings of topic name to
+	// the error returned when creating or deleting the topic.
+	CreateTopicErrors map[string]error
+	DeleteTopicErrors map[string]error
+	// Err, if non-nil, is returned by all calls that return an error.
+	Err error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewFakeAdmin returns a *FakeAdmin with no topics.
+func NewFakeAdmin() *FakeAdmin {
+	return &FakeAdmin{
+		Topics:            kafkaadmin.TopicStates{},
+		Offsets:           map[string]kafkaadmin.PartitionOffsets{},
+		CreateTopicErrors: map[string]error{},
+		DeleteTopicErrors: map[string]error{},
+	}
+}
+
+// Calls returns the recorded calls, in order. If methods are specified, only
+// calls to those methods are returned.
+func (f *FakeAdmin) Calls(methods ...string) []Call {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if len(methods) == 0 {
+		return append([]Call{}, f.calls...)
+	}
+
+	var calls []Call
+	for _, c := range f.calls {
+		for _, m := range methods {
+			if c.Method == m {
+				calls = append(calls, c)
+			}
+		}
+	}
+
+	return calls
+}
+
+// record records a call. The caller must hold the lock.
+func (f *FakeAdmin) record(method string, args ...interface{}) {
+	f.calls = append(f.calls, Call{Method: method, Args: args})
+}
+
+// Close implements kafkaadmin.KafkaAdmin.
+func (f *FakeAdmin) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("Close")
+}
+
+// CreateTopic implements kafkaadmin.KafkaAdmin. Unless an error is
+// programmed, the topic is added to Topics with its partitions placed
+// according to the ReplicaAssignment, if any.
+func (f *FakeAdmin) CreateTopic(_ context.Context, cfg kafkaadmin.CreateTopicConfig) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("CreateTopic", cfg)
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	if err := f.CreateTopicErrors[cfg.Name]; err != nil {
+		return err
+	}
+
+	ts := kafkaadmin.TopicState{Name: cfg.Name}
+	for i := 0; i < cfg.Partitions; i++ {
+		ps := kafkaadmin.PartitionState{ID: int32(i), Leader: -1}
+		if i < len(cfg.ReplicaAssignment) && len(cfg.ReplicaAssignment[i]) > 0 {
+			ps.Replicas = cfg.ReplicaAssignment[i]
+			ps.ISR = cfg.ReplicaAssignment[i]
+			ps.Leader = cfg.ReplicaAssignment[i][0]
+		}
+		ts.Partitions = append(ts.Partitions, ps)
+	}
+
+	f.Topics[cfg.Name] = ts
+
+	return nil
+}
+
+// DeleteTopic implements kafkaadmin.KafkaAdmin. Unless an error is
+// programmed, the topic is removed from Topics.
+func (f *FakeAdmin) DeleteTopic(_ context.Context, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("DeleteTopic", name)
+
+	if f.Err != nil {
+		return f.Err
+	}
+
+	if err := f.DeleteTopicErrors[name]; err != nil {
+		return err
+	}
+
+	delete(f.Topics, name)
+
+	return nil
+}
+
+// TopicExists implements kafkaadmin.KafkaAdmin.
+func (f *FakeAdmin) TopicExists(_ context.Context, name string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("TopicExists", name)
+
+	if f.Err != nil {
+		return false, f.Err
+	}
+
+	_, exists := f.Topics[name]
+
+	return exists, nil
+}
+
+// WaitForTopic implements kafkaadmin.KafkaAdmin. If the topic doesn't exist,
+// WaitForTopic blocks until the context is done.
+func (f *FakeAdmin) WaitForTopic(ctx context.Context, name string) error {
+	f.mu.Lock()
+	f.record("WaitForTopic", name)
+	_, exists := f.Topics[name]
+	err := f.Err
+	f.mu.Unlock()
+
+	if err != nil || exists {
+		return err
+	}
+
+	<-ctx.Done()
+
+	return ctx.Err()
+}
+
+// DescribeTopics implements kafkaadmin.KafkaAdmin. As with the Client, topics
+// that don't exist have an ErrTopicMetadata Err.
+func (f *FakeAdmin) DescribeTopics(_ context.Context, names []string) (kafkaadmin.TopicStates, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("DescribeTopics", names)
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	states := kafkaadmin.TopicStates{}
+	for _, name := range names {
+		ts, exists := f.Topics[name]
+		if !exists {
+			ts = kafkaadmin.TopicState{Name: name, Err: unknownTopic(name)}
+		}
+		states[name] = ts
+	}
+
+	return states, nil
+}
+
+// RefreshMetadata implements kafkaadmin.KafkaAdmin.
+func (f *FakeAdmin) RefreshMetadata(context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("RefreshMetadata")
+
+	return f.Err
+}
+
+// GetLogEndOffsets implements kafkaadmin.KafkaAdmin, returning the Offsets
+// for the topic.
+func (f *FakeAdmin) GetLogEndOffsets(_ context.Context, topic string) (kafkaadmin.PartitionOffsets, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.record("GetLogEndOffsets", topic)
+
+	if f.Err != nil {
+		return nil, f.Err
+	}
+
+	if _, exists := f.Topics[topic]; !exists {
+		return nil, unknownTopic(topic)
+	}
+
+	return f.Offsets[topic], nil
+}
+
+func unknownTopic(name string) error {
+	return kafkaadmin.ErrTopicMetadata{Topic: name, Code: kafka.ErrUnknownTopicOrPart}
+}
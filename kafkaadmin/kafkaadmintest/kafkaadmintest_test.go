@@ -0,0 +1,46 @@
+package kafkaadmintest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkaadmin"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// FakeAdmin must implement kafkaadmin.KafkaAdmin.
+var _ kafkaadmin.KafkaAdmin = &FakeAdmin{}
+
+func TestFakeAdminCreateTopic(t *testing.T) {
+	f := NewFakeAdmin()
+	f.CreateTopicErrors["denied"] = errors.New("policy violation")
+
+	ctx := context.Background()
+
+	created := kafkaadmin.CreateTopicConfig{
+		Name:              "test",
+		Partitions:        2,
+		ReplicaAssignment: kafkaadmin.ReplicaAssignment{{1001, 1002}, {1002, 1001}},
+	}
+	denied := kafkaadmin.CreateTopicConfig{Name: "denied", Partitions: 1, ReplicationFactor: 2}
+
+	assert.Nil(t, f.CreateTopic(ctx, created))
+	assert.EqualError(t, f.CreateTopic(ctx, denied), "policy violation")
+
+	// Both calls are recorded.
+	calls := f.Calls("CreateTopic")
+	assert.Len(t, calls, 2)
+	assert.Equal(t, Call{Method: "CreateTopic", Args: []interface{}{created}}, calls[0])
+	assert.Equal(t, Call{Method: "CreateTopic", Args: []interface{}{denied}}, calls[1])
+
+	// Only the successfully created topic exists.
+	states, err := f.DescribeTopics(ctx, []string{"test", "denied"})
+	assert.Nil(t, err)
+	assert.Nil(t, states["test"].Err)
+	assert.Equal(t, int32(1002), states["test"].Partitions[1].Leader)
+	assert.IsType(t, kafkaadmin.ErrTopicMetadata{}, states["denied"].Err)
+
+	assert.Len(t, f.Calls(), 3)
+}
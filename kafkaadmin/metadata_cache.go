@@ -0,0 +1,87 @@
+package kafkaadmin
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// metadataFetcher fetches cluster metadata.
+type metadataFetcher func(context.Context) (*kafka.Metadata, error)
+
+// metadataCache caches cluster metadata for a TTL.
+type metadataCache struct {
+	sync.Mutex
+	ttl     time.Duration
+	fetch   metadataFetcher
+	md      *kafka.Metadata
+	fetched time.Time
+}
+
+func newMetadataCache(ttl time.Duration, fetch metadataFetcher) *metadataCache {
+	return &metadataCache{ttl: ttl, fetch: fetch}
+}
+
+// get returns the cached metadata if it's younger than the TTL, otherwise
+// fresh metadata is fetched and cached.
+func (m *metadataCache) get(ctx context.Context) (*kafka.Metadata, error) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.md != nil && time.Since(m.fetched) < m.ttl {
+		return m.md, nil
+	}
+
+	return m.refresh(ctx)
+}
+
+// refresh fetches and caches fresh metadata. The caller must hold the lock.
+func (m *metadataCache) refresh(ctx context.Context) (*kafka.Metadata, error) {
+	md, err := m.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	m.md, m.fetched = md, time.Now()
+
+	return md, nil
+}
+
+// invalidate clears the cached metadata.
+func (m *metadataCache) invalidate() {
+	m.Lock()
+	defer m.Unlock()
+
+	m.md = nil
+}
+
+// RefreshMetadata fetches fresh cluster metadata, replacing any cached
+// metadata. This is useful following mutations when metadata caching is
+// enabled. If caching is disabled, this only checks that metadata can be
+// fetched.
+func (c Client) RefreshMetadata(ctx context.Context) error {
+	_, err := c.fetchMetadata(ctx)
+	return err
+}
+
+// fetchMetadata fetches fresh cluster metadata, updating the cache if
+// enabled.
+func (c Client) fetchMetadata(ctx context.Context) (*kafka.Metadata, error) {
+	if c.cache == nil {
+		return c.requestMetadata(ctx)
+	}
+
+	c.cache.Lock()
+	defer c.cache.Unlock()
+
+	return c.cache.refresh(ctx)
+}
+
+// invalidateMetadata clears any cached metadata.
+func (c Client) invalidateMetadata() {
+	if c.cache != nil {
+		c.cache.invalidate()
+	}
+}
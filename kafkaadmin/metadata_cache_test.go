@@ -0,0 +1,56 @@
+package kafkaadmin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetadataCache(t *testing.T) {
+	var fetches int
+
+	fetch := func(_ context.Context) (*kafka.Metadata, error) {
+		fetches++
+		noErr := kafka.NewError(kafka.ErrNoError, "", false)
+		return &kafka.Metadata{
+			Topics: map[string]kafka.TopicMetadata{
+				"test": {Topic: "test", Error: noErr},
+			},
+		}, nil
+	}
+
+	c := Client{cache: newMetadataCache(time.Minute, fetch)}
+	ctx := context.Background()
+
+	// Two describes within the TTL fetch metadata once.
+	for i := 0; i < 2; i++ {
+		states, err := c.DescribeTopics(ctx, []string{"test"})
+		assert.Nil(t, err)
+		assert.Nil(t, states["test"].Err)
+	}
+	assert.Equal(t, 1, fetches)
+
+	// A refresh forces a fetch, which is then cached.
+	assert.Nil(t, c.RefreshMetadata(ctx))
+	assert.Equal(t, 2, fetches)
+
+	exists, err := c.TopicExists(ctx, "test")
+	assert.Nil(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, 2, fetches)
+
+	// Expired metadata is refetched.
+	c.cache.fetched = time.Now().Add(-time.Hour)
+	_, err = c.DescribeTopics(ctx, []string{"test"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, fetches)
+
+	// Invalidated metadata is refetched.
+	c.invalidateMetadata()
+	_, err = c.DescribeTopics(ctx, []string{"test"})
+	assert.Nil(t, err)
+	assert.Equal(t, 4, fetches)
+}
@@ -0,0 +1,78 @@
+package kafkaadmin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// PartitionOffsets is a mapping of partition ID to Offsets.
+type PartitionOffsets map[int32]Offsets
+
+// Offsets holds the low watermark (the earliest available offset) and the
+// log-end offset (the offset of the next message to be written) of a
+// partition.
+type Offsets struct {
+	LowWatermark int64
+	LogEnd       int64
+}
+
+// watermarkQuerier queries the watermark offsets of a partition. This is
+// implemented by both kafka.Producer and kafka.Consumer.
+type watermarkQuerier interface {
+	QueryWatermarkOffsets(topic string, partition int32, timeoutMs int) (low, high int64, err error)
+}
+
+// GetLogEndOffsets returns the low watermark and log-end offsets for each
+// partition of the named topic. Partitions are looked up in the cluster
+// metadata and the offsets are queried from each partition leader. An error is
+// returned if the topic metadata is in error or the offsets for any partition
+// can't be fetched.
+func (c Client) GetLogEndOffsets(ctx context.Context, topic string) (PartitionOffsets, error) {
+	states, err := c.DescribeTopics(ctx, []string{topic})
+	if err != nil {
+		return nil, err
+	}
+
+	ts := states[topic]
+	if ts.Err != nil {
+		return nil, ts.Err
+	}
+
+	var ids []int32
+	for _, p := range ts.Partitions {
+		ids = append(ids, p.ID)
+	}
+
+	// The admin client doesn't support offset requests; a producer is used
+	// since, unlike a consumer, it doesn't require a group.id.
+	p, err := kafka.NewProducer(c.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("[librdkafka] %s", err)
+	}
+	defer p.Close()
+
+	return getOffsets(ctx, p, topic, ids)
+}
+
+func getOffsets(ctx context.Context, q watermarkQuerier, topic string, partitions []int32) (PartitionOffsets, error) {
+	offsets := PartitionOffsets{}
+
+	for _, id := range partitions {
+		// Each query is bounded by the time remaining in the context.
+		timeout, err := requestTimeout(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		low, high, err := q.QueryWatermarkOffsets(topic, id, int(timeout.Milliseconds()))
+		if err != nil {
+			return nil, fmt.Errorf("topic %s partition %d: %s", topic, id, err)
+		}
+
+		offsets[id] = Offsets{LowWatermark: low, LogEnd: high}
+	}
+
+	return offsets, nil
+}
@@ -0,0 +1,42 @@
+package kafkaadmin
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockWatermarkQuerier returns watermark offsets from a mapping of partition
+// ID to [low, high] offsets.
+type mockWatermarkQuerier map[int32][2]int64
+
+func (m mockWatermarkQuerier) QueryWatermarkOffsets(_ string, partition int32, _ int) (int64, int64, error) {
+	o, exists := m[partition]
+	if !exists {
+		return 0, 0, errors.New("unknown partition")
+	}
+
+	return o[0], o[1], nil
+}
+
+func TestGetOffsets(t *testing.T) {
+	q := mockWatermarkQuerier{
+		0: {10, 250},
+		1: {0, 1024},
+	}
+
+	offsets, err := getOffsets(context.Background(), q, "test", []int32{0, 1})
+	assert.Nil(t, err)
+
+	expected := PartitionOffsets{
+		0: {LowWatermark: 10, LogEnd: 250},
+		1: {LowWatermark: 0, LogEnd: 1024},
+	}
+	assert.Equal(t, expected, offsets)
+
+	// A failed query fails the request.
+	_, err = getOffsets(context.Background(), q, "test", []int32{0, 2})
+	assert.EqualError(t, err, "topic test partition 2: unknown partition")
+}
@@ -2,10 +2,20 @@ package kafkaadmin
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/confluentinc/confluent-kafka-go/kafka"
 )
 
+const (
+	// defaultMetadataTimeout is the metadata and offset request timeout used
+	// when the request context has no deadline.
+	defaultMetadataTimeout = 5 * time.Second
+	// waitForTopicInterval is the WaitForTopic metadata poll interval.
+	waitForTopicInterval = 250 * time.Millisecond
+)
+
 // CreateTopicConfig holds CreateTopic parameters.
 type CreateTopicConfig struct {
 	Name              string
@@ -17,6 +27,62 @@ type CreateTopicConfig struct {
 
 type ReplicaAssignment [][]int32
 
+// TopicStates is a mapping of topic name to TopicState.
+type TopicStates map[string]TopicState
+
+// TopicState describes a topic as seen in the cluster metadata. If Err is
+// non-nil, the topic metadata is in error and Partitions is empty.
+type TopicState struct {
+	Name       string
+	Partitions []PartitionState
+	Err        error
+}
+
+// PartitionState describes a partition as seen in the cluster metadata. If Err
+// is non-nil, the remaining fields may be incomplete. A partition without a
+// leader has an ErrLeaderNotAvailable Err but is otherwise populated.
+type PartitionState struct {
+	ID       int32
+	Leader   int32
+	Replicas []int32
+	ISR      []int32
+	Err      error
+}
+
+// ErrTopicMetadata is set in a TopicState when the topic metadata is in
+// error, e.g. the topic doesn't exist.
+type ErrTopicMetadata struct {
+	Topic string
+	Code  kafka.ErrorCode
+}
+
+func (e ErrTopicMetadata) Error() string {
+	return fmt.Sprintf("topic %s: %s", e.Topic, e.Code)
+}
+
+// ErrLeaderNotAvailable is set in a PartitionState when the partition
+// currently has no leader.
+type ErrLeaderNotAvailable struct {
+	Topic     string
+	Partition int32
+}
+
+func (e ErrLeaderNotAvailable) Error() string {
+	return fmt.Sprintf("topic %s partition %d: leader not available", e.Topic, e.Partition)
+}
+
+// ErrPartitionMetadata is set in a PartitionState when the partition metadata
+// is in error for any reason other than an unavailable leader.
+type ErrPartitionMetadata struct {
+	Topic     string
+	Partition int32
+	Code      kafka.ErrorCode
+}
+
+func (e ErrPartitionMetadata) Error() string {
+	return fmt.Sprintf("topic %s partition %d: %s", e.Topic, e.Partition, e.Code)
+}
+
 // CreateTopic creates a topic.
 func (c Client) CreateTopic(ctx context.Context, cfg CreateTopicConfig) error {
 	spec := kafka.TopicSpecification{
@@ -36,6 +102,7 @@ func (c Client) CreateTopic(ctx context.Context, cfg CreateTopicConfig) error {
 	topic := []kafka.TopicSpecification{spec}
 
 	_, err := c.c.CreateTopics(ctx, topic)
+	c.invalidateMetadata()
 
 	return err
 }
@@ -43,5 +110,153 @@ func (c Client) CreateTopic(ctx context.Context, cfg CreateTopicConfig) error {
 // DeleteTopic deletes a topic.
 func (c Client) DeleteTopic(ctx context.Context, name string) error {
 	_, err := c.c.DeleteTopics(ctx, []string{name})
+	c.invalidateMetadata()
+
 	return err
 }
+
+// TopicExists returns whether the named topic is visible in the cluster
+// metadata. Metadata is requested for all topics rather than the named topic
+// to avoid triggering auto topic creation on brokers where it's enabled.
+func (c Client) TopicExists(ctx context.Context, name string) (bool, error) {
+	md, err := c.getMetadata(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return topicExists(md, name), nil
+}
+
+func topicExists(md *kafka.Metadata, name string) bool {
+	tm, exists := md.Topics[name]
+	if !exists {
+		return false
+	}
+
+	return tm.Error.Code() == kafka.ErrNoError
+}
+
+// DescribeTopics returns a TopicState for each of the named topics. Errors
+// affecting individual topics or partitions are set in the respective Err
+// fields rather than failing the entire request; the returned error is only
+// non-nil if the metadata request itself fails.
+func (c Client) DescribeTopics(ctx context.Context, names []string) (TopicStates, error) {
+	md, err := c.getMetadata(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return topicStatesFromMetadata(md, names), nil
+}
+
+func topicStatesFromMetadata(md *kafka.Metadata, names []string) TopicStates {
+	states := TopicStates{}
+
+	for _, name := range names {
+		ts := TopicState{Name: name}
+
+		tm, exists := md.Topics[name]
+		switch {
+		case !exists:
+			ts.Err = ErrTopicMetadata{Topic: name, Code: kafka.ErrUnknownTopicOrPart}
+		case tm.Error.Code() != kafka.ErrNoError:
+			ts.Err = ErrTopicMetadata{Topic: name, Code: tm.Error.Code()}
+		default:
+			for _, pm := range tm.Partitions {
+				ps := PartitionState{
+					ID:       pm.ID,
+					Leader:   pm.Leader,
+					Replicas: pm.Replicas,
+					ISR:      pm.Isrs,
+				}
+
+				switch code := pm.Error.Code(); {
+				case code == kafka.ErrLeaderNotAvailable, code == kafka.ErrNoError && pm.Leader < 0:
+					ps.Err = ErrLeaderNotAvailable{Topic: name, Partition: pm.ID}
+				case code != kafka.ErrNoError:
+					ps.Err = ErrPartitionMetadata{Topic: name, Partition: pm.ID, Code: code}
+				}
+
+				ts.Partitions = append(ts.Partitions, ps)
+			}
+		}
+
+		states[name] = ts
+	}
+
+	return states
+}
+
+// getMetadata returns metadata for all topics, served from the cache if
+// enabled.
+func (c Client) getMetadata(ctx context.Context) (*kafka.Metadata, error) {
+	if c.cache != nil {
+		return c.cache.get(ctx)
+	}
+
+	return c.requestMetadata(ctx)
+}
+
+// requestMetadata fetches metadata for all topics, using the context deadline
+// as the request timeout if set.
+func (c Client) requestMetadata(ctx context.Context) (*kafka.Metadata, error) {
+	timeout, err := requestTimeout(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.c.GetMetadata(nil, true, int(timeout.Milliseconds()))
+}
+
+// requestTimeout returns the context deadline as a request timeout, or the
+// defaultMetadataTimeout if the context has no deadline.
+func requestTimeout(ctx context.Context) (time.Duration, error) {
+	timeout := defaultMetadataTimeout
+	if d, ok := ctx.Deadline(); ok {
+		timeout = time.Until(d)
+	}
+
+	// A non-positive timeout would block indefinitely.
+	if timeout <= 0 {
+		return 0, context.DeadlineExceeded
+	}
+
+	return timeout, nil
+}
+
+// WaitForTopic polls the cluster metadata until the named topic is visible or
+// the context is done. This is useful following a CreateTopic call, since
+// metadata propagation may lag topic creation. Cached metadata isn't used.
+func (c Client) WaitForTopic(ctx context.Context, name string) error {
+	exists := func(ctx context.Context, name string) (bool, error) {
+		md, err := c.fetchMetadata(ctx)
+		if err != nil {
+			return false, err
+		}
+		return topicExists(md, name), nil
+	}
+
+	return waitForTopic(ctx, name, exists, waitForTopicInterval)
+}
+
+func waitForTopic(ctx context.Context, name string, exists func(context.Context, string) (bool, error), interval time.Duration) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		ok, err := exists(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package kafkaadmin
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWaitForTopic(t *testing.T) {
+	var polls int
+
+	// The topic becomes visible on the second poll.
+	exists := func(_ context.Context, name string) (bool, error) {
+		polls++
+		return name == "test" && polls >= 2, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	err := waitForTopic(ctx, "test", exists, time.Millisecond)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, polls)
+}
+
+func TestWaitForTopicTimeout(t *testing.T) {
+	exists := func(_ context.Context, _ string) (bool, error) {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := waitForTopic(ctx, "test", exists, time.Millisecond)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestTopicStatesFromMetadata(t *testing.T) {
+	noErr := kafka.NewError(kafka.ErrNoError, "", false)
+
+	md := &kafka.Metadata{
+		Topics: map[string]kafka.TopicMetadata{
+			"test": kafka.TopicMetadata{
+				Topic: "test",
+				Error: noErr,
+				Partitions: []kafka.PartitionMetadata{
+					{ID: 0, Error: noErr, Leader: 1001, Replicas: []int32{1001, 1002}, Isrs: []int32{1001, 1002}},
+					{ID: 1, Error: kafka.NewError(kafka.ErrLeaderNotAvailable, "", false), Leader: -1, Replicas: []int32{1002, 1003}, Isrs: []int32{}},
+					{ID: 2, Error: noErr, Leader: 1003, Replicas: []int32{1003, 1001}, Isrs: []int32{1003}},
+				},
+			},
+		},
+	}
+
+	states := topicStatesFromMetadata(md, []string{"test", "missing"})
+
+	// The topic is healthy, with only partition 1 in error.
+	ts := states["test"]
+	assert.Nil(t, ts.Err)
+	assert.Len(t, ts.Partitions, 3)
+
+	for _, p := range ts.Partitions {
+		if p.ID == 1 {
+			assert.Equal(t, ErrLeaderNotAvailable{Topic: "test", Partition: 1}, p.Err)
+			assert.Equal(t, []int32{1002, 1003}, p.Replicas)
+			continue
+		}
+		assert.Nil(t, p.Err)
+	}
+
+	// Topics not found in the metadata are in error.
+	assert.Equal(t, ErrTopicMetadata{Topic: "missing", Code: kafka.ErrUnknownTopicOrPart}, states["missing"].Err)
+}
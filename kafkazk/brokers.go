@@ -40,6 +40,8 @@ type BrokerMetricsMap map[int]*BrokerMetrics
 // data fetched from ZK.
 type BrokerMetrics struct {
 	StorageFree float64
+	// Storage consumption in bytes/hour, if available.
+	StorageGrowthRate float64
 }
 
 // BrokerUseStats holds counts
@@ -103,6 +105,10 @@ type Broker struct {
 	Replace     bool
 	Missing     bool
 	New         bool
+	// Weight is the relative capacity weight used in count based placement;
+	// brokers are filled toward partition counts proportional to their
+	// weight. A zero value is treated as a weight of 1.
+	Weight float64
 }
 
 // BrokerMap holds a mapping of broker IDs to *Broker.
@@ -115,6 +121,60 @@ func NewBrokerMap() BrokerMap {
 	}
 }
 
+// weightedUsed returns the broker's Used count scaled by its Weight.
+func (b *Broker) weightedUsed() float64 {
+	return float64(b.Used) / b.weight()
+}
+
+// CapacityWeights is a mapping of broker IDs to a relative capacity weight
+// (e.g. reflecting CPU or disk capacity). Brokers not present in the map have
+// a weight of 1.
+type CapacityWeights map[int]float64
+
+// SetWeights sets the Weight of each broker in the BrokerMap from the
+// CapacityWeights.
+func (b BrokerMap) SetWeights(w CapacityWeights) {
+	for id, br := range b {
+		if v, ok := w[id]; ok && v > 0 {
+			br.Weight = v
+		}
+	}
+}
+
+// TargetCounts takes a total replica count n and returns a mapping of broker
+// ID to the target replica count for each broker not marked for replacement.
+// Targets are proportional to broker weights.
+func (b BrokerMap) TargetCounts(n int) map[int]float64 {
+	var total float64
+	for _, br := range b {
+		if !br.Replace {
+			total += br.weight()
+		}
+	}
+
+	targets := map[int]float64{}
+	if total == 0 {
+		return targets
+	}
+
+	for id, br := range b {
+		if !br.Replace {
+			targets[id] = float64(n) * br.weight() / total
+		}
+	}
+
+	return targets
+}
+
+// weight returns the broker's Weight, or 1 if unset.
+func (b *Broker) weight() float64 {
+	if b.Weight > 0 {
+		return b.Weight
+	}
+
+	return 1
+}
+
 // BrokerList is a slice of brokers for sorting by used count.
 type BrokerList []*Broker
 
@@ -129,10 +189,10 @@ type brokersByID BrokerList
 func (b brokersByCount) Len() int      { return len(b) }
 func (b brokersByCount) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
 func (b brokersByCount) Less(i, j int) bool {
-	if b[i].Used < b[j].Used {
+	if b[i].weightedUsed() < b[j].weightedUsed() {
 		return true
 	}
-	if b[i].Used > b[j].Used {
+	if b[i].weightedUsed() > b[j].weightedUsed() {
 		return false
 	}
 
@@ -170,6 +230,43 @@ func (b BrokerList) SortByStorage() {
 	sort.Sort(brokersByStorage(b))
 }
 
+// SortHybrid sorts the BrokerList by a blend of StorageFree and Used values,
+// where w is the weight between 0 and 1 given to storage. Each broker is
+// scored by its storage used relative to the broker with the most storage
+// free and its weighted Used count relative to the highest weighted count.
+// Brokers with the lowest blended score sort first. A weight of 1 sorts
+// identically to SortByStorage and a weight of 0 to SortByCount.
+func (b BrokerList) SortHybrid(w float64) {
+	var maxFree, maxUsed float64
+	for _, br := range b {
+		if br.StorageFree > maxFree {
+			maxFree = br.StorageFree
+		}
+		if br.weightedUsed() > maxUsed {
+			maxUsed = br.weightedUsed()
+		}
+	}
+
+	scores := map[int]float64{}
+	for _, br := range b {
+		var storage, count float64
+		if maxFree > 0 {
+			storage = 1 - br.StorageFree/maxFree
+		}
+		if maxUsed > 0 {
+			count = br.weightedUsed() / maxUsed
+		}
+		scores[br.ID] = w*storage + (1-w)*count
+	}
+
+	sort.Slice(b, func(i, j int) bool {
+		if scores[b[i].ID] != scores[b[j].ID] {
+			return scores[b[i].ID] < scores[b[j].ID]
+		}
+		return b[i].ID < b[j].ID
+	})
+}
+
 // SortByID sorts the BrokerList by ID values.
 func (b BrokerList) SortByID() {
 	sort.Sort(brokersByID(b))
@@ -196,15 +293,15 @@ func (b BrokerList) SortPseudoShuffle(seed int64) {
 
 	s := 0
 	stop := len(b) - 1
-	currVal := b[0].Used
+	currVal := b[0].weightedUsed()
 
 	// For each continuous run of
 	// a given Used value, shuffle
 	// that range of the slice.
 	for k := range b {
 		switch {
-		case b[k].Used != currVal:
-			currVal = b[k].Used
+		case b[k].weightedUsed() != currVal:
+			currVal = b[k].weightedUsed()
 			rand.Shuffle(len(b[s:k]), func(i, j int) {
 				b[s:k][i], b[s:k][j] = b[s:k][j], b[s:k][i]
 			})
@@ -491,6 +588,7 @@ func (b BrokerMap) Copy() BrokerMap {
 			Replace:     br.Replace,
 			Missing:     br.Missing,
 			New:         br.New,
+			Weight:      br.Weight,
 		}
 	}
 
@@ -507,5 +605,6 @@ func (b Broker) Copy() Broker {
 		Replace:     b.Replace,
 		Missing:     b.Missing,
 		New:         b.New,
+		Weight:      b.Weight,
 	}
 }
@@ -55,6 +55,47 @@ func TestSortBrokerListByCount(t *testing.T) {
 	}
 }
 
+func TestSortBrokerListByCountWeighted(t *testing.T) {
+	bl := BrokerList{
+		&Broker{ID: 1001, Used: 4, Weight: 2},
+		&Broker{ID: 1002, Used: 3},
+		&Broker{ID: 1003, Used: 2},
+	}
+
+	// 1001 has a weighted use of 2.
+	bl.SortByCount()
+
+	expected := []int{1001, 1003, 1002}
+	for i, br := range bl {
+		if br.ID != expected[i] {
+			t.Errorf("Expected ID %d at position %d, got %d", expected[i], i, br.ID)
+		}
+	}
+}
+
+func TestTargetCounts(t *testing.T) {
+	bm := BrokerMap{
+		1001: &Broker{ID: 1001},
+		1002: &Broker{ID: 1002},
+		1003: &Broker{ID: 1003, Replace: true},
+	}
+
+	bm.SetWeights(CapacityWeights{1001: 3, 1003: 2})
+
+	targets := bm.TargetCounts(20)
+
+	expected := map[int]float64{1001: 15, 1002: 5}
+	if len(targets) != len(expected) {
+		t.Fatalf("Expected %v, got %v", expected, targets)
+	}
+
+	for id, n := range expected {
+		if targets[id] != n {
+			t.Errorf("Expected broker %d target %.1f, got %.1f", id, n, targets[id])
+		}
+	}
+}
+
 func TestSortBrokerListByStorage(t *testing.T) {
 	b := newStubBrokerMap2()
 	bl := b.Filter(func(b *Broker) bool { return true }).List()
@@ -75,6 +116,36 @@ func TestSortBrokerListByStorage(t *testing.T) {
 	}
 }
 
+func TestSortBrokerListHybrid(t *testing.T) {
+	tests := map[float64][]int{
+		// Pure storage.
+		1: {1004, 1005, 1006, 1007, 1003, 1002, 1001},
+		// Pure count.
+		0: {1001, 1002, 1004, 1005, 1003, 1006, 1007},
+		// Blended; 1006 and 1007 have the highest count but the most
+		// storage free, ranking them ahead of 1001-1003.
+		0.5: {1004, 1005, 1006, 1007, 1002, 1003, 1001},
+	}
+
+	for w, expected := range tests {
+		b := newStubBrokerMap2()
+		bl := b.Filter(func(b *Broker) bool { return !b.Replace }).List()
+
+		bl.SortHybrid(w)
+
+		var blIDs []int
+		for _, br := range bl {
+			blIDs = append(blIDs, br.ID)
+		}
+
+		for i, br := range bl {
+			if br.ID != expected[i] {
+				t.Fatalf("[weight %.1f] Expected %v, got %v", w, expected, blIDs)
+			}
+		}
+	}
+}
+
 func TestSortBrokerListByID(t *testing.T) {
 	b := newStubBrokerMap2()
 	bl := b.Filter(func(b *Broker) bool { return true }).List()
@@ -0,0 +1,119 @@
+package kafkazk
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// BrokerChurn tracks broker registration and deregistration events over a
+// sliding window. It's fed the set of registered broker IDs at each
+// observation (e.g. from periodic GetAllBrokerMeta calls) and reports per
+// broker churn counts, allowing consumers to avoid acting on flapping brokers.
+type BrokerChurn struct {
+	mu     sync.Mutex
+	window time.Duration
+	// The registered brokers as of the last observation; nil prior to the
+	// first observation.
+	registered map[int]struct{}
+	// Registration and deregistration event times by broker ID.
+	events map[int][]time.Time
+}
+
+// NewBrokerChurn takes a window duration and returns a *BrokerChurn.
+func NewBrokerChurn(window time.Duration) *BrokerChurn {
+	return &BrokerChurn{
+		window: window,
+		events: map[int][]time.Time{},
+	}
+}
+
+// Observe takes the IDs of all currently registered brokers and the time of
+// the observation. Each broker that registered or deregistered since the
+// previous observation records a churn event. The first observation only
+// establishes the registered brokers.
+func (c *BrokerChurn) Observe(ids []int, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current := map[int]struct{}{}
+	for _, id := range ids {
+		current[id] = struct{}{}
+	}
+
+	if c.registered != nil {
+		// Registrations.
+		for id := range current {
+			if _, exists := c.registered[id]; !exists {
+				c.events[id] = append(c.events[id], now)
+			}
+		}
+
+		// Deregistrations.
+		for id := range c.registered {
+			if _, exists := current[id]; !exists {
+				c.events[id] = append(c.events[id], now)
+			}
+		}
+	}
+
+	c.registered = current
+	c.expire(now)
+}
+
+// Churn takes a broker ID and time and returns the number of registration
+// and deregistration events for the broker within the window ending at now.
+func (c *BrokerChurn) Churn(id int, now time.Time) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var n int
+	for _, t := range c.events[id] {
+		if now.Sub(t) <= c.window {
+			n++
+		}
+	}
+
+	return n
+}
+
+// Flapping takes a threshold and time and returns the IDs of brokers with at
+// least threshold churn events within the window ending at now, sorted
+// ascending.
+func (c *BrokerChurn) Flapping(threshold int, now time.Time) []int {
+	c.mu.Lock()
+	var ids []int
+	for id := range c.events {
+		ids = append(ids, id)
+	}
+	c.mu.Unlock()
+
+	var flapping []int
+	for _, id := range ids {
+		if c.Churn(id, now) >= threshold {
+			flapping = append(flapping, id)
+		}
+	}
+
+	sort.Ints(flapping)
+
+	return flapping
+}
+
+// expire drops events older than the window. The caller must hold the lock.
+func (c *BrokerChurn) expire(now time.Time) {
+	for id, events := range c.events {
+		var retained []time.Time
+		for _, t := range events {
+			if now.Sub(t) <= c.window {
+				retained = append(retained, t)
+			}
+		}
+
+		if len(retained) == 0 {
+			delete(c.events, id)
+		} else {
+			c.events[id] = retained
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package kafkazk
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBrokerChurn(t *testing.T) {
+	c := NewBrokerChurn(10 * time.Minute)
+	start := time.Date(2020, 1, 2, 3, 0, 0, 0, time.UTC)
+
+	// The first observation establishes the registered brokers.
+	c.Observe([]int{1001, 1002}, start)
+	if n := c.Churn(1001, start); n != 0 {
+		t.Errorf("Expected churn of 0, got %d", n)
+	}
+
+	// 1002 repeatedly deregisters and registers.
+	for i := 1; i <= 4; i++ {
+		now := start.Add(time.Duration(i) * time.Minute)
+
+		ids := []int{1001}
+		if i%2 == 0 {
+			ids = append(ids, 1002)
+		}
+
+		c.Observe(ids, now)
+
+		if n := c.Churn(1002, now); n != i {
+			t.Errorf("Expected churn of %d, got %d", i, n)
+		}
+	}
+
+	now := start.Add(4 * time.Minute)
+
+	if n := c.Churn(1001, now); n != 0 {
+		t.Errorf("Expected churn of 0 for 1001, got %d", n)
+	}
+
+	if f := c.Flapping(3, now); len(f) != 1 || f[0] != 1002 {
+		t.Errorf("Expected 1002 to be flapping, got %v", f)
+	}
+
+	// Prior events age out of the window.
+	later := start.Add(15 * time.Minute)
+	c.Observe([]int{1001}, later)
+
+	if n := c.Churn(1002, later); n != 1 {
+		t.Errorf("Expected churn of 1, got %d", n)
+	}
+
+	if f := c.Flapping(3, later); len(f) != 0 {
+		t.Errorf("Expected no flapping brokers, got %v", f)
+	}
+}
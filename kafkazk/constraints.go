@@ -34,11 +34,20 @@ type ConstraintsParams struct {
 	MinUniqueRackIDs int
 	RequestSize      float64
 	SeedVal          int64
+	// HybridWeight is the weight between 0 and 1 given to storage over
+	// partition count when using the hybrid selector method.
+	HybridWeight float64
+	// SoftConstraints are placement preferences applied to candidates that
+	// pass all constraints, evaluated for the replica set Position.
+	SoftConstraints []SoftConstraint
+	Position        int
 }
 
 // SelectBroker takes a BrokerList and a ConstraintsParams and
 // selects the most suitable broker that passes all specified
-// constraints.
+// constraints. If SoftConstraints are specified, the passing broker with the
+// lowest total penalty is selected, with ties going to the broker ranked
+// first by the selector method.
 func (c *Constraints) SelectBroker(b BrokerList, p ConstraintsParams) (*Broker, error) {
 	// Sort type based on the
 	// desired placement criteria.
@@ -49,26 +58,51 @@ func (c *Constraints) SelectBroker(b BrokerList, p ConstraintsParams) (*Broker,
 		b.SortPseudoShuffle(p.SeedVal)
 	case "storage":
 		b.SortByStorage()
+	case "hybrid":
+		// A zero weight is pure count placement, including the
+		// pseudo random ordering of brokers with equal counts.
+		if p.HybridWeight == 0 {
+			b.SortPseudoShuffle(p.SeedVal)
+		} else {
+			b.SortHybrid(p.HybridWeight)
+		}
 	default:
 		return nil, ErrInvalidSelectionMethod
 	}
 
-	var candidate *Broker
+	var selected *Broker
+	var penalty float64
 
 	// Iterate over candidates.
-	for _, candidate = range b.Filter(AllBrokersFn) {
-		// Candidate passes, return.
-		if c.passesWithParams(candidate, p) {
-			c.requestSize = p.RequestSize
-			c.Add(candidate)
-			candidate.Used++
+	for _, candidate := range b.Filter(AllBrokersFn) {
+		if !c.passesWithParams(candidate, p) {
+			continue
+		}
 
-			return candidate, nil
+		// Without soft constraints, the first passing candidate is selected.
+		if len(p.SoftConstraints) == 0 {
+			selected = candidate
+			break
+		}
+
+		cp := softPenalty(p.SoftConstraints, candidate, p.Position)
+		if selected == nil || cp < penalty {
+			selected, penalty = candidate, cp
 		}
 	}
 
 	// List exhausted, no brokers passed.
-	return nil, ErrNoBrokers
+	if selected == nil {
+		return nil, ErrNoBrokers
+	}
+
+	c.requestSize = p.RequestSize
+	c.Add(selected)
+	selected.Used++
+
+	softPlaced(p.SoftConstraints, selected, p.Position)
+
+	return selected, nil
 }
 
 // TODO deprecate.
@@ -0,0 +1,133 @@
+package kafkazk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+
+	zkclient "github.com/samuel/go-zookeeper/zk"
+)
+
+// ISRShrinkEvent describes a replica dropping out of a partition's ISR.
+type ISRShrinkEvent struct {
+	Topic     string
+	Partition int
+	// The replica no longer in the ISR.
+	Replica int
+	// The ISR following the shrink.
+	ISR []int
+}
+
+// ISRShrinks takes a topic name, partition number and the previous and
+// current PartitionState and returns an ISRShrinkEvent for each replica in
+// the previous ISR that's missing from the current ISR.
+func ISRShrinks(t string, p int, prev, cur PartitionState) []ISRShrinkEvent {
+	current := map[int]struct{}{}
+	for _, id := range cur.ISR {
+		current[id] = struct{}{}
+	}
+
+	var events []ISRShrinkEvent
+
+	for _, id := range prev.ISR {
+		if _, ok := current[id]; !ok {
+			events = append(events, ISRShrinkEvent{
+				Topic:     t,
+				Partition: p,
+				Replica:   id,
+				ISR:       append([]int{}, cur.ISR...),
+			})
+		}
+	}
+
+	return events
+}
+
+// getWFunc returns the data at a znode path along with a channel that
+// receives an event upon the next change to the znode.
+type getWFunc func(string) ([]byte, <-chan zkclient.Event, error)
+
+// watchISRShrinks takes a Context, getWFunc, the topic's partitions znode
+// path, the topic name and partition numbers, and an ISRShrinkEvent channel.
+// The state znode of each partition is watched and an ISRShrinkEvent is sent
+// for each replica dropping out of its ISR. watchISRShrinks blocks until the
+// context is cancelled, returning the context error, or until a watch fails.
+func watchISRShrinks(ctx context.Context, getW getWFunc, path, t string, partitions []string, events chan<- ISRShrinkEvent) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ids := make([]int, len(partitions))
+	for i, p := range partitions {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return fmt.Errorf("invalid partition %s: %s", p, err)
+		}
+		ids[i] = n
+	}
+
+	errs := make(chan error, len(partitions))
+	wg := &sync.WaitGroup{}
+
+	for i, p := range partitions {
+		wg.Add(1)
+		go func(ppath string, n int) {
+			defer wg.Done()
+			// Watches stopped by the cancellation aren't errors.
+			if err := watchPartitionState(ctx, getW, ppath, t, n, events); err != nil && err != ctx.Err() {
+				errs <- err
+				cancel()
+			}
+		}(fmt.Sprintf("%s/%s/state", path, p), ids[i])
+	}
+
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return err
+	default:
+		return ctx.Err()
+	}
+}
+
+// watchPartitionState watches the partition state znode at path, sending an
+// ISRShrinkEvent for each replica dropping out of the ISR, until the context
+// is cancelled or the watch fails.
+func watchPartitionState(ctx context.Context, getW getWFunc, path, t string, p int, events chan<- ISRShrinkEvent) error {
+	var prev *PartitionState
+
+	for {
+		data, w, err := getW(path)
+		if err != nil {
+			return fmt.Errorf("[%s] %s", path, err.Error())
+		}
+
+		state := PartitionState{}
+		if err := json.Unmarshal(data, &state); err != nil {
+			return fmt.Errorf("[%s] %s", path, err.Error())
+		}
+
+		if prev != nil {
+			for _, e := range ISRShrinks(t, p, *prev, state) {
+				select {
+				case events <- e:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+
+		prev = &state
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e := <-w:
+			if e.Err != nil {
+				return fmt.Errorf("[%s] %s", path, e.Err.Error())
+			}
+		}
+	}
+}
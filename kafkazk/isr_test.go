@@ -0,0 +1,77 @@
+package kafkazk
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestISRShrinks(t *testing.T) {
+	prev := PartitionState{Leader: 1001, ISR: []int{1001, 1002, 1003}}
+	cur := PartitionState{Leader: 1001, ISR: []int{1001, 1003}}
+
+	events := ISRShrinks("test", 0, prev, cur)
+	if len(events) != 1 {
+		t.Fatalf("Expected 1 event, got %d", len(events))
+	}
+
+	e := events[0]
+	if e.Topic != "test" || e.Partition != 0 || e.Replica != 1002 || !intsEqual(e.ISR, []int{1001, 1003}) {
+		t.Errorf("Unexpected event %+v", e)
+	}
+
+	// ISR expansions aren't shrinks.
+	if events := ISRShrinks("test", 0, cur, prev); len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+}
+
+func TestStubWatchISRShrinks(t *testing.T) {
+	zk := NewZooKeeperStub().(*Stub)
+
+	zk.Set("/brokers/topics/test/partitions/0/state", `{"leader":1001,"isr":[1001,1002]}`)
+	zk.Set("/brokers/topics/test/partitions/1/state", `{"leader":1002,"isr":[1002,1003]}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := make(chan ISRShrinkEvent, 10)
+	done := make(chan error)
+
+	go func() { done <- zk.WatchISRShrinks(ctx, "test", events) }()
+
+	// Wait for both partition state watches.
+	for i := 0; ; i++ {
+		zk.mu.Lock()
+		n := len(zk.dataWatches)
+		zk.mu.Unlock()
+
+		if n == 2 {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Timed out waiting for partition state watches")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// 1003 drops out of the p1 ISR.
+	zk.Set("/brokers/topics/test/partitions/1/state", `{"leader":1002,"isr":[1002]}`)
+
+	select {
+	case e := <-events:
+		if e.Topic != "test" || e.Partition != 1 || e.Replica != 1003 || !intsEqual(e.ISR, []int{1002}) {
+			t.Errorf("Unexpected event %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the ISR shrink event")
+	}
+
+	cancel()
+
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected error '%s', got '%v'", context.Canceled, err)
+	}
+
+	if len(events) != 0 {
+		t.Errorf("Expected no further events, got %d", len(events))
+	}
+}
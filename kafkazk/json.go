@@ -0,0 +1,147 @@
+package kafkazk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// JSONSchema describes the expected structure of a JSON object stored in a
+// znode. It implements a small subset of JSON Schema suited to the flat
+// configuration and metadata objects stored by kafka-kit: required fields,
+// per-field types and whether undeclared fields are permitted.
+type JSONSchema struct {
+	// Required is a list of field names that must be present.
+	Required []string
+	// Properties is a mapping of field names to JSON types. Valid types are
+	// string, number, integer, boolean, object, array and null.
+	Properties map[string]string
+	// AdditionalProperties permits fields not declared in Properties.
+	AdditionalProperties bool
+}
+
+// ErrSchemaValidation is returned when a JSON object fails JSONSchema
+// validation.
+type ErrSchemaValidation struct {
+	Field  string
+	Reason string
+}
+
+func (e ErrSchemaValidation) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("schema validation failed: %s", e.Reason)
+	}
+
+	return fmt.Sprintf("schema validation failed: field %s %s", e.Field, e.Reason)
+}
+
+// Validate takes JSON data and returns an ErrSchemaValidation if the data
+// doesn't conform to the JSONSchema.
+func (s JSONSchema) Validate(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var obj map[string]interface{}
+	if err := dec.Decode(&obj); err != nil || obj == nil {
+		return ErrSchemaValidation{Reason: "data is not a JSON object"}
+	}
+
+	for _, f := range s.Required {
+		if _, exists := obj[f]; !exists {
+			return ErrSchemaValidation{Field: f, Reason: "is required"}
+		}
+	}
+
+	// Check fields in order for consistent errors.
+	var fields []string
+	for f := range obj {
+		fields = append(fields, f)
+	}
+
+	sort.Strings(fields)
+
+	for _, f := range fields {
+		t, declared := s.Properties[f]
+		if !declared {
+			if !s.AdditionalProperties {
+				return ErrSchemaValidation{Field: f, Reason: "is not permitted"}
+			}
+			continue
+		}
+
+		if !jsonTypeMatches(obj[f], t) {
+			return ErrSchemaValidation{Field: f, Reason: fmt.Sprintf("must be of type %s", t)}
+		}
+	}
+
+	return nil
+}
+
+// jsonTypeMatches returns whether the decoded JSON value v is of the JSON
+// type t. Numbers must be decoded as json.Number.
+func jsonTypeMatches(v interface{}, t string) bool {
+	switch val := v.(type) {
+	case string:
+		return t == "string"
+	case json.Number:
+		if t == "number" {
+			return true
+		}
+		_, err := val.Int64()
+		return t == "integer" && err == nil
+	case bool:
+		return t == "boolean"
+	case map[string]interface{}:
+		return t == "object"
+	case []interface{}:
+		return t == "array"
+	case nil:
+		return t == "null"
+	}
+
+	return false
+}
+
+// SetJSON takes a Handler, znode path, a value to marshal and an optional
+// *JSONSchema. The value is JSON encoded and, if a schema is provided,
+// validated before being written; data that fails validation is never
+// written. The znode is created if it doesn't exist.
+func SetJSON(zk Handler, p string, in interface{}, schema *JSONSchema) error {
+	d, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("error marshalling %s: %s", p, err)
+	}
+
+	if schema != nil {
+		if err := schema.Validate(d); err != nil {
+			return err
+		}
+	}
+
+	exists, err := zk.Exists(p)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return zk.Create(p, string(d))
+	}
+
+	return zk.Set(p, string(d))
+}
+
+// GetJSON takes a Handler, znode path and a pointer to a value and
+// unmarshals the JSON data stored at the znode into the value.
+func GetJSON(zk Handler, p string, out interface{}) error {
+	d, err := zk.Get(p)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(d, out); err != nil {
+		return fmt.Errorf("error unmarshalling %s: %s", p, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+type testOverride struct {
+	Rate       interface{} `json:"rate"`
+	AutoRemove bool        `json:"autoremove"`
+}
+
+var testOverrideSchema = &JSONSchema{
+	Required: []string{"rate"},
+	Properties: map[string]string{
+		"rate":       "integer",
+		"autoremove": "boolean",
+	},
+}
+
+func TestSetGetJSON(t *testing.T) {
+	zk := NewZooKeeperStub()
+	p := "/test/override"
+
+	in := testOverride{Rate: 100, AutoRemove: true}
+	if err := SetJSON(zk, p, in, testOverrideSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	// Overwrite the existing znode.
+	in.Rate = 50
+	if err := SetJSON(zk, p, in, testOverrideSchema); err != nil {
+		t.Fatal(err)
+	}
+
+	var out struct {
+		Rate       int  `json:"rate"`
+		AutoRemove bool `json:"autoremove"`
+	}
+
+	if err := GetJSON(zk, p, &out); err != nil {
+		t.Fatal(err)
+	}
+
+	if out.Rate != 50 || !out.AutoRemove {
+		t.Errorf("Unexpected value %+v", out)
+	}
+}
+
+func TestSetJSONSchemaViolation(t *testing.T) {
+	zk := NewZooKeeperStub()
+	p := "/test/override"
+
+	// Rate must be an integer.
+	err := SetJSON(zk, p, testOverride{Rate: "fast"}, testOverrideSchema)
+
+	expected := ErrSchemaValidation{Field: "rate", Reason: "must be of type integer"}
+	if err != expected {
+		t.Errorf("Expected error '%s', got '%v'", expected, err)
+	}
+
+	// The rejected data must not be written.
+	if exists, _ := zk.Exists(p); exists {
+		t.Error("Expected rejected data to not be written")
+	}
+}
+
+func TestJSONSchemaValidate(t *testing.T) {
+	tests := map[string]error{
+		`{"rate": 10}`:                     nil,
+		`{"rate": 10, "autoremove": true}`: nil,
+		`{"autoremove": true}`:             ErrSchemaValidation{Field: "rate", Reason: "is required"},
+		`{"rate": 1.5}`:                    ErrSchemaValidation{Field: "rate", Reason: "must be of type integer"},
+		`{"rate": 10, "other": 1}`:         ErrSchemaValidation{Field: "other", Reason: "is not permitted"},
+		`[10]`:                             ErrSchemaValidation{Reason: "data is not a JSON object"},
+	}
+
+	for data, expected := range tests {
+		if err := testOverrideSchema.Validate([]byte(data)); err != expected {
+			t.Errorf("[%s] Expected error '%v', got '%v'", data, expected, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package kafkazk
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ErrNotReplica is returned when a broker isn't a replica of a partition.
+type ErrNotReplica struct {
+	Topic     string
+	Partition int
+	Broker    int
+}
+
+func (e ErrNotReplica) Error() string {
+	return fmt.Sprintf("broker %d is not a replica of %s p%d", e.Broker, e.Topic, e.Partition)
+}
+
+// SetPreferredLeader takes a Handler, topic, partition and broker ID and makes
+// the broker the preferred leader of the partition by moving it to the front
+// of the replica list. The broker must already be a replica, otherwise an
+// ErrNotReplica is returned. The reordered replica list is submitted as a
+// single partition reassignment via SubmitReassignment; since the replica set
+// is unchanged, no data is moved. The reordered replica list is returned.
+// Leadership moves to the broker on the next preferred leader election.
+func SetPreferredLeader(zk Handler, topic string, partition, id int) ([]int, error) {
+	ts, err := zk.GetTopicState(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	current, exists := ts.Partitions[strconv.Itoa(partition)]
+	if !exists {
+		return nil, fmt.Errorf("%s p%d does not exist", topic, partition)
+	}
+
+	replicas := []int{id}
+	for _, r := range current {
+		if r != id {
+			replicas = append(replicas, r)
+		}
+	}
+
+	if len(replicas) != len(current) {
+		return nil, ErrNotReplica{Topic: topic, Partition: partition, Broker: id}
+	}
+
+	// The broker is already the preferred leader.
+	if current[0] == id {
+		return replicas, nil
+	}
+
+	pm := NewPartitionMap()
+	pm.Partitions = PartitionList{
+		Partition{Topic: topic, Partition: partition, Replicas: replicas},
+	}
+
+	if err := zk.SubmitReassignment(pm); err != nil {
+		return nil, err
+	}
+
+	return replicas, nil
+}
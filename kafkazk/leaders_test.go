@@ -0,0 +1,37 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestSetPreferredLeader(t *testing.T) {
+	zk := NewZooKeeperStub()
+
+	// The stub topic state has partition 2 on [1004 1005].
+	replicas, err := SetPreferredLeader(zk, "test_topic", 2, 1005)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []int{1005, 1004}
+	if !replicasEqual(replicas, expected) {
+		t.Errorf("Expected replicas %v, got %v", expected, replicas)
+	}
+
+	// The reordered replica list is submitted as a reassignment.
+	data, _ := zk.Get("/admin/reassign_partitions")
+	submitted, err := PartitionMapFromString(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(submitted.Partitions) != 1 || !submitted.Partitions[0].Equal(Partition{Topic: "test_topic", Partition: 2, Replicas: expected}) {
+		t.Errorf("Unexpected reassignment %v", submitted.Partitions)
+	}
+
+	// Brokers that aren't replicas are rejected.
+	_, err = SetPreferredLeader(zk, "test_topic", 2, 1001)
+	if err != (ErrNotReplica{Topic: "test_topic", Partition: 2, Broker: 1001}) {
+		t.Errorf("Expected ErrNotReplica, got %v", err)
+	}
+}
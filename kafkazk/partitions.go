@@ -15,6 +15,10 @@ type Partition struct {
 	Topic     string `json:"topic"`
 	Partition int    `json:"partition"`
 	Replicas  []int  `json:"replicas"`
+	// Observers is the subset of Replicas that are observers (asynchronous
+	// replicas that aren't eligible for the ISR), as supported by Confluent
+	// Server. Observers are listed after all sync replicas in Replicas.
+	Observers []int `json:"observers,omitempty"`
 }
 
 // PartitionList is a []Partition.
@@ -112,6 +116,7 @@ func (p PartitionList) SortBySize(m PartitionMetaMap) {
 type replicasByLeaderFollowerRatio struct {
 	replicas []int
 	stats    BrokerUseStatsMap
+	weights  LeaderWeights
 }
 
 func (r replicasByLeaderFollowerRatio) Len() int { return len(r.replicas) }
@@ -124,11 +129,15 @@ func (r replicasByLeaderFollowerRatio) Less(i, j int) bool {
 	id1 := r.replicas[i]
 	id2 := r.replicas[j]
 
+	// Leadership counts are scaled by the broker weight.
+	l1 := float64(r.stats[id1].Leader) / r.weights.weight(id1)
+	l2 := float64(r.stats[id2].Leader) / r.weights.weight(id2)
+
 	switch {
 	// Neither broker holds follower positions, compare
 	// leadership counts.
 	case r.stats[id1].Follower == 0 && r.stats[id2].Follower == 0:
-		return r.stats[id1].Leader < r.stats[id2].Leader
+		return l1 < l2
 	// i ratio == ∞
 	case r.stats[id1].Follower == 0:
 		return false
@@ -137,12 +146,25 @@ func (r replicasByLeaderFollowerRatio) Less(i, j int) bool {
 		return true
 	// We have a comparable ratio.
 	default:
-		a := float64(r.stats[id1].Leader) / float64(r.stats[id1].Follower)
-		b := float64(r.stats[id2].Leader) / float64(r.stats[id2].Follower)
+		a := l1 / float64(r.stats[id1].Follower)
+		b := l2 / float64(r.stats[id2].Follower)
 		return a < b
 	}
 }
 
+// LeaderWeights is a mapping of broker IDs to a relative leadership capacity
+// weight. Brokers with a higher weight are favored for leadership. Brokers
+// not present in the map have a weight of 1.
+type LeaderWeights map[int]float64
+
+func (w LeaderWeights) weight(id int) float64 {
+	if v, ok := w[id]; ok && v > 0 {
+		return v
+	}
+
+	return 1
+}
+
 // PartitionMeta holds partition metadata.
 type PartitionMeta struct {
 	Size float64 // In bytes.
@@ -190,6 +212,12 @@ type RebuildParams struct {
 	Affinities       SubstitutionAffinities
 	PartnSzFactor    float64
 	MinUniqueRackIDs int
+	// HybridWeight is the weight between 0 and 1 given to storage over
+	// partition count when using the hybrid strategy.
+	HybridWeight float64
+	// SoftConstraints are placement preferences optimized for when
+	// selecting replacement brokers; see SoftConstraint.
+	SoftConstraints []SoftConstraint
 }
 
 // NewRebuildParams initializes a RebuildParams.
@@ -206,11 +234,22 @@ func NewRebuildParams() RebuildParams {
 // go further down the replica list. This ratio is recalculated at each
 // replica set visited to avoid extreme skew.
 func (pm *PartitionMap) OptimizeLeaderFollower() {
+	pm.OptimizeLeaderFollowerWeighted(nil)
+}
+
+// OptimizeLeaderFollowerWeighted performs the OptimizeLeaderFollower
+// optimization where each broker's leadership count is scaled by its weight
+// in the provided LeaderWeights. Brokers with a higher weight are given
+// proportionally more leadership positions. Replicas are only reordered
+// within their existing replica sets, excluding observers.
+func (pm *PartitionMap) OptimizeLeaderFollowerWeighted(w LeaderWeights) {
 	for i := 0; i < len(pm.Partitions[0].Replicas); i++ {
 		for _, partn := range pm.Partitions {
+			// Observers can't lead and aren't reordered.
 			sort.Sort(replicasByLeaderFollowerRatio{
-				replicas: partn.Replicas,
+				replicas: partn.Replicas[:len(partn.Replicas)-len(partn.Observers)],
 				stats:    pm.UseStats(),
+				weights:  w,
 			})
 		}
 	}
@@ -218,14 +257,27 @@ func (pm *PartitionMap) OptimizeLeaderFollower() {
 
 // Rebuild takes a BrokerMap and rebuild strategy. It then traverses the
 // partition map, replacing brokers marked removal with the best available
-// candidate based on the selected rebuild strategy. A rebuilt *PartitionMap
-// and []error of errors is returned.
+// candidate based on the selected rebuild strategy. Broker partition counts
+// and storage free are tracked across all topics in the map, so placements
+// account for the cumulative effect of every topic rather than balancing each
+// topic in isolation. A rebuilt *PartitionMap and []error of errors is
+// returned.
 func (pm *PartitionMap) Rebuild(params RebuildParams) (*PartitionMap, []error) {
 	var newMap *PartitionMap
 	var errs []error
 
 	params.pm = pm
 
+	for _, sc := range params.SoftConstraints {
+		sc.Init(pm, params.BM)
+	}
+
+	// A hybrid weight of 0 gives no weight to storage; this is count
+	// placement, without the storage constraint or the need for metrics.
+	if params.Strategy == "hybrid" && params.HybridWeight == 0 {
+		params.Strategy = "count"
+	}
+
 	switch params.Strategy {
 	case "count":
 		// Standard sort
@@ -251,11 +303,25 @@ func (pm *PartitionMap) Rebuild(params RebuildParams) (*PartitionMap, []error) {
 			// brokers for each partition at a time (in contrast to placeByPosition).
 			// Shuffling has proven so far to distribute leadership even though
 			// it's purely by probability. Eventually, write a real optimizer.
-			newMap.shuffle(func(_ Partition) bool { return true })
+			// Partitions with observers aren't shuffled since observers
+			// must remain last.
+			newMap.shuffle(func(p Partition) bool { return len(p.Observers) == 0 })
 		// Invalid optimization.
 		default:
 			return nil, []error{fmt.Errorf("Invalid optimization '%s'", params.Optimization)}
 		}
+	case "hybrid":
+		if params.HybridWeight < 0 || params.HybridWeight > 1 {
+			return nil, []error{fmt.Errorf("Invalid hybrid weight '%f'", params.HybridWeight)}
+		}
+		// Sort by size.
+		s := partitionsBySize{
+			pl: params.pm.Partitions,
+			pm: params.PMM,
+		}
+		sort.Sort(partitionsBySize(s))
+		// Perform placements.
+		newMap, errs = placeByPosition(params)
 	// Invalid placement.
 	default:
 		return nil, []error{fmt.Errorf("Invalid rebuild strategy '%s'", params.Strategy)}
@@ -344,12 +410,15 @@ func placeByPosition(params RebuildParams) (*PartitionMap, []error) {
 				constraintsParams := ConstraintsParams{
 					SelectorMethod:   params.Strategy,
 					MinUniqueRackIDs: params.MinUniqueRackIDs,
+					HybridWeight:     params.HybridWeight,
+					SoftConstraints:  params.SoftConstraints,
+					Position:         pass,
 				}
 				constraints.MergeConstraints(replicaSet)
 
 				// Add any necessary meta from current partition
 				// to the constraints.
-				if params.Strategy == "storage" {
+				if params.Strategy == "storage" || params.Strategy == "hybrid" {
 					s, err := params.PMM.Size(partn)
 					if err != nil {
 						e := fmt.Errorf("%s p%d: %s", partn.Topic, partn.Partition, err.Error())
@@ -378,6 +447,8 @@ func placeByPosition(params RebuildParams) (*PartitionMap, []error) {
 					// here in case the inference logic is faulty.
 					if passes := constraints.passesWithParams(replacement, constraintsParams); !passes {
 						err = ErrNoBrokers
+					} else {
+						softPlaced(params.SoftConstraints, replacement, pass)
 					}
 				} else {
 					// Otherwise, use the standard
@@ -412,6 +483,9 @@ func placeByPosition(params RebuildParams) (*PartitionMap, []error) {
 		}
 	}
 
+	// Carry over observer designations.
+	newMap.setObserversFrom(params.pm)
+
 	// Return map, errors.
 	return newMap, errs
 }
@@ -468,13 +542,16 @@ func placeByPartition(params RebuildParams) (*PartitionMap, []error) {
 				constraintsParams := ConstraintsParams{
 					SelectorMethod:   params.Strategy,
 					MinUniqueRackIDs: params.MinUniqueRackIDs,
+					HybridWeight:     params.HybridWeight,
+					SoftConstraints:  params.SoftConstraints,
+					Position:         len(newPartn.Replicas),
 					SeedVal:          1,
 				}
 				constraints.MergeConstraints(replicaSet)
 
 				// Add any necessary meta from current partition
 				// to the constraints.
-				if params.Strategy == "storage" {
+				if params.Strategy == "storage" || params.Strategy == "hybrid" {
 					s, err := params.PMM.Size(partn)
 					if err != nil {
 						e := fmt.Errorf("%s p%d: %s", partn.Topic, partn.Partition, err.Error())
@@ -513,6 +590,9 @@ func placeByPartition(params RebuildParams) (*PartitionMap, []error) {
 		}
 	}
 
+	// Carry over observer designations.
+	newMap.setObserversFrom(params.pm)
+
 	// Return map, errors.
 	return newMap, errs
 }
@@ -653,20 +733,52 @@ func (pm *PartitionMap) SetReplication(r int) {
 		l := len(p.Replicas)
 
 		switch {
-		// Truncate replicas beyond r.
+		// Truncate replicas beyond r. Observers are last and are therefore
+		// removed first.
 		case l > r:
 			pm.Partitions[n].Replicas = p.Replicas[:r]
-		// Add stub brokers to meet r.
+			var observers []int
+			for _, id := range p.Observers {
+				if pm.Partitions[n].IsReplica(id) {
+					observers = append(observers, id)
+				}
+			}
+			pm.Partitions[n].Observers = observers
+		// Add stub brokers to meet r. Stubs are added as sync replicas ahead
+		// of any observers.
 		case l < r:
-			r := make([]int, r-l)
-			for i := 0; i < len(r); i++ {
-				r[i] = StubBrokerID
+			sync := p.SyncReplicas()
+			replicas := make([]int, 0, r)
+			replicas = append(replicas, sync...)
+			for i := 0; i < r-l; i++ {
+				replicas = append(replicas, StubBrokerID)
 			}
-			pm.Partitions[n].Replicas = append(p.Replicas, r...)
+			replicas = append(replicas, p.Replicas[len(sync):]...)
+			pm.Partitions[n].Replicas = replicas
 		}
 	}
 }
 
+// setObserversFrom sets the Observers of each partition to the replicas at
+// the positions held by observers in the corresponding partition of the
+// source map, which must be ordered identically.
+func (pm *PartitionMap) setObserversFrom(src *PartitionMap) {
+	for i, p := range src.Partitions {
+		if len(p.Observers) == 0 {
+			continue
+		}
+
+		var observers []int
+		for j, id := range p.Replicas {
+			if p.IsObserver(id) && j < len(pm.Partitions[i].Replicas) {
+				observers = append(observers, pm.Partitions[i].Replicas[j])
+			}
+		}
+
+		pm.Partitions[i].Observers = observers
+	}
+}
+
 // Topics returns a []string of topic names held in the PartitionMap.
 func (pm *PartitionMap) Topics() []string {
 	// Set.
@@ -711,6 +823,12 @@ func (pm *PartitionMap) Copy() *PartitionMap {
 		}
 
 		copy(part.Replicas, p.Replicas)
+
+		if p.Observers != nil {
+			part.Observers = make([]int, len(p.Observers))
+			copy(part.Observers, p.Observers)
+		}
+
 		cpy.Partitions = append(cpy.Partitions, part)
 	}
 
@@ -738,6 +856,8 @@ func (pm *PartitionMap) Equal(pm2 *PartitionMap) (bool, error) {
 			return false, errors.New("partition order")
 		case len(p1.Replicas) != len(p2.Replicas):
 			return false, errors.New("replica list")
+		case len(p1.Observers) != len(p2.Observers):
+			return false, errors.New("observer list")
 		}
 
 		for n := range p1.Replicas {
@@ -745,6 +865,12 @@ func (pm *PartitionMap) Equal(pm2 *PartitionMap) (bool, error) {
 				return false, errors.New("replica")
 			}
 		}
+
+		for n := range p1.Observers {
+			if p1.Observers[n] != p2.Observers[n] {
+				return false, errors.New("observer")
+			}
+		}
 	}
 
 	return true, nil
@@ -823,7 +949,7 @@ func (pm *PartitionMap) UseStats() BrokerUseStatsMap {
 }
 
 // Equal defines equalty between two Partition objects
-// as an equality of topic, partition and replicas.
+// as an equality of topic, partition, replicas and observers.
 func (p Partition) Equal(p2 Partition) bool {
 	switch {
 	case p.Topic != p2.Topic:
@@ -832,6 +958,8 @@ func (p Partition) Equal(p2 Partition) bool {
 		return false
 	case len(p.Replicas) != len(p2.Replicas):
 		return false
+	case len(p.Observers) != len(p2.Observers):
+		return false
 	}
 
 	for i := range p.Replicas {
@@ -840,5 +968,50 @@ func (p Partition) Equal(p2 Partition) bool {
 		}
 	}
 
+	for i := range p.Observers {
+		if p.Observers[i] != p2.Observers[i] {
+			return false
+		}
+	}
+
 	return true
 }
+
+// IsObserver returns whether the broker ID is an observer of the partition.
+func (p Partition) IsObserver(id int) bool {
+	for _, o := range p.Observers {
+		if o == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsReplica returns whether the broker ID is a replica of the partition.
+func (p Partition) IsReplica(id int) bool {
+	for _, r := range p.Replicas {
+		if r == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SyncReplicas returns the partition's replicas that aren't observers, in
+// replica order.
+func (p Partition) SyncReplicas() []int {
+	if len(p.Observers) == 0 {
+		return p.Replicas
+	}
+
+	var sync []int
+	for _, id := range p.Replicas {
+		if !p.IsObserver(id) {
+			sync = append(sync, id)
+		}
+	}
+
+	return sync
+}
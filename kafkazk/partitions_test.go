@@ -1,6 +1,7 @@
 package kafkazk
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"regexp"
@@ -55,6 +56,69 @@ func TestPartitionEquality(t *testing.T) {
 	if p1.Equal(p5) {
 		t.Error("Unexpected equality between p1 and p5")
 	}
+
+	p6 := Partition{Topic: "test_topic", Partition: 1, Replicas: []int{1, 2, 3}, Observers: []int{3}}
+	if p1.Equal(p6) {
+		t.Error("Unexpected equality between p1 and p6")
+	}
+}
+
+func TestPartitionMapFromTopicStateObservers(t *testing.T) {
+	data := []byte(`{"version":2,"partitions":{"0":[1001,1002,1003],"1":[1002,1003,1001],"2":[1003,1001]},"observers":{"0":[1003],"1":[1001],"2":[1004]}}`)
+
+	ts := &TopicState{}
+	if err := json.Unmarshal(data, ts); err != nil {
+		t.Fatal(err)
+	}
+
+	pm := partitionMapFromTopicState("test", ts)
+
+	expected := []struct {
+		sync, observers []int
+	}{
+		{[]int{1001, 1002}, []int{1003}},
+		{[]int{1002, 1003}, []int{1001}},
+		// 1004 isn't a replica.
+		{[]int{1003, 1001}, nil},
+	}
+
+	for i, p := range pm.Partitions {
+		if !intsEqual(p.SyncReplicas(), expected[i].sync) {
+			t.Errorf("p%d: expected sync replicas %v, got %v", i, expected[i].sync, p.SyncReplicas())
+		}
+
+		if !intsEqual(p.Observers, expected[i].observers) {
+			t.Errorf("p%d: expected observers %v, got %v", i, expected[i].observers, p.Observers)
+		}
+
+		// Observers remain in the full replica set.
+		if len(p.Replicas) != len(expected[i].sync)+len(expected[i].observers) {
+			t.Errorf("p%d: unexpected replicas %v", i, p.Replicas)
+		}
+	}
+
+	if !pm.Partitions[0].IsObserver(1003) || pm.Partitions[0].IsObserver(1001) {
+		t.Error("Unexpected observer classification for p0")
+	}
+
+	// Observers are retained in copies.
+	if eq, _ := pm.Equal(pm.Copy()); !eq {
+		t.Error("Expected copy to equal the original")
+	}
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
 }
 
 func testGetMapString(n string) string {
@@ -643,6 +707,166 @@ func TestRebuildByStorageStorage(t *testing.T) {
 	}
 }
 
+// Hybrid rebuild at a storage weight of 1 matches a storage rebuild.
+func TestRebuildByHybrid(t *testing.T) {
+	var outs []*PartitionMap
+
+	for _, strategy := range []string{"storage", "hybrid"} {
+		zk := &Stub{}
+		bm, _ := zk.GetAllBrokerMeta(true)
+		pm, _ := PartitionMapFromString(testGetMapString4("test_topic"))
+		pmm, _ := zk.GetAllPartitionMeta()
+
+		for _, partn := range pmm["test_topic"] {
+			partn.Size = partn.Size / 3
+		}
+
+		brokers := BrokerMapFromPartitionMap(pm, bm, true)
+		pmStripped := pm.Strip()
+
+		// Skew storage so that placements differ from count placement.
+		for id, b := range brokers {
+			b.StorageFree = 6000.00 + float64(id%10)*500
+		}
+
+		rebuildParams := RebuildParams{
+			PMM:           pmm,
+			BM:            brokers,
+			Strategy:      strategy,
+			Optimization:  "distribution",
+			PartnSzFactor: 1,
+			HybridWeight:  1,
+		}
+
+		out, errs := pmStripped.Rebuild(rebuildParams)
+		if errs != nil {
+			t.Fatalf("Unexpected error(s): %s", errs)
+		}
+
+		outs = append(outs, out)
+	}
+
+	same, err := outs[0].Equal(outs[1])
+	if !same {
+		t.Errorf("Unexpected inequality between storage and hybrid rebuild: %s", err)
+	}
+
+	// Invalid weight.
+	pm, _ := PartitionMapFromString(testGetMapString4("test_topic"))
+	_, errs := pm.Rebuild(RebuildParams{Strategy: "hybrid", HybridWeight: 2})
+	if errs == nil {
+		t.Error("Expected invalid hybrid weight error")
+	}
+}
+
+func TestRebuildByHybridWeight(t *testing.T) {
+	rebuild := func(strategy string, weight float64, metrics bool) *PartitionMap {
+		zk := &Stub{}
+		bm, _ := zk.GetAllBrokerMeta(metrics)
+		pm, _ := PartitionMapFromString(testGetMapString4("test_topic"))
+
+		brokers := BrokerMapFromPartitionMap(pm, bm, true)
+		pmStripped := pm.Strip()
+
+		rebuildParams := RebuildParams{
+			BM:            brokers,
+			Strategy:      strategy,
+			Optimization:  "distribution",
+			PartnSzFactor: 1,
+			HybridWeight:  weight,
+		}
+
+		if metrics {
+			pmm, _ := zk.GetAllPartitionMeta()
+			for _, partn := range pmm["test_topic"] {
+				partn.Size = partn.Size / 3
+			}
+
+			// Skew storage so that placements differ from count placement.
+			for id, b := range brokers {
+				b.StorageFree = 6000.00 + float64(id%10)*500
+			}
+
+			rebuildParams.PMM = pmm
+		}
+
+		out, errs := pmStripped.Rebuild(rebuildParams)
+		if errs != nil {
+			t.Fatalf("Unexpected error(s): %s", errs)
+		}
+
+		return out
+	}
+
+	// A weight of 0 is count placement and doesn't require metrics.
+	count := rebuild("count", 0, false)
+	if same, err := count.Equal(rebuild("hybrid", 0, false)); !same {
+		t.Errorf("Unexpected inequality between count and hybrid rebuild with weight 0: %s", err)
+	}
+
+	// A weight of 0 ignores partition sizes and storage free.
+	if same, err := rebuild("count", 0, true).Equal(rebuild("hybrid", 0, true)); !same {
+		t.Errorf("Unexpected inequality between count and hybrid rebuild with weight 0: %s", err)
+	}
+
+	// A midpoint weight differs from both extremes.
+	mid := rebuild("hybrid", 0.5, true)
+	if same, _ := mid.Equal(rebuild("count", 0, true)); same {
+		t.Error("Expected hybrid rebuild with weight 0.5 to differ from count rebuild")
+	}
+	if same, _ := mid.Equal(rebuild("storage", 0, true)); same {
+		t.Error("Expected hybrid rebuild with weight 0.5 to differ from storage rebuild")
+	}
+}
+
+func TestRebuildObservers(t *testing.T) {
+	zk := &Stub{}
+	bm, _ := zk.GetAllBrokerMeta(false)
+	pm, _ := PartitionMapFromString(`{"version":1,"partitions":[
+    {"topic":"test_topic","partition":0,"replicas":[1001,1002]},
+    {"topic":"test_topic","partition":1,"replicas":[1002,1001]},
+    {"topic":"test_topic","partition":2,"replicas":[1003,1004,1001],"observers":[1001]},
+    {"topic":"test_topic","partition":3,"replicas":[1004,1003,1002],"observers":[1002]}]}`)
+
+	brokers := BrokerMapFromPartitionMap(pm, bm, false)
+	brokers[1001].Replace = true
+
+	out, errs := pm.Rebuild(RebuildParams{BM: brokers, Strategy: "count"})
+	if errs != nil {
+		t.Fatalf("Unexpected error(s): %s", errs)
+	}
+
+	for i, p := range out.Partitions {
+		if len(pm.Partitions[i].Observers) == 0 {
+			if p.Observers != nil {
+				t.Errorf("p%d: expected no observers, got %v", i, p.Observers)
+			}
+			continue
+		}
+
+		// The replica in the observer position is the observer, including
+		// replacements.
+		if len(p.Observers) != 1 || p.Observers[0] != p.Replicas[2] || p.Observers[0] == 1001 {
+			t.Errorf("p%d: expected observer %d, got %v", i, p.Replicas[2], p.Observers)
+		}
+	}
+
+	// Increasing the replication factor adds sync replicas ahead of
+	// observers.
+	pm.SetReplication(4)
+
+	if r := pm.Partitions[2].Replicas; !intsEqual(r, []int{1003, 1004, StubBrokerID, 1001}) {
+		t.Errorf("Expected stub broker ahead of the observer, got %v", r)
+	}
+
+	// Decreasing the replication factor removes observers first.
+	pm.SetReplication(2)
+
+	if o := pm.Partitions[2].Observers; o != nil {
+		t.Errorf("Expected observers removed, got %v", o)
+	}
+}
+
 func TestLocalitiesAvailable(t *testing.T) {
 	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
 	bm := newStubBrokerMap()
@@ -679,6 +903,32 @@ func TestOptimizeLeaderFollower(t *testing.T) {
 	}
 }
 
+func TestOptimizeLeaderFollowerWeighted(t *testing.T) {
+	pm := NewPartitionMap()
+	for i := 0; i < 8; i++ {
+		replicas := []int{1001, 1002}
+		if i%2 == 0 {
+			replicas = []int{1002, 1001}
+		}
+		pm.Partitions = append(pm.Partitions, Partition{Topic: "test_topic", Partition: i, Replicas: replicas})
+	}
+
+	pm.OptimizeLeaderFollowerWeighted(LeaderWeights{1001: 3, 1002: 1})
+
+	stats := pm.UseStats()
+	if stats[1001].Leader <= stats[1002].Leader {
+		t.Errorf("Expected broker 1001 to lead more partitions than 1002, got %d and %d",
+			stats[1001].Leader, stats[1002].Leader)
+	}
+
+	// Replica sets must remain unchanged.
+	for _, p := range pm.Partitions {
+		if len(p.Replicas) != 2 || p.Replicas[0] == p.Replicas[1] {
+			t.Errorf("Unexpected replica set %v", p.Replicas)
+		}
+	}
+}
+
 func TestShuffle(t *testing.T) {
 	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
 
@@ -714,3 +964,43 @@ func TestShuffle(t *testing.T) {
 		t.Errorf("Unexpected shuffle results")
 	}
 }
+
+func TestRebuildByCountMultipleTopics(t *testing.T) {
+	// 12 single partition topics, all on 1001 and 1002.
+	pm := NewPartitionMap()
+	for i := 0; i < 12; i++ {
+		pm.Partitions = append(pm.Partitions, Partition{
+			Topic:     fmt.Sprintf("topic%02d", i),
+			Partition: 0,
+			Replicas:  []int{1001, 1002},
+		})
+	}
+
+	bm := BrokerMapFromPartitionMap(pm, BrokerMetaMap{}, false)
+	bm[1001].Replace = true
+	bm[1002].Replace = true
+	for _, id := range []int{1003, 1004, 1005, 1006} {
+		bm[id] = &Broker{ID: id}
+	}
+
+	out, errs := pm.Rebuild(RebuildParams{BM: bm, Strategy: "count"})
+	if errs != nil {
+		t.Fatalf("Unexpected error(s): %s", errs)
+	}
+
+	// Each topic alone would be balanced by any pair of brokers; placing each
+	// topic in isolation would put every topic on the same pair. The 24
+	// combined replicas should instead be spread evenly.
+	counts := map[int]int{}
+	for _, p := range out.Partitions {
+		for _, id := range p.Replicas {
+			counts[id]++
+		}
+	}
+
+	for _, id := range []int{1003, 1004, 1005, 1006} {
+		if counts[id] != 6 {
+			t.Errorf("Expected 6 replicas on broker %d, got %d", id, counts[id])
+		}
+	}
+}
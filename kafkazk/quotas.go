@@ -0,0 +1,335 @@
+package kafkazk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Client quota config keys.
+const (
+	ProducerByteRate  = "producer_byte_rate"
+	ConsumerByteRate  = "consumer_byte_rate"
+	RequestPercentage = "request_percentage"
+
+	// DefaultQuotaEntity is the entity name used for default quotas.
+	DefaultQuotaEntity = "<default>"
+)
+
+var (
+	// ErrInvalidQuotaEntity is returned when a ClientQuotaEntity has neither
+	// a user nor client ID.
+	ErrInvalidQuotaEntity = errors.New("quota entity requires a user and/or client ID")
+
+	validQuotaKeys = map[string]struct{}{
+		ProducerByteRate:  struct{}{},
+		ConsumerByteRate:  struct{}{},
+		RequestPercentage: struct{}{},
+	}
+)
+
+// ClientQuotaEntity identifies the subject of a client quota. Quotas may be
+// set for a user, a client ID, or a client ID of a specific user. Either
+// field may be DefaultQuotaEntity to reference default quotas.
+type ClientQuotaEntity struct {
+	User     string `json:"user,omitempty"`
+	ClientID string `json:"client_id,omitempty"`
+}
+
+// ClientQuotaLimits is a mapping of quota config key to value.
+type ClientQuotaLimits map[string]float64
+
+// ClientQuota is a ClientQuotaEntity and its configured limits.
+type ClientQuota struct {
+	Entity ClientQuotaEntity `json:"entity"`
+	Limits ClientQuotaLimits `json:"limits"`
+}
+
+// ClientQuotas is a []ClientQuota.
+type ClientQuotas []ClientQuota
+
+// ClientQuotaFilter filters quotas in DescribeClientQuotas. An empty field
+// matches any value.
+type ClientQuotaFilter struct {
+	User     string
+	ClientID string
+}
+
+// ClientQuotaAlteration describes a change to the quotas for an entity.
+// Limits in Set are created or updated and keys in Remove are deleted.
+type ClientQuotaAlteration struct {
+	Entity ClientQuotaEntity
+	Set    ClientQuotaLimits
+	Remove []string
+}
+
+// path returns the config znode path for the entity, relative to the
+// /config path.
+func (e ClientQuotaEntity) path() string {
+	switch {
+	case e.User != "" && e.ClientID != "":
+		return fmt.Sprintf("users/%s/clients/%s", sanitizeQuotaEntityName(e.User), sanitizeQuotaEntityName(e.ClientID))
+	case e.User != "":
+		return fmt.Sprintf("users/%s", sanitizeQuotaEntityName(e.User))
+	default:
+		return fmt.Sprintf("clients/%s", sanitizeQuotaEntityName(e.ClientID))
+	}
+}
+
+// matches returns whether the entity satisfies the filter.
+func (f ClientQuotaFilter) matches(e ClientQuotaEntity) bool {
+	if f.User != "" && f.User != e.User {
+		return false
+	}
+
+	if f.ClientID != "" && f.ClientID != e.ClientID {
+		return false
+	}
+
+	return true
+}
+
+// sanitizeQuotaEntityName URL encodes quota entity names as Kafka does.
+func sanitizeQuotaEntityName(s string) string {
+	if s == DefaultQuotaEntity {
+		return s
+	}
+
+	s = url.QueryEscape(s)
+	s = strings.Replace(s, "+", "%20", -1)
+	return strings.Replace(s, "*", "%2A", -1)
+}
+
+// desanitizeQuotaEntityName reverses sanitizeQuotaEntityName.
+func desanitizeQuotaEntityName(s string) string {
+	if d, err := url.PathUnescape(s); err == nil {
+		return d
+	}
+
+	return s
+}
+
+// describeClientQuotas implements DescribeClientQuotas for a Handler with
+// the Kafka ZooKeeper prefix p.
+func describeClientQuotas(zk Handler, p string, f ClientQuotaFilter) (ClientQuotas, error) {
+	config := quotaConfigPath(p)
+
+	// Build a list of all entities with config znodes.
+	var entities []ClientQuotaEntity
+
+	users, err := childrenIfExists(zk, config+"/users")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, u := range users {
+		user := desanitizeQuotaEntityName(u)
+		entities = append(entities, ClientQuotaEntity{User: user})
+
+		clients, err := childrenIfExists(zk, fmt.Sprintf("%s/users/%s/clients", config, u))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, c := range clients {
+			entities = append(entities, ClientQuotaEntity{User: user, ClientID: desanitizeQuotaEntityName(c)})
+		}
+	}
+
+	clients, err := childrenIfExists(zk, config+"/clients")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range clients {
+		entities = append(entities, ClientQuotaEntity{ClientID: desanitizeQuotaEntityName(c)})
+	}
+
+	quotas := ClientQuotas{}
+
+	for _, e := range entities {
+		if !f.matches(e) {
+			continue
+		}
+
+		data, err := getKafkaConfigData(zk, fmt.Sprintf("%s/%s", config, e.path()))
+		if err != nil {
+			return nil, err
+		}
+
+		limits := ClientQuotaLimits{}
+		for k, v := range data.Config {
+			if _, valid := validQuotaKeys[k]; !valid {
+				continue
+			}
+
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s value for %s: %s", k, e.path(), v)
+			}
+
+			limits[k] = f
+		}
+
+		// Entities with no quotas are parent znodes of user/client quotas.
+		if len(limits) > 0 {
+			quotas = append(quotas, ClientQuota{Entity: e, Limits: limits})
+		}
+	}
+
+	sort.Slice(quotas, func(i, j int) bool {
+		return quotas[i].Entity.path() < quotas[j].Entity.path()
+	})
+
+	return quotas, nil
+}
+
+// alterClientQuotas implements AlterClientQuotas for a Handler with the
+// Kafka ZooKeeper prefix p.
+func alterClientQuotas(zk Handler, p string, alts []ClientQuotaAlteration) error {
+	config := quotaConfigPath(p)
+
+	// Validate all alterations before making any changes.
+	for _, a := range alts {
+		if a.Entity.User == "" && a.Entity.ClientID == "" {
+			return ErrInvalidQuotaEntity
+		}
+
+		for k, v := range a.Set {
+			if _, valid := validQuotaKeys[k]; !valid {
+				return fmt.Errorf("invalid quota key %s", k)
+			}
+
+			if v < 0 {
+				return fmt.Errorf("quota %s must be >= 0", k)
+			}
+		}
+
+		for _, k := range a.Remove {
+			if _, valid := validQuotaKeys[k]; !valid {
+				return fmt.Errorf("invalid quota key %s", k)
+			}
+		}
+	}
+
+	for _, a := range alts {
+		entityPath := a.Entity.path()
+		path := fmt.Sprintf("%s/%s", config, entityPath)
+
+		data, err := getKafkaConfigData(zk, path)
+		if err != nil {
+			return err
+		}
+
+		for k, v := range a.Set {
+			data.Config[k] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+
+		for _, k := range a.Remove {
+			delete(data.Config, k)
+		}
+
+		d, err := json.Marshal(data)
+		if err != nil {
+			return fmt.Errorf("Error marshalling config: %s", err)
+		}
+
+		if err := createOrSet(zk, path, string(d)); err != nil {
+			return err
+		}
+
+		// Write a change notification at /config/changes/config_change_<seq>.
+		cdata := fmt.Sprintf(`{"version":2,"entity_path":"%s"}`, entityPath)
+		if err := zk.CreateSequential(config+"/changes/config_change_", cdata); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// quotaConfigPath returns the /config path for the Kafka ZooKeeper prefix p.
+func quotaConfigPath(p string) string {
+	if p != "" {
+		return fmt.Sprintf("/%s/config", p)
+	}
+
+	return "/config"
+}
+
+// getKafkaConfigData returns the KafkaConfigData at path p. An empty
+// KafkaConfigData is returned if the znode doesn't exist or has no data.
+func getKafkaConfigData(zk Handler, p string) (KafkaConfigData, error) {
+	config := NewKafkaConfigData()
+	config.Version = 1
+
+	exists, err := zk.Exists(p)
+	if err != nil || !exists {
+		return config, err
+	}
+
+	d, err := zk.Get(p)
+	if err != nil {
+		return config, err
+	}
+
+	if len(d) == 0 {
+		return config, nil
+	}
+
+	if err := json.Unmarshal(d, &config); err != nil {
+		return config, fmt.Errorf("error unmarshalling %s: %s", p, err)
+	}
+
+	if config.Config == nil {
+		config.Config = make(map[string]string)
+	}
+
+	return config, nil
+}
+
+// childrenIfExists returns the children of the znode at path p, or an empty
+// list if the znode doesn't exist.
+func childrenIfExists(zk Handler, p string) ([]string, error) {
+	exists, err := zk.Exists(p)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	return zk.Children(p)
+}
+
+// createOrSet sets the data d at path p, creating the znode and any missing
+// parent znodes.
+func createOrSet(zk Handler, p string, d string) error {
+	exists, err := zk.Exists(p)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return zk.Set(p, d)
+	}
+
+	// Create any missing parents.
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	for i := 1; i < len(parts); i++ {
+		parent := "/" + strings.Join(parts[:i], "/")
+		exists, err := zk.Exists(parent)
+		if err != nil {
+			return err
+		}
+
+		if !exists {
+			if err := zk.Create(parent, ""); err != nil {
+				return err
+			}
+		}
+	}
+
+	return zk.Create(p, d)
+}
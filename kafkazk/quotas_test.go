@@ -0,0 +1,124 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestAlterDescribeClientQuotas(t *testing.T) {
+	zk := NewZooKeeperStub()
+
+	alts := []ClientQuotaAlteration{
+		{
+			Entity: ClientQuotaEntity{ClientID: "producer-1"},
+			Set:    ClientQuotaLimits{ProducerByteRate: 1048576},
+		},
+		{
+			Entity: ClientQuotaEntity{User: "alice", ClientID: "consumer-1"},
+			Set:    ClientQuotaLimits{ConsumerByteRate: 2048, RequestPercentage: 50},
+		},
+	}
+
+	if err := zk.AlterClientQuotas(alts); err != nil {
+		t.Fatal(err)
+	}
+
+	// Describe the client ID quota back.
+	quotas, err := zk.DescribeClientQuotas(ClientQuotaFilter{ClientID: "producer-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(quotas) != 1 {
+		t.Fatalf("Expected 1 quota, got %d", len(quotas))
+	}
+
+	if quotas[0].Entity != (ClientQuotaEntity{ClientID: "producer-1"}) {
+		t.Errorf("Unexpected entity %+v", quotas[0].Entity)
+	}
+
+	if r := quotas[0].Limits[ProducerByteRate]; r != 1048576 {
+		t.Errorf("Expected %s 1048576, got %f", ProducerByteRate, r)
+	}
+
+	// All quotas. The alice user znode has no quotas of its own.
+	quotas, err = zk.DescribeClientQuotas(ClientQuotaFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(quotas) != 2 {
+		t.Fatalf("Expected 2 quotas, got %d", len(quotas))
+	}
+
+	// Sorted by entity path; clients before users.
+	if quotas[1].Entity != (ClientQuotaEntity{User: "alice", ClientID: "consumer-1"}) {
+		t.Errorf("Unexpected entity %+v", quotas[1].Entity)
+	}
+
+	if r := quotas[1].Limits[RequestPercentage]; r != 50 {
+		t.Errorf("Expected %s 50, got %f", RequestPercentage, r)
+	}
+
+	// Remove a limit.
+	alts = []ClientQuotaAlteration{
+		{
+			Entity: ClientQuotaEntity{User: "alice", ClientID: "consumer-1"},
+			Remove: []string{RequestPercentage},
+		},
+	}
+
+	if err := zk.AlterClientQuotas(alts); err != nil {
+		t.Fatal(err)
+	}
+
+	quotas, err = zk.DescribeClientQuotas(ClientQuotaFilter{User: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(quotas) != 1 || len(quotas[0].Limits) != 1 || quotas[0].Limits[ConsumerByteRate] != 2048 {
+		t.Errorf("Unexpected quotas %+v", quotas)
+	}
+}
+
+func TestAlterClientQuotasInvalid(t *testing.T) {
+	zk := NewZooKeeperStub()
+
+	tests := [][]ClientQuotaAlteration{
+		{{Set: ClientQuotaLimits{ProducerByteRate: 1024}}},
+		{{Entity: ClientQuotaEntity{User: "alice"}, Set: ClientQuotaLimits{"retention.ms": 1}}},
+		{{Entity: ClientQuotaEntity{User: "alice"}, Set: ClientQuotaLimits{ProducerByteRate: -1}}},
+		{{Entity: ClientQuotaEntity{User: "alice"}, Remove: []string{"retention.ms"}}},
+	}
+
+	for i, alts := range tests {
+		if err := zk.AlterClientQuotas(alts); err == nil {
+			t.Errorf("[test %d] Expected non-nil error", i)
+		}
+	}
+
+	if err := zk.AlterClientQuotas(tests[0]); err != ErrInvalidQuotaEntity {
+		t.Errorf("Expected error '%s', got '%v'", ErrInvalidQuotaEntity, err)
+	}
+}
+
+func TestClientQuotaEntityPath(t *testing.T) {
+	tests := map[ClientQuotaEntity]string{
+		{User: "alice"}:                               "users/alice",
+		{ClientID: "client 1"}:                        "clients/client%201",
+		{User: "CN=a*b", ClientID: "c"}:               "users/CN%3Da%2Ab/clients/c",
+		{User: DefaultQuotaEntity}:                    "users/<default>",
+		{User: "alice", ClientID: DefaultQuotaEntity}: "users/alice/clients/<default>",
+	}
+
+	for e, expected := range tests {
+		if p := e.path(); p != expected {
+			t.Errorf("Expected path %s, got %s", expected, p)
+		}
+
+		// Names round trip.
+		if n := desanitizeQuotaEntityName(sanitizeQuotaEntityName(e.User)); n != e.User {
+			t.Errorf("Expected name %s, got %s", e.User, n)
+		}
+	}
+}
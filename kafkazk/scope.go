@@ -0,0 +1,152 @@
+package kafkazk
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ReassignmentScope describes the topics and brokers participating in a
+// reassignment. It's published to ZooKeeper by tools that generate
+// reassignments (e.g. topicmappr) so that other tools (e.g. autothrottle)
+// can target the participants without having to infer them.
+type ReassignmentScope struct {
+	Topics    []string `json:"topics"`
+	Brokers   []int    `json:"brokers"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+// NewReassignmentScope takes an original and updated *PartitionMap and
+// returns a ReassignmentScope. Only partitions where the replica set differs
+// between the two maps are considered; all brokers found in either the
+// original or the updated replica set of those partitions are included.
+func NewReassignmentScope(pm1, pm2 *PartitionMap) ReassignmentScope {
+	var topics = map[string]struct{}{}
+	var brokers = map[int]struct{}{}
+
+	original := map[string]map[int][]int{}
+	for _, p := range pm1.Partitions {
+		if original[p.Topic] == nil {
+			original[p.Topic] = map[int][]int{}
+		}
+		original[p.Topic][p.Partition] = p.Replicas
+	}
+
+	for _, p := range pm2.Partitions {
+		prev := original[p.Topic][p.Partition]
+		if replicasEqual(prev, p.Replicas) {
+			continue
+		}
+
+		topics[p.Topic] = struct{}{}
+		for _, id := range append(prev, p.Replicas...) {
+			brokers[id] = struct{}{}
+		}
+	}
+
+	s := ReassignmentScope{
+		Topics:    []string{},
+		Brokers:   []int{},
+		Timestamp: time.Now().Unix(),
+	}
+
+	for t := range topics {
+		s.Topics = append(s.Topics, t)
+	}
+
+	for id := range brokers {
+		s.Brokers = append(s.Brokers, id)
+	}
+
+	sort.Strings(s.Topics)
+	sort.Ints(s.Brokers)
+
+	return s
+}
+
+// HasTopic returns whether the topic is part of the ReassignmentScope.
+func (s ReassignmentScope) HasTopic(t string) bool {
+	for _, name := range s.Topics {
+		if name == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasBroker returns whether the broker is part of the ReassignmentScope.
+func (s ReassignmentScope) HasBroker(id int) bool {
+	for _, b := range s.Brokers {
+		if b == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SetReassignmentScope takes a Handler, znode path and ReassignmentScope and
+// writes the scope to the znode. The znode is created if it doesn't exist.
+func SetReassignmentScope(zk Handler, p string, s ReassignmentScope) error {
+	d, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("error marshalling reassignment scope: %s", err)
+	}
+
+	exists, err := zk.Exists(p)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return zk.Create(p, string(d))
+	}
+
+	return zk.Set(p, string(d))
+}
+
+// GetReassignmentScope takes a Handler and znode path and returns the
+// *ReassignmentScope stored at the znode. A nil *ReassignmentScope is
+// returned if the znode doesn't exist or is empty.
+func GetReassignmentScope(zk Handler, p string) (*ReassignmentScope, error) {
+	exists, err := zk.Exists(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	d, err := zk.Get(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(d) == 0 {
+		return nil, nil
+	}
+
+	s := &ReassignmentScope{}
+	if err := json.Unmarshal(d, s); err != nil {
+		return nil, fmt.Errorf("error unmarshalling reassignment scope: %s", err)
+	}
+
+	return s, nil
+}
+
+func replicasEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
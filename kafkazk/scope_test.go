@@ -0,0 +1,65 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestNewReassignmentScope(t *testing.T) {
+	pm1, _ := PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test_topic","partition":0,"replicas":[1001,1002]},
+		{"topic":"test_topic","partition":1,"replicas":[1002,1001]},
+		{"topic":"test_topic2","partition":0,"replicas":[1003,1004]}]}`)
+	pm2, _ := PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test_topic","partition":0,"replicas":[1001,1005]},
+		{"topic":"test_topic","partition":1,"replicas":[1002,1001]},
+		{"topic":"test_topic2","partition":0,"replicas":[1003,1004]}]}`)
+
+	s := NewReassignmentScope(pm1, pm2)
+
+	if len(s.Topics) != 1 || s.Topics[0] != "test_topic" {
+		t.Errorf("Expected topics [test_topic], got %v", s.Topics)
+	}
+
+	expected := []int{1001, 1002, 1005}
+	if len(s.Brokers) != len(expected) {
+		t.Fatalf("Expected brokers %v, got %v", expected, s.Brokers)
+	}
+
+	for i := range expected {
+		if s.Brokers[i] != expected[i] {
+			t.Errorf("Expected broker %d, got %d", expected[i], s.Brokers[i])
+		}
+	}
+}
+
+func TestSetGetReassignmentScope(t *testing.T) {
+	zk := NewZooKeeperStub()
+	p := "/test/reassignment_scope"
+
+	s, err := GetReassignmentScope(zk, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s != nil {
+		t.Error("Expected nil scope for nonexistent znode")
+	}
+
+	in := ReassignmentScope{Topics: []string{"test_topic"}, Brokers: []int{1001, 1002}}
+	if err := SetReassignmentScope(zk, p, in); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = GetReassignmentScope(zk, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !s.HasTopic("test_topic") || s.HasTopic("test_topic2") {
+		t.Errorf("Unexpected scope topics %v", s.Topics)
+	}
+
+	if !s.HasBroker(1002) || s.HasBroker(1003) {
+		t.Errorf("Unexpected scope brokers %v", s.Brokers)
+	}
+}
@@ -0,0 +1,365 @@
+package kafkazk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"time"
+)
+
+var (
+	// ErrSnapshotUnsupported is returned by a snapshot Handler for operations
+	// that require a live cluster.
+	ErrSnapshotUnsupported = errors.New("operation not supported with a snapshot")
+	// ErrSnapshotNoMetrics is returned by a snapshot Handler when metrics
+	// are requested but the snapshot was captured without metrics.
+	ErrSnapshotNoMetrics = errors.New("snapshot has no metrics")
+)
+
+// Snapshot is a point in time capture of the cluster metadata used for
+// partition mapping. A Snapshot can be written to a file and later used as a
+// Handler for offline planning.
+type Snapshot struct {
+	Version   int    `json:"version"`
+	Timestamp int64  `json:"timestamp"` // Unix epoch ns.
+	ClusterID string `json:"cluster_id"`
+	// Whether broker and partition metrics were captured.
+	Metrics bool `json:"metrics"`
+	// The age of the metrics at capture time.
+	MetricsAge    time.Duration    `json:"metrics_age"`
+	Brokers       BrokerMetaMap    `json:"brokers"`
+	PartitionMeta PartitionMetaMap `json:"partition_meta"`
+	// Topic partition maps, with replica sets of partitions undergoing
+	// reassignment set to the reassignment target.
+	Topics map[string]*PartitionMap `json:"topics"`
+	// Topic config overrides along with the cluster-wide dynamic broker
+	// defaults, from which effective topic configs are derived. Snapshots
+	// captured without configs have a nil TopicConfigs.
+	TopicConfigs    map[string]*TopicConfig `json:"topic_configs"`
+	BrokerDefaults  map[string]string       `json:"broker_defaults"`
+	PendingDeletion []string                `json:"pending_deletion"`
+	Reassignments   Reassignments           `json:"reassignments"`
+}
+
+// NewSnapshot captures a Snapshot from a Handler. Metrics are captured if
+// available; the Snapshot Metrics field indicates whether they were.
+func NewSnapshot(zk Handler) (*Snapshot, error) {
+	s := &Snapshot{
+		Version:      1,
+		Timestamp:    time.Now().UnixNano(),
+		Topics:       map[string]*PartitionMap{},
+		TopicConfigs: map[string]*TopicConfig{},
+	}
+
+	// The cluster ID is informational; older clusters may not have one.
+	s.ClusterID, _ = zk.GetClusterID()
+
+	// Metrics are fetched first so that the absence of metrics can fall
+	// back to a broker metadata only snapshot.
+	bm, _ := zk.GetAllBrokerMeta(true)
+	pmm, pmmErr := zk.GetAllPartitionMeta()
+	age, ageErr := zk.MaxMetaAge()
+
+	if bm != nil && pmmErr == nil && ageErr == nil {
+		s.Metrics = true
+		s.Brokers, s.PartitionMeta, s.MetricsAge = bm, pmm, age
+	} else {
+		var errs []error
+		if s.Brokers, errs = zk.GetAllBrokerMeta(false); s.Brokers == nil {
+			return nil, fmt.Errorf("error fetching broker metadata: %v", errs)
+		}
+		s.PartitionMeta = NewPartitionMetaMap()
+	}
+
+	topics, err := zk.GetTopics([]*regexp.Regexp{regexp.MustCompile(".*")})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range topics {
+		pm, err := zk.GetPartitionMap(t)
+		if err != nil {
+			return nil, err
+		}
+		s.Topics[t] = pm
+
+		tc, err := zk.GetTopicConfig(t)
+		switch err.(type) {
+		case nil:
+		case ErrNoNode:
+			// Topics without a config znode have no overrides.
+			tc = &TopicConfig{Version: 1, Config: map[string]string{}}
+		default:
+			return nil, err
+		}
+		s.TopicConfigs[t] = tc
+	}
+
+	// The ZKHandler prefix determines the broker defaults path.
+	var prefix string
+	if z, ok := zk.(*ZKHandler); ok {
+		prefix = z.Prefix
+	}
+
+	path := fmt.Sprintf("%s/brokers/%s", quotaConfigPath(prefix), DefaultQuotaEntity)
+	defaults, err := getKafkaConfigData(zk, path)
+	if err != nil {
+		return nil, err
+	}
+	s.BrokerDefaults = defaults.Config
+
+	if s.PendingDeletion, err = zk.GetPendingDeletion(); err != nil {
+		return nil, err
+	}
+
+	s.Reassignments = zk.GetReassignments()
+
+	return s, nil
+}
+
+// SnapshotFromFile reads a Snapshot from the file at path p.
+func SnapshotFromFile(p string) (*Snapshot, error) {
+	data, err := ioutil.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Snapshot{}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("error unmarshalling snapshot %s: %s", p, err)
+	}
+
+	if s.Version != 1 {
+		return nil, fmt.Errorf("unsupported snapshot version %d", s.Version)
+	}
+
+	return s, nil
+}
+
+// WriteFile writes the Snapshot to the file at path p.
+func (s *Snapshot) WriteFile(p string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(p, data, 0644)
+}
+
+// Handler returns a read-only Handler backed by the Snapshot. Operations that
+// require a live cluster return an ErrSnapshotUnsupported.
+func (s *Snapshot) Handler() Handler {
+	return &snapshotHandler{s: s}
+}
+
+// snapshotHandler implements the Handler interface for a Snapshot.
+type snapshotHandler struct {
+	s *Snapshot
+}
+
+// Exists is unsupported.
+func (h *snapshotHandler) Exists(string) (bool, error) { return false, ErrSnapshotUnsupported }
+
+// Create is unsupported.
+func (h *snapshotHandler) Create(string, string) error { return ErrSnapshotUnsupported }
+
+// CreateSequential is unsupported.
+func (h *snapshotHandler) CreateSequential(string, string) error { return ErrSnapshotUnsupported }
+
+// Set is unsupported.
+func (h *snapshotHandler) Set(string, string) error { return ErrSnapshotUnsupported }
+
+// Get is unsupported.
+func (h *snapshotHandler) Get(string) ([]byte, error) { return nil, ErrSnapshotUnsupported }
+
+// Delete is unsupported.
+func (h *snapshotHandler) Delete(string) error { return ErrSnapshotUnsupported }
+
+// Children is unsupported.
+func (h *snapshotHandler) Children(string) ([]string, error) { return nil, ErrSnapshotUnsupported }
+
+// NextInt is unsupported.
+func (h *snapshotHandler) NextInt(string) (int32, error) { return 0, ErrSnapshotUnsupported }
+
+// Close is a no-op.
+func (h *snapshotHandler) Close() {}
+
+// Ready always returns true.
+func (h *snapshotHandler) Ready() bool { return true }
+
+// GetTopicState is unsupported; the live state of partitions undergoing
+// reassignment isn't captured.
+func (h *snapshotHandler) GetTopicState(string) (*TopicState, error) {
+	return nil, ErrSnapshotUnsupported
+}
+
+// GetTopicStateISR is unsupported.
+func (h *snapshotHandler) GetTopicStateISR(string) (TopicStateISR, error) {
+	return nil, ErrSnapshotUnsupported
+}
+
+// UpdateKafkaConfig is unsupported.
+func (h *snapshotHandler) UpdateKafkaConfig(KafkaConfig) ([]bool, error) {
+	return nil, ErrSnapshotUnsupported
+}
+
+// DescribeClientQuotas is unsupported.
+func (h *snapshotHandler) DescribeClientQuotas(ClientQuotaFilter) (ClientQuotas, error) {
+	return nil, ErrSnapshotUnsupported
+}
+
+// AlterClientQuotas is unsupported.
+func (h *snapshotHandler) AlterClientQuotas([]ClientQuotaAlteration) error {
+	return ErrSnapshotUnsupported
+}
+
+// GetReassignments returns the captured Reassignments.
+func (h *snapshotHandler) GetReassignments() Reassignments {
+	return h.s.Reassignments
+}
+
+// SubmitReassignment is unsupported.
+func (h *snapshotHandler) SubmitReassignment(*PartitionMap) error {
+	return ErrSnapshotUnsupported
+}
+
+// WaitReassignmentComplete is unsupported.
+func (h *snapshotHandler) WaitReassignmentComplete(context.Context) error {
+	return ErrSnapshotUnsupported
+}
+
+// WatchISRShrinks is unsupported.
+func (h *snapshotHandler) WatchISRShrinks(context.Context, string, chan<- ISRShrinkEvent) error {
+	return ErrSnapshotUnsupported
+}
+
+// GetUnderReplicated is unsupported.
+func (h *snapshotHandler) GetUnderReplicated() ([]string, error) {
+	return nil, ErrSnapshotUnsupported
+}
+
+// GetPendingDeletion returns the captured topics pending deletion.
+func (h *snapshotHandler) GetPendingDeletion() ([]string, error) {
+	return h.s.PendingDeletion, nil
+}
+
+// GetTopics takes a []*regexp.Regexp and returns a []string of captured
+// topic names that match any of the provided regex.
+func (h *snapshotHandler) GetTopics(ts []*regexp.Regexp) ([]string, error) {
+	var matched []string
+
+	for t := range h.s.Topics {
+		for _, re := range ts {
+			if re.MatchString(t) {
+				matched = append(matched, t)
+				break
+			}
+		}
+	}
+
+	sort.Strings(matched)
+
+	return matched, nil
+}
+
+// GetTopicConfig returns a copy of the captured config overrides for the
+// topic. ErrSnapshotUnsupported is returned if the snapshot was captured
+// without topic configs.
+func (h *snapshotHandler) GetTopicConfig(t string) (*TopicConfig, error) {
+	if h.s.TopicConfigs == nil {
+		return nil, ErrSnapshotUnsupported
+	}
+
+	tc, exists := h.s.TopicConfigs[t]
+	if !exists {
+		return nil, ErrNoNode{s: fmt.Sprintf("[%s] topic config not found in snapshot", t)}
+	}
+
+	config := map[string]string{}
+	for k, v := range tc.Config {
+		config[k] = v
+	}
+
+	return &TopicConfig{Version: tc.Version, Config: config}, nil
+}
+
+// GetEffectiveTopicConfig returns the topic's effective config derived from
+// the captured topic overrides and dynamic broker defaults along with the
+// provided static broker configs, as with ZKHandler.GetEffectiveTopicConfig.
+func (h *snapshotHandler) GetEffectiveTopicConfig(t string, static map[string]string) (*TopicConfig, error) {
+	overrides, err := h.GetTopicConfig(t)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TopicConfig{
+		Version: overrides.Version,
+		Config:  effectiveTopicConfig(overrides.Config, static, h.s.BrokerDefaults),
+	}, nil
+}
+
+// GetAllBrokerMeta returns a copy of the captured BrokerMetaMap. If
+// withMetrics is false, storage metrics are omitted.
+func (h *snapshotHandler) GetAllBrokerMeta(withMetrics bool) (BrokerMetaMap, []error) {
+	if withMetrics && !h.s.Metrics {
+		return nil, []error{ErrSnapshotNoMetrics}
+	}
+
+	bmm := BrokerMetaMap{}
+	for id, b := range h.s.Brokers {
+		meta := *b
+		if !withMetrics {
+			meta.StorageFree, meta.MetricsIncomplete = 0, false
+		}
+		bmm[id] = &meta
+	}
+
+	return bmm, nil
+}
+
+// GetAllPartitionMeta returns the captured PartitionMetaMap.
+func (h *snapshotHandler) GetAllPartitionMeta() (PartitionMetaMap, error) {
+	if !h.s.Metrics {
+		return nil, ErrSnapshotNoMetrics
+	}
+
+	return h.s.PartitionMeta, nil
+}
+
+// MaxMetaAge returns the age of the captured metrics at capture time.
+func (h *snapshotHandler) MaxMetaAge() (time.Duration, error) {
+	if !h.s.Metrics {
+		return time.Nanosecond, ErrSnapshotNoMetrics
+	}
+
+	return h.s.MetricsAge, nil
+}
+
+// GetPartitionMap takes a topic name and returns a copy of its captured
+// *PartitionMap.
+func (h *snapshotHandler) GetPartitionMap(t string) (*PartitionMap, error) {
+	pm, exists := h.s.Topics[t]
+	if !exists {
+		return nil, ErrNoNode{s: fmt.Sprintf("[%s] topic not found in snapshot", t)}
+	}
+
+	return pm.Copy(), nil
+}
+
+// GetClusterID returns the captured cluster ID.
+func (h *snapshotHandler) GetClusterID() (string, error) {
+	if h.s.ClusterID == "" {
+		return "", ErrNoClusterID{s: "cluster id not found in snapshot"}
+	}
+
+	return h.s.ClusterID, nil
+}
+
+// GetFeatures is unsupported.
+func (h *snapshotHandler) GetFeatures() (*Features, error) {
+	return nil, ErrSnapshotUnsupported
+}
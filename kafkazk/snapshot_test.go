@@ -0,0 +1,179 @@
+package kafkazk
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestSnapshotHandler(t *testing.T) {
+	s, err := NewSnapshot(&Stub{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !s.Metrics {
+		t.Error("Expected a snapshot with metrics")
+	}
+
+	zk := s.Handler()
+
+	topics, _ := zk.GetTopics([]*regexp.Regexp{regexp.MustCompile("test_topic2")})
+	if len(topics) != 1 || topics[0] != "test_topic2" {
+		t.Errorf("Expected topics [test_topic2], got %v", topics)
+	}
+
+	if _, err := zk.GetPartitionMap("missing"); err == nil {
+		t.Error("Expected error for a topic not in the snapshot")
+	}
+
+	// Metrics are stripped unless requested.
+	bm, _ := zk.GetAllBrokerMeta(false)
+	if bm[1001].StorageFree != 0 || s.Brokers[1001].StorageFree != 2000 {
+		t.Error("Unexpected broker metrics")
+	}
+
+	// Modifying a returned map doesn't modify the snapshot.
+	pm, _ := zk.GetPartitionMap("test_topic")
+	pm.Partitions[0].Replicas[0] = 1010
+	if s.Topics["test_topic"].Partitions[0].Replicas[0] != 1001 {
+		t.Error("Snapshot modified through a returned partition map")
+	}
+
+	if err := zk.SubmitReassignment(pm); err != ErrSnapshotUnsupported {
+		t.Errorf("Expected ErrSnapshotUnsupported, got %v", err)
+	}
+
+	// Snapshots without metrics.
+	s.Metrics = false
+	if _, err := zk.GetAllPartitionMeta(); err != ErrSnapshotNoMetrics {
+		t.Errorf("Expected ErrSnapshotNoMetrics, got %v", err)
+	}
+}
+
+func TestSnapshotTopicConfigs(t *testing.T) {
+	live := NewZooKeeperStub()
+	live.Set("/config/brokers/<default>", `{"version":1,"config":{"min.insync.replicas":"2"}}`)
+
+	s, err := NewSnapshot(live)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	zk := s.Handler()
+
+	tc, err := zk.GetTopicConfig("test_topic")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if tc.Config["retention.ms"] != "172800000" {
+		t.Errorf("Expected the captured topic config, got %v", tc.Config)
+	}
+
+	// The dynamic broker default takes precedence over static configs.
+	static := map[string]string{"min.insync.replicas": "1", "log.retention.hours": "1"}
+
+	for _, h := range []Handler{live, zk} {
+		tc, err := h.GetEffectiveTopicConfig("test_topic", static)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tc.Config["min.insync.replicas"] != "2" || tc.Config["retention.ms"] != "172800000" {
+			t.Errorf("Unexpected effective topic config %v", tc.Config)
+		}
+	}
+
+	// Modifying a returned config doesn't modify the snapshot.
+	tc.Config["retention.ms"] = "1"
+	if s.TopicConfigs["test_topic"].Config["retention.ms"] != "172800000" {
+		t.Error("Snapshot modified through a returned topic config")
+	}
+
+	// Snapshots captured without topic configs.
+	s.TopicConfigs = nil
+	if _, err := zk.GetTopicConfig("test_topic"); err != ErrSnapshotUnsupported {
+		t.Errorf("Expected ErrSnapshotUnsupported, got %v", err)
+	}
+}
+
+func TestSnapshotRebuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	live := &Stub{}
+
+	// Capture a snapshot to a file and read it back.
+	s, err := NewSnapshot(live)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "snapshot.json")
+	if err := s.WriteFile(path); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = SnapshotFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	offline := s.Handler()
+
+	// Run the same storage placement rebuild, replacing 1004, against both
+	// Handlers.
+	rebuild := func(zk Handler) *PartitionMap {
+		bm, errs := zk.GetAllBrokerMeta(true)
+		if errs != nil {
+			t.Fatal(errs)
+		}
+
+		pmm, err := zk.GetAllPartitionMeta()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pm, err := PartitionMapFromZK([]*regexp.Regexp{regexp.MustCompile("test_topic$")}, zk)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		brokers := BrokerMapFromPartitionMap(pm, bm, false)
+		brokers.Update([]int{1001, 1002, 1003, 1005, 1007}, bm)
+
+		params := NewRebuildParams()
+		params.PMM = pmm
+		params.BM = brokers
+		params.Strategy = "storage"
+		params.Optimization = "storage"
+
+		out, errs := pm.Rebuild(params)
+		if errs != nil {
+			t.Fatal(errs)
+		}
+
+		return out
+	}
+
+	expected, got := rebuild(live), rebuild(offline)
+
+	if same, err := expected.Equal(got); !same {
+		t.Errorf("Snapshot rebuild differs from live rebuild: %s", err)
+	}
+
+	// Sanity check that the rebuild replaced 1004.
+	for _, p := range got.Partitions {
+		for _, id := range p.Replicas {
+			if id == 1004 {
+				t.Errorf("Unexpected broker 1004 in %s p%d", p.Topic, p.Partition)
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package kafkazk
+
+// SoftConstraint is a placement preference. Unlike the hard constraints
+// checked by Constraints (unique broker IDs, rack IDs and storage capacity),
+// soft constraints never exclude a candidate broker; they assign a penalty to
+// each candidate and the passing candidate with the lowest total penalty is
+// selected.
+type SoftConstraint interface {
+	// Init is called with the PartitionMap being rebuilt and the BrokerMap
+	// before any placements are made.
+	Init(*PartitionMap, BrokerMap)
+	// Penalty returns a non-negative penalty for placing a replica on the
+	// broker at the replica set position, where position 0 is the leader.
+	Penalty(b *Broker, position int) float64
+	// Placed is called when a broker is selected for a replica set position.
+	Placed(b *Broker, position int)
+}
+
+// softPenalty returns the sum of penalties for the broker and position across
+// all SoftConstraints.
+func softPenalty(sc []SoftConstraint, b *Broker, position int) float64 {
+	var p float64
+	for _, c := range sc {
+		p += c.Penalty(b, position)
+	}
+
+	return p
+}
+
+// softPlaced calls Placed on all SoftConstraints.
+func softPlaced(sc []SoftConstraint, b *Broker, position int) {
+	for _, c := range sc {
+		c.Placed(b, position)
+	}
+}
+
+// LeaderBalance is a SoftConstraint that prefers placing leaders on brokers
+// leading the fewest partitions. The penalty for a leader placement is the
+// number of partitions the broker leads multiplied by the Weight; follower
+// placements aren't penalized.
+type LeaderBalance struct {
+	Weight  float64
+	leaders map[int]int
+}
+
+// NewLeaderBalance returns a *LeaderBalance with the provided weight.
+func NewLeaderBalance(w float64) *LeaderBalance {
+	return &LeaderBalance{Weight: w, leaders: map[int]int{}}
+}
+
+// Init counts the leaders in the PartitionMap, excluding brokers marked for
+// replacement.
+func (l *LeaderBalance) Init(pm *PartitionMap, bm BrokerMap) {
+	l.leaders = map[int]int{}
+
+	for _, p := range pm.Partitions {
+		if len(p.Replicas) == 0 {
+			continue
+		}
+
+		if b, exists := bm[p.Replicas[0]]; exists && !b.Replace {
+			l.leaders[b.ID]++
+		}
+	}
+}
+
+// Penalty implements SoftConstraint.
+func (l *LeaderBalance) Penalty(b *Broker, position int) float64 {
+	if position != 0 {
+		return 0
+	}
+
+	return float64(l.leaders[b.ID]) * l.Weight
+}
+
+// Placed implements SoftConstraint.
+func (l *LeaderBalance) Placed(b *Broker, position int) {
+	if position == 0 {
+		l.leaders[b.ID]++
+	}
+}
@@ -0,0 +1,66 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestRebuildWithLeaderBalance(t *testing.T) {
+	pm, _ := PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"test","partition":0,"replicas":[1005,1003]},
+		{"topic":"test","partition":1,"replicas":[1001,1004]},
+		{"topic":"test","partition":2,"replicas":[1001,1003]},
+		{"topic":"test","partition":3,"replicas":[1002,1004]},
+		{"topic":"test","partition":4,"replicas":[1003,1002]},
+		{"topic":"test","partition":5,"replicas":[1004,1002]}]}`)
+
+	// 1001 has fewer replicas than 1002 but leads more partitions. 1006 is
+	// empty but shares a rack with the p0 follower, 1003.
+	newBrokers := func() BrokerMap {
+		bm := BrokerMapFromPartitionMap(pm, BrokerMetaMap{
+			1001: &BrokerMeta{Rack: "a"},
+			1002: &BrokerMeta{Rack: "a"},
+			1003: &BrokerMeta{Rack: "b"},
+			1004: &BrokerMeta{Rack: "b"},
+			1005: &BrokerMeta{Rack: "a"},
+		}, false)
+		bm[1005].Replace = true
+		bm[1006] = &Broker{ID: 1006, Locality: "b"}
+		return bm
+	}
+
+	params := RebuildParams{BM: newBrokers(), Strategy: "count"}
+
+	// Count placement selects the rack a broker with the fewest replicas.
+	out, errs := pm.Rebuild(params)
+	if errs != nil {
+		t.Fatalf("Unexpected error(s): %s", errs)
+	}
+
+	if leader := out.Partitions[0].Replicas[0]; leader != 1001 {
+		t.Errorf("Expected leader 1001, got %d", leader)
+	}
+
+	// With leader balance, the rack a broker with the fewest leaders is
+	// selected. 1006 leads no partitions but is never selected since it would
+	// violate the rack constraint.
+	params.BM = newBrokers()
+	params.SoftConstraints = []SoftConstraint{NewLeaderBalance(1)}
+
+	out, errs = pm.Rebuild(params)
+	if errs != nil {
+		t.Fatalf("Unexpected error(s): %s", errs)
+	}
+
+	if leader := out.Partitions[0].Replicas[0]; leader != 1002 {
+		t.Errorf("Expected leader 1002, got %d", leader)
+	}
+
+	for _, p := range out.Partitions {
+		if rackCount := len(map[string]bool{
+			params.BM[p.Replicas[0]].Locality: true,
+			params.BM[p.Replicas[1]].Locality: true,
+		}); rackCount != 2 {
+			t.Errorf("Expected p%d replicas %v in distinct racks", p.Partition, p.Replicas)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package kafkazk
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// configSynonym is a broker config that provides the default for a topic
+// config.
+type configSynonym struct {
+	name string
+	// The multiplier converting the broker config value to the topic config
+	// unit. A zero value uses the broker config value as-is.
+	scale int64
+}
+
+// topicConfigSynonyms maps topic configs to the broker configs that provide
+// their defaults, in order of precedence. This follows the Kafka LogConfig
+// synonyms.
+var topicConfigSynonyms = map[string][]configSynonym{
+	"cleanup.policy":                      {{name: "log.cleanup.policy"}},
+	"compression.type":                    {{name: "compression.type"}},
+	"delete.retention.ms":                 {{name: "log.cleaner.delete.retention.ms"}},
+	"file.delete.delay.ms":                {{name: "log.segment.delete.delay.ms"}},
+	"flush.messages":                      {{name: "log.flush.interval.messages"}},
+	"flush.ms":                            {{name: "log.flush.interval.ms"}},
+	"index.interval.bytes":                {{name: "log.index.interval.bytes"}},
+	"max.compaction.lag.ms":               {{name: "log.cleaner.max.compaction.lag.ms"}},
+	"max.message.bytes":                   {{name: "message.max.bytes"}},
+	"message.downconversion.enable":       {{name: "log.message.downconversion.enable"}},
+	"message.format.version":              {{name: "log.message.format.version"}},
+	"message.timestamp.difference.max.ms": {{name: "log.message.timestamp.difference.max.ms"}},
+	"message.timestamp.type":              {{name: "log.message.timestamp.type"}},
+	"min.cleanable.dirty.ratio":           {{name: "log.cleaner.min.cleanable.ratio"}},
+	"min.compaction.lag.ms":               {{name: "log.cleaner.min.compaction.lag.ms"}},
+	"min.insync.replicas":                 {{name: "min.insync.replicas"}},
+	"preallocate":                         {{name: "log.preallocate"}},
+	"retention.bytes":                     {{name: "log.retention.bytes"}},
+	"retention.ms": {
+		{name: "log.retention.ms"},
+		{name: "log.retention.minutes", scale: 60 * 1000},
+		{name: "log.retention.hours", scale: 60 * 60 * 1000},
+	},
+	"segment.bytes":       {{name: "log.segment.bytes"}},
+	"segment.index.bytes": {{name: "log.index.size.max.bytes"}},
+	"segment.jitter.ms": {
+		{name: "log.roll.jitter.ms"},
+		{name: "log.roll.jitter.hours", scale: 60 * 60 * 1000},
+	},
+	"segment.ms": {
+		{name: "log.roll.ms"},
+		{name: "log.roll.hours", scale: 60 * 60 * 1000},
+	},
+	"unclean.leader.election.enable": {{name: "unclean.leader.election.enable"}},
+}
+
+// getEffectiveTopicConfig implements GetEffectiveTopicConfig for a Handler
+// with the Kafka ZooKeeper prefix p.
+func getEffectiveTopicConfig(zk Handler, p string, t string, static map[string]string) (*TopicConfig, error) {
+	overrides, err := zk.GetTopicConfig(t)
+	if err != nil {
+		return nil, err
+	}
+
+	path := fmt.Sprintf("%s/brokers/%s", quotaConfigPath(p), DefaultQuotaEntity)
+	dynamic, err := getKafkaConfigData(zk, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TopicConfig{
+		Version: overrides.Version,
+		Config:  effectiveTopicConfig(overrides.Config, static, dynamic.Config),
+	}, nil
+}
+
+// effectiveTopicConfig takes topic config overrides and any number of broker
+// configs, in increasing order of precedence, and returns the topic config
+// with broker config defaults filled in for any keys not overridden.
+func effectiveTopicConfig(overrides map[string]string, brokerConfigs ...map[string]string) map[string]string {
+	broker := map[string]string{}
+	for _, bc := range brokerConfigs {
+		for k, v := range bc {
+			broker[k] = v
+		}
+	}
+
+	config := map[string]string{}
+
+	for k, synonyms := range topicConfigSynonyms {
+		for _, s := range synonyms {
+			v, exists := broker[s.name]
+			if !exists {
+				continue
+			}
+
+			if s.scale != 0 {
+				n, err := strconv.ParseInt(v, 10, 64)
+				if err != nil {
+					continue
+				}
+				// Negative values, e.g. an unlimited retention, are
+				// normalized to -1.
+				if n < 0 {
+					n = -1
+				} else {
+					n *= s.scale
+				}
+				v = strconv.FormatInt(n, 10)
+			}
+
+			config[k] = v
+			break
+		}
+	}
+
+	for k, v := range overrides {
+		config[k] = v
+	}
+
+	return config
+}
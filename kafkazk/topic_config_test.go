@@ -0,0 +1,75 @@
+package kafkazk
+
+import (
+	"testing"
+)
+
+func TestGetEffectiveTopicConfig(t *testing.T) {
+	zk := NewZooKeeperStub()
+
+	dynamic := `{"version":1,"config":{"log.segment.bytes":"536870912","log.retention.hours":"72"}}`
+	if err := zk.Set("/config/brokers/<default>", dynamic); err != nil {
+		t.Fatal(err)
+	}
+
+	static := map[string]string{
+		"log.segment.bytes":   "1073741824",
+		"min.insync.replicas": "2",
+		"broker.id":           "1001",
+	}
+
+	config, err := zk.GetEffectiveTopicConfig("test_topic", static)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		// The topic override wins over the dynamic default.
+		"retention.ms": "172800000",
+		// Unset keys fall back to the dynamic then static defaults.
+		"segment.bytes":       "536870912",
+		"min.insync.replicas": "2",
+		// Overrides without defaults are kept.
+		"leader.replication.throttled.replicas": "0:1001,0:1002",
+	}
+
+	for k, v := range expected {
+		if config.Config[k] != v {
+			t.Errorf("Expected %s value %s, got %s", k, v, config.Config[k])
+		}
+	}
+
+	// Broker-only configs aren't topic configs.
+	if _, exists := config.Config["broker.id"]; exists {
+		t.Error("Unexpected broker.id config")
+	}
+}
+
+func TestEffectiveTopicConfig(t *testing.T) {
+	tests := []struct {
+		broker   map[string]string
+		expected string
+	}{
+		{map[string]string{"log.retention.hours": "168"}, "604800000"},
+		{map[string]string{"log.retention.hours": "168", "log.retention.minutes": "60"}, "3600000"},
+		{map[string]string{"log.retention.minutes": "60", "log.retention.ms": "1000"}, "1000"},
+		{map[string]string{"log.retention.hours": "-1"}, "-1"},
+		{map[string]string{}, ""},
+	}
+
+	for _, tt := range tests {
+		config := effectiveTopicConfig(nil, tt.broker)
+		if config["retention.ms"] != tt.expected {
+			t.Errorf("%v: expected retention.ms %s, got %s", tt.broker, tt.expected, config["retention.ms"])
+		}
+	}
+
+	// Later broker configs take precedence.
+	config := effectiveTopicConfig(nil,
+		map[string]string{"log.retention.ms": "1000"},
+		map[string]string{"log.retention.ms": "2000"})
+
+	if config["retention.ms"] != "2000" {
+		t.Errorf("Expected retention.ms 2000, got %s", config["retention.ms"])
+	}
+}
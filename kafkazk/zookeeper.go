@@ -3,6 +3,7 @@ package kafkazk
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -40,6 +41,20 @@ func (e ErrNoNode) Error() string {
 	return e.s
 }
 
+// ErrReassignmentInProgress is returned when a reassignment is submitted
+// while another is in progress.
+var ErrReassignmentInProgress = errors.New("a reassignment is already in progress")
+
+// ErrNoClusterID is returned when the cluster id znode doesn't exist, such as
+// with clusters that predate Kafka cluster ids.
+type ErrNoClusterID struct {
+	s string
+}
+
+func (e ErrNoClusterID) Error() string {
+	return e.s
+}
+
 // Handler provides basic ZooKeeper operations along with
 // calls that return kafkazk types describing Kafka states.
 type Handler interface {
@@ -56,22 +71,77 @@ type Handler interface {
 	// Kafka specific.
 	GetTopicState(string) (*TopicState, error)
 	GetTopicStateISR(string) (TopicStateISR, error)
+	WatchISRShrinks(context.Context, string, chan<- ISRShrinkEvent) error
 	UpdateKafkaConfig(KafkaConfig) ([]bool, error)
+	DescribeClientQuotas(ClientQuotaFilter) (ClientQuotas, error)
+	AlterClientQuotas([]ClientQuotaAlteration) error
 	GetReassignments() Reassignments
+	SubmitReassignment(*PartitionMap) error
+	WaitReassignmentComplete(context.Context) error
 	GetUnderReplicated() ([]string, error)
 	GetPendingDeletion() ([]string, error)
 	GetTopics([]*regexp.Regexp) ([]string, error)
 	GetTopicConfig(string) (*TopicConfig, error)
+	GetEffectiveTopicConfig(string, map[string]string) (*TopicConfig, error)
 	GetAllBrokerMeta(bool) (BrokerMetaMap, []error)
 	GetAllPartitionMeta() (PartitionMetaMap, error)
 	MaxMetaAge() (time.Duration, error)
 	GetPartitionMap(string) (*PartitionMap, error)
+	GetClusterID() (string, error)
+	GetFeatures() (*Features, error)
+}
+
+// ClusterID is used for unmarshalling ZooKeeper json data from the
+// /cluster/id znode.
+type ClusterID struct {
+	Version string `json:"version"`
+	ID      string `json:"id"`
+}
+
+// Feature znode statuses.
+const (
+	FeaturesDisabled = 0
+	FeaturesEnabled  = 1
+)
+
+// Features is used for unmarshalling ZooKeeper json data from the /feature
+// znode (KIP-584), which holds the cluster-wide finalized feature versions.
+type Features struct {
+	Version  int                              `json:"version"`
+	Status   int                              `json:"status"`
+	Features map[string]FinalizedVersionRange `json:"features"`
+}
+
+// FinalizedVersionRange is the finalized version range of a feature.
+type FinalizedVersionRange struct {
+	MinVersionLevel int `json:"min_version_level"`
+	MaxVersionLevel int `json:"max_version_level"`
+}
+
+// Enabled returns whether feature versioning is enabled.
+func (f *Features) Enabled() bool {
+	return f.Status == FeaturesEnabled
+}
+
+// MaxVersionLevel takes a feature name and returns the finalized max version
+// level and whether the feature is finalized. Features are never finalized if
+// feature versioning isn't enabled.
+func (f *Features) MaxVersionLevel(name string) (int, bool) {
+	if !f.Enabled() {
+		return 0, false
+	}
+
+	v, exists := f.Features[name]
+	return v.MaxVersionLevel, exists
 }
 
 // TopicState is used for unmarshing ZooKeeper json data from a topic:
 // e.g. /brokers/topics/some-topic
 type TopicState struct {
 	Partitions map[string][]int `json:"partitions"`
+	// Observers maps partition numbers to the replicas assigned as observers,
+	// if any.
+	Observers map[string][]int `json:"observers,omitempty"`
 }
 
 // TopicStateISR is a map of partition numbers to PartitionState.
@@ -336,6 +406,68 @@ func (z *ZKHandler) GetReassignments() Reassignments {
 	return reassigns
 }
 
+// SubmitReassignment takes a *PartitionMap and writes it to the
+// /admin/reassign_partitions znode, triggering a reassignment. An
+// ErrReassignmentInProgress is returned if a reassignment is in progress.
+func (z *ZKHandler) SubmitReassignment(pm *PartitionMap) error {
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/admin/reassign_partitions", z.Prefix)
+	} else {
+		path = "/admin/reassign_partitions"
+	}
+
+	data, err := json.Marshal(pm)
+	if err != nil {
+		return fmt.Errorf("error marshalling partition map: %s", err)
+	}
+
+	_, err = z.client.Create(path, data, 0, zkclient.WorldACL(31))
+	switch err {
+	case nil:
+		return nil
+	case zkclient.ErrNodeExists:
+		return ErrReassignmentInProgress
+	default:
+		return fmt.Errorf("[%s] %s", path, err.Error())
+	}
+}
+
+// WaitReassignmentComplete blocks until the /admin/reassign_partitions znode
+// is deleted, signaling that all ongoing reassignments have completed. An
+// exists-watch is used rather than polling. If the context is cancelled
+// before the reassignment completes, the context error is returned.
+func (z *ZKHandler) WaitReassignmentComplete(ctx context.Context) error {
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/admin/reassign_partitions", z.Prefix)
+	} else {
+		path = "/admin/reassign_partitions"
+	}
+
+	for {
+		exists, _, w, err := z.client.ExistsW(path)
+		if err != nil {
+			return fmt.Errorf("[%s] %s", path, err.Error())
+		}
+
+		if !exists {
+			return nil
+		}
+
+		// The watch fires on any change to the znode; we loop and re-check
+		// whether the znode still exists.
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case e := <-w:
+			if e.Err != nil {
+				return fmt.Errorf("[%s] %s", path, e.Err.Error())
+			}
+		}
+	}
+}
+
 // GetPendingDeletion returns any topics pending deletion.
 func (z *ZKHandler) GetPendingDeletion() ([]string, error) {
 	var path string
@@ -396,6 +528,19 @@ func (z *ZKHandler) GetTopics(ts []*regexp.Regexp) ([]string, error) {
 	return matchingTopics, nil
 }
 
+// DescribeClientQuotas takes a ClientQuotaFilter and returns the ClientQuotas
+// for all user and client ID entities matching the filter.
+func (z *ZKHandler) DescribeClientQuotas(f ClientQuotaFilter) (ClientQuotas, error) {
+	return describeClientQuotas(z, z.Prefix, f)
+}
+
+// AlterClientQuotas takes a []ClientQuotaAlteration and applies each to the
+// quota configs of the respective entity. A config change notification is
+// written for each altered entity.
+func (z *ZKHandler) AlterClientQuotas(a []ClientQuotaAlteration) error {
+	return alterClientQuotas(z, z.Prefix, a)
+}
+
 // GetTopicConfig takes a topic name. If the topic exists, the topic config
 // is returned as a *TopicConfig.
 func (z *ZKHandler) GetTopicConfig(t string) (*TopicConfig, error) {
@@ -419,6 +564,94 @@ func (z *ZKHandler) GetTopicConfig(t string) (*TopicConfig, error) {
 	return config, nil
 }
 
+// GetEffectiveTopicConfig takes a topic name and a map of static broker
+// configs (e.g. those set in server.properties or described via the Kafka
+// admin API) and returns the topic's effective config as a *TopicConfig.
+// Topic overrides take precedence over the cluster-wide dynamic broker
+// defaults in /config/brokers/<default>, which take precedence over the
+// static broker configs. Broker configs are translated to their topic config
+// names; configs without a topic override or broker default are omitted.
+func (z *ZKHandler) GetEffectiveTopicConfig(t string, static map[string]string) (*TopicConfig, error) {
+	return getEffectiveTopicConfig(z, z.Prefix, t, static)
+}
+
+// GetClusterID returns the Kafka cluster id. An ErrNoClusterID is returned
+// if the cluster id znode doesn't exist.
+func (z *ZKHandler) GetClusterID() (string, error) {
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/cluster/id", z.Prefix)
+	} else {
+		path = "/cluster/id"
+	}
+
+	data, err := z.Get(path)
+	if err != nil {
+		if _, ok := err.(ErrNoNode); ok {
+			return "", ErrNoClusterID{s: fmt.Sprintf("[%s] cluster id not found", path)}
+		}
+		return "", err
+	}
+
+	return clusterIDFromJSON(data)
+}
+
+// GetFeatures returns the finalized feature versions from the /feature znode.
+// Clusters that predate feature versioning don't have the znode, in which
+// case an empty, disabled *Features is returned.
+func (z *ZKHandler) GetFeatures() (*Features, error) {
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/feature", z.Prefix)
+	} else {
+		path = "/feature"
+	}
+
+	data, err := z.Get(path)
+	if err != nil {
+		if _, ok := err.(ErrNoNode); ok {
+			return featuresFromJSON(nil)
+		}
+		return nil, err
+	}
+
+	return featuresFromJSON(data)
+}
+
+// featuresFromJSON takes json encoded /feature znode data and returns a
+// *Features. Empty data returns an empty, disabled *Features.
+func featuresFromJSON(data []byte) (*Features, error) {
+	f := &Features{Features: map[string]FinalizedVersionRange{}}
+	if len(data) == 0 {
+		return f, nil
+	}
+
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("error unmarshalling features: %s", err)
+	}
+
+	if f.Features == nil {
+		f.Features = map[string]FinalizedVersionRange{}
+	}
+
+	return f, nil
+}
+
+// clusterIDFromJSON takes json encoded /cluster/id znode data and returns
+// the cluster id.
+func clusterIDFromJSON(data []byte) (string, error) {
+	cid := &ClusterID{}
+	if err := json.Unmarshal(data, cid); err != nil {
+		return "", fmt.Errorf("error unmarshalling cluster id: %s", err)
+	}
+
+	if cid.ID == "" {
+		return "", ErrNoClusterID{s: "cluster id not found"}
+	}
+
+	return cid.ID, nil
+}
+
 // GetAllBrokerMeta looks up all registered Kafka brokers and returns their
 // metadata as a BrokerMetaMap. A withMetrics bool param determines whether
 // we additionally want to fetch stored broker metrics.
@@ -712,6 +945,32 @@ func (z *ZKHandler) GetTopicStateISR(t string) (TopicStateISR, error) {
 	return ts, nil
 }
 
+// WatchISRShrinks takes a Context, topic name and an ISRShrinkEvent channel.
+// The state znode of each of the topic's partitions is watched and an
+// ISRShrinkEvent is sent for each replica that drops out of a partition's
+// ISR. Watches are used rather than polling. WatchISRShrinks blocks until the
+// context is cancelled, returning the context error, or until a watch fails.
+func (z *ZKHandler) WatchISRShrinks(ctx context.Context, t string, events chan<- ISRShrinkEvent) error {
+	var path string
+	if z.Prefix != "" {
+		path = fmt.Sprintf("/%s/brokers/topics/%s/partitions", z.Prefix, t)
+	} else {
+		path = fmt.Sprintf("/brokers/topics/%s/partitions", t)
+	}
+
+	partitions, err := z.Children(path)
+	if err != nil {
+		return err
+	}
+
+	getW := func(p string) ([]byte, <-chan zkclient.Event, error) {
+		data, _, w, err := z.client.GetW(p)
+		return data, w, err
+	}
+
+	return watchISRShrinks(ctx, getW, path, t, partitions, events)
+}
+
 // GetPartitionMap takes a topic name. If the topic exists, the state of
 // the topic is fetched and returned as a *PartitionMap.
 func (z *ZKHandler) GetPartitionMap(t string) (*PartitionMap, error) {
@@ -738,24 +997,37 @@ func (z *ZKHandler) GetPartitionMap(t string) (*PartitionMap, error) {
 		}
 	}
 
-	// Map TopicState to a
-	// PartitionMap.
+	return partitionMapFromTopicState(t, ts), nil
+}
+
+// partitionMapFromTopicState takes a topic name and *TopicState and returns a
+// *PartitionMap. Observers not found in the partition's replicas, e.g. where
+// the replicas were overwritten by an in progress reassignment, are omitted.
+func partitionMapFromTopicState(t string, ts *TopicState) *PartitionMap {
 	pm := NewPartitionMap()
 	pl := PartitionList{}
 
 	for partition, replicas := range ts.Partitions {
 		i, _ := strconv.Atoi(partition)
-		pl = append(pl, Partition{
+		p := Partition{
 			Topic:     t,
 			Partition: i,
 			Replicas:  replicas,
-		})
+		}
+
+		for _, id := range ts.Observers[partition] {
+			if p.IsReplica(id) {
+				p.Observers = append(p.Observers, id)
+			}
+		}
+
+		pl = append(pl, p)
 	}
 	pm.Partitions = pl
 
 	sort.Sort(pm.Partitions)
 
-	return pm, nil
+	return pm
 }
 
 // UpdateKafkaConfig takes a KafkaConfig with key value pairs of
@@ -5,6 +5,7 @@ package kafkazk
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -391,6 +392,83 @@ func TestGetReassignments(t *testing.T) {
 	}
 }
 
+func TestWaitReassignmentComplete(t *testing.T) {
+	path := zkprefix + "/admin/reassign_partitions"
+
+	// Store the current reassignment data to restore afterward.
+	data, _, err := zkc.Get(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- zki.WaitReassignmentComplete(ctx) }()
+
+	// Ensure the waiter is blocked while the znode exists.
+	select {
+	case err := <-done:
+		t.Fatalf("Expected WaitReassignmentComplete to block, returned: %v", err)
+	case <-time.After(250 * time.Millisecond):
+	}
+
+	if err := zkc.Delete(path, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected WaitReassignmentComplete to return after znode deletion")
+	}
+
+	// Restore.
+	if _, err := zkc.Create(path, data, 0, zkclient.WorldACL(31)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSubmitReassignment(t *testing.T) {
+	path := zkprefix + "/admin/reassign_partitions"
+
+	pm, _ := PartitionMapFromString(`{"version":1,"partitions":[
+		{"topic":"topic0","partition":0,"replicas":[1003,1004]}]}`)
+
+	// A reassignment is already in progress.
+	if err := zki.SubmitReassignment(pm); err != ErrReassignmentInProgress {
+		t.Errorf("Expected ErrReassignmentInProgress, got %v", err)
+	}
+
+	// Store the current reassignment data to restore afterward.
+	data, _, err := zkc.Get(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := zkc.Delete(path, -1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := zki.SubmitReassignment(pm); err != nil {
+		t.Fatal(err)
+	}
+
+	re := zki.GetReassignments()
+	if replicas := re["topic0"][0]; len(replicas) != 2 || replicas[0] != 1003 || replicas[1] != 1004 {
+		t.Errorf("Expected topic0 p0 reassignment to [1003 1004], got %v", replicas)
+	}
+
+	// Restore.
+	if _, err := zkc.Set(path, data, -1); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func TestGetPendingDeletion(t *testing.T) {
 	pd, err := zki.GetPendingDeletion()
 	if err != nil {
@@ -451,6 +529,35 @@ func TestGetTopicConfig(t *testing.T) {
 	}
 }
 
+func TestGetClusterID(t *testing.T) {
+	path := zkprefix + "/cluster"
+
+	_, err := zki.GetClusterID()
+	if _, ok := err.(ErrNoClusterID); !ok {
+		t.Errorf("Expected ErrNoClusterID, got %v", err)
+	}
+
+	_, err = zkc.Create(path, nil, 0, zkclient.WorldACL(31))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := []byte(`{"version":"1","id":"Sd4o7gKLQ2aE8VuV_hNfXw"}`)
+	_, err = zkc.Create(path+"/id", data, 0, zkclient.WorldACL(31))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := zki.GetClusterID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != "Sd4o7gKLQ2aE8VuV_hNfXw" {
+		t.Errorf("Expected cluster id 'Sd4o7gKLQ2aE8VuV_hNfXw', got '%s'", id)
+	}
+}
+
 func TestGetAllBrokerMeta(t *testing.T) {
 	bm, err := zki.GetAllBrokerMeta(false)
 	if err != nil {
@@ -4,19 +4,31 @@
 package kafkazk
 
 import (
-	"errors"
+	"context"
+	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
+
+	zkclient "github.com/samuel/go-zookeeper/zk"
 )
 
 var (
-	errNotExist = errors.New("znode doesn't exist")
+	// errNotExist is an ErrNoNode, as returned by the ZKHandler.
+	errNotExist = ErrNoNode{s: "znode doesn't exist"}
 )
 
 // Stub stubs the Handler interface.
 type Stub struct {
+	// mu guards the znode data and watches.
+	mu   sync.Mutex
 	data map[string]*StubZnode
+	// watches are notified when the znode at the keyed path is deleted.
+	watches map[string][]chan struct{}
+	// dataWatches are notified when the znode at the keyed path is set.
+	dataWatches map[string][]chan zkclient.Event
 }
 
 // StubZnode stubs a ZooKeeper znode.
@@ -46,6 +58,55 @@ func (zk *Stub) GetReassignments() Reassignments {
 	return r
 }
 
+// SubmitReassignment stubs SubmitReassignment. The partition map is written
+// to the /admin/reassign_partitions znode.
+func (zk *Stub) SubmitReassignment(pm *PartitionMap) error {
+	path := "/admin/reassign_partitions"
+
+	data, err := json.Marshal(pm)
+	if err != nil {
+		return err
+	}
+
+	zk.mu.Lock()
+	defer zk.mu.Unlock()
+
+	if _, err := zk.get(path); err != errNotExist {
+		return ErrReassignmentInProgress
+	}
+
+	zk.set(path, string(data))
+
+	return nil
+}
+
+// WaitReassignmentComplete stubs WaitReassignmentComplete. The stub blocks
+// until the /admin/reassign_partitions znode is removed with Delete.
+func (zk *Stub) WaitReassignmentComplete(ctx context.Context) error {
+	path := "/admin/reassign_partitions"
+
+	zk.mu.Lock()
+	if _, err := zk.get(path); err == errNotExist {
+		zk.mu.Unlock()
+		return nil
+	}
+
+	if zk.watches == nil {
+		zk.watches = map[string][]chan struct{}{}
+	}
+
+	w := make(chan struct{})
+	zk.watches[path] = append(zk.watches[path], w)
+	zk.mu.Unlock()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w:
+		return nil
+	}
+}
+
 func (zk *Stub) GetUnderReplicated() ([]string, error) {
 	return []string{"underreplicated_topic"}, nil
 }
@@ -67,7 +128,10 @@ func (zk *Stub) CreateSequential(a, b string) error {
 
 // Exists stubs Exists.
 func (zk *Stub) Exists(p string) (bool, error) {
-	_, err := zk.Get(p)
+	zk.mu.Lock()
+	defer zk.mu.Unlock()
+
+	_, err := zk.get(p)
 	if err == errNotExist {
 		return false, nil
 	}
@@ -77,6 +141,17 @@ func (zk *Stub) Exists(p string) (bool, error) {
 
 // Set stubs Set.
 func (zk *Stub) Set(p, d string) error {
+	zk.mu.Lock()
+	defer zk.mu.Unlock()
+
+	zk.set(p, d)
+
+	return nil
+}
+
+// set sets the data at path p, creating any missing znodes. The caller must
+// hold zk.mu.
+func (zk *Stub) set(p, d string) {
 	pathTrimmed := strings.Trim(p, "/")
 	paths := strings.Split(pathTrimmed, "/")
 	var current *StubZnode
@@ -100,11 +175,24 @@ func (zk *Stub) Set(p, d string) error {
 	current.value = []byte(d)
 	current.version++
 
-	return nil
+	// Notify any data watches on the path.
+	key := "/" + pathTrimmed
+	for _, w := range zk.dataWatches[key] {
+		w <- zkclient.Event{Type: zkclient.EventNodeDataChanged, Path: key}
+	}
+	delete(zk.dataWatches, key)
 }
 
 // Get stubs Get.
 func (zk *Stub) Get(p string) ([]byte, error) {
+	zk.mu.Lock()
+	defer zk.mu.Unlock()
+
+	return zk.get(p)
+}
+
+// get returns the data at path p. The caller must hold zk.mu.
+func (zk *Stub) get(p string) ([]byte, error) {
 	pathTrimmed := strings.Trim(p, "/")
 	paths := strings.Split(pathTrimmed, "/")
 	var current *StubZnode
@@ -126,6 +214,19 @@ func (zk *Stub) Get(p string) ([]byte, error) {
 
 // Delete stubs Delete.
 func (zk *Stub) Delete(p string) error {
+	zk.mu.Lock()
+	defer zk.mu.Unlock()
+
+	// Notify any watches on the path.
+	defer func() {
+		if _, err := zk.get(p); err == errNotExist {
+			for _, w := range zk.watches[p] {
+				close(w)
+			}
+			delete(zk.watches, p)
+		}
+	}()
+
 	pathTrimmed := strings.Trim(p, "/")
 	paths := strings.Split(pathTrimmed, "/")
 	var current *StubZnode
@@ -153,6 +254,9 @@ func (zk *Stub) Delete(p string) error {
 
 // Children stubs children.
 func (zk *Stub) Children(p string) ([]string, error) {
+	zk.mu.Lock()
+	defer zk.mu.Unlock()
+
 	pathTrimmed := strings.Trim(p, "/")
 	paths := strings.Split(pathTrimmed, "/")
 	children := []string{}
@@ -183,6 +287,9 @@ func (zk *Stub) Children(p string) ([]string, error) {
 }
 
 func (zk *Stub) NextInt(p string) (int32, error) {
+	zk.mu.Lock()
+	defer zk.mu.Unlock()
+
 	pathTrimmed := strings.Trim(p, "/")
 	paths := strings.Split(pathTrimmed, "/")
 	var current *StubZnode
@@ -235,6 +342,40 @@ func (zk *Stub) GetTopicStateISR(t string) (TopicStateISR, error) {
 	}, nil
 }
 
+// WatchISRShrinks stubs WatchISRShrinks. Partition state changes are made
+// with Set.
+func (zk *Stub) WatchISRShrinks(ctx context.Context, t string, events chan<- ISRShrinkEvent) error {
+	path := fmt.Sprintf("/brokers/topics/%s/partitions", t)
+
+	partitions, err := zk.Children(path)
+	if err != nil {
+		return err
+	}
+
+	getW := func(p string) ([]byte, <-chan zkclient.Event, error) {
+		zk.mu.Lock()
+		defer zk.mu.Unlock()
+
+		data, err := zk.get(p)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if zk.dataWatches == nil {
+			zk.dataWatches = map[string][]chan zkclient.Event{}
+		}
+
+		// Buffered so that Set doesn't block on the watcher.
+		w := make(chan zkclient.Event, 1)
+		key := "/" + strings.Trim(p, "/")
+		zk.dataWatches[key] = append(zk.dataWatches[key], w)
+
+		return data, w, nil
+	}
+
+	return watchISRShrinks(ctx, getW, path, t, partitions, events)
+}
+
 // Close stubs Close.
 func (zk *Stub) Close() {
 	return
@@ -280,6 +421,16 @@ func (zk *Stub) GetTopics(ts []*regexp.Regexp) ([]string, error) {
 	return matched, nil
 }
 
+// DescribeClientQuotas stubs DescribeClientQuotas.
+func (zk *Stub) DescribeClientQuotas(f ClientQuotaFilter) (ClientQuotas, error) {
+	return describeClientQuotas(zk, "", f)
+}
+
+// AlterClientQuotas stubs AlterClientQuotas.
+func (zk *Stub) AlterClientQuotas(a []ClientQuotaAlteration) error {
+	return alterClientQuotas(zk, "", a)
+}
+
 // GetTopicConfig stubs GetTopicConfig.
 func (zk *Stub) GetTopicConfig(t string) (*TopicConfig, error) {
 	return &TopicConfig{
@@ -292,6 +443,11 @@ func (zk *Stub) GetTopicConfig(t string) (*TopicConfig, error) {
 	}, nil
 }
 
+// GetEffectiveTopicConfig stubs GetEffectiveTopicConfig.
+func (zk *Stub) GetEffectiveTopicConfig(t string, static map[string]string) (*TopicConfig, error) {
+	return getEffectiveTopicConfig(zk, "", t, static)
+}
+
 // GetAllBrokerMeta stubs GetAllBrokerMeta.
 func (zk *Stub) GetAllBrokerMeta(withMetrics bool) (BrokerMetaMap, []error) {
 	b := BrokerMetaMap{
@@ -343,6 +499,27 @@ func (zk *Stub) GetAllPartitionMeta() (PartitionMetaMap, error) {
 	return pm, nil
 }
 
+// GetClusterID stubs GetClusterID. The cluster id is read from the
+// /cluster/id znode, which can be populated with Set.
+func (zk *Stub) GetClusterID() (string, error) {
+	data, err := zk.Get("/cluster/id")
+	if err == errNotExist {
+		return "", ErrNoClusterID{s: "[/cluster/id] cluster id not found"}
+	}
+
+	return clusterIDFromJSON(data)
+}
+
+// GetFeatures stubs GetFeatures.
+func (zk *Stub) GetFeatures() (*Features, error) {
+	data, err := zk.Get("/feature")
+	if err == errNotExist {
+		return featuresFromJSON(nil)
+	}
+
+	return featuresFromJSON(data)
+}
+
 // GetPartitionMap stubs GetPartitionMap.
 func (zk *Stub) GetPartitionMap(t string) (*PartitionMap, error) {
 	p := &PartitionMap{
@@ -0,0 +1,184 @@
+package kafkazk
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStubWaitReassignmentComplete(t *testing.T) {
+	zk := NewZooKeeperStub()
+	path := "/admin/reassign_partitions"
+
+	zk.Create(path, `{"version":1,"partitions":[]}`)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error)
+	go func() { done <- zk.WaitReassignmentComplete(ctx) }()
+
+	// Ensure the waiter is blocked while the znode exists.
+	select {
+	case err := <-done:
+		t.Fatalf("Expected WaitReassignmentComplete to block, returned: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	zk.Delete(path)
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected WaitReassignmentComplete to return after znode deletion")
+	}
+
+	// No reassignment; returns immediately.
+	if err := zk.WaitReassignmentComplete(ctx); err != nil {
+		t.Error(err)
+	}
+
+	// Context cancellation.
+	zk.Create(path, "")
+	cancel()
+
+	if err := zk.WaitReassignmentComplete(ctx); err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestStubGetClusterID(t *testing.T) {
+	zk := NewZooKeeperStub()
+
+	_, err := zk.GetClusterID()
+	if _, ok := err.(ErrNoClusterID); !ok {
+		t.Fatalf("Expected ErrNoClusterID, got %v", err)
+	}
+
+	zk.Create("/cluster/id", `{"version":"1","id":"Sd4o7gKLQ2aE8VuV_hNfXw"}`)
+
+	id, err := zk.GetClusterID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if id != "Sd4o7gKLQ2aE8VuV_hNfXw" {
+		t.Errorf("Expected cluster id 'Sd4o7gKLQ2aE8VuV_hNfXw', got '%s'", id)
+	}
+}
+
+func TestStubGetFeatures(t *testing.T) {
+	zk := NewZooKeeperStub()
+
+	// No /feature znode.
+	f, err := zk.GetFeatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Enabled() {
+		t.Error("Expected features to be disabled")
+	}
+
+	if _, finalized := f.MaxVersionLevel("metadata.version"); finalized {
+		t.Error("Expected metadata.version to not be finalized")
+	}
+
+	zk.Create("/feature", `{"version":2,"status":1,"features":{"metadata.version":{"min_version_level":1,"max_version_level":7},"group_coordinator":{"min_version_level":1,"max_version_level":1}}}`)
+
+	f, err = zk.GetFeatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !f.Enabled() {
+		t.Error("Expected features to be enabled")
+	}
+
+	v, finalized := f.MaxVersionLevel("metadata.version")
+	if !finalized || v != 7 {
+		t.Errorf("Expected finalized metadata.version 7, got %d (finalized: %t)", v, finalized)
+	}
+
+	if r := f.Features["group_coordinator"]; r.MinVersionLevel != 1 || r.MaxVersionLevel != 1 {
+		t.Errorf("Unexpected group_coordinator version range %+v", r)
+	}
+
+	if _, finalized := f.MaxVersionLevel("transaction.version"); finalized {
+		t.Error("Expected transaction.version to not be finalized")
+	}
+
+	// Disabled feature versioning.
+	zk.Set("/feature", `{"version":1,"status":0,"features":{}}`)
+
+	f, err = zk.GetFeatures()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Enabled() {
+		t.Error("Expected features to be disabled")
+	}
+}
+
+func TestStubSubmitReassignment(t *testing.T) {
+	zk := NewZooKeeperStub()
+	pm, _ := PartitionMapFromString(testGetMapString("test_topic"))
+
+	if err := zk.SubmitReassignment(pm); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := zk.Get("/admin/reassign_partitions")
+	submitted, err := PartitionMapFromString(string(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if eq, _ := pm.Equal(submitted); !eq {
+		t.Error("Expected the submitted map to match the input map")
+	}
+
+	if err := zk.SubmitReassignment(pm); err != ErrReassignmentInProgress {
+		t.Errorf("Expected ErrReassignmentInProgress, got %v", err)
+	}
+}
+
+func TestStubConcurrentAccess(t *testing.T) {
+	zk := NewZooKeeperStub()
+	wg := &sync.WaitGroup{}
+
+	// Run with -race to detect unsynchronized access.
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+
+			p := fmt.Sprintf("/test/nodes/%d", n)
+			for j := 0; j < 50; j++ {
+				zk.Set(p, "data")
+				zk.Get(p)
+				zk.Exists(p)
+				zk.Children("/test/nodes")
+				zk.NextInt(p)
+				zk.Delete(p)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	children, err := zk.Children("/test/nodes")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(children) != 0 {
+		t.Errorf("Expected no children, got %v", children)
+	}
+}
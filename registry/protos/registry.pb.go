@@ -63,8 +63,12 @@ func (m *TagResponse) GetMessage() string {
 }
 
 type BrokerRequest struct {
-	Tag                  []string `protobuf:"bytes,1,rep,name=tag,proto3" json:"tag,omitempty"`
-	Id                   uint32   `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Tag     []string `protobuf:"bytes,1,rep,name=tag,proto3" json:"tag,omitempty"`
+	Id      uint32   `protobuf:"varint,2,opt,name=id,proto3" json:"id,omitempty"`
+	Force   bool     `protobuf:"varint,3,opt,name=force,proto3" json:"force,omitempty"`
+	GroupBy string   `protobuf:"bytes,4,opt,name=group_by,json=groupBy,proto3" json:"group_by,omitempty"`
+	// If set, tags set by TagBroker expire after this many seconds.
+	TtlSeconds           uint32   `protobuf:"varint,5,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -109,12 +113,34 @@ func (m *BrokerRequest) GetId() uint32 {
 	return 0
 }
 
+func (m *BrokerRequest) GetForce() bool {
+	if m != nil {
+		return m.Force
+	}
+	return false
+}
+
+func (m *BrokerRequest) GetGroupBy() string {
+	if m != nil {
+		return m.GroupBy
+	}
+	return ""
+}
+
+func (m *BrokerRequest) GetTtlSeconds() uint32 {
+	if m != nil {
+		return m.TtlSeconds
+	}
+	return 0
+}
+
 type BrokerResponse struct {
-	Brokers              map[uint32]*Broker `protobuf:"bytes,5,rep,name=brokers,proto3" json:"brokers,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	Ids                  []uint32           `protobuf:"varint,6,rep,packed,name=ids,proto3" json:"ids,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	Brokers              map[uint32]*Broker      `protobuf:"bytes,5,rep,name=brokers,proto3" json:"brokers,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Ids                  []uint32                `protobuf:"varint,6,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	Groups               map[string]*BrokerGroup `protobuf:"bytes,7,rep,name=groups,proto3" json:"groups,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}                `json:"-"`
+	XXX_unrecognized     []byte                  `json:"-"`
+	XXX_sizecache        int32                   `json:"-"`
 }
 
 func (m *BrokerResponse) Reset()         { *m = BrokerResponse{} }
@@ -156,6 +182,78 @@ func (m *BrokerResponse) GetIds() []uint32 {
 	return nil
 }
 
+func (m *BrokerResponse) GetGroups() map[string]*BrokerGroup {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+type BrokerGroup struct {
+	Ids   []uint32 `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	Count uint32   `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// The total storage free in bytes for brokers in the group.
+	StorageFree float64 `protobuf:"fixed64,3,opt,name=storage_free,json=storageFree,proto3" json:"storage_free,omitempty"`
+	// Whether storage metrics are unavailable for any brokers in the group.
+	StorageUnknown       bool     `protobuf:"varint,4,opt,name=storage_unknown,json=storageUnknown,proto3" json:"storage_unknown,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BrokerGroup) Reset()         { *m = BrokerGroup{} }
+func (m *BrokerGroup) String() string { return proto.CompactTextString(m) }
+func (*BrokerGroup) ProtoMessage()    {}
+func (*BrokerGroup) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{3}
+}
+
+func (m *BrokerGroup) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BrokerGroup.Unmarshal(m, b)
+}
+func (m *BrokerGroup) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BrokerGroup.Marshal(b, m, deterministic)
+}
+func (m *BrokerGroup) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BrokerGroup.Merge(m, src)
+}
+func (m *BrokerGroup) XXX_Size() int {
+	return xxx_messageInfo_BrokerGroup.Size(m)
+}
+func (m *BrokerGroup) XXX_DiscardUnknown() {
+	xxx_messageInfo_BrokerGroup.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BrokerGroup proto.InternalMessageInfo
+
+func (m *BrokerGroup) GetIds() []uint32 {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+func (m *BrokerGroup) GetCount() uint32 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+func (m *BrokerGroup) GetStorageFree() float64 {
+	if m != nil {
+		return m.StorageFree
+	}
+	return 0
+}
+
+func (m *BrokerGroup) GetStorageUnknown() bool {
+	if m != nil {
+		return m.StorageUnknown
+	}
+	return false
+}
+
 type UnmappedBrokersRequest struct {
 	Exclude              []string `protobuf:"bytes,1,rep,name=exclude,proto3" json:"exclude,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -167,7 +265,7 @@ func (m *UnmappedBrokersRequest) Reset()         { *m = UnmappedBrokersRequest{}
 func (m *UnmappedBrokersRequest) String() string { return proto.CompactTextString(m) }
 func (*UnmappedBrokersRequest) ProtoMessage()    {}
 func (*UnmappedBrokersRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4215e5fe8e6d7e5d, []int{3}
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{4}
 }
 
 func (m *UnmappedBrokersRequest) XXX_Unmarshal(b []byte) error {
@@ -195,6 +293,176 @@ func (m *UnmappedBrokersRequest) GetExclude() []string {
 	return nil
 }
 
+type DecommissionPreviewRequest struct {
+	Ids                  []uint32 `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DecommissionPreviewRequest) Reset()         { *m = DecommissionPreviewRequest{} }
+func (m *DecommissionPreviewRequest) String() string { return proto.CompactTextString(m) }
+func (*DecommissionPreviewRequest) ProtoMessage()    {}
+func (*DecommissionPreviewRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{5}
+}
+
+func (m *DecommissionPreviewRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DecommissionPreviewRequest.Unmarshal(m, b)
+}
+func (m *DecommissionPreviewRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DecommissionPreviewRequest.Marshal(b, m, deterministic)
+}
+func (m *DecommissionPreviewRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DecommissionPreviewRequest.Merge(m, src)
+}
+func (m *DecommissionPreviewRequest) XXX_Size() int {
+	return xxx_messageInfo_DecommissionPreviewRequest.Size(m)
+}
+func (m *DecommissionPreviewRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_DecommissionPreviewRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DecommissionPreviewRequest proto.InternalMessageInfo
+
+func (m *DecommissionPreviewRequest) GetIds() []uint32 {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+type DecommissionPreview struct {
+	Topics     []string         `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+	Partitions []*PartitionMove `protobuf:"bytes,2,rep,name=partitions,proto3" json:"partitions,omitempty"`
+	// The estimated total bytes to move, excluding partitions of unknown size.
+	TotalBytes           uint64   `protobuf:"varint,3,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DecommissionPreview) Reset()         { *m = DecommissionPreview{} }
+func (m *DecommissionPreview) String() string { return proto.CompactTextString(m) }
+func (*DecommissionPreview) ProtoMessage()    {}
+func (*DecommissionPreview) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{6}
+}
+
+func (m *DecommissionPreview) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DecommissionPreview.Unmarshal(m, b)
+}
+func (m *DecommissionPreview) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DecommissionPreview.Marshal(b, m, deterministic)
+}
+func (m *DecommissionPreview) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DecommissionPreview.Merge(m, src)
+}
+func (m *DecommissionPreview) XXX_Size() int {
+	return xxx_messageInfo_DecommissionPreview.Size(m)
+}
+func (m *DecommissionPreview) XXX_DiscardUnknown() {
+	xxx_messageInfo_DecommissionPreview.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DecommissionPreview proto.InternalMessageInfo
+
+func (m *DecommissionPreview) GetTopics() []string {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *DecommissionPreview) GetPartitions() []*PartitionMove {
+	if m != nil {
+		return m.Partitions
+	}
+	return nil
+}
+
+func (m *DecommissionPreview) GetTotalBytes() uint64 {
+	if m != nil {
+		return m.TotalBytes
+	}
+	return 0
+}
+
+type PartitionMove struct {
+	Topic     string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Partition uint32 `protobuf:"varint,2,opt,name=partition,proto3" json:"partition,omitempty"`
+	// The replicas that must be moved off of the decommissioned brokers.
+	Replicas []uint32 `protobuf:"varint,3,rep,packed,name=replicas,proto3" json:"replicas,omitempty"`
+	// The estimated bytes to move; the partition size times the number of
+	// replicas to move.
+	SizeBytes uint64 `protobuf:"varint,4,opt,name=size_bytes,json=sizeBytes,proto3" json:"size_bytes,omitempty"`
+	// Whether the partition size is unavailable in the stored metrics.
+	SizeUnknown          bool     `protobuf:"varint,5,opt,name=size_unknown,json=sizeUnknown,proto3" json:"size_unknown,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PartitionMove) Reset()         { *m = PartitionMove{} }
+func (m *PartitionMove) String() string { return proto.CompactTextString(m) }
+func (*PartitionMove) ProtoMessage()    {}
+func (*PartitionMove) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{7}
+}
+
+func (m *PartitionMove) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PartitionMove.Unmarshal(m, b)
+}
+func (m *PartitionMove) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PartitionMove.Marshal(b, m, deterministic)
+}
+func (m *PartitionMove) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PartitionMove.Merge(m, src)
+}
+func (m *PartitionMove) XXX_Size() int {
+	return xxx_messageInfo_PartitionMove.Size(m)
+}
+func (m *PartitionMove) XXX_DiscardUnknown() {
+	xxx_messageInfo_PartitionMove.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PartitionMove proto.InternalMessageInfo
+
+func (m *PartitionMove) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *PartitionMove) GetPartition() uint32 {
+	if m != nil {
+		return m.Partition
+	}
+	return 0
+}
+
+func (m *PartitionMove) GetReplicas() []uint32 {
+	if m != nil {
+		return m.Replicas
+	}
+	return nil
+}
+
+func (m *PartitionMove) GetSizeBytes() uint64 {
+	if m != nil {
+		return m.SizeBytes
+	}
+	return 0
+}
+
+func (m *PartitionMove) GetSizeUnknown() bool {
+	if m != nil {
+		return m.SizeUnknown
+	}
+	return false
+}
+
 type Broker struct {
 	// Registry metadata.
 	Tags map[string]string `protobuf:"bytes,1,rep,name=tags,proto3" json:"tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
@@ -217,7 +485,7 @@ func (m *Broker) Reset()         { *m = Broker{} }
 func (m *Broker) String() string { return proto.CompactTextString(m) }
 func (*Broker) ProtoMessage()    {}
 func (*Broker) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4215e5fe8e6d7e5d, []int{4}
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{8}
 }
 
 func (m *Broker) XXX_Unmarshal(b []byte) error {
@@ -309,8 +577,10 @@ func (m *Broker) GetVersion() uint32 {
 }
 
 type TopicRequest struct {
-	Tag                  []string `protobuf:"bytes,1,rep,name=tag,proto3" json:"tag,omitempty"`
-	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Tag  []string `protobuf:"bytes,1,rep,name=tag,proto3" json:"tag,omitempty"`
+	Name string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// If set, tags set by TagTopic expire after this many seconds.
+	TtlSeconds           uint32   `protobuf:"varint,3,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -320,7 +590,7 @@ func (m *TopicRequest) Reset()         { *m = TopicRequest{} }
 func (m *TopicRequest) String() string { return proto.CompactTextString(m) }
 func (*TopicRequest) ProtoMessage()    {}
 func (*TopicRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4215e5fe8e6d7e5d, []int{5}
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{9}
 }
 
 func (m *TopicRequest) XXX_Unmarshal(b []byte) error {
@@ -355,6 +625,13 @@ func (m *TopicRequest) GetName() string {
 	return ""
 }
 
+func (m *TopicRequest) GetTtlSeconds() uint32 {
+	if m != nil {
+		return m.TtlSeconds
+	}
+	return 0
+}
+
 type CreateTopicRequest struct {
 	Topic                *Topic   `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
 	TargetBrokerTags     []string `protobuf:"bytes,2,rep,name=target_broker_tags,json=targetBrokerTags,proto3" json:"target_broker_tags,omitempty"`
@@ -367,7 +644,7 @@ func (m *CreateTopicRequest) Reset()         { *m = CreateTopicRequest{} }
 func (m *CreateTopicRequest) String() string { return proto.CompactTextString(m) }
 func (*CreateTopicRequest) ProtoMessage()    {}
 func (*CreateTopicRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4215e5fe8e6d7e5d, []int{6}
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{10}
 }
 
 func (m *CreateTopicRequest) XXX_Unmarshal(b []byte) error {
@@ -402,6 +679,117 @@ func (m *CreateTopicRequest) GetTargetBrokerTags() []string {
 	return nil
 }
 
+type ReplicationFactorRequest struct {
+	Name                 string   `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Replication          uint32   `protobuf:"varint,2,opt,name=replication,proto3" json:"replication,omitempty"`
+	TargetBrokerTags     []string `protobuf:"bytes,3,rep,name=target_broker_tags,json=targetBrokerTags,proto3" json:"target_broker_tags,omitempty"`
+	Apply                bool     `protobuf:"varint,4,opt,name=apply,proto3" json:"apply,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplicationFactorRequest) Reset()         { *m = ReplicationFactorRequest{} }
+func (m *ReplicationFactorRequest) String() string { return proto.CompactTextString(m) }
+func (*ReplicationFactorRequest) ProtoMessage()    {}
+func (*ReplicationFactorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{11}
+}
+
+func (m *ReplicationFactorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplicationFactorRequest.Unmarshal(m, b)
+}
+func (m *ReplicationFactorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplicationFactorRequest.Marshal(b, m, deterministic)
+}
+func (m *ReplicationFactorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicationFactorRequest.Merge(m, src)
+}
+func (m *ReplicationFactorRequest) XXX_Size() int {
+	return xxx_messageInfo_ReplicationFactorRequest.Size(m)
+}
+func (m *ReplicationFactorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicationFactorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicationFactorRequest proto.InternalMessageInfo
+
+func (m *ReplicationFactorRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ReplicationFactorRequest) GetReplication() uint32 {
+	if m != nil {
+		return m.Replication
+	}
+	return 0
+}
+
+func (m *ReplicationFactorRequest) GetTargetBrokerTags() []string {
+	if m != nil {
+		return m.TargetBrokerTags
+	}
+	return nil
+}
+
+func (m *ReplicationFactorRequest) GetApply() bool {
+	if m != nil {
+		return m.Apply
+	}
+	return false
+}
+
+type ReplicationFactorResponse struct {
+	// The reassignment plan in the Kafka partition reassignment JSON format.
+	Plan                 string   `protobuf:"bytes,1,opt,name=plan,proto3" json:"plan,omitempty"`
+	Applied              bool     `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReplicationFactorResponse) Reset()         { *m = ReplicationFactorResponse{} }
+func (m *ReplicationFactorResponse) String() string { return proto.CompactTextString(m) }
+func (*ReplicationFactorResponse) ProtoMessage()    {}
+func (*ReplicationFactorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{12}
+}
+
+func (m *ReplicationFactorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReplicationFactorResponse.Unmarshal(m, b)
+}
+func (m *ReplicationFactorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReplicationFactorResponse.Marshal(b, m, deterministic)
+}
+func (m *ReplicationFactorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReplicationFactorResponse.Merge(m, src)
+}
+func (m *ReplicationFactorResponse) XXX_Size() int {
+	return xxx_messageInfo_ReplicationFactorResponse.Size(m)
+}
+func (m *ReplicationFactorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReplicationFactorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReplicationFactorResponse proto.InternalMessageInfo
+
+func (m *ReplicationFactorResponse) GetPlan() string {
+	if m != nil {
+		return m.Plan
+	}
+	return ""
+}
+
+func (m *ReplicationFactorResponse) GetApplied() bool {
+	if m != nil {
+		return m.Applied
+	}
+	return false
+}
+
 type TopicResponse struct {
 	Topics               map[string]*Topic `protobuf:"bytes,5,rep,name=topics,proto3" json:"topics,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	Names                []string          `protobuf:"bytes,6,rep,name=names,proto3" json:"names,omitempty"`
@@ -414,7 +802,7 @@ func (m *TopicResponse) Reset()         { *m = TopicResponse{} }
 func (m *TopicResponse) String() string { return proto.CompactTextString(m) }
 func (*TopicResponse) ProtoMessage()    {}
 func (*TopicResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4215e5fe8e6d7e5d, []int{7}
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{13}
 }
 
 func (m *TopicResponse) XXX_Unmarshal(b []byte) error {
@@ -466,7 +854,7 @@ func (m *Topic) Reset()         { *m = Topic{} }
 func (m *Topic) String() string { return proto.CompactTextString(m) }
 func (*Topic) ProtoMessage()    {}
 func (*Topic) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4215e5fe8e6d7e5d, []int{8}
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{14}
 }
 
 func (m *Topic) XXX_Unmarshal(b []byte) error {
@@ -522,44 +910,167 @@ func (m *Topic) GetConfigs() map[string]string {
 	return nil
 }
 
-type OffsetMapping struct {
-	UpstreamOffset       uint64   `protobuf:"varint,1,opt,name=upstream_offset,json=upstreamOffset,proto3" json:"upstream_offset,omitempty"`
-	LocalOffset          uint64   `protobuf:"varint,2,opt,name=local_offset,json=localOffset,proto3" json:"local_offset,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+type TopicConfigRecommendation struct {
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// The tags of the matched rules, in rule order.
+	MatchedTags []string `protobuf:"bytes,2,rep,name=matched_tags,json=matchedTags,proto3" json:"matched_tags,omitempty"`
+	// The recommended configs.
+	Configs map[string]string `protobuf:"bytes,3,rep,name=configs,proto3" json:"configs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The recommended configs that differ from the topic's current configs.
+	Changes              map[string]string `protobuf:"bytes,4,rep,name=changes,proto3" json:"changes,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
-func (m *OffsetMapping) Reset()         { *m = OffsetMapping{} }
-func (m *OffsetMapping) String() string { return proto.CompactTextString(m) }
-func (*OffsetMapping) ProtoMessage()    {}
-func (*OffsetMapping) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4215e5fe8e6d7e5d, []int{9}
+func (m *TopicConfigRecommendation) Reset()         { *m = TopicConfigRecommendation{} }
+func (m *TopicConfigRecommendation) String() string { return proto.CompactTextString(m) }
+func (*TopicConfigRecommendation) ProtoMessage()    {}
+func (*TopicConfigRecommendation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{15}
 }
 
-func (m *OffsetMapping) XXX_Unmarshal(b []byte) error {
-	return xxx_messageInfo_OffsetMapping.Unmarshal(m, b)
+func (m *TopicConfigRecommendation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicConfigRecommendation.Unmarshal(m, b)
 }
-func (m *OffsetMapping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_OffsetMapping.Marshal(b, m, deterministic)
+func (m *TopicConfigRecommendation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicConfigRecommendation.Marshal(b, m, deterministic)
 }
-func (m *OffsetMapping) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_OffsetMapping.Merge(m, src)
+func (m *TopicConfigRecommendation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicConfigRecommendation.Merge(m, src)
 }
-func (m *OffsetMapping) XXX_Size() int {
-	return xxx_messageInfo_OffsetMapping.Size(m)
+func (m *TopicConfigRecommendation) XXX_Size() int {
+	return xxx_messageInfo_TopicConfigRecommendation.Size(m)
 }
-func (m *OffsetMapping) XXX_DiscardUnknown() {
-	xxx_messageInfo_OffsetMapping.DiscardUnknown(m)
+func (m *TopicConfigRecommendation) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicConfigRecommendation.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_OffsetMapping proto.InternalMessageInfo
+var xxx_messageInfo_TopicConfigRecommendation proto.InternalMessageInfo
 
-func (m *OffsetMapping) GetUpstreamOffset() uint64 {
+func (m *TopicConfigRecommendation) GetName() string {
 	if m != nil {
-		return m.UpstreamOffset
+		return m.Name
 	}
-	return 0
+	return ""
+}
+
+func (m *TopicConfigRecommendation) GetMatchedTags() []string {
+	if m != nil {
+		return m.MatchedTags
+	}
+	return nil
+}
+
+func (m *TopicConfigRecommendation) GetConfigs() map[string]string {
+	if m != nil {
+		return m.Configs
+	}
+	return nil
+}
+
+func (m *TopicConfigRecommendation) GetChanges() map[string]string {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+type UnderReplicationEvent struct {
+	// One of under_replicated or recovered.
+	Type  string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Topic string `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	// Unix timestamp in seconds.
+	Timestamp            int64    `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UnderReplicationEvent) Reset()         { *m = UnderReplicationEvent{} }
+func (m *UnderReplicationEvent) String() string { return proto.CompactTextString(m) }
+func (*UnderReplicationEvent) ProtoMessage()    {}
+func (*UnderReplicationEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{16}
+}
+
+func (m *UnderReplicationEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UnderReplicationEvent.Unmarshal(m, b)
+}
+func (m *UnderReplicationEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UnderReplicationEvent.Marshal(b, m, deterministic)
+}
+func (m *UnderReplicationEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UnderReplicationEvent.Merge(m, src)
+}
+func (m *UnderReplicationEvent) XXX_Size() int {
+	return xxx_messageInfo_UnderReplicationEvent.Size(m)
+}
+func (m *UnderReplicationEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_UnderReplicationEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UnderReplicationEvent proto.InternalMessageInfo
+
+func (m *UnderReplicationEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *UnderReplicationEvent) GetTopic() string {
+	if m != nil {
+		return m.Topic
+	}
+	return ""
+}
+
+func (m *UnderReplicationEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type OffsetMapping struct {
+	UpstreamOffset       uint64   `protobuf:"varint,1,opt,name=upstream_offset,json=upstreamOffset,proto3" json:"upstream_offset,omitempty"`
+	LocalOffset          uint64   `protobuf:"varint,2,opt,name=local_offset,json=localOffset,proto3" json:"local_offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OffsetMapping) Reset()         { *m = OffsetMapping{} }
+func (m *OffsetMapping) String() string { return proto.CompactTextString(m) }
+func (*OffsetMapping) ProtoMessage()    {}
+func (*OffsetMapping) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{17}
+}
+
+func (m *OffsetMapping) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OffsetMapping.Unmarshal(m, b)
+}
+func (m *OffsetMapping) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OffsetMapping.Marshal(b, m, deterministic)
+}
+func (m *OffsetMapping) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OffsetMapping.Merge(m, src)
+}
+func (m *OffsetMapping) XXX_Size() int {
+	return xxx_messageInfo_OffsetMapping.Size(m)
+}
+func (m *OffsetMapping) XXX_DiscardUnknown() {
+	xxx_messageInfo_OffsetMapping.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OffsetMapping proto.InternalMessageInfo
+
+func (m *OffsetMapping) GetUpstreamOffset() uint64 {
+	if m != nil {
+		return m.UpstreamOffset
+	}
+	return 0
 }
 
 func (m *OffsetMapping) GetLocalOffset() uint64 {
@@ -581,7 +1092,7 @@ func (m *TranslateOffsetRequest) Reset()         { *m = TranslateOffsetRequest{}
 func (m *TranslateOffsetRequest) String() string { return proto.CompactTextString(m) }
 func (*TranslateOffsetRequest) ProtoMessage()    {}
 func (*TranslateOffsetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4215e5fe8e6d7e5d, []int{10}
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{18}
 }
 
 func (m *TranslateOffsetRequest) XXX_Unmarshal(b []byte) error {
@@ -627,7 +1138,7 @@ func (m *TranslateOffsetResponse) Reset()         { *m = TranslateOffsetResponse
 func (m *TranslateOffsetResponse) String() string { return proto.CompactTextString(m) }
 func (*TranslateOffsetResponse) ProtoMessage()    {}
 func (*TranslateOffsetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4215e5fe8e6d7e5d, []int{11}
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{19}
 }
 
 func (m *TranslateOffsetResponse) XXX_Unmarshal(b []byte) error {
@@ -655,6 +1166,790 @@ func (m *TranslateOffsetResponse) GetOffsets() map[string]*OffsetMapping {
 	return nil
 }
 
+type SnapshotRequest struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotRequest) Reset()         { *m = SnapshotRequest{} }
+func (m *SnapshotRequest) String() string { return proto.CompactTextString(m) }
+func (*SnapshotRequest) ProtoMessage()    {}
+func (*SnapshotRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{20}
+}
+
+func (m *SnapshotRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotRequest.Unmarshal(m, b)
+}
+func (m *SnapshotRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotRequest.Marshal(b, m, deterministic)
+}
+func (m *SnapshotRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotRequest.Merge(m, src)
+}
+func (m *SnapshotRequest) XXX_Size() int {
+	return xxx_messageInfo_SnapshotRequest.Size(m)
+}
+func (m *SnapshotRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotRequest proto.InternalMessageInfo
+
+func (m *SnapshotRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type SnapshotResponse struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Timestamp            int64    `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotResponse) Reset()         { *m = SnapshotResponse{} }
+func (m *SnapshotResponse) String() string { return proto.CompactTextString(m) }
+func (*SnapshotResponse) ProtoMessage()    {}
+func (*SnapshotResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{21}
+}
+
+func (m *SnapshotResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotResponse.Unmarshal(m, b)
+}
+func (m *SnapshotResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotResponse.Marshal(b, m, deterministic)
+}
+func (m *SnapshotResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotResponse.Merge(m, src)
+}
+func (m *SnapshotResponse) XXX_Size() int {
+	return xxx_messageInfo_SnapshotResponse.Size(m)
+}
+func (m *SnapshotResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotResponse proto.InternalMessageInfo
+
+func (m *SnapshotResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SnapshotResponse) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type SnapshotDiff struct {
+	BrokersAdded         []uint32 `protobuf:"varint,1,rep,packed,name=brokers_added,json=brokersAdded,proto3" json:"brokers_added,omitempty"`
+	BrokersRemoved       []uint32 `protobuf:"varint,2,rep,packed,name=brokers_removed,json=brokersRemoved,proto3" json:"brokers_removed,omitempty"`
+	BrokersChanged       []uint32 `protobuf:"varint,3,rep,packed,name=brokers_changed,json=brokersChanged,proto3" json:"brokers_changed,omitempty"`
+	TopicsAdded          []string `protobuf:"bytes,4,rep,name=topics_added,json=topicsAdded,proto3" json:"topics_added,omitempty"`
+	TopicsRemoved        []string `protobuf:"bytes,5,rep,name=topics_removed,json=topicsRemoved,proto3" json:"topics_removed,omitempty"`
+	TopicsChanged        []string `protobuf:"bytes,6,rep,name=topics_changed,json=topicsChanged,proto3" json:"topics_changed,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SnapshotDiff) Reset()         { *m = SnapshotDiff{} }
+func (m *SnapshotDiff) String() string { return proto.CompactTextString(m) }
+func (*SnapshotDiff) ProtoMessage()    {}
+func (*SnapshotDiff) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{22}
+}
+
+func (m *SnapshotDiff) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SnapshotDiff.Unmarshal(m, b)
+}
+func (m *SnapshotDiff) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SnapshotDiff.Marshal(b, m, deterministic)
+}
+func (m *SnapshotDiff) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SnapshotDiff.Merge(m, src)
+}
+func (m *SnapshotDiff) XXX_Size() int {
+	return xxx_messageInfo_SnapshotDiff.Size(m)
+}
+func (m *SnapshotDiff) XXX_DiscardUnknown() {
+	xxx_messageInfo_SnapshotDiff.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SnapshotDiff proto.InternalMessageInfo
+
+func (m *SnapshotDiff) GetBrokersAdded() []uint32 {
+	if m != nil {
+		return m.BrokersAdded
+	}
+	return nil
+}
+
+func (m *SnapshotDiff) GetBrokersRemoved() []uint32 {
+	if m != nil {
+		return m.BrokersRemoved
+	}
+	return nil
+}
+
+func (m *SnapshotDiff) GetBrokersChanged() []uint32 {
+	if m != nil {
+		return m.BrokersChanged
+	}
+	return nil
+}
+
+func (m *SnapshotDiff) GetTopicsAdded() []string {
+	if m != nil {
+		return m.TopicsAdded
+	}
+	return nil
+}
+
+func (m *SnapshotDiff) GetTopicsRemoved() []string {
+	if m != nil {
+		return m.TopicsRemoved
+	}
+	return nil
+}
+
+func (m *SnapshotDiff) GetTopicsChanged() []string {
+	if m != nil {
+		return m.TopicsChanged
+	}
+	return nil
+}
+
+type RebalanceRecommendationRequest struct {
+	// Skew thresholds, expressed as the difference between the highest and
+	// lowest per-broker values relative to the mean.
+	PartitionSkewThreshold  float64  `protobuf:"fixed64,1,opt,name=partition_skew_threshold,json=partitionSkewThreshold,proto3" json:"partition_skew_threshold,omitempty"`
+	StorageSkewThreshold    float64  `protobuf:"fixed64,2,opt,name=storage_skew_threshold,json=storageSkewThreshold,proto3" json:"storage_skew_threshold,omitempty"`
+	LeadershipSkewThreshold float64  `protobuf:"fixed64,3,opt,name=leadership_skew_threshold,json=leadershipSkewThreshold,proto3" json:"leadership_skew_threshold,omitempty"`
+	XXX_NoUnkeyedLiteral    struct{} `json:"-"`
+	XXX_unrecognized        []byte   `json:"-"`
+	XXX_sizecache           int32    `json:"-"`
+}
+
+func (m *RebalanceRecommendationRequest) Reset()         { *m = RebalanceRecommendationRequest{} }
+func (m *RebalanceRecommendationRequest) String() string { return proto.CompactTextString(m) }
+func (*RebalanceRecommendationRequest) ProtoMessage()    {}
+func (*RebalanceRecommendationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{23}
+}
+
+func (m *RebalanceRecommendationRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RebalanceRecommendationRequest.Unmarshal(m, b)
+}
+func (m *RebalanceRecommendationRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RebalanceRecommendationRequest.Marshal(b, m, deterministic)
+}
+func (m *RebalanceRecommendationRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RebalanceRecommendationRequest.Merge(m, src)
+}
+func (m *RebalanceRecommendationRequest) XXX_Size() int {
+	return xxx_messageInfo_RebalanceRecommendationRequest.Size(m)
+}
+func (m *RebalanceRecommendationRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RebalanceRecommendationRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RebalanceRecommendationRequest proto.InternalMessageInfo
+
+func (m *RebalanceRecommendationRequest) GetPartitionSkewThreshold() float64 {
+	if m != nil {
+		return m.PartitionSkewThreshold
+	}
+	return 0
+}
+
+func (m *RebalanceRecommendationRequest) GetStorageSkewThreshold() float64 {
+	if m != nil {
+		return m.StorageSkewThreshold
+	}
+	return 0
+}
+
+func (m *RebalanceRecommendationRequest) GetLeadershipSkewThreshold() float64 {
+	if m != nil {
+		return m.LeadershipSkewThreshold
+	}
+	return 0
+}
+
+type RebalanceRecommendation struct {
+	// Whether any metric exceeded its threshold.
+	Recommended bool `protobuf:"varint,1,opt,name=recommended,proto3" json:"recommended,omitempty"`
+	// One of none, low, medium or high.
+	Severity             string             `protobuf:"bytes,2,opt,name=severity,proto3" json:"severity,omitempty"`
+	Metrics              []*ImbalanceMetric `protobuf:"bytes,3,rep,name=metrics,proto3" json:"metrics,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *RebalanceRecommendation) Reset()         { *m = RebalanceRecommendation{} }
+func (m *RebalanceRecommendation) String() string { return proto.CompactTextString(m) }
+func (*RebalanceRecommendation) ProtoMessage()    {}
+func (*RebalanceRecommendation) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{24}
+}
+
+func (m *RebalanceRecommendation) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RebalanceRecommendation.Unmarshal(m, b)
+}
+func (m *RebalanceRecommendation) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RebalanceRecommendation.Marshal(b, m, deterministic)
+}
+func (m *RebalanceRecommendation) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RebalanceRecommendation.Merge(m, src)
+}
+func (m *RebalanceRecommendation) XXX_Size() int {
+	return xxx_messageInfo_RebalanceRecommendation.Size(m)
+}
+func (m *RebalanceRecommendation) XXX_DiscardUnknown() {
+	xxx_messageInfo_RebalanceRecommendation.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RebalanceRecommendation proto.InternalMessageInfo
+
+func (m *RebalanceRecommendation) GetRecommended() bool {
+	if m != nil {
+		return m.Recommended
+	}
+	return false
+}
+
+func (m *RebalanceRecommendation) GetSeverity() string {
+	if m != nil {
+		return m.Severity
+	}
+	return ""
+}
+
+func (m *RebalanceRecommendation) GetMetrics() []*ImbalanceMetric {
+	if m != nil {
+		return m.Metrics
+	}
+	return nil
+}
+
+type ImbalanceMetric struct {
+	// One of partition_count, storage_free or leadership.
+	Name      string  `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Skew      float64 `protobuf:"fixed64,2,opt,name=skew,proto3" json:"skew,omitempty"`
+	Threshold float64 `protobuf:"fixed64,3,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Triggered bool    `protobuf:"varint,4,opt,name=triggered,proto3" json:"triggered,omitempty"`
+	// The brokers with the lowest and highest values.
+	MinBroker            uint32   `protobuf:"varint,5,opt,name=min_broker,json=minBroker,proto3" json:"min_broker,omitempty"`
+	MaxBroker            uint32   `protobuf:"varint,6,opt,name=max_broker,json=maxBroker,proto3" json:"max_broker,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImbalanceMetric) Reset()         { *m = ImbalanceMetric{} }
+func (m *ImbalanceMetric) String() string { return proto.CompactTextString(m) }
+func (*ImbalanceMetric) ProtoMessage()    {}
+func (*ImbalanceMetric) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{25}
+}
+
+func (m *ImbalanceMetric) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ImbalanceMetric.Unmarshal(m, b)
+}
+func (m *ImbalanceMetric) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ImbalanceMetric.Marshal(b, m, deterministic)
+}
+func (m *ImbalanceMetric) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImbalanceMetric.Merge(m, src)
+}
+func (m *ImbalanceMetric) XXX_Size() int {
+	return xxx_messageInfo_ImbalanceMetric.Size(m)
+}
+func (m *ImbalanceMetric) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImbalanceMetric.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImbalanceMetric proto.InternalMessageInfo
+
+func (m *ImbalanceMetric) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *ImbalanceMetric) GetSkew() float64 {
+	if m != nil {
+		return m.Skew
+	}
+	return 0
+}
+
+func (m *ImbalanceMetric) GetThreshold() float64 {
+	if m != nil {
+		return m.Threshold
+	}
+	return 0
+}
+
+func (m *ImbalanceMetric) GetTriggered() bool {
+	if m != nil {
+		return m.Triggered
+	}
+	return false
+}
+
+func (m *ImbalanceMetric) GetMinBroker() uint32 {
+	if m != nil {
+		return m.MinBroker
+	}
+	return 0
+}
+
+func (m *ImbalanceMetric) GetMaxBroker() uint32 {
+	if m != nil {
+		return m.MaxBroker
+	}
+	return 0
+}
+
+type DesiredState struct {
+	// The complete desired configs and tags of each topic.
+	Topics []*Topic `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+	// Live topics absent from the desired topics are planned for deletion if
+	// they match all prune_tag tags. If unset, no deletes are planned.
+	PruneTag             []string `protobuf:"bytes,2,rep,name=prune_tag,json=pruneTag,proto3" json:"prune_tag,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *DesiredState) Reset()         { *m = DesiredState{} }
+func (m *DesiredState) String() string { return proto.CompactTextString(m) }
+func (*DesiredState) ProtoMessage()    {}
+func (*DesiredState) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{26}
+}
+
+func (m *DesiredState) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_DesiredState.Unmarshal(m, b)
+}
+func (m *DesiredState) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_DesiredState.Marshal(b, m, deterministic)
+}
+func (m *DesiredState) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_DesiredState.Merge(m, src)
+}
+func (m *DesiredState) XXX_Size() int {
+	return xxx_messageInfo_DesiredState.Size(m)
+}
+func (m *DesiredState) XXX_DiscardUnknown() {
+	xxx_messageInfo_DesiredState.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_DesiredState proto.InternalMessageInfo
+
+func (m *DesiredState) GetTopics() []*Topic {
+	if m != nil {
+		return m.Topics
+	}
+	return nil
+}
+
+func (m *DesiredState) GetPruneTag() []string {
+	if m != nil {
+		return m.PruneTag
+	}
+	return nil
+}
+
+type ReconciliationPlan struct {
+	Actions []*TopicAction `protobuf:"bytes,1,rep,name=actions,proto3" json:"actions,omitempty"`
+	// Differences that can't be reconciled, such as partition count or
+	// replication factor changes.
+	Warnings             []string `protobuf:"bytes,2,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReconciliationPlan) Reset()         { *m = ReconciliationPlan{} }
+func (m *ReconciliationPlan) String() string { return proto.CompactTextString(m) }
+func (*ReconciliationPlan) ProtoMessage()    {}
+func (*ReconciliationPlan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{27}
+}
+
+func (m *ReconciliationPlan) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReconciliationPlan.Unmarshal(m, b)
+}
+func (m *ReconciliationPlan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReconciliationPlan.Marshal(b, m, deterministic)
+}
+func (m *ReconciliationPlan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReconciliationPlan.Merge(m, src)
+}
+func (m *ReconciliationPlan) XXX_Size() int {
+	return xxx_messageInfo_ReconciliationPlan.Size(m)
+}
+func (m *ReconciliationPlan) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReconciliationPlan.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReconciliationPlan proto.InternalMessageInfo
+
+func (m *ReconciliationPlan) GetActions() []*TopicAction {
+	if m != nil {
+		return m.Actions
+	}
+	return nil
+}
+
+func (m *ReconciliationPlan) GetWarnings() []string {
+	if m != nil {
+		return m.Warnings
+	}
+	return nil
+}
+
+type TopicAction struct {
+	// One of create, update or delete.
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// The topic to create.
+	Topic *Topic `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	// Config and tag changes for updates.
+	SetConfigs           map[string]string `protobuf:"bytes,4,rep,name=set_configs,json=setConfigs,proto3" json:"set_configs,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	DeleteConfigs        []string          `protobuf:"bytes,5,rep,name=delete_configs,json=deleteConfigs,proto3" json:"delete_configs,omitempty"`
+	SetTags              map[string]string `protobuf:"bytes,6,rep,name=set_tags,json=setTags,proto3" json:"set_tags,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	DeleteTags           []string          `protobuf:"bytes,7,rep,name=delete_tags,json=deleteTags,proto3" json:"delete_tags,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *TopicAction) Reset()         { *m = TopicAction{} }
+func (m *TopicAction) String() string { return proto.CompactTextString(m) }
+func (*TopicAction) ProtoMessage()    {}
+func (*TopicAction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{28}
+}
+
+func (m *TopicAction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicAction.Unmarshal(m, b)
+}
+func (m *TopicAction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicAction.Marshal(b, m, deterministic)
+}
+func (m *TopicAction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicAction.Merge(m, src)
+}
+func (m *TopicAction) XXX_Size() int {
+	return xxx_messageInfo_TopicAction.Size(m)
+}
+func (m *TopicAction) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicAction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicAction proto.InternalMessageInfo
+
+func (m *TopicAction) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *TopicAction) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *TopicAction) GetTopic() *Topic {
+	if m != nil {
+		return m.Topic
+	}
+	return nil
+}
+
+func (m *TopicAction) GetSetConfigs() map[string]string {
+	if m != nil {
+		return m.SetConfigs
+	}
+	return nil
+}
+
+func (m *TopicAction) GetDeleteConfigs() []string {
+	if m != nil {
+		return m.DeleteConfigs
+	}
+	return nil
+}
+
+func (m *TopicAction) GetSetTags() map[string]string {
+	if m != nil {
+		return m.SetTags
+	}
+	return nil
+}
+
+func (m *TopicAction) GetDeleteTags() []string {
+	if m != nil {
+		return m.DeleteTags
+	}
+	return nil
+}
+
+type ApplyResponse struct {
+	Results              []*TopicActionResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ApplyResponse) Reset()         { *m = ApplyResponse{} }
+func (m *ApplyResponse) String() string { return proto.CompactTextString(m) }
+func (*ApplyResponse) ProtoMessage()    {}
+func (*ApplyResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{29}
+}
+
+func (m *ApplyResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ApplyResponse.Unmarshal(m, b)
+}
+func (m *ApplyResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ApplyResponse.Marshal(b, m, deterministic)
+}
+func (m *ApplyResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ApplyResponse.Merge(m, src)
+}
+func (m *ApplyResponse) XXX_Size() int {
+	return xxx_messageInfo_ApplyResponse.Size(m)
+}
+func (m *ApplyResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ApplyResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ApplyResponse proto.InternalMessageInfo
+
+func (m *ApplyResponse) GetResults() []*TopicActionResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type TopicActionResult struct {
+	Action               *TopicAction `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Applied              bool         `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	Error                string       `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *TopicActionResult) Reset()         { *m = TopicActionResult{} }
+func (m *TopicActionResult) String() string { return proto.CompactTextString(m) }
+func (*TopicActionResult) ProtoMessage()    {}
+func (*TopicActionResult) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{30}
+}
+
+func (m *TopicActionResult) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TopicActionResult.Unmarshal(m, b)
+}
+func (m *TopicActionResult) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TopicActionResult.Marshal(b, m, deterministic)
+}
+func (m *TopicActionResult) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TopicActionResult.Merge(m, src)
+}
+func (m *TopicActionResult) XXX_Size() int {
+	return xxx_messageInfo_TopicActionResult.Size(m)
+}
+func (m *TopicActionResult) XXX_DiscardUnknown() {
+	xxx_messageInfo_TopicActionResult.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TopicActionResult proto.InternalMessageInfo
+
+func (m *TopicActionResult) GetAction() *TopicAction {
+	if m != nil {
+		return m.Action
+	}
+	return nil
+}
+
+func (m *TopicActionResult) GetApplied() bool {
+	if m != nil {
+		return m.Applied
+	}
+	return false
+}
+
+func (m *TopicActionResult) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type AuditLogRequest struct {
+	// If set, only entries matching all set fields are streamed.
+	EntityType           string   `protobuf:"bytes,1,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	Entity               string   `protobuf:"bytes,2,opt,name=entity,proto3" json:"entity,omitempty"`
+	Method               string   `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditLogRequest) Reset()         { *m = AuditLogRequest{} }
+func (m *AuditLogRequest) String() string { return proto.CompactTextString(m) }
+func (*AuditLogRequest) ProtoMessage()    {}
+func (*AuditLogRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{31}
+}
+
+func (m *AuditLogRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditLogRequest.Unmarshal(m, b)
+}
+func (m *AuditLogRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditLogRequest.Marshal(b, m, deterministic)
+}
+func (m *AuditLogRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditLogRequest.Merge(m, src)
+}
+func (m *AuditLogRequest) XXX_Size() int {
+	return xxx_messageInfo_AuditLogRequest.Size(m)
+}
+func (m *AuditLogRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditLogRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditLogRequest proto.InternalMessageInfo
+
+func (m *AuditLogRequest) GetEntityType() string {
+	if m != nil {
+		return m.EntityType
+	}
+	return ""
+}
+
+func (m *AuditLogRequest) GetEntity() string {
+	if m != nil {
+		return m.Entity
+	}
+	return ""
+}
+
+func (m *AuditLogRequest) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+type AuditEntry struct {
+	// Unix timestamp in seconds.
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// The RPC name, e.g. TagTopic.
+	Method string `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	// One of topic, broker, snapshot or reconciliation.
+	EntityType string `protobuf:"bytes,3,opt,name=entity_type,json=entityType,proto3" json:"entity_type,omitempty"`
+	// The topic name or broker ID, if applicable.
+	Entity string `protobuf:"bytes,4,opt,name=entity,proto3" json:"entity,omitempty"`
+	// The address of the requestor.
+	Requestor string `protobuf:"bytes,5,opt,name=requestor,proto3" json:"requestor,omitempty"`
+	// The JSON encoded request.
+	Request              string   `protobuf:"bytes,6,opt,name=request,proto3" json:"request,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AuditEntry) Reset()         { *m = AuditEntry{} }
+func (m *AuditEntry) String() string { return proto.CompactTextString(m) }
+func (*AuditEntry) ProtoMessage()    {}
+func (*AuditEntry) Descriptor() ([]byte, []int) {
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{32}
+}
+
+func (m *AuditEntry) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditEntry.Unmarshal(m, b)
+}
+func (m *AuditEntry) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditEntry.Marshal(b, m, deterministic)
+}
+func (m *AuditEntry) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditEntry.Merge(m, src)
+}
+func (m *AuditEntry) XXX_Size() int {
+	return xxx_messageInfo_AuditEntry.Size(m)
+}
+func (m *AuditEntry) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditEntry.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditEntry proto.InternalMessageInfo
+
+func (m *AuditEntry) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *AuditEntry) GetMethod() string {
+	if m != nil {
+		return m.Method
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetEntityType() string {
+	if m != nil {
+		return m.EntityType
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetEntity() string {
+	if m != nil {
+		return m.Entity
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetRequestor() string {
+	if m != nil {
+		return m.Requestor
+	}
+	return ""
+}
+
+func (m *AuditEntry) GetRequest() string {
+	if m != nil {
+		return m.Request
+	}
+	return ""
+}
+
 type Empty struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -665,7 +1960,7 @@ func (m *Empty) Reset()         { *m = Empty{} }
 func (m *Empty) String() string { return proto.CompactTextString(m) }
 func (*Empty) ProtoMessage()    {}
 func (*Empty) Descriptor() ([]byte, []int) {
-	return fileDescriptor_4215e5fe8e6d7e5d, []int{12}
+	return fileDescriptor_4215e5fe8e6d7e5d, []int{33}
 }
 
 func (m *Empty) XXX_Unmarshal(b []byte) error {
@@ -691,107 +1986,222 @@ func init() {
 	proto.RegisterType((*BrokerRequest)(nil), "registry.BrokerRequest")
 	proto.RegisterType((*BrokerResponse)(nil), "registry.BrokerResponse")
 	proto.RegisterMapType((map[uint32]*Broker)(nil), "registry.BrokerResponse.BrokersEntry")
+	proto.RegisterMapType((map[string]*BrokerGroup)(nil), "registry.BrokerResponse.GroupsEntry")
+	proto.RegisterType((*BrokerGroup)(nil), "registry.BrokerGroup")
 	proto.RegisterType((*UnmappedBrokersRequest)(nil), "registry.UnmappedBrokersRequest")
+	proto.RegisterType((*DecommissionPreviewRequest)(nil), "registry.DecommissionPreviewRequest")
+	proto.RegisterType((*DecommissionPreview)(nil), "registry.DecommissionPreview")
+	proto.RegisterType((*PartitionMove)(nil), "registry.PartitionMove")
 	proto.RegisterType((*Broker)(nil), "registry.Broker")
 	proto.RegisterMapType((map[string]string)(nil), "registry.Broker.ListenersecurityprotocolmapEntry")
 	proto.RegisterMapType((map[string]string)(nil), "registry.Broker.TagsEntry")
 	proto.RegisterType((*TopicRequest)(nil), "registry.TopicRequest")
 	proto.RegisterType((*CreateTopicRequest)(nil), "registry.CreateTopicRequest")
+	proto.RegisterType((*ReplicationFactorRequest)(nil), "registry.ReplicationFactorRequest")
+	proto.RegisterType((*ReplicationFactorResponse)(nil), "registry.ReplicationFactorResponse")
 	proto.RegisterType((*TopicResponse)(nil), "registry.TopicResponse")
 	proto.RegisterMapType((map[string]*Topic)(nil), "registry.TopicResponse.TopicsEntry")
 	proto.RegisterType((*Topic)(nil), "registry.Topic")
 	proto.RegisterMapType((map[string]string)(nil), "registry.Topic.ConfigsEntry")
 	proto.RegisterMapType((map[string]string)(nil), "registry.Topic.TagsEntry")
+	proto.RegisterType((*TopicConfigRecommendation)(nil), "registry.TopicConfigRecommendation")
+	proto.RegisterMapType((map[string]string)(nil), "registry.TopicConfigRecommendation.ChangesEntry")
+	proto.RegisterMapType((map[string]string)(nil), "registry.TopicConfigRecommendation.ConfigsEntry")
+	proto.RegisterType((*UnderReplicationEvent)(nil), "registry.UnderReplicationEvent")
 	proto.RegisterType((*OffsetMapping)(nil), "registry.OffsetMapping")
 	proto.RegisterType((*TranslateOffsetRequest)(nil), "registry.TranslateOffsetRequest")
 	proto.RegisterType((*TranslateOffsetResponse)(nil), "registry.TranslateOffsetResponse")
 	proto.RegisterMapType((map[string]*OffsetMapping)(nil), "registry.TranslateOffsetResponse.OffsetsEntry")
+	proto.RegisterType((*SnapshotRequest)(nil), "registry.SnapshotRequest")
+	proto.RegisterType((*SnapshotResponse)(nil), "registry.SnapshotResponse")
+	proto.RegisterType((*SnapshotDiff)(nil), "registry.SnapshotDiff")
+	proto.RegisterType((*RebalanceRecommendationRequest)(nil), "registry.RebalanceRecommendationRequest")
+	proto.RegisterType((*RebalanceRecommendation)(nil), "registry.RebalanceRecommendation")
+	proto.RegisterType((*ImbalanceMetric)(nil), "registry.ImbalanceMetric")
+	proto.RegisterType((*DesiredState)(nil), "registry.DesiredState")
+	proto.RegisterType((*ReconciliationPlan)(nil), "registry.ReconciliationPlan")
+	proto.RegisterType((*TopicAction)(nil), "registry.TopicAction")
+	proto.RegisterMapType((map[string]string)(nil), "registry.TopicAction.SetConfigsEntry")
+	proto.RegisterMapType((map[string]string)(nil), "registry.TopicAction.SetTagsEntry")
+	proto.RegisterType((*ApplyResponse)(nil), "registry.ApplyResponse")
+	proto.RegisterType((*TopicActionResult)(nil), "registry.TopicActionResult")
+	proto.RegisterType((*AuditLogRequest)(nil), "registry.AuditLogRequest")
+	proto.RegisterType((*AuditEntry)(nil), "registry.AuditEntry")
 	proto.RegisterType((*Empty)(nil), "registry.Empty")
 }
 
 func init() { proto.RegisterFile("protos/registry.proto", fileDescriptor_4215e5fe8e6d7e5d) }
 
 var fileDescriptor_4215e5fe8e6d7e5d = []byte{
-	// 1260 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x57, 0xdb, 0x6e, 0x1b, 0xc5,
-	0x1b, 0x97, 0x9d, 0x38, 0x8e, 0xbf, 0xb5, 0x9b, 0x74, 0xda, 0x24, 0x9b, 0x6d, 0xfe, 0x95, 0xbb,
-	0x7f, 0x95, 0x46, 0x51, 0xe3, 0xa5, 0x06, 0x51, 0x28, 0x17, 0x15, 0x3d, 0xa8, 0x80, 0xca, 0x69,
-	0x49, 0x11, 0x14, 0x21, 0x33, 0xf5, 0x4e, 0xb6, 0x8b, 0xf7, 0xc4, 0xce, 0xb8, 0x6a, 0x14, 0xe5,
-	0x86, 0x57, 0xe0, 0x86, 0x37, 0xe0, 0x0e, 0x89, 0x3b, 0x78, 0x0e, 0x5e, 0x81, 0x27, 0xe0, 0x09,
-	0xd0, 0x7c, 0x33, 0x13, 0xaf, 0x0f, 0xdb, 0x2a, 0xe1, 0xca, 0xf3, 0xcd, 0x7c, 0xf3, 0xfb, 0xcd,
-	0xfc, 0xe6, 0x3b, 0xac, 0x61, 0x23, 0x2f, 0x32, 0x91, 0x71, 0xaf, 0x60, 0x61, 0xc4, 0x45, 0x71,
-	0xd4, 0x43, 0x9b, 0xac, 0x1a, 0xdb, 0xd9, 0x09, 0xb3, 0x2c, 0x8c, 0x99, 0x47, 0xf3, 0xc8, 0xa3,
-	0x69, 0x9a, 0x09, 0x2a, 0xa2, 0x2c, 0xe5, 0xca, 0xcf, 0xbd, 0x01, 0xd6, 0x01, 0x0d, 0x7d, 0xc6,
-	0xf3, 0x2c, 0xe5, 0x8c, 0xd8, 0xd0, 0x4c, 0x18, 0xe7, 0x34, 0x64, 0x76, 0xad, 0x5b, 0xdb, 0x6d,
-	0xf9, 0xc6, 0x74, 0x6f, 0x41, 0xe7, 0x5e, 0x91, 0x8d, 0x58, 0xe1, 0xb3, 0x1f, 0xc7, 0x8c, 0x0b,
-	0xb2, 0x0e, 0x4b, 0x82, 0x86, 0x76, 0xad, 0xbb, 0xb4, 0xdb, 0xf2, 0xe5, 0x90, 0x5c, 0x80, 0x7a,
-	0x14, 0xd8, 0xf5, 0x6e, 0x6d, 0xb7, 0xe3, 0xd7, 0xa3, 0xc0, 0xfd, 0xbd, 0x06, 0x17, 0xcc, 0x1e,
-	0x8d, 0x7f, 0x17, 0x9a, 0xcf, 0x70, 0x86, 0xdb, 0x8d, 0xee, 0xd2, 0xae, 0xd5, 0xbf, 0xde, 0x3b,
-	0x3d, 0xf8, 0xb4, 0xab, 0x36, 0xf9, 0xc3, 0x54, 0x14, 0x47, 0xbe, 0xd9, 0x25, 0x59, 0xa3, 0x80,
-	0xdb, 0x2b, 0xdd, 0xa5, 0xdd, 0x8e, 0x2f, 0x87, 0xce, 0x63, 0x68, 0x97, 0x5d, 0xa5, 0xc7, 0x88,
-	0x1d, 0xe1, 0xf1, 0x3b, 0xbe, 0x1c, 0x92, 0x37, 0xa0, 0xf1, 0x82, 0xc6, 0x63, 0x86, 0x47, 0xb3,
-	0xfa, 0xeb, 0x73, 0x94, 0x6a, 0xf9, 0x4e, 0xfd, 0xdd, 0x9a, 0xdb, 0x87, 0xcd, 0x27, 0x69, 0x42,
-	0xf3, 0x9c, 0x05, 0x1a, 0xd5, 0xdc, 0xd7, 0x86, 0x26, 0x7b, 0x39, 0x8c, 0xc7, 0x01, 0xd3, 0x77,
-	0x36, 0xa6, 0xfb, 0xcf, 0x12, 0xac, 0x28, 0x67, 0xd2, 0x83, 0x65, 0x41, 0x43, 0x8e, 0x1e, 0x56,
-	0xdf, 0x99, 0x65, 0xea, 0x1d, 0xd0, 0x50, 0xdf, 0x08, 0xfd, 0xb4, 0x64, 0x0d, 0x23, 0x19, 0xe1,
-	0x70, 0x25, 0x8e, 0xb8, 0x60, 0x29, 0x2b, 0x38, 0x1b, 0x8e, 0x8b, 0x48, 0x1c, 0xe1, 0x3b, 0x0d,
-	0xb3, 0x38, 0xa1, 0x39, 0x5e, 0xdb, 0xea, 0xdf, 0x9a, 0x83, 0x7d, 0x5c, 0xbd, 0x47, 0xb1, 0xbd,
-	0x0a, 0x95, 0xec, 0x40, 0x8b, 0xa5, 0x41, 0x9e, 0x45, 0xa9, 0xe0, 0x76, 0x13, 0xef, 0x36, 0x99,
-	0x20, 0x04, 0x96, 0x0b, 0x3a, 0x1c, 0xd9, 0xab, 0x18, 0x0f, 0x38, 0x96, 0x5a, 0xfc, 0x90, 0xbc,
-	0xcc, 0xb3, 0x42, 0xd8, 0x2d, 0x3c, 0xbb, 0x31, 0xa5, 0xf7, 0xf3, 0x8c, 0x0b, 0x1b, 0x94, 0xb7,
-	0x1c, 0x4b, 0x7c, 0x11, 0x25, 0x8c, 0x0b, 0x9a, 0xe4, 0xb6, 0xd5, 0xad, 0xed, 0x2e, 0xf9, 0x93,
-	0x09, 0xb9, 0x03, 0x81, 0xda, 0x08, 0x84, 0x63, 0x89, 0xff, 0x82, 0x15, 0x3c, 0xca, 0x52, 0xbb,
-	0xa3, 0xf0, 0xb5, 0xe9, 0xdc, 0x86, 0xd6, 0xa9, 0x86, 0xe5, 0xa7, 0x6e, 0xa9, 0xa7, 0xbe, 0x5c,
-	0x7e, 0xea, 0x56, 0xe9, 0x61, 0x9d, 0x4f, 0xa1, 0xfb, 0x3a, 0x95, 0xce, 0x82, 0xe7, 0xbe, 0x0d,
-	0xed, 0x83, 0x2c, 0x8f, 0x86, 0xd5, 0xe9, 0x40, 0x60, 0x39, 0xa5, 0x89, 0xd9, 0x8a, 0x63, 0x37,
-	0x02, 0x72, 0xbf, 0x60, 0x54, 0xb0, 0xa9, 0xbd, 0xd7, 0xa1, 0x21, 0xa4, 0x8d, 0xcc, 0x56, 0x7f,
-	0x6d, 0xf2, 0xbe, 0xca, 0x4d, 0xad, 0x92, 0x9b, 0x40, 0x04, 0x2d, 0x42, 0x26, 0x06, 0x2a, 0x1b,
-	0x06, 0x18, 0x6a, 0x75, 0x64, 0x5c, 0x57, 0x2b, 0x2a, 0x1e, 0xa4, 0x42, 0xee, 0x6f, 0x35, 0xe8,
-	0x68, 0x16, 0x9d, 0x7c, 0xef, 0xc3, 0x0a, 0x02, 0x99, 0xdc, 0xfb, 0xff, 0x2c, 0x8f, 0x49, 0x3d,
-	0xb4, 0x74, 0x9c, 0xea, 0x2d, 0x52, 0x09, 0x79, 0x03, 0x95, 0x7a, 0x2d, 0x5f, 0x19, 0xce, 0xc7,
-	0x60, 0x95, 0x9c, 0x17, 0x08, 0x78, 0x7d, 0x3a, 0xf7, 0xe6, 0xaf, 0x36, 0x51, 0xf4, 0xcf, 0x3a,
-	0x34, 0x70, 0x92, 0xec, 0x4f, 0x65, 0xd1, 0xf6, 0xcc, 0x9e, 0xb9, 0x24, 0x32, 0x42, 0x37, 0x26,
-	0x42, 0x93, 0xab, 0x00, 0x39, 0x2d, 0x44, 0x84, 0xb5, 0xce, 0x5e, 0xc1, 0x20, 0x2a, 0xcd, 0x90,
-	0x2e, 0x58, 0x05, 0xcb, 0xe3, 0x68, 0x88, 0xd5, 0xd0, 0x6e, 0xa2, 0x43, 0x79, 0x8a, 0xbc, 0x03,
-	0xcd, 0x61, 0x96, 0x1e, 0x46, 0x21, 0xb7, 0x57, 0xf1, 0x1c, 0x3b, 0xb3, 0xe7, 0xb8, 0xaf, 0x96,
-	0x75, 0x85, 0xd2, 0xce, 0xe7, 0x8f, 0xd0, 0x3b, 0xd0, 0x2e, 0x23, 0x9e, 0x29, 0x1a, 0xbf, 0x85,
-	0xce, 0x67, 0x87, 0x87, 0x9c, 0x89, 0x4f, 0x68, 0x9e, 0x47, 0x69, 0x48, 0x6e, 0xc0, 0xda, 0x38,
-	0xe7, 0xa2, 0x60, 0x34, 0x19, 0x64, 0xb8, 0x82, 0x40, 0xcb, 0xfe, 0x05, 0x33, 0xad, 0xfc, 0xc9,
-	0x35, 0x68, 0xc7, 0xd9, 0x90, 0xc6, 0xc6, 0xab, 0x8e, 0x5e, 0x16, 0xce, 0x29, 0x17, 0x97, 0xc1,
-	0xe6, 0x41, 0x41, 0x53, 0x1e, 0x53, 0xc1, 0xd4, 0x94, 0x09, 0xdc, 0x37, 0xe1, 0x72, 0xc1, 0x92,
-	0x4c, 0xb0, 0xc1, 0x30, 0x1e, 0x73, 0xc1, 0x8a, 0x01, 0x8d, 0x23, 0xca, 0xf5, 0x99, 0x89, 0x5a,
-	0xbb, 0xaf, 0x96, 0x3e, 0x90, 0x2b, 0x64, 0x1b, 0x56, 0xc3, 0x22, 0x1b, 0xe7, 0x03, 0xdd, 0x29,
-	0x5a, 0x7e, 0x13, 0xed, 0x8f, 0x02, 0xf7, 0x8f, 0x1a, 0x6c, 0xcd, 0xf1, 0xe8, 0xd0, 0xfd, 0x10,
-	0x9a, 0xea, 0x7c, 0x26, 0x28, 0x7a, 0xa5, 0xc7, 0x58, 0xbc, 0xa7, 0xa7, 0x4c, 0xf3, 0x3c, 0x7a,
-	0xbb, 0xf3, 0x25, 0xb4, 0xcb, 0x0b, 0x0b, 0x54, 0xde, 0x9f, 0x0e, 0xd9, 0xad, 0x09, 0xd3, 0x94,
-	0xc4, 0x65, 0xf9, 0x9b, 0xd0, 0x78, 0x98, 0xe4, 0xe2, 0xa8, 0xff, 0x6b, 0x1b, 0x56, 0x7d, 0xed,
-	0x4e, 0x0e, 0x00, 0x1e, 0x99, 0x94, 0xe4, 0x64, 0x6b, 0xbe, 0xd3, 0xa1, 0x88, 0x8e, 0x5d, 0xd5,
-	0x02, 0xdd, 0x4b, 0x3f, 0xfd, 0xf5, 0xf7, 0xcf, 0xf5, 0x0e, 0xb1, 0xbc, 0x17, 0xb7, 0x3c, 0xd3,
-	0x01, 0x9f, 0x82, 0x25, 0x0b, 0xd9, 0x7f, 0x80, 0xb5, 0x11, 0x96, 0x90, 0xf5, 0x12, 0xac, 0x27,
-	0x1b, 0x04, 0x19, 0xc1, 0xda, 0x4c, 0xf7, 0x23, 0xdd, 0x09, 0xcc, 0xe2, 0xc6, 0xf8, 0x0a, 0xa2,
-	0x1d, 0x24, 0xda, 0x24, 0x97, 0xcb, 0x44, 0x63, 0x8d, 0x42, 0x3e, 0x87, 0xd6, 0x23, 0x26, 0x54,
-	0xf9, 0x20, 0x9b, 0x73, 0xb5, 0x48, 0x81, 0x6f, 0x55, 0xd4, 0x28, 0x97, 0x20, 0x76, 0x9b, 0x80,
-	0xc4, 0xd6, 0x35, 0xea, 0x2b, 0x00, 0x29, 0xcd, 0x79, 0x21, 0xb7, 0x10, 0xf2, 0x22, 0x59, 0x9b,
-	0x40, 0x2a, 0x59, 0x9e, 0x82, 0x55, 0xaa, 0xda, 0xa4, 0x54, 0x08, 0xe6, 0x8b, 0xb9, 0x53, 0x2a,
-	0x71, 0x18, 0x13, 0x46, 0x05, 0xf7, 0x62, 0x09, 0x76, 0x88, 0xfb, 0xee, 0xd4, 0xf6, 0xc8, 0x17,
-	0x60, 0x3d, 0x60, 0x31, 0x33, 0xd8, 0x55, 0x87, 0x9e, 0x43, 0xdd, 0x46, 0xd4, 0x4b, 0x7b, 0x65,
-	0xd4, 0x63, 0x59, 0xfa, 0x4e, 0xc8, 0x77, 0x70, 0xd1, 0x67, 0x94, 0xf3, 0x28, 0x4c, 0xa3, 0x34,
-	0xd4, 0x6a, 0xcc, 0x02, 0x54, 0xcb, 0x70, 0x15, 0x91, 0x6d, 0xb2, 0x59, 0x42, 0x2e, 0x26, 0x78,
-	0x84, 0xc1, 0xc6, 0x93, 0x34, 0x90, 0xcf, 0xac, 0x8a, 0x25, 0x0b, 0xce, 0x4c, 0xe1, 0x22, 0xc5,
-	0x0e, 0x71, 0x4a, 0x14, 0x63, 0x89, 0x59, 0x9c, 0x62, 0x92, 0x40, 0xb7, 0x2f, 0x9d, 0x6e, 0xd5,
-	0xef, 0x59, 0x1d, 0x7f, 0xd7, 0x90, 0xe6, 0x0a, 0xd9, 0x96, 0x34, 0x89, 0xc6, 0x51, 0x7c, 0x46,
-	0xab, 0xc0, 0x7c, 0xa2, 0x9e, 0xd2, 0x54, 0x26, 0x54, 0xe5, 0x6d, 0xba, 0x48, 0xe3, 0x10, 0x7b,
-	0x8a, 0x46, 0xc5, 0xbb, 0x77, 0x1c, 0x05, 0x27, 0xe4, 0x6b, 0x58, 0x3d, 0xa0, 0xe1, 0xab, 0x5f,
-	0x78, 0xa3, 0x34, 0x3f, 0xf9, 0x22, 0x77, 0xff, 0x87, 0xe0, 0x5b, 0xce, 0x46, 0x49, 0x2a, 0x41,
-	0x43, 0x73, 0xfe, 0x01, 0xac, 0x95, 0xc2, 0x47, 0x36, 0x9e, 0x73, 0x12, 0xec, 0x55, 0x10, 0x7c,
-	0x83, 0xed, 0x4c, 0x7f, 0xde, 0x56, 0x6a, 0x53, 0x81, 0xad, 0x43, 0xdf, 0x99, 0x2a, 0x00, 0x08,
-	0x2e, 0x55, 0xf9, 0x1e, 0xd6, 0xd5, 0xd9, 0x27, 0x5f, 0x2d, 0xe7, 0x65, 0xd8, 0x5b, 0xcc, 0xf0,
-	0x4b, 0x0d, 0xd6, 0x67, 0xda, 0xc3, 0x54, 0x45, 0x5b, 0xdc, 0xd6, 0x9c, 0x6b, 0xaf, 0x6d, 0x2e,
-	0xee, 0x5d, 0xe4, 0x7d, 0x8f, 0xdc, 0x46, 0xd5, 0x8c, 0xd3, 0xbe, 0xee, 0x32, 0xde, 0xf1, 0xa2,
-	0xb6, 0x78, 0xe2, 0x1d, 0x9b, 0xde, 0x77, 0x72, 0xaf, 0xf7, 0xf4, 0x66, 0x18, 0x89, 0xe7, 0xe3,
-	0x67, 0xbd, 0x61, 0x96, 0x78, 0x0f, 0xa8, 0xa0, 0x0f, 0xb2, 0xd0, 0x1b, 0xd1, 0xc3, 0x11, 0xdd,
-	0x1f, 0x45, 0xe2, 0xf4, 0xff, 0x9c, 0xa7, 0xfe, 0xdf, 0x3d, 0x5b, 0xc1, 0xdf, 0xb7, 0xfe, 0x0d,
-	0x00, 0x00, 0xff, 0xff, 0xc1, 0x48, 0x67, 0x99, 0xf0, 0x0d, 0x00, 0x00,
+	// 2684 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x59, 0xcf, 0x73, 0x1c, 0x47,
+	0xf5, 0xaf, 0xd9, 0xd5, 0xaf, 0x7d, 0xbb, 0x2b, 0xc9, 0x6d, 0x79, 0xb5, 0x1a, 0xcb, 0xb1, 0x34,
+	0x8e, 0x13, 0x7d, 0xfd, 0x8d, 0xb4, 0x8e, 0x02, 0x24, 0x18, 0x52, 0xc1, 0x8e, 0x63, 0x93, 0x54,
+	0x02, 0x66, 0xbc, 0xa6, 0xf2, 0xa3, 0x60, 0x69, 0xed, 0xb4, 0x56, 0x83, 0x66, 0x67, 0x86, 0x99,
+	0x5e, 0xd9, 0x8a, 0xcb, 0x87, 0x00, 0x55, 0x14, 0x55, 0xdc, 0xa0, 0x0a, 0xae, 0x5c, 0x38, 0x72,
+	0xe1, 0x02, 0x47, 0xee, 0xdc, 0xf8, 0x0f, 0x28, 0xfe, 0x02, 0x0e, 0x9c, 0xa9, 0x7e, 0xdd, 0x3d,
+	0xd3, 0x33, 0x3b, 0x2b, 0x47, 0xce, 0x69, 0xa7, 0x5f, 0xbf, 0xfe, 0xbc, 0xd7, 0xaf, 0x5f, 0xbf,
+	0x1f, 0xbd, 0x70, 0x29, 0x4e, 0x22, 0x1e, 0xa5, 0xbd, 0x84, 0x8d, 0xfc, 0x94, 0x27, 0xa7, 0x7b,
+	0x38, 0x26, 0x4b, 0x7a, 0x6c, 0x6f, 0x8e, 0xa2, 0x68, 0x14, 0xb0, 0x1e, 0x8d, 0xfd, 0x1e, 0x0d,
+	0xc3, 0x88, 0x53, 0xee, 0x47, 0x61, 0x2a, 0xf9, 0x9c, 0x57, 0xa1, 0xd9, 0xa7, 0x23, 0x97, 0xa5,
+	0x71, 0x14, 0xa6, 0x8c, 0x74, 0x61, 0x71, 0xcc, 0xd2, 0x94, 0x8e, 0x58, 0xd7, 0xda, 0xb2, 0x76,
+	0x1a, 0xae, 0x1e, 0x3a, 0xbf, 0xb0, 0xa0, 0x7d, 0x27, 0x89, 0x8e, 0x59, 0xe2, 0xb2, 0x9f, 0x4d,
+	0x58, 0xca, 0xc9, 0x2a, 0xd4, 0x39, 0x1d, 0x75, 0xad, 0xad, 0xfa, 0x4e, 0xc3, 0x15, 0x9f, 0x64,
+	0x19, 0x6a, 0xbe, 0xd7, 0xad, 0x6d, 0x59, 0x3b, 0x6d, 0xb7, 0xe6, 0x7b, 0x64, 0x0d, 0xe6, 0x0f,
+	0xa3, 0x64, 0xc8, 0xba, 0xf5, 0x2d, 0x6b, 0x67, 0xc9, 0x95, 0x03, 0xb2, 0x01, 0x4b, 0xa3, 0x24,
+	0x9a, 0xc4, 0x83, 0x83, 0xd3, 0xee, 0x9c, 0x14, 0x82, 0xe3, 0x3b, 0xa7, 0xe4, 0x2a, 0x34, 0x39,
+	0x0f, 0x06, 0x29, 0x1b, 0x46, 0xa1, 0x97, 0x76, 0xe7, 0x11, 0x09, 0x38, 0x0f, 0x1e, 0x4a, 0x8a,
+	0xf3, 0xf7, 0x1a, 0x2c, 0x6b, 0x2d, 0x94, 0xca, 0xef, 0xc0, 0xe2, 0x01, 0x52, 0x04, 0x7f, 0x7d,
+	0xa7, 0xb9, 0x7f, 0x7d, 0x2f, 0xb3, 0x45, 0x91, 0x55, 0x0d, 0xd3, 0xf7, 0x42, 0x9e, 0x9c, 0xba,
+	0x7a, 0x95, 0xd8, 0x87, 0xef, 0xa5, 0xdd, 0x85, 0xad, 0xfa, 0x4e, 0xdb, 0x15, 0x9f, 0xe4, 0xdb,
+	0xb0, 0x80, 0x1a, 0xa5, 0xdd, 0x45, 0x44, 0x7c, 0x79, 0x26, 0xe2, 0x7d, 0x64, 0x93, 0x80, 0x6a,
+	0x8d, 0xfd, 0x21, 0xb4, 0x4c, 0x41, 0x02, 0xff, 0x98, 0x9d, 0xa2, 0x3d, 0xdb, 0xae, 0xf8, 0x24,
+	0xaf, 0xc0, 0xfc, 0x09, 0x0d, 0x26, 0x0c, 0x4d, 0xd5, 0xdc, 0x5f, 0x9d, 0x82, 0x97, 0xd3, 0xb7,
+	0x6a, 0x6f, 0x59, 0xf6, 0x03, 0x68, 0x1a, 0x42, 0x4c, 0xb0, 0x86, 0x04, 0xfb, 0xff, 0x22, 0xd8,
+	0xa5, 0x32, 0x18, 0xae, 0x36, 0x10, 0x9d, 0x2f, 0x2c, 0x68, 0x1a, 0x53, 0x7a, 0xff, 0x56, 0xbe,
+	0xff, 0x35, 0x98, 0x1f, 0x46, 0x93, 0x90, 0xab, 0xa3, 0x94, 0x03, 0xb2, 0x0d, 0xad, 0x94, 0x47,
+	0x09, 0x1d, 0xb1, 0xc1, 0x61, 0xc2, 0xe4, 0xa1, 0x5a, 0x6e, 0x53, 0xd1, 0xee, 0x25, 0x8c, 0x91,
+	0x57, 0x61, 0x45, 0xb3, 0x4c, 0xc2, 0xe3, 0x30, 0x7a, 0x1c, 0xe2, 0x09, 0x2f, 0xb9, 0xcb, 0x8a,
+	0xfc, 0x48, 0x52, 0x9d, 0x7d, 0xe8, 0x3c, 0x0a, 0xc7, 0x34, 0x8e, 0x99, 0xa7, 0x6c, 0xa5, 0xbd,
+	0xaa, 0x0b, 0x8b, 0xec, 0xc9, 0x30, 0x98, 0x78, 0x4c, 0x79, 0x96, 0x1e, 0x3a, 0x7b, 0x60, 0xdf,
+	0x65, 0xc3, 0x68, 0x3c, 0xf6, 0xd3, 0xd4, 0x8f, 0xc2, 0x07, 0x09, 0x3b, 0xf1, 0xd9, 0x63, 0xc3,
+	0x1b, 0x8b, 0xbb, 0x70, 0x7e, 0x65, 0xc1, 0xc5, 0x8a, 0x05, 0xa4, 0x03, 0x0b, 0x3c, 0x8a, 0xfd,
+	0x61, 0xaa, 0x04, 0xa8, 0x11, 0x79, 0x13, 0x20, 0xa6, 0x09, 0xf7, 0xf1, 0x7a, 0x74, 0x6b, 0x78,
+	0xf2, 0xeb, 0xb9, 0x35, 0x1f, 0xe8, 0xb9, 0x8f, 0xa2, 0x13, 0xe6, 0x1a, 0xac, 0xe8, 0xb5, 0x11,
+	0xa7, 0xc1, 0xe0, 0xe0, 0x94, 0xb3, 0x14, 0xed, 0x32, 0xe7, 0x02, 0x92, 0xee, 0x08, 0x8a, 0xf3,
+	0x47, 0x0b, 0xda, 0x85, 0xe5, 0xc2, 0xc2, 0x28, 0x55, 0x1d, 0xa4, 0x1c, 0x90, 0x4d, 0x68, 0x64,
+	0xb0, 0xca, 0xf6, 0x39, 0x81, 0xd8, 0xb0, 0x94, 0xb0, 0x38, 0xf0, 0x87, 0x54, 0xc8, 0x10, 0xdb,
+	0xcc, 0xc6, 0xe4, 0x0a, 0x40, 0xea, 0x7f, 0xce, 0x94, 0x06, 0x73, 0xa8, 0x41, 0x43, 0x50, 0x50,
+	0x01, 0x3c, 0x3a, 0xff, 0xf3, 0xfc, 0x50, 0xe6, 0xf1, 0x50, 0x9a, 0x82, 0xa6, 0x4f, 0xe4, 0x3f,
+	0x75, 0x58, 0x90, 0x47, 0x41, 0xf6, 0x60, 0x8e, 0xd3, 0x91, 0x34, 0x4f, 0x73, 0xdf, 0x2e, 0x3b,
+	0xd4, 0x5e, 0x9f, 0x8e, 0x94, 0xcb, 0x23, 0x9f, 0xba, 0xf6, 0xf3, 0xd9, 0xb5, 0x4f, 0xe1, 0x72,
+	0xe0, 0xa7, 0x9c, 0x85, 0x2c, 0x49, 0xd9, 0x70, 0x92, 0xf8, 0xfc, 0x14, 0x83, 0xcd, 0x30, 0x0a,
+	0xc6, 0x34, 0xc6, 0x8b, 0xd6, 0xdc, 0x7f, 0x7d, 0x0a, 0xf6, 0xc3, 0xd9, 0x6b, 0xa4, 0xb4, 0xb3,
+	0x50, 0x85, 0xed, 0x58, 0xe8, 0xc5, 0x91, 0x1f, 0x72, 0x79, 0x6d, 0x1b, 0x6e, 0x4e, 0x20, 0x04,
+	0xe6, 0x12, 0x3a, 0x3c, 0xee, 0x2e, 0xa1, 0xb9, 0xf1, 0x5b, 0x78, 0xda, 0x4f, 0xc7, 0x4f, 0xe2,
+	0x28, 0xe1, 0xdd, 0x06, 0xea, 0xae, 0x87, 0x82, 0xfb, 0x28, 0x4a, 0x79, 0x17, 0x24, 0xb7, 0xf8,
+	0x16, 0xf8, 0xdc, 0x1f, 0xb3, 0x94, 0xd3, 0x71, 0xdc, 0x6d, 0x6e, 0x59, 0x3b, 0x75, 0x37, 0x27,
+	0x88, 0x15, 0x08, 0xd4, 0x42, 0x20, 0xfc, 0x16, 0xf8, 0x27, 0x2c, 0x11, 0x9e, 0xd7, 0x6d, 0x4b,
+	0x7c, 0x35, 0xb4, 0xdf, 0x84, 0x46, 0x66, 0xc3, 0x8a, 0x1b, 0xbd, 0x66, 0xde, 0xe8, 0x86, 0x19,
+	0x0c, 0xbe, 0x07, 0x5b, 0xcf, 0xb3, 0xd2, 0x79, 0xf0, 0x9c, 0x47, 0xd0, 0xea, 0x0b, 0xcf, 0x9b,
+	0x1d, 0xd2, 0x09, 0xcc, 0x85, 0x74, 0xac, 0x97, 0xe2, 0x77, 0x39, 0x4a, 0xd7, 0xa7, 0xa2, 0xb4,
+	0x0f, 0xe4, 0xdd, 0x84, 0x51, 0xce, 0x0a, 0xe0, 0xd7, 0x4d, 0x9f, 0x6f, 0xee, 0xaf, 0xe4, 0x0e,
+	0x20, 0xd9, 0xe4, 0x2c, 0x79, 0x0d, 0x08, 0xa7, 0xc9, 0x88, 0xf1, 0x81, 0x0c, 0xd0, 0x03, 0xf4,
+	0xc5, 0x1a, 0xaa, 0xb4, 0x2a, 0x67, 0xa4, 0xc3, 0x08, 0x13, 0x3a, 0xbf, 0xb3, 0xa0, 0xeb, 0xca,
+	0x5b, 0x20, 0x2e, 0xc9, 0x3d, 0x3a, 0xe4, 0x51, 0x96, 0xa1, 0xb4, 0xf2, 0x96, 0xa1, 0xfc, 0x16,
+	0x34, 0x93, 0x9c, 0x5f, 0xdd, 0x32, 0x93, 0x34, 0x43, 0x81, 0x7a, 0xb5, 0x02, 0xc2, 0xb8, 0x34,
+	0x8e, 0x83, 0x53, 0x15, 0xe8, 0xe4, 0xc0, 0x79, 0x1f, 0x36, 0x2a, 0xb4, 0x52, 0x19, 0x4b, 0x38,
+	0x4b, 0x40, 0x43, 0xad, 0x96, 0xf8, 0x16, 0xce, 0x22, 0x56, 0xfa, 0x4c, 0xe6, 0xcf, 0x25, 0x57,
+	0x0f, 0x9d, 0x3f, 0x5b, 0xd0, 0x56, 0x76, 0x54, 0xeb, 0xbf, 0x95, 0x05, 0x30, 0x99, 0xf0, 0xae,
+	0x95, 0x2d, 0xa9, 0xb3, 0x13, 0x8e, 0x74, 0x76, 0x92, 0x4b, 0x84, 0xbe, 0xc2, 0x0e, 0x32, 0xdf,
+	0x35, 0x5c, 0x39, 0xb0, 0x3f, 0x80, 0xa6, 0xc1, 0x5c, 0xe1, 0x43, 0xd7, 0x8b, 0x59, 0x66, 0xfa,
+	0xf0, 0x72, 0xa7, 0xfa, 0x5b, 0x0d, 0xe6, 0x91, 0x48, 0x76, 0x0b, 0x81, 0x64, 0xa3, 0xb4, 0x66,
+	0x2a, 0x8e, 0xe8, 0xe3, 0x9a, 0x37, 0x8e, 0xeb, 0xa5, 0x42, 0x50, 0x5e, 0x90, 0xae, 0x96, 0x53,
+	0xca, 0xc7, 0xb9, 0x38, 0x7d, 0x9c, 0xdf, 0x80, 0xc5, 0x61, 0x14, 0x1e, 0xfa, 0xa3, 0xb4, 0xbb,
+	0x84, 0x7a, 0x6c, 0x96, 0xf5, 0x78, 0x57, 0x4e, 0xab, 0xb2, 0x40, 0x31, 0xbf, 0xf8, 0x25, 0xbd,
+	0x05, 0x2d, 0x13, 0xf1, 0x5c, 0x17, 0xf2, 0x5f, 0x35, 0xd8, 0x40, 0xa5, 0x24, 0x82, 0x8b, 0xe9,
+	0x8b, 0x85, 0x9e, 0xdc, 0x4a, 0x95, 0x3f, 0x6f, 0x43, 0x6b, 0x4c, 0xf9, 0xf0, 0x88, 0x79, 0xe6,
+	0x45, 0x69, 0x2a, 0x1a, 0xba, 0xe8, 0x07, 0xb9, 0x05, 0xea, 0x68, 0x81, 0x9b, 0x25, 0x0b, 0x54,
+	0x09, 0xab, 0xb6, 0x0a, 0x62, 0x1d, 0xd1, 0x70, 0x84, 0x59, 0xe6, 0xcb, 0x63, 0xc9, 0x25, 0x1a,
+	0x4b, 0x8e, 0xbe, 0x8a, 0xa1, 0x70, 0xad, 0x01, 0x7a, 0x2e, 0x23, 0x0f, 0xe0, 0xd2, 0xa3, 0xd0,
+	0x63, 0x89, 0x71, 0x43, 0xdf, 0x3b, 0x61, 0x21, 0xc6, 0x0b, 0x7e, 0x1a, 0x67, 0xf6, 0x15, 0xdf,
+	0x79, 0xa6, 0xae, 0x95, 0x32, 0x75, 0x9e, 0x0d, 0xea, 0xa5, 0x6c, 0xe0, 0x7c, 0x06, 0xed, 0xef,
+	0x1f, 0x1e, 0xa6, 0x8c, 0x7f, 0x44, 0xe3, 0xd8, 0x0f, 0x47, 0xa2, 0x2e, 0x9a, 0xc4, 0x29, 0x4f,
+	0x18, 0x1d, 0x0f, 0x22, 0x9c, 0x41, 0x19, 0x73, 0xee, 0xb2, 0x26, 0x4b, 0x7e, 0x71, 0x9a, 0x41,
+	0x34, 0xa4, 0x81, 0xe6, 0xaa, 0x21, 0x57, 0x13, 0x69, 0x92, 0xc5, 0x61, 0xd0, 0xe9, 0x27, 0x34,
+	0x4c, 0x03, 0xca, 0x99, 0x24, 0xe9, 0x70, 0x77, 0x13, 0xd6, 0x12, 0x36, 0x8e, 0x38, 0x1b, 0x0c,
+	0x83, 0x49, 0xca, 0x59, 0x32, 0xa0, 0x81, 0x4f, 0x53, 0xb5, 0x1d, 0x22, 0xe7, 0xde, 0x95, 0x53,
+	0xb7, 0xc5, 0x4c, 0x5e, 0x8a, 0xab, 0xb2, 0x5d, 0x97, 0xe2, 0xef, 0x7b, 0xce, 0x5f, 0x2d, 0x58,
+	0x9f, 0x92, 0xa3, 0x02, 0xd0, 0x77, 0x61, 0x51, 0xea, 0xa7, 0xaf, 0xf6, 0x9e, 0xe1, 0x04, 0xd5,
+	0x6b, 0xf6, 0xe4, 0x50, 0xbb, 0x80, 0x5a, 0x6e, 0x3f, 0x84, 0x96, 0x39, 0x51, 0x71, 0x8c, 0xbb,
+	0xc5, 0xc0, 0x63, 0x14, 0x64, 0x05, 0x13, 0x9b, 0xe7, 0xbb, 0x0d, 0x2b, 0x0f, 0x43, 0x1a, 0xa7,
+	0x47, 0x51, 0x66, 0x1a, 0x59, 0xa2, 0x48, 0xd8, 0x9a, 0xef, 0x39, 0xdf, 0x81, 0xd5, 0x9c, 0x45,
+	0xed, 0xaa, 0xc4, 0x53, 0x3c, 0xe3, 0x5a, 0xf9, 0x8c, 0xff, 0x6b, 0x41, 0x4b, 0x43, 0xdc, 0xf5,
+	0x0f, 0x0f, 0xc9, 0x35, 0x68, 0xab, 0x8e, 0x62, 0x40, 0x3d, 0x8f, 0x79, 0xaa, 0x14, 0x6d, 0x29,
+	0xe2, 0x6d, 0x41, 0x13, 0x8e, 0xa0, 0x99, 0xc4, 0x71, 0x9c, 0x60, 0xb8, 0x17, 0x6c, 0xcb, 0x07,
+	0xba, 0x0c, 0x46, 0xaa, 0xc9, 0x28, 0xaf, 0x8b, 0xd7, 0xad, 0x17, 0x18, 0xa5, 0xf7, 0x7b, 0xc2,
+	0x63, 0x64, 0x64, 0x57, 0x52, 0xe7, 0xe4, 0xfd, 0x97, 0x34, 0x29, 0xf4, 0x3a, 0x2c, 0x2b, 0x16,
+	0x2d, 0x73, 0x1e, 0x99, 0xda, 0x92, 0xaa, 0x45, 0xe6, 0x6c, 0x5a, 0xe2, 0x82, 0xc9, 0xa6, 0x04,
+	0x3a, 0xff, 0xb0, 0xe0, 0x25, 0x97, 0x1d, 0xd0, 0x80, 0x86, 0x43, 0x56, 0xbc, 0xe7, 0xda, 0xda,
+	0x6f, 0x41, 0x37, 0x0b, 0xd1, 0x83, 0xf4, 0x98, 0x3d, 0x1e, 0xf0, 0xa3, 0x84, 0xa5, 0x47, 0x51,
+	0x20, 0xed, 0x6b, 0xb9, 0x9d, 0x6c, 0xfe, 0xe1, 0x31, 0x7b, 0xdc, 0xd7, 0xb3, 0xe4, 0x6b, 0xd0,
+	0xd1, 0x0d, 0x44, 0x69, 0x5d, 0x0d, 0xd7, 0xad, 0xa9, 0xd9, 0xe2, 0xaa, 0x5b, 0xb0, 0x11, 0x30,
+	0xea, 0xb1, 0x24, 0x3d, 0xf2, 0xe3, 0xf2, 0x42, 0xd9, 0xa6, 0xac, 0xe7, 0x0c, 0x85, 0xb5, 0xce,
+	0x6f, 0x2c, 0x58, 0x9f, 0xb1, 0x1d, 0x99, 0x5c, 0x14, 0x85, 0x49, 0xd5, 0x97, 0x5c, 0x93, 0x24,
+	0x6a, 0xf2, 0x94, 0x9d, 0x30, 0x51, 0x88, 0xa9, 0x0b, 0x94, 0x8d, 0xc9, 0x1b, 0xa2, 0x97, 0xe6,
+	0x89, 0xc8, 0xd3, 0xf5, 0x72, 0x02, 0x7c, 0x7f, 0xac, 0x24, 0x7e, 0x84, 0x1c, 0xae, 0xe6, 0x74,
+	0xfe, 0x62, 0xc1, 0x4a, 0x69, 0xb2, 0x32, 0xec, 0x13, 0x98, 0x13, 0xfb, 0x54, 0x66, 0xc1, 0x6f,
+	0x74, 0xd8, 0xd2, 0xb6, 0x73, 0x02, 0xce, 0x26, 0xfe, 0x68, 0xc4, 0x12, 0xf4, 0x12, 0xb1, 0x95,
+	0x9c, 0x20, 0x1a, 0x88, 0xb1, 0x1f, 0xaa, 0x8a, 0x47, 0xd5, 0xf2, 0x8d, 0xb1, 0x1f, 0xaa, 0x96,
+	0x40, 0x4c, 0xd3, 0x27, 0x7a, 0x7a, 0x41, 0x4d, 0xd3, 0x27, 0x72, 0xda, 0xe9, 0x43, 0xeb, 0x2e,
+	0x4b, 0xfd, 0x84, 0x79, 0x0f, 0x39, 0xe5, 0xa2, 0x0f, 0x34, 0x5b, 0xac, 0x8a, 0x72, 0x41, 0x4d,
+	0x93, 0xcb, 0xd0, 0x88, 0x93, 0x49, 0xc8, 0x44, 0xee, 0x52, 0xa9, 0x6b, 0x09, 0x09, 0x7d, 0x3a,
+	0x72, 0x28, 0x10, 0x71, 0x20, 0xe1, 0xd0, 0x0f, 0x7c, 0x3c, 0x90, 0x07, 0xa2, 0x52, 0xea, 0xc1,
+	0x22, 0x1d, 0xca, 0x72, 0x40, 0x82, 0x5f, 0x2a, 0x81, 0xdf, 0xc6, 0x59, 0x57, 0x73, 0x89, 0x33,
+	0x7a, 0x4c, 0x93, 0xd0, 0x0f, 0xb3, 0xec, 0x98, 0x8d, 0x9d, 0x3f, 0xd5, 0xa1, 0x69, 0x2c, 0xaa,
+	0xcc, 0x00, 0x55, 0x25, 0x70, 0x56, 0xcb, 0xd6, 0xcf, 0xac, 0x65, 0xef, 0x41, 0x33, 0x65, 0x7c,
+	0xa0, 0xb3, 0xef, 0x5c, 0xf9, 0x7d, 0xc2, 0x10, 0xbd, 0xf7, 0x90, 0xf1, 0x42, 0xca, 0x85, 0x34,
+	0x23, 0x88, 0xab, 0xe9, 0xb1, 0x80, 0x89, 0xc8, 0xae, 0xa0, 0xd4, 0x0d, 0x96, 0x54, 0xcd, 0xf6,
+	0xb6, 0xf0, 0x46, 0x2e, 0xeb, 0x00, 0xd9, 0x65, 0x39, 0x33, 0x65, 0xe5, 0xc5, 0xd7, 0x62, 0x2a,
+	0x47, 0xa2, 0xae, 0x57, 0x52, 0x10, 0x41, 0x36, 0x51, 0x20, 0x49, 0x82, 0xc1, 0x7e, 0x1b, 0x56,
+	0x4a, 0x5a, 0x9e, 0x37, 0x67, 0x9b, 0x82, 0xcf, 0x95, 0xb3, 0xef, 0x41, 0xfb, 0xb6, 0xa8, 0xac,
+	0xb3, 0x68, 0xfd, 0x75, 0x58, 0x4c, 0x58, 0x3a, 0x09, 0xb2, 0x1c, 0x74, 0xb9, 0xda, 0x0d, 0x90,
+	0xc7, 0xd5, 0xbc, 0x4e, 0x02, 0x17, 0xa6, 0x66, 0xc9, 0x2e, 0x2c, 0x48, 0x67, 0x51, 0xad, 0xc9,
+	0x0c, 0x8f, 0x52, 0x4c, 0xb3, 0x6b, 0x75, 0xa1, 0x3f, 0x4b, 0x92, 0x28, 0x41, 0xb7, 0x68, 0xb8,
+	0x72, 0xe0, 0x1c, 0xc0, 0xca, 0xed, 0x89, 0xe7, 0xf3, 0x0f, 0xa3, 0x91, 0x8e, 0x90, 0x57, 0xa1,
+	0xc9, 0x42, 0xee, 0xf3, 0xd3, 0x81, 0xe1, 0x6e, 0x20, 0x49, 0x7d, 0xe1, 0x74, 0x1d, 0x58, 0x90,
+	0x23, 0x65, 0x0a, 0x35, 0x12, 0xf4, 0x31, 0xe3, 0x47, 0x91, 0xa7, 0x44, 0xa8, 0x91, 0x88, 0x1b,
+	0x80, 0x42, 0xa4, 0x69, 0x0b, 0xb9, 0xcb, 0x2a, 0x77, 0xab, 0x39, 0x48, 0xcd, 0x04, 0x29, 0x6b,
+	0x55, 0x3f, 0x43, 0xab, 0xb9, 0x82, 0x56, 0x9b, 0xd0, 0x48, 0xe4, 0xce, 0xa2, 0x44, 0x95, 0xef,
+	0x39, 0x41, 0xd8, 0x4b, 0x0d, 0x30, 0x72, 0x34, 0x5c, 0x3d, 0x74, 0x16, 0x61, 0xfe, 0xbd, 0x71,
+	0xcc, 0x4f, 0xf7, 0xbf, 0xe8, 0xc0, 0x92, 0xab, 0x6c, 0x4e, 0xfa, 0x00, 0xf7, 0x75, 0x8f, 0x95,
+	0x92, 0xf5, 0xe9, 0xc7, 0x37, 0x5c, 0x6c, 0x77, 0x67, 0xbd, 0xca, 0x39, 0x17, 0x7f, 0xfe, 0xcf,
+	0x7f, 0xff, 0xb6, 0xd6, 0x26, 0xcd, 0xde, 0xc9, 0xeb, 0x3d, 0xfd, 0xcc, 0xf7, 0x29, 0x34, 0x45,
+	0xef, 0xfc, 0x15, 0x60, 0xbb, 0x08, 0x4b, 0xc8, 0xaa, 0x01, 0xdb, 0x0b, 0xfc, 0x94, 0x93, 0x63,
+	0x58, 0x29, 0x3d, 0x67, 0x91, 0xad, 0x1c, 0xa6, 0xfa, 0xa5, 0xeb, 0x0c, 0x41, 0x9b, 0x28, 0xa8,
+	0x43, 0xd6, 0x4c, 0x41, 0x13, 0x85, 0x42, 0x1e, 0x40, 0xe3, 0x3e, 0xe3, 0xb2, 0x5d, 0x23, 0x9d,
+	0xa9, 0xde, 0x4f, 0x82, 0xaf, 0xcf, 0xe8, 0x09, 0x1d, 0x82, 0xd8, 0x2d, 0x02, 0x02, 0x5b, 0x45,
+	0xe1, 0x1f, 0x02, 0x08, 0xd3, 0xbc, 0x28, 0xe4, 0x3a, 0x42, 0x5e, 0x20, 0x2b, 0x39, 0xa4, 0x34,
+	0xcb, 0xa7, 0xd0, 0x34, 0xde, 0x01, 0x88, 0xd1, 0x78, 0x4d, 0x3f, 0x0f, 0xd8, 0x46, 0x0c, 0x45,
+	0x9f, 0xd0, 0x56, 0x70, 0x2e, 0x18, 0xb0, 0x43, 0x5c, 0x77, 0xcb, 0xba, 0x41, 0x7e, 0x00, 0xcd,
+	0xbb, 0x32, 0x32, 0x21, 0xf6, 0x2c, 0xa5, 0xa7, 0x50, 0x37, 0x10, 0xf5, 0xe2, 0x0d, 0x13, 0xf5,
+	0xa9, 0x88, 0xe9, 0xcf, 0xc8, 0xaf, 0x2d, 0x58, 0x97, 0x55, 0xce, 0x54, 0xef, 0x4e, 0x8c, 0x40,
+	0x3a, 0xeb, 0xb9, 0xc1, 0xbe, 0x76, 0x26, 0x8f, 0x32, 0xd6, 0x75, 0x94, 0x7f, 0xd5, 0xbe, 0x62,
+	0xc8, 0x37, 0xda, 0x55, 0xad, 0xcb, 0x8f, 0xe0, 0x82, 0xcb, 0x68, 0x9a, 0xfa, 0x23, 0x91, 0xa8,
+	0xd4, 0xc9, 0x94, 0x37, 0x33, 0xfb, 0x48, 0x5e, 0x42, 0x29, 0x5d, 0xd2, 0x29, 0x48, 0xc9, 0xf0,
+	0x08, 0x2b, 0xb5, 0x40, 0xcc, 0x3b, 0xb7, 0x08, 0x07, 0x45, 0x6c, 0x12, 0xdb, 0x10, 0x31, 0x11,
+	0x98, 0x49, 0x86, 0x49, 0x9e, 0x82, 0xdd, 0xa7, 0x7e, 0x50, 0xd9, 0x6d, 0x55, 0xc8, 0xba, 0x6a,
+	0xde, 0x99, 0x8a, 0x25, 0xce, 0xff, 0xa1, 0xcc, 0x6b, 0x64, 0x7b, 0xb6, 0xcc, 0x1e, 0x43, 0xf0,
+	0x9b, 0x16, 0xf9, 0xc2, 0x82, 0xb5, 0xac, 0xa0, 0x33, 0x7a, 0xd3, 0x99, 0xbe, 0x72, 0xed, 0x4b,
+	0xb4, 0xb2, 0xce, 0x6b, 0xa8, 0xc2, 0x2b, 0xe4, 0xe5, 0x82, 0x65, 0xb3, 0x8a, 0x70, 0x57, 0x26,
+	0x68, 0x7d, 0x8c, 0x9e, 0x7a, 0xbb, 0x51, 0x5d, 0xca, 0xec, 0xcb, 0x35, 0x3b, 0x18, 0x6c, 0xa3,
+	0xc0, 0xcb, 0x64, 0x43, 0x08, 0x1c, 0x2b, 0x1c, 0x29, 0x39, 0x97, 0xa2, 0xfe, 0x14, 0xc9, 0xc4,
+	0xcc, 0x8c, 0x6e, 0x33, 0x8f, 0x73, 0x0b, 0xc5, 0xd8, 0xa4, 0x5b, 0x10, 0x23, 0x83, 0x4f, 0xef,
+	0xa9, 0xef, 0x3d, 0x23, 0x1f, 0xc3, 0x52, 0x9f, 0x8e, 0xce, 0xbe, 0x6e, 0x66, 0xe6, 0xcc, 0xff,
+	0x56, 0x72, 0xae, 0x20, 0xf8, 0xba, 0x7d, 0xc9, 0x30, 0x1a, 0xa7, 0x99, 0x95, 0x06, 0xb0, 0x62,
+	0xdc, 0x65, 0xac, 0x45, 0x5e, 0x4c, 0xc0, 0x8d, 0x19, 0x02, 0x3e, 0xc1, 0xb7, 0x1c, 0x55, 0xcb,
+	0xce, 0xb4, 0xcd, 0x0c, 0x6c, 0x15, 0x87, 0xec, 0x42, 0x34, 0x46, 0x70, 0x61, 0x95, 0x9f, 0xc0,
+	0xaa, 0xd4, 0xdd, 0x78, 0x13, 0x7c, 0x41, 0x09, 0x37, 0xaa, 0x25, 0x7c, 0x0c, 0x2d, 0xd9, 0xa2,
+	0xbd, 0xa0, 0xfe, 0x2a, 0x6d, 0xdd, 0x28, 0xa4, 0x2d, 0x44, 0xfe, 0xa5, 0x05, 0x17, 0xd5, 0xbf,
+	0x22, 0xe6, 0x1f, 0x25, 0xc4, 0xf8, 0xbf, 0x6b, 0xf6, 0x3f, 0x2e, 0xf6, 0x95, 0x33, 0xb9, 0x9c,
+	0x1d, 0x14, 0xeb, 0x90, 0x2d, 0x53, 0xac, 0x67, 0x30, 0xf6, 0x62, 0xc9, 0x49, 0xfe, 0x60, 0xc1,
+	0x6a, 0xe9, 0xd9, 0xa0, 0x90, 0x3f, 0xab, 0x9f, 0x3b, 0xec, 0xed, 0xe7, 0x3e, 0x3a, 0x38, 0xef,
+	0xa0, 0x0e, 0xdf, 0x24, 0x6f, 0xa2, 0x5b, 0x68, 0xa6, 0x5d, 0xf5, 0xfa, 0xd0, 0x7b, 0x5a, 0xf5,
+	0x5c, 0xf2, 0xac, 0xf7, 0x54, 0xbf, 0x89, 0x3c, 0x23, 0x7d, 0x58, 0x96, 0xa9, 0x4a, 0xb7, 0xfa,
+	0xd3, 0x41, 0xcb, 0xf8, 0x7f, 0xa4, 0xfc, 0xa4, 0xe0, 0x5c, 0x42, 0xf9, 0x2b, 0x4e, 0x5b, 0xc8,
+	0x4f, 0xd5, 0x6c, 0x4a, 0x0e, 0xa0, 0x25, 0x9e, 0x0c, 0x32, 0xcc, 0x8d, 0x2a, 0x08, 0xb9, 0xc9,
+	0xce, 0xf4, 0x94, 0x58, 0xea, 0x5c, 0x45, 0xe4, 0x0d, 0xb2, 0x5e, 0x40, 0xc6, 0x63, 0xed, 0x79,
+	0xe2, 0x39, 0xe2, 0xf7, 0x16, 0xd8, 0xf7, 0x85, 0x29, 0xaa, 0x5b, 0xdb, 0x1d, 0x33, 0x57, 0x9d,
+	0xd5, 0xcc, 0xdb, 0xdb, 0xcf, 0xe5, 0x2c, 0xc6, 0x44, 0x65, 0xcc, 0x5e, 0xa2, 0x99, 0x77, 0x93,
+	0xa2, 0xe8, 0xcf, 0x60, 0x0e, 0x1b, 0xb9, 0x8e, 0xe9, 0x3f, 0x79, 0xf3, 0x68, 0x6f, 0x9a, 0x02,
+	0xcb, 0xed, 0x9f, 0xbe, 0xe9, 0x0e, 0x11, 0xb2, 0x12, 0x35, 0xcf, 0x7a, 0xe2, 0x11, 0x5d, 0x94,
+	0x05, 0x3f, 0x86, 0x79, 0xec, 0x13, 0xc8, 0x99, 0x28, 0x66, 0x18, 0x2c, 0xb4, 0x15, 0x3a, 0x71,
+	0x3a, 0x17, 0x8b, 0xf0, 0xf8, 0xaa, 0x2f, 0xf0, 0x3f, 0x81, 0x96, 0xc8, 0x68, 0xba, 0x9e, 0x37,
+	0x8f, 0xae, 0x54, 0xe3, 0xdb, 0x6b, 0xa5, 0x29, 0xac, 0xcc, 0x9d, 0x0e, 0x0a, 0x58, 0x25, 0xcb,
+	0x42, 0x00, 0x15, 0xf4, 0x1e, 0xa7, 0x7e, 0x70, 0xd3, 0xba, 0xb3, 0xf7, 0xe9, 0x6b, 0x23, 0x9f,
+	0x1f, 0x4d, 0x0e, 0xf6, 0x86, 0xd1, 0xb8, 0x77, 0x97, 0x72, 0x7a, 0x37, 0x1a, 0xf5, 0x8e, 0xe9,
+	0xe1, 0x31, 0xdd, 0x3d, 0xf6, 0x79, 0xf6, 0x0f, 0x7f, 0x4f, 0xfe, 0xe3, 0x7f, 0xb0, 0x80, 0xbf,
+	0x6f, 0xfc, 0x6f, 0x00, 0x1b, 0x7d, 0xf5, 0x48, 0x02, 0x20, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -817,6 +2227,9 @@ type RegistryClient interface {
 	// a single broker ID is returned matching the ID specified in the
 	// Broker object if the broker exists. Otherwise all brokers are returned,
 	// optionally filtered by any provided BrokerRequest.tags parameters.
+	// If the BrokerRequest.group_by field is set, the groups field is also
+	// populated with the matched brokers bucketed by the value of the
+	// specified tag key or broker field (e.g. rack).
 	ListBrokers(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*BrokerResponse, error)
 	// UnmappedBrokers returns a BrokerResponse with the ids field
 	// populated with broker IDs that do not hold any assigned partitions.
@@ -839,7 +2252,8 @@ type RegistryClient interface {
 	// optionally filtered by any provided TopicRequest.tags parameters.
 	ListTopics(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicResponse, error)
 	//
-	//CreateTopic creates a topic.
+	//CreateTopic creates a topic. Brokers tagged maintenance:true aren't assigned
+	//replicas.
 	//Example:
 	//$ curl -XPOST "localhost:8080/v1/topics/create" -d '{
 	//"topic": {
@@ -857,12 +2271,30 @@ type RegistryClient interface {
 	//Example:
 	//$ curl -XDELETE "localhost:8080/v1/topics/mytopic"
 	DeleteTopic(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*Empty, error)
+	//
+	//ChangeReplicationFactor takes a ReplicationFactorRequest and builds a
+	//reassignment plan setting the replication factor of the topic specified in
+	//the ReplicationFactorRequest.name field. New replicas are placed using the
+	//topicmappr placement constraints, optionally scoped to brokers by tag.
+	//Brokers tagged maintenance:true are excluded as targets. The plan is returned
+	//and is applied if ReplicationFactorRequest.apply is true.
+	//Example:
+	//$ curl -XPUT "localhost:8080/v1/topics/replication/mytopic?replication=3&apply=true"
+	ChangeReplicationFactor(ctx context.Context, in *ReplicationFactorRequest, opts ...grpc.CallOption) (*ReplicationFactorResponse, error)
 	// ReassigningTopics returns a TopicResponse with the names field populated
 	// with topic names of all topics undergoing a reassignment.
 	ReassigningTopics(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TopicResponse, error)
 	// UnderReplicatedTopics returns a TopicResponse with the names field populated
 	// with topic names of all under replicated topics.
 	UnderReplicatedTopics(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TopicResponse, error)
+	// TailUnderReplicationEvents streams an UnderReplicationEvent each time a
+	// topic becomes or recovers from being under-replicated while subscribed.
+	// The under-replication monitor must be enabled.
+	TailUnderReplicationEvents(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Registry_TailUnderReplicationEventsClient, error)
+	// RecommendTopicConfig returns a TopicConfigRecommendation for the topic
+	// specified in the TopicRequest.name field. Recommended configs are sourced
+	// from the configured topic config rules whose tag is set on the topic.
+	RecommendTopicConfig(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicConfigRecommendation, error)
 	// TopicMappings returns a BrokerResponse with the ids field
 	// populated with broker IDs that hold at least one partition
 	// for the requested topic. Both a single topic name or specified in the
@@ -889,6 +2321,16 @@ type RegistryClient interface {
 	// specified tags for the named broker. Tags must be provided
 	// as key names only; "key:value" will not target the tag "key".
 	DeleteBrokerTags(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error)
+	// RemoveBroker takes a BrokerRequest and removes all registry state (e.g.
+	// custom tags) held for the specified broker. Removal is refused if the
+	// broker still holds partition replicas, unless BrokerRequest.force is set.
+	RemoveBroker(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error)
+	// PreviewDecommission takes a DecommissionPreviewRequest and returns a
+	// DecommissionPreview listing all topics and partitions with replicas on the
+	// specified brokers, along with an estimate of the bytes that would need to
+	// be moved to decommission the brokers. Size estimates are sourced from the
+	// partition metrics stored in ZooKeeper (see metricsfetcher).
+	PreviewDecommission(ctx context.Context, in *DecommissionPreviewRequest, opts ...grpc.CallOption) (*DecommissionPreview, error)
 	// TranslateOffsets returns a TranslateOffsetResponse with the
 	// the upstream/local offsets for the provided consumer group
 	// populated per topic/partition.
@@ -896,6 +2338,38 @@ type RegistryClient interface {
 	// in the TranslateOffsetRequest.remote_cluster_alias and
 	// TranslateOffsetRequest.group_id respectively.
 	TranslateOffsets(ctx context.Context, in *TranslateOffsetRequest, opts ...grpc.CallOption) (*TranslateOffsetResponse, error)
+	// CreateSnapshot captures the current broker and topic metadata, including
+	// tags, and stores it. A SnapshotResponse with the snapshot id is returned.
+	CreateSnapshot(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SnapshotResponse, error)
+	// DiffSnapshot returns a SnapshotDiff describing the brokers and topics
+	// that were added, removed or changed between the snapshot specified in the
+	// SnapshotRequest.id field and the current cluster state.
+	DiffSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*SnapshotDiff, error)
+	// GetRebalanceRecommendation evaluates the current partition count, free
+	// storage and leadership skew across all brokers against the thresholds in
+	// the RebalanceRecommendationRequest and returns a RebalanceRecommendation
+	// with a severity and the evaluated metrics. Unset thresholds use defaults.
+	// Brokers tagged maintenance:true aren't evaluated.
+	GetRebalanceRecommendation(ctx context.Context, in *RebalanceRecommendationRequest, opts ...grpc.CallOption) (*RebalanceRecommendation, error)
+	//
+	//Plan takes a DesiredState and returns a ReconciliationPlan listing the topic
+	//creates, config and tag updates, and deletes required for the cluster to
+	//match the desired state. Nothing is applied.
+	//Example:
+	//$ curl -XPOST "localhost:8080/v1/reconcile/plan" -d '{
+	//"topics": [{"name": "mytopic", "partitions": 32, "replication": 2,
+	//"configs": {"retention.ms": "86400000"}, "tags": {"team": "eng"}}],
+	//"prune_tag": ["managed:true"]
+	//}'
+	Plan(ctx context.Context, in *DesiredState, opts ...grpc.CallOption) (*ReconciliationPlan, error)
+	// Apply takes a ReconciliationPlan, as returned by Plan, and applies each
+	// action in order. An ApplyResponse with the result of each action is
+	// returned; a failed action doesn't prevent subsequent actions.
+	Apply(ctx context.Context, in *ReconciliationPlan, opts ...grpc.CallOption) (*ApplyResponse, error)
+	// TailAuditLog streams an AuditEntry for each mutating request completed
+	// successfully while subscribed. Entries can be filtered by the
+	// AuditLogRequest entity_type, entity and method fields.
+	TailAuditLog(ctx context.Context, in *AuditLogRequest, opts ...grpc.CallOption) (Registry_TailAuditLogClient, error)
 }
 
 type registryClient struct {
@@ -969,6 +2443,15 @@ func (c *registryClient) DeleteTopic(ctx context.Context, in *TopicRequest, opts
 	return out, nil
 }
 
+func (c *registryClient) ChangeReplicationFactor(ctx context.Context, in *ReplicationFactorRequest, opts ...grpc.CallOption) (*ReplicationFactorResponse, error) {
+	out := new(ReplicationFactorResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/ChangeReplicationFactor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *registryClient) ReassigningTopics(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TopicResponse, error) {
 	out := new(TopicResponse)
 	err := c.cc.Invoke(ctx, "/registry.Registry/ReassigningTopics", in, out, opts...)
@@ -987,6 +2470,47 @@ func (c *registryClient) UnderReplicatedTopics(ctx context.Context, in *Empty, o
 	return out, nil
 }
 
+func (c *registryClient) TailUnderReplicationEvents(ctx context.Context, in *Empty, opts ...grpc.CallOption) (Registry_TailUnderReplicationEventsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Registry_serviceDesc.Streams[0], "/registry.Registry/TailUnderReplicationEvents", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &registryTailUnderReplicationEventsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Registry_TailUnderReplicationEventsClient interface {
+	Recv() (*UnderReplicationEvent, error)
+	grpc.ClientStream
+}
+
+type registryTailUnderReplicationEventsClient struct {
+	grpc.ClientStream
+}
+
+func (x *registryTailUnderReplicationEventsClient) Recv() (*UnderReplicationEvent, error) {
+	m := new(UnderReplicationEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *registryClient) RecommendTopicConfig(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*TopicConfigRecommendation, error) {
+	out := new(TopicConfigRecommendation)
+	err := c.cc.Invoke(ctx, "/registry.Registry/RecommendTopicConfig", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *registryClient) TopicMappings(ctx context.Context, in *TopicRequest, opts ...grpc.CallOption) (*BrokerResponse, error) {
 	out := new(BrokerResponse)
 	err := c.cc.Invoke(ctx, "/registry.Registry/TopicMappings", in, out, opts...)
@@ -1041,6 +2565,24 @@ func (c *registryClient) DeleteBrokerTags(ctx context.Context, in *BrokerRequest
 	return out, nil
 }
 
+func (c *registryClient) RemoveBroker(ctx context.Context, in *BrokerRequest, opts ...grpc.CallOption) (*TagResponse, error) {
+	out := new(TagResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/RemoveBroker", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) PreviewDecommission(ctx context.Context, in *DecommissionPreviewRequest, opts ...grpc.CallOption) (*DecommissionPreview, error) {
+	out := new(DecommissionPreview)
+	err := c.cc.Invoke(ctx, "/registry.Registry/PreviewDecommission", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *registryClient) TranslateOffsets(ctx context.Context, in *TranslateOffsetRequest, opts ...grpc.CallOption) (*TranslateOffsetResponse, error) {
 	out := new(TranslateOffsetResponse)
 	err := c.cc.Invoke(ctx, "/registry.Registry/TranslateOffsets", in, out, opts...)
@@ -1050,6 +2592,83 @@ func (c *registryClient) TranslateOffsets(ctx context.Context, in *TranslateOffs
 	return out, nil
 }
 
+func (c *registryClient) CreateSnapshot(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*SnapshotResponse, error) {
+	out := new(SnapshotResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/CreateSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) DiffSnapshot(ctx context.Context, in *SnapshotRequest, opts ...grpc.CallOption) (*SnapshotDiff, error) {
+	out := new(SnapshotDiff)
+	err := c.cc.Invoke(ctx, "/registry.Registry/DiffSnapshot", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) GetRebalanceRecommendation(ctx context.Context, in *RebalanceRecommendationRequest, opts ...grpc.CallOption) (*RebalanceRecommendation, error) {
+	out := new(RebalanceRecommendation)
+	err := c.cc.Invoke(ctx, "/registry.Registry/GetRebalanceRecommendation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Plan(ctx context.Context, in *DesiredState, opts ...grpc.CallOption) (*ReconciliationPlan, error) {
+	out := new(ReconciliationPlan)
+	err := c.cc.Invoke(ctx, "/registry.Registry/Plan", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) Apply(ctx context.Context, in *ReconciliationPlan, opts ...grpc.CallOption) (*ApplyResponse, error) {
+	out := new(ApplyResponse)
+	err := c.cc.Invoke(ctx, "/registry.Registry/Apply", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *registryClient) TailAuditLog(ctx context.Context, in *AuditLogRequest, opts ...grpc.CallOption) (Registry_TailAuditLogClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Registry_serviceDesc.Streams[1], "/registry.Registry/TailAuditLog", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &registryTailAuditLogClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Registry_TailAuditLogClient interface {
+	Recv() (*AuditEntry, error)
+	grpc.ClientStream
+}
+
+type registryTailAuditLogClient struct {
+	grpc.ClientStream
+}
+
+func (x *registryTailAuditLogClient) Recv() (*AuditEntry, error) {
+	m := new(AuditEntry)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // RegistryServer is the server API for Registry service.
 type RegistryServer interface {
 	// GetBrokers returns a BrokerResponse with the brokers field populated
@@ -1063,6 +2682,9 @@ type RegistryServer interface {
 	// a single broker ID is returned matching the ID specified in the
 	// Broker object if the broker exists. Otherwise all brokers are returned,
 	// optionally filtered by any provided BrokerRequest.tags parameters.
+	// If the BrokerRequest.group_by field is set, the groups field is also
+	// populated with the matched brokers bucketed by the value of the
+	// specified tag key or broker field (e.g. rack).
 	ListBrokers(context.Context, *BrokerRequest) (*BrokerResponse, error)
 	// UnmappedBrokers returns a BrokerResponse with the ids field
 	// populated with broker IDs that do not hold any assigned partitions.
@@ -1085,7 +2707,8 @@ type RegistryServer interface {
 	// optionally filtered by any provided TopicRequest.tags parameters.
 	ListTopics(context.Context, *TopicRequest) (*TopicResponse, error)
 	//
-	//CreateTopic creates a topic.
+	//CreateTopic creates a topic. Brokers tagged maintenance:true aren't assigned
+	//replicas.
 	//Example:
 	//$ curl -XPOST "localhost:8080/v1/topics/create" -d '{
 	//"topic": {
@@ -1103,12 +2726,30 @@ type RegistryServer interface {
 	//Example:
 	//$ curl -XDELETE "localhost:8080/v1/topics/mytopic"
 	DeleteTopic(context.Context, *TopicRequest) (*Empty, error)
+	//
+	//ChangeReplicationFactor takes a ReplicationFactorRequest and builds a
+	//reassignment plan setting the replication factor of the topic specified in
+	//the ReplicationFactorRequest.name field. New replicas are placed using the
+	//topicmappr placement constraints, optionally scoped to brokers by tag.
+	//Brokers tagged maintenance:true are excluded as targets. The plan is returned
+	//and is applied if ReplicationFactorRequest.apply is true.
+	//Example:
+	//$ curl -XPUT "localhost:8080/v1/topics/replication/mytopic?replication=3&apply=true"
+	ChangeReplicationFactor(context.Context, *ReplicationFactorRequest) (*ReplicationFactorResponse, error)
 	// ReassigningTopics returns a TopicResponse with the names field populated
 	// with topic names of all topics undergoing a reassignment.
 	ReassigningTopics(context.Context, *Empty) (*TopicResponse, error)
 	// UnderReplicatedTopics returns a TopicResponse with the names field populated
 	// with topic names of all under replicated topics.
 	UnderReplicatedTopics(context.Context, *Empty) (*TopicResponse, error)
+	// TailUnderReplicationEvents streams an UnderReplicationEvent each time a
+	// topic becomes or recovers from being under-replicated while subscribed.
+	// The under-replication monitor must be enabled.
+	TailUnderReplicationEvents(*Empty, Registry_TailUnderReplicationEventsServer) error
+	// RecommendTopicConfig returns a TopicConfigRecommendation for the topic
+	// specified in the TopicRequest.name field. Recommended configs are sourced
+	// from the configured topic config rules whose tag is set on the topic.
+	RecommendTopicConfig(context.Context, *TopicRequest) (*TopicConfigRecommendation, error)
 	// TopicMappings returns a BrokerResponse with the ids field
 	// populated with broker IDs that hold at least one partition
 	// for the requested topic. Both a single topic name or specified in the
@@ -1135,6 +2776,16 @@ type RegistryServer interface {
 	// specified tags for the named broker. Tags must be provided
 	// as key names only; "key:value" will not target the tag "key".
 	DeleteBrokerTags(context.Context, *BrokerRequest) (*TagResponse, error)
+	// RemoveBroker takes a BrokerRequest and removes all registry state (e.g.
+	// custom tags) held for the specified broker. Removal is refused if the
+	// broker still holds partition replicas, unless BrokerRequest.force is set.
+	RemoveBroker(context.Context, *BrokerRequest) (*TagResponse, error)
+	// PreviewDecommission takes a DecommissionPreviewRequest and returns a
+	// DecommissionPreview listing all topics and partitions with replicas on the
+	// specified brokers, along with an estimate of the bytes that would need to
+	// be moved to decommission the brokers. Size estimates are sourced from the
+	// partition metrics stored in ZooKeeper (see metricsfetcher).
+	PreviewDecommission(context.Context, *DecommissionPreviewRequest) (*DecommissionPreview, error)
 	// TranslateOffsets returns a TranslateOffsetResponse with the
 	// the upstream/local offsets for the provided consumer group
 	// populated per topic/partition.
@@ -1142,6 +2793,38 @@ type RegistryServer interface {
 	// in the TranslateOffsetRequest.remote_cluster_alias and
 	// TranslateOffsetRequest.group_id respectively.
 	TranslateOffsets(context.Context, *TranslateOffsetRequest) (*TranslateOffsetResponse, error)
+	// CreateSnapshot captures the current broker and topic metadata, including
+	// tags, and stores it. A SnapshotResponse with the snapshot id is returned.
+	CreateSnapshot(context.Context, *Empty) (*SnapshotResponse, error)
+	// DiffSnapshot returns a SnapshotDiff describing the brokers and topics
+	// that were added, removed or changed between the snapshot specified in the
+	// SnapshotRequest.id field and the current cluster state.
+	DiffSnapshot(context.Context, *SnapshotRequest) (*SnapshotDiff, error)
+	// GetRebalanceRecommendation evaluates the current partition count, free
+	// storage and leadership skew across all brokers against the thresholds in
+	// the RebalanceRecommendationRequest and returns a RebalanceRecommendation
+	// with a severity and the evaluated metrics. Unset thresholds use defaults.
+	// Brokers tagged maintenance:true aren't evaluated.
+	GetRebalanceRecommendation(context.Context, *RebalanceRecommendationRequest) (*RebalanceRecommendation, error)
+	//
+	//Plan takes a DesiredState and returns a ReconciliationPlan listing the topic
+	//creates, config and tag updates, and deletes required for the cluster to
+	//match the desired state. Nothing is applied.
+	//Example:
+	//$ curl -XPOST "localhost:8080/v1/reconcile/plan" -d '{
+	//"topics": [{"name": "mytopic", "partitions": 32, "replication": 2,
+	//"configs": {"retention.ms": "86400000"}, "tags": {"team": "eng"}}],
+	//"prune_tag": ["managed:true"]
+	//}'
+	Plan(context.Context, *DesiredState) (*ReconciliationPlan, error)
+	// Apply takes a ReconciliationPlan, as returned by Plan, and applies each
+	// action in order. An ApplyResponse with the result of each action is
+	// returned; a failed action doesn't prevent subsequent actions.
+	Apply(context.Context, *ReconciliationPlan) (*ApplyResponse, error)
+	// TailAuditLog streams an AuditEntry for each mutating request completed
+	// successfully while subscribed. Entries can be filtered by the
+	// AuditLogRequest entity_type, entity and method fields.
+	TailAuditLog(*AuditLogRequest, Registry_TailAuditLogServer) error
 }
 
 func RegisterRegistryServer(s *grpc.Server, srv RegistryServer) {
@@ -1274,6 +2957,24 @@ func _Registry_DeleteTopic_Handler(srv interface{}, ctx context.Context, dec fun
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Registry_ChangeReplicationFactor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplicationFactorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).ChangeReplicationFactor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/ChangeReplicationFactor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).ChangeReplicationFactor(ctx, req.(*ReplicationFactorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Registry_ReassigningTopics_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(Empty)
 	if err := dec(in); err != nil {
@@ -1310,6 +3011,45 @@ func _Registry_UnderReplicatedTopics_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Registry_TailUnderReplicationEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RegistryServer).TailUnderReplicationEvents(m, &registryTailUnderReplicationEventsServer{stream})
+}
+
+type Registry_TailUnderReplicationEventsServer interface {
+	Send(*UnderReplicationEvent) error
+	grpc.ServerStream
+}
+
+type registryTailUnderReplicationEventsServer struct {
+	grpc.ServerStream
+}
+
+func (x *registryTailUnderReplicationEventsServer) Send(m *UnderReplicationEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Registry_RecommendTopicConfig_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopicRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).RecommendTopicConfig(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/RecommendTopicConfig",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).RecommendTopicConfig(ctx, req.(*TopicRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Registry_TopicMappings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TopicRequest)
 	if err := dec(in); err != nil {
@@ -1418,6 +3158,42 @@ func _Registry_DeleteBrokerTags_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Registry_RemoveBroker_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BrokerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).RemoveBroker(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/RemoveBroker",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).RemoveBroker(ctx, req.(*BrokerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_PreviewDecommission_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecommissionPreviewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).PreviewDecommission(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/PreviewDecommission",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).PreviewDecommission(ctx, req.(*DecommissionPreviewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _Registry_TranslateOffsets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TranslateOffsetRequest)
 	if err := dec(in); err != nil {
@@ -1436,6 +3212,117 @@ func _Registry_TranslateOffsets_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Registry_CreateSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).CreateSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/CreateSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).CreateSnapshot(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_DiffSnapshot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SnapshotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).DiffSnapshot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/DiffSnapshot",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).DiffSnapshot(ctx, req.(*SnapshotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_GetRebalanceRecommendation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RebalanceRecommendationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).GetRebalanceRecommendation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/GetRebalanceRecommendation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).GetRebalanceRecommendation(ctx, req.(*RebalanceRecommendationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_Plan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DesiredState)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).Plan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/Plan",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).Plan(ctx, req.(*DesiredState))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_Apply_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReconciliationPlan)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RegistryServer).Apply(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/registry.Registry/Apply",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RegistryServer).Apply(ctx, req.(*ReconciliationPlan))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Registry_TailAuditLog_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(AuditLogRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(RegistryServer).TailAuditLog(m, &registryTailAuditLogServer{stream})
+}
+
+type Registry_TailAuditLogServer interface {
+	Send(*AuditEntry) error
+	grpc.ServerStream
+}
+
+type registryTailAuditLogServer struct {
+	grpc.ServerStream
+}
+
+func (x *registryTailAuditLogServer) Send(m *AuditEntry) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _Registry_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "registry.Registry",
 	HandlerType: (*RegistryServer)(nil),
@@ -1468,6 +3355,10 @@ var _Registry_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteTopic",
 			Handler:    _Registry_DeleteTopic_Handler,
 		},
+		{
+			MethodName: "ChangeReplicationFactor",
+			Handler:    _Registry_ChangeReplicationFactor_Handler,
+		},
 		{
 			MethodName: "ReassigningTopics",
 			Handler:    _Registry_ReassigningTopics_Handler,
@@ -1476,6 +3367,10 @@ var _Registry_serviceDesc = grpc.ServiceDesc{
 			MethodName: "UnderReplicatedTopics",
 			Handler:    _Registry_UnderReplicatedTopics_Handler,
 		},
+		{
+			MethodName: "RecommendTopicConfig",
+			Handler:    _Registry_RecommendTopicConfig_Handler,
+		},
 		{
 			MethodName: "TopicMappings",
 			Handler:    _Registry_TopicMappings_Handler,
@@ -1500,11 +3395,50 @@ var _Registry_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteBrokerTags",
 			Handler:    _Registry_DeleteBrokerTags_Handler,
 		},
+		{
+			MethodName: "RemoveBroker",
+			Handler:    _Registry_RemoveBroker_Handler,
+		},
+		{
+			MethodName: "PreviewDecommission",
+			Handler:    _Registry_PreviewDecommission_Handler,
+		},
 		{
 			MethodName: "TranslateOffsets",
 			Handler:    _Registry_TranslateOffsets_Handler,
 		},
+		{
+			MethodName: "CreateSnapshot",
+			Handler:    _Registry_CreateSnapshot_Handler,
+		},
+		{
+			MethodName: "DiffSnapshot",
+			Handler:    _Registry_DiffSnapshot_Handler,
+		},
+		{
+			MethodName: "GetRebalanceRecommendation",
+			Handler:    _Registry_GetRebalanceRecommendation_Handler,
+		},
+		{
+			MethodName: "Plan",
+			Handler:    _Registry_Plan_Handler,
+		},
+		{
+			MethodName: "Apply",
+			Handler:    _Registry_Apply_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TailUnderReplicationEvents",
+			Handler:       _Registry_TailUnderReplicationEvents_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "TailAuditLog",
+			Handler:       _Registry_TailAuditLog_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "protos/registry.proto",
 }
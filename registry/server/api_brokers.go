@@ -4,9 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/DataDog/kafka-kit/v3/kafkazk"
 	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
@@ -21,6 +24,17 @@ var (
 	ErrBrokerIDEmpty = errors.New("broker Id field must be specified")
 )
 
+// ErrBrokerHasDependents error.
+type ErrBrokerHasDependents struct {
+	id     uint32
+	topics []string
+}
+
+func (e ErrBrokerHasDependents) Error() string {
+	return fmt.Sprintf("broker %d holds replicas for topics %s; set force to remove anyway",
+		e.id, strings.Join(e.topics, ", "))
+}
+
 // BrokerSet is a mapping of broker IDs to *pb.Broker.
 type BrokerSet map[uint32]*pb.Broker
 
@@ -50,6 +64,8 @@ func (s *Server) GetBrokers(ctx context.Context, req *pb.BrokerRequest) (*pb.Bro
 // non-zero, the specified broker is matched if it exists. Otherwise, all
 // brokers found in ZooKeeper are matched. Matched brokers are then filtered
 // by all tags specified, if specified, in the *pb.BrokerRequest tag field.
+// If the *pb.BrokerRequest GroupBy field is set, matched brokers are also
+// returned bucketed by the value of the specified tag key or broker field.
 func (s *Server) ListBrokers(ctx context.Context, req *pb.BrokerRequest) (*pb.BrokerResponse, error) {
 	ctx, err := s.ValidateRequest(ctx, req, readRequest)
 	if err != nil {
@@ -65,9 +81,53 @@ func (s *Server) ListBrokers(ctx context.Context, req *pb.BrokerRequest) (*pb.Br
 	// Populate response Ids field.
 	resp := &pb.BrokerResponse{Ids: brokers.IDs()}
 
+	// Group brokers if requested.
+	if req.GroupBy != "" {
+		resp.Groups, err = s.groupBrokers(brokers, req.GroupBy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return resp, nil
 }
 
+// groupBrokers takes a BrokerSet and a tag key and returns the brokers
+// bucketed by the value of the key, along with per-group counts and the total
+// storage free. The key may be either a custom tag or a broker field (e.g.
+// rack). Brokers without a value for the key are grouped under an empty key.
+func (s *Server) groupBrokers(brokers BrokerSet, key string) (map[string]*pb.BrokerGroup, error) {
+	// Get storage metrics. Metrics may be partially or entirely unavailable;
+	// affected groups are marked as having unknown storage.
+	meta, _ := s.ZK.GetAllBrokerMeta(true)
+
+	groups := map[string]*pb.BrokerGroup{}
+
+	for _, id := range brokers.IDs() {
+		ts, err := s.Tags.TagSetFromObject(brokers[id])
+		if err != nil {
+			return nil, err
+		}
+
+		v := ts[key]
+		if _, exists := groups[v]; !exists {
+			groups[v] = &pb.BrokerGroup{}
+		}
+
+		g := groups[v]
+		g.Ids = append(g.Ids, id)
+		g.Count++
+
+		if m, exists := meta[int(id)]; exists && !m.MetricsIncomplete {
+			g.StorageFree += m.StorageFree
+		} else {
+			g.StorageUnknown = true
+		}
+	}
+
+	return groups, nil
+}
+
 // UnmappedBrokers returns a list of broker IDs that hold no partitions. An
 // optional list of topic names can be specified in the UnmappedBrokersRequest
 // exclude field where partitions for those topics are not considered. For
@@ -174,6 +234,28 @@ func (s *Server) BrokerMappings(ctx context.Context, req *pb.BrokerRequest) (*pb
 		return nil, ErrBrokerNotExist
 	}
 
+	// Get the broker to topics mapping.
+	bmapping, err := s.brokerTopicMappings()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get a []string of topic names where at least one
+	// partition is held by the requested broker.
+	names := []string{}
+	for n := range bmapping[int(req.Id)] {
+		names = append(names, n)
+	}
+
+	sort.Strings(names)
+
+	return &pb.TopicResponse{Names: names}, nil
+}
+
+// brokerTopicMappings returns a mapping of broker IDs to the set of topic
+// names that have at least one partition held by the broker. This is
+// structured as a map[<broker ID>]map[<topic name>]struct{}.
+func (s *Server) brokerTopicMappings() (map[int]map[string]struct{}, error) {
 	// Get all topic names.
 	ts, err := s.ZK.GetTopics([]*regexp.Regexp{regexp.MustCompile(".*")})
 	if err != nil {
@@ -190,8 +272,6 @@ func (s *Server) BrokerMappings(ctx context.Context, req *pb.BrokerRequest) (*pb
 		pms = append(pms, pm)
 	}
 
-	// Build a mapping of brokers to topics. This is structured
-	// as a map[<broker ID>]map[<topic name>]struct{}.
 	var bmapping = make(map[int]map[string]struct{})
 
 	for _, pm := range pms {
@@ -209,16 +289,7 @@ func (s *Server) BrokerMappings(ctx context.Context, req *pb.BrokerRequest) (*pb
 		}
 	}
 
-	// Get a []string of topic names where at least one
-	// partition is held by the requested broker.
-	names := []string{}
-	for n := range bmapping[int(req.Id)] {
-		names = append(names, n)
-	}
-
-	sort.Strings(names)
-
-	return &pb.TopicResponse{Names: names}, nil
+	return bmapping, nil
 }
 
 // fetchBrokerSet fetches metadata for all brokers.
@@ -277,7 +348,16 @@ func (s *Server) TagBroker(ctx context.Context, req *pb.BrokerRequest) (*pb.TagR
 
 	// Set the tags.
 	id := fmt.Sprintf("%d", req.Id)
-	err = s.Tags.Store.SetTags(KafkaObject{Type: "broker", ID: id}, ts)
+	o := KafkaObject{Type: "broker", ID: id}
+
+	// Ensure that no immutable tags are being changed.
+	if err := s.Tags.CheckImmutable(o, ts, nil); err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+
+	err = s.Tags.SetTags(o, ts, ttl)
 	if err != nil {
 		return nil, err
 	}
@@ -314,7 +394,14 @@ func (s *Server) DeleteBrokerTags(ctx context.Context, req *pb.BrokerRequest) (*
 
 	// Delete the tags.
 	id := fmt.Sprintf("%d", req.Id)
-	err = s.Tags.Store.DeleteTags(KafkaObject{Type: "broker", ID: id}, req.Tag)
+	o := KafkaObject{Type: "broker", ID: id}
+
+	// Ensure that no immutable tags are being deleted.
+	if err := s.Tags.CheckImmutable(o, nil, req.Tag); err != nil {
+		return nil, err
+	}
+
+	err = s.Tags.Store.DeleteTags(o, req.Tag)
 	if err != nil {
 		return nil, err
 	}
@@ -322,6 +409,150 @@ func (s *Server) DeleteBrokerTags(ctx context.Context, req *pb.BrokerRequest) (*
 	return &pb.TagResponse{Message: "success"}, nil
 }
 
+// RemoveBroker removes all registry state held for the specified broker.
+// If the broker still holds replicas for any topics, an ErrBrokerHasDependents
+// listing the topics is returned unless the request force field is set. The
+// broker doesn't need to exist in ZooKeeper; this allows state for already
+// decommissioned brokers to be cleaned up.
+func (s *Server) RemoveBroker(ctx context.Context, req *pb.BrokerRequest) (*pb.TagResponse, error) {
+	ctx, err := s.ValidateRequest(ctx, req, writeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Id == 0 {
+		return nil, ErrBrokerIDEmpty
+	}
+
+	// Check for topics with replicas on the broker.
+	bmapping, err := s.brokerTopicMappings()
+	if err != nil {
+		return nil, err
+	}
+
+	if dependents := bmapping[int(req.Id)]; len(dependents) > 0 {
+		var names []string
+		for n := range dependents {
+			names = append(names, n)
+		}
+
+		sort.Strings(names)
+
+		if !req.Force {
+			return nil, ErrBrokerHasDependents{id: req.Id, topics: names}
+		}
+
+		log.Printf("Force removing broker %d holding replicas for topics %s\n",
+			req.Id, strings.Join(names, ", "))
+	}
+
+	// Delete all stored tags.
+	o := KafkaObject{Type: "broker", ID: fmt.Sprintf("%d", req.Id)}
+
+	tags, err := s.Tags.Store.GetTags(o)
+	switch err {
+	case nil:
+	case ErrKafkaObjectDoesNotExist:
+		return &pb.TagResponse{Message: "success"}, nil
+	default:
+		return nil, err
+	}
+
+	var keys []string
+	for k := range tags {
+		keys = append(keys, k)
+	}
+
+	if len(keys) > 0 {
+		if err := s.Tags.Store.DeleteTags(o, keys); err != nil {
+			return nil, err
+		}
+	}
+
+	return &pb.TagResponse{Message: "success"}, nil
+}
+
+// PreviewDecommission returns all topics and partitions with replicas on the
+// requested brokers along with an estimate of the bytes to move. The brokers
+// don't need to exist in ZooKeeper since decommissioning brokers that have
+// already failed is a common case. If partition metrics are unavailable, all
+// partitions are marked as having an unknown size.
+func (s *Server) PreviewDecommission(ctx context.Context, req *pb.DecommissionPreviewRequest) (*pb.DecommissionPreview, error) {
+	ctx, err := s.ValidateRequest(ctx, req, readRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.Ids) == 0 {
+		return nil, ErrBrokerIDEmpty
+	}
+
+	var targets = map[int]struct{}{}
+	for _, id := range req.Ids {
+		targets[int(id)] = struct{}{}
+	}
+
+	// Get all topic names.
+	ts, err := s.ZK.GetTopics([]*regexp.Regexp{regexp.MustCompile(".*")})
+	if err != nil {
+		return nil, ErrFetchingTopics
+	}
+
+	sort.Strings(ts)
+
+	// Get partition sizes.
+	pmm, err := s.ZK.GetAllPartitionMeta()
+	if err != nil {
+		log.Printf("Partition sizes unavailable for decommission preview: %s\n", err)
+		pmm = kafkazk.NewPartitionMetaMap()
+	}
+
+	preview := &pb.DecommissionPreview{}
+
+	for _, t := range ts {
+		pm, err := s.ZK.GetPartitionMap(t)
+		if err != nil {
+			return nil, err
+		}
+
+		var affected bool
+
+		for _, p := range pm.Partitions {
+			move := &pb.PartitionMove{
+				Topic:     p.Topic,
+				Partition: uint32(p.Partition),
+			}
+
+			for _, id := range p.Replicas {
+				if _, ok := targets[id]; ok {
+					move.Replicas = append(move.Replicas, uint32(id))
+				}
+			}
+
+			if len(move.Replicas) == 0 {
+				continue
+			}
+
+			affected = true
+
+			if size, err := pmm.Size(p); err != nil {
+				move.SizeUnknown = true
+			} else {
+				move.SizeBytes = uint64(size) * uint64(len(move.Replicas))
+				preview.TotalBytes += move.SizeBytes
+			}
+
+			preview.Partitions = append(preview.Partitions, move)
+		}
+
+		if affected {
+			preview.Topics = append(preview.Topics, t)
+		}
+	}
+
+	return preview, nil
+}
+
 // IDs returns a []uint32 of IDs from a BrokerSet.
 func (b BrokerSet) IDs() []uint32 {
 	var ids = []uint32{}
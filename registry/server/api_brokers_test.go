@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+
+	"github.com/golang/protobuf/proto"
 )
 
 func TestGetBrokers(t *testing.T) {
@@ -73,6 +75,70 @@ func TestListBrokers(t *testing.T) {
 	}
 }
 
+func TestListBrokersGroupBy(t *testing.T) {
+	s := testServer()
+
+	req := &pb.BrokerRequest{GroupBy: "rack"}
+	resp, err := s.ListBrokers(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]*pb.BrokerGroup{
+		"a": &pb.BrokerGroup{Ids: []uint32{1001, 1004}, Count: 2, StorageFree: 10000},
+		"b": &pb.BrokerGroup{Ids: []uint32{1002, 1005}, Count: 2, StorageFree: 14000},
+		"":  &pb.BrokerGroup{Ids: []uint32{1003, 1007}, Count: 2, StorageFree: 18000},
+	}
+
+	if len(resp.Groups) != len(expected) {
+		t.Fatalf("Expected %d groups, got %d", len(expected), len(resp.Groups))
+	}
+
+	for k, e := range expected {
+		got, exists := resp.Groups[k]
+		if !exists {
+			t.Errorf("Expected group '%s'", k)
+			continue
+		}
+
+		if !intsEqual(e.Ids, got.Ids) {
+			t.Errorf("[%s] Expected broker list %v, got %v", k, e.Ids, got.Ids)
+		}
+
+		if got.Count != e.Count {
+			t.Errorf("[%s] Expected count %d, got %d", k, e.Count, got.Count)
+		}
+
+		if got.StorageFree != e.StorageFree {
+			t.Errorf("[%s] Expected storage free %f, got %f", k, e.StorageFree, got.StorageFree)
+		}
+
+		if got.StorageUnknown {
+			t.Errorf("[%s] Unexpected storage unknown", k)
+		}
+	}
+
+	// Group by a custom tag.
+	tagReq := &pb.BrokerRequest{Id: 1001, Tag: []string{"tier:hot"}}
+	if _, err := s.TagBroker(context.Background(), tagReq); err != nil {
+		t.Fatal(err)
+	}
+
+	req = &pb.BrokerRequest{GroupBy: "tier", Tag: []string{"rack:a"}}
+	resp, err = s.ListBrokers(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g := resp.Groups["hot"]; g == nil || !intsEqual([]uint32{1001}, g.Ids) {
+		t.Errorf("Expected group 'hot' with broker 1001, got %v", g)
+	}
+
+	if g := resp.Groups[""]; g == nil || !intsEqual([]uint32{1004}, g.Ids) {
+		t.Errorf("Expected ungrouped broker 1004, got %v", g)
+	}
+}
+
 func TestUnmappedBrokers(t *testing.T) {
 	s := testServer()
 
@@ -179,6 +245,31 @@ func TestTagBroker(t *testing.T) {
 	}
 }
 
+func TestTagBrokerImmutable(t *testing.T) {
+	s := testServer()
+	s.Tags.ImmutableKeys = map[string]struct{}{"cluster_id": {}}
+
+	// Initial set.
+	req := &pb.BrokerRequest{Id: 1001, Tag: []string{"cluster_id:a"}}
+	if _, err := s.TagBroker(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ErrImmutableTag{t: "cluster_id"}
+
+	// Modification.
+	req = &pb.BrokerRequest{Id: 1001, Tag: []string{"cluster_id:b"}}
+	if _, err := s.TagBroker(context.Background(), req); err != expected {
+		t.Errorf("Expected err '%v', got '%v'", expected, err)
+	}
+
+	// Deletion.
+	req = &pb.BrokerRequest{Id: 1001, Tag: []string{"cluster_id"}}
+	if _, err := s.DeleteBrokerTags(context.Background(), req); err != expected {
+		t.Errorf("Expected err '%v', got '%v'", expected, err)
+	}
+}
+
 func TestDeleteBrokerTags(t *testing.T) {
 	s := testServer()
 
@@ -285,3 +376,107 @@ func TestBrokerMappings(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestRemoveBroker(t *testing.T) {
+	s := testServer()
+
+	o := KafkaObject{Type: "broker", ID: "1002"}
+	s.Tags.Store.SetTags(o, TagSet{"pool": "inbound"})
+
+	// Broker 1002 holds replicas; removal should be refused.
+	req := &pb.BrokerRequest{Id: 1002}
+	_, err := s.RemoveBroker(context.Background(), req)
+
+	e, ok := err.(ErrBrokerHasDependents)
+	if !ok {
+		t.Fatalf("Expected ErrBrokerHasDependents, got %v", err)
+	}
+
+	expected := []string{"test_topic", "test_topic2"}
+	if !stringsEqual(e.topics, expected) {
+		t.Errorf("Expected dependent topics %v, got %v", expected, e.topics)
+	}
+
+	if tags, _ := s.Tags.Store.GetTags(o); len(tags) != 1 {
+		t.Errorf("Expected tags to be retained, got %v", tags)
+	}
+
+	// Forced removal.
+	req.Force = true
+	if _, err := s.RemoveBroker(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	if tags, _ := s.Tags.Store.GetTags(o); len(tags) != 0 {
+		t.Errorf("Expected tags to be removed, got %v", tags)
+	}
+
+	// Broker 1005 holds no replicas.
+	req = &pb.BrokerRequest{Id: 1005}
+	if _, err := s.RemoveBroker(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	// Test no ID.
+	req = &pb.BrokerRequest{}
+	if _, err := s.RemoveBroker(context.Background(), req); err != ErrBrokerIDEmpty {
+		t.Fatal(err)
+	}
+}
+
+func TestPreviewDecommission(t *testing.T) {
+	s := testServer()
+
+	req := &pb.DecommissionPreviewRequest{Ids: []uint32{1003}}
+	resp, err := s.PreviewDecommission(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTopics := []string{"test_topic", "test_topic2"}
+	if !stringsEqual(resp.Topics, expectedTopics) {
+		t.Errorf("Expected topics %v, got %v", expectedTopics, resp.Topics)
+	}
+
+	// Partitions 2 and 3 of each topic are held by broker 1003. Only
+	// test_topic has stored partition sizes.
+	expected := []*pb.PartitionMove{
+		{Topic: "test_topic", Partition: 2, Replicas: []uint32{1003}, SizeBytes: 2000},
+		{Topic: "test_topic", Partition: 3, Replicas: []uint32{1003}, SizeBytes: 2500},
+		{Topic: "test_topic2", Partition: 2, Replicas: []uint32{1003}, SizeUnknown: true},
+		{Topic: "test_topic2", Partition: 3, Replicas: []uint32{1003}, SizeUnknown: true},
+	}
+
+	if len(resp.Partitions) != len(expected) {
+		t.Fatalf("Expected %d partitions, got %d", len(expected), len(resp.Partitions))
+	}
+
+	for i, p := range resp.Partitions {
+		if !proto.Equal(p, expected[i]) {
+			t.Errorf("Expected partition %v, got %v", expected[i], p)
+		}
+	}
+
+	if resp.TotalBytes != 4500 {
+		t.Errorf("Expected total bytes 4500, got %d", resp.TotalBytes)
+	}
+
+	// Partitions with multiple replicas on the target brokers count the
+	// size once per replica moved.
+	req = &pb.DecommissionPreviewRequest{Ids: []uint32{1001, 1002}}
+	resp, err = s.PreviewDecommission(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// test_topic: p0 2*1000, p1 2*1500, p2 1*2000, p3 1*2500.
+	if resp.TotalBytes != 9500 {
+		t.Errorf("Expected total bytes 9500, got %d", resp.TotalBytes)
+	}
+
+	// Test no IDs.
+	req = &pb.DecommissionPreviewRequest{}
+	if _, err := s.PreviewDecommission(context.Background(), req); err != ErrBrokerIDEmpty {
+		t.Fatal(err)
+	}
+}
@@ -1 +1,202 @@
 package server
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+)
+
+// Default rebalance recommendation skew thresholds.
+const (
+	defaultPartitionSkewThreshold  = 0.2
+	defaultStorageSkewThreshold    = 0.2
+	defaultLeadershipSkewThreshold = 0.2
+)
+
+// Rebalance recommendation severities. The severity is determined by the
+// highest ratio of skew to threshold among all metrics.
+const (
+	severityNone   = "none"
+	severityLow    = "low"
+	severityMedium = "medium"
+	severityHigh   = "high"
+)
+
+// GetRebalanceRecommendation evaluates the partition count, free storage and
+// leadership skew across all brokers and returns a RebalanceRecommendation. If
+// broker metrics are unavailable, free storage isn't evaluated. Brokers tagged
+// for maintenance aren't evaluated.
+func (s *Server) GetRebalanceRecommendation(ctx context.Context, req *pb.RebalanceRecommendationRequest) (*pb.RebalanceRecommendation, error) {
+	ctx, err := s.ValidateRequest(ctx, req, readRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	bm, errs := s.ZK.GetAllBrokerMeta(false)
+	if errs != nil {
+		return nil, ErrFetchingBrokers
+	}
+
+	// Get broker metrics.
+	var withStorage = true
+	if bmWithMetrics, errs := s.ZK.GetAllBrokerMeta(true); errs != nil {
+		log.Printf("Broker metrics unavailable for rebalance recommendation: %s\n", errs)
+		withStorage = false
+	} else {
+		bm = bmWithMetrics
+	}
+
+	// Exclude brokers in maintenance; they aren't rebalance targets.
+	maintenance, err := s.maintenanceBrokers()
+	if err != nil {
+		return nil, err
+	}
+
+	for id := range maintenance {
+		delete(bm, id)
+	}
+
+	// Get all topic names.
+	ts, err := s.ZK.GetTopics([]*regexp.Regexp{regexp.MustCompile(".*")})
+	if err != nil {
+		return nil, ErrFetchingTopics
+	}
+
+	pm := kafkazk.NewPartitionMap()
+	for _, t := range ts {
+		m, err := s.ZK.GetPartitionMap(t)
+		if err != nil {
+			return nil, err
+		}
+		pm.Partitions = append(pm.Partitions, m.Partitions...)
+	}
+
+	return rebalanceRecommendation(pm, bm, withStorage, req), nil
+}
+
+// rebalanceRecommendation takes a *kafkazk.PartitionMap of all partitions, a
+// kafkazk.BrokerMetaMap of all brokers, whether storage metrics are available
+// and a *pb.RebalanceRecommendationRequest of thresholds. Skew for each metric
+// is the difference between the highest and lowest per-broker values relative
+// to the mean. Brokers that hold no replicas are included.
+func rebalanceRecommendation(pm *kafkazk.PartitionMap, bm kafkazk.BrokerMetaMap, withStorage bool, req *pb.RebalanceRecommendationRequest) *pb.RebalanceRecommendation {
+	replicas := map[int]float64{}
+	leaders := map[int]float64{}
+	storage := map[int]float64{}
+
+	for id, b := range bm {
+		replicas[id], leaders[id] = 0, 0
+		storage[id] = b.StorageFree
+	}
+
+	for _, p := range pm.Partitions {
+		for i, id := range p.Replicas {
+			// Exclude unregistered brokers.
+			if _, exists := bm[id]; !exists {
+				continue
+			}
+
+			replicas[id]++
+			if i == 0 {
+				leaders[id]++
+			}
+		}
+	}
+
+	metrics := []*pb.ImbalanceMetric{
+		imbalanceMetric("partition_count", replicas, thresholdOrDefault(req.PartitionSkewThreshold, defaultPartitionSkewThreshold)),
+	}
+
+	if withStorage {
+		metrics = append(metrics,
+			imbalanceMetric("storage_free", storage, thresholdOrDefault(req.StorageSkewThreshold, defaultStorageSkewThreshold)))
+	}
+
+	metrics = append(metrics,
+		imbalanceMetric("leadership", leaders, thresholdOrDefault(req.LeadershipSkewThreshold, defaultLeadershipSkewThreshold)))
+
+	rec := &pb.RebalanceRecommendation{
+		Severity: severityNone,
+		Metrics:  metrics,
+	}
+
+	// Get the highest skew to threshold ratio.
+	var ratio float64
+	for _, m := range metrics {
+		if m.Triggered {
+			rec.Recommended = true
+		}
+
+		if r := m.Skew / m.Threshold; r > ratio {
+			ratio = r
+		}
+	}
+
+	switch {
+	case ratio >= 3:
+		rec.Severity = severityHigh
+	case ratio >= 2:
+		rec.Severity = severityMedium
+	case ratio >= 1:
+		rec.Severity = severityLow
+	}
+
+	return rec
+}
+
+// imbalanceMetric takes a metric name, a mapping of broker ID to value and a
+// skew threshold and returns a *pb.ImbalanceMetric.
+func imbalanceMetric(name string, values map[int]float64, threshold float64) *pb.ImbalanceMetric {
+	m := &pb.ImbalanceMetric{
+		Name:      name,
+		Threshold: threshold,
+	}
+
+	if len(values) == 0 {
+		return m
+	}
+
+	// Sort IDs for consistent min/max brokers on ties.
+	var ids []int
+	for id := range values {
+		ids = append(ids, id)
+	}
+
+	sort.Ints(ids)
+
+	var sum float64
+	low, high := ids[0], ids[0]
+
+	for _, id := range ids {
+		sum += values[id]
+		if values[id] < values[low] {
+			low = id
+		}
+		if values[id] > values[high] {
+			high = id
+		}
+	}
+
+	m.MinBroker, m.MaxBroker = uint32(low), uint32(high)
+
+	if mean := sum / float64(len(ids)); mean > 0 {
+		m.Skew = (values[high] - values[low]) / mean
+	}
+
+	m.Triggered = m.Skew >= threshold
+
+	return m
+}
+
+// thresholdOrDefault returns t if t is greater than 0, otherwise d.
+func thresholdOrDefault(t, d float64) float64 {
+	if t > 0 {
+		return t
+	}
+
+	return d
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+)
+
+func TestGetRebalanceRecommendation(t *testing.T) {
+	s := testServer()
+
+	resp, err := s.GetRebalanceRecommendation(context.Background(), &pb.RebalanceRecommendationRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{"partition_count", "storage_free", "leadership"}
+	if len(resp.Metrics) != len(names) {
+		t.Fatalf("Expected %d metrics, got %d", len(names), len(resp.Metrics))
+	}
+
+	for i, m := range resp.Metrics {
+		if m.Name != names[i] {
+			t.Errorf("Expected metric %s, got %s", names[i], m.Name)
+		}
+	}
+
+	// Stub broker storage free ranges from 2000 to 12000 with a mean of 7000.
+	if m := resp.Metrics[1]; !m.Triggered || m.MinBroker != 1001 || m.MaxBroker != 1007 {
+		t.Errorf("Unexpected storage_free metric %v", m)
+	}
+}
+
+func TestGetRebalanceRecommendationMaintenance(t *testing.T) {
+	s := testServer()
+
+	// 1007 has the most storage free and would be the storage offload target.
+	tagReq := &pb.BrokerRequest{Id: 1007, Tag: []string{"maintenance:true"}}
+	if _, err := s.TagBroker(context.Background(), tagReq); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := s.GetRebalanceRecommendation(context.Background(), &pb.RebalanceRecommendationRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, m := range resp.Metrics {
+		if m.MinBroker == 1007 || m.MaxBroker == 1007 {
+			t.Errorf("Expected broker 1007 excluded from metric %v", m)
+		}
+	}
+
+	if m := resp.Metrics[1]; m.MaxBroker != 1005 {
+		t.Errorf("Expected storage_free max broker 1005, got %d", m.MaxBroker)
+	}
+}
+
+func TestRebalanceRecommendation(t *testing.T) {
+	bm := kafkazk.BrokerMetaMap{
+		1001: &kafkazk.BrokerMeta{StorageFree: 1000},
+		1002: &kafkazk.BrokerMeta{StorageFree: 1000},
+		1003: &kafkazk.BrokerMeta{StorageFree: 1000},
+	}
+
+	// Balanced.
+	pm := kafkazk.NewPartitionMap()
+	for i := 0; i < 6; i++ {
+		pm.Partitions = append(pm.Partitions, kafkazk.Partition{
+			Topic:     "test",
+			Partition: i,
+			Replicas:  []int{1001 + i%3, 1001 + (i+1)%3},
+		})
+	}
+
+	rec := rebalanceRecommendation(pm, bm, true, &pb.RebalanceRecommendationRequest{})
+	if rec.Recommended || rec.Severity != severityNone {
+		t.Errorf("Expected no recommendation, got %v", rec)
+	}
+
+	// Badly skewed; all partitions are on 1001 and 1002, led by 1001, and
+	// 1001 is nearly out of storage.
+	bm[1001].StorageFree = 100
+	for i := range pm.Partitions {
+		pm.Partitions[i].Replicas = []int{1001, 1002}
+	}
+
+	rec = rebalanceRecommendation(pm, bm, true, &pb.RebalanceRecommendationRequest{})
+	if !rec.Recommended {
+		t.Error("Expected a recommendation")
+	}
+
+	if rec.Severity != severityHigh {
+		t.Errorf("Expected severity %s, got %s", severityHigh, rec.Severity)
+	}
+
+	expected := map[string]struct {
+		min, max uint32
+	}{
+		"partition_count": {1003, 1001},
+		"storage_free":    {1001, 1002},
+		"leadership":      {1002, 1001},
+	}
+
+	for _, m := range rec.Metrics {
+		e := expected[m.Name]
+		if !m.Triggered {
+			t.Errorf("Expected %s to be triggered", m.Name)
+		}
+
+		if m.MinBroker != e.min || m.MaxBroker != e.max {
+			t.Errorf("%s: expected min/max brokers %d/%d, got %d/%d",
+				m.Name, e.min, e.max, m.MinBroker, m.MaxBroker)
+		}
+	}
+
+	// Thresholds can be raised.
+	req := &pb.RebalanceRecommendationRequest{
+		PartitionSkewThreshold:  10,
+		StorageSkewThreshold:    10,
+		LeadershipSkewThreshold: 10,
+	}
+
+	rec = rebalanceRecommendation(pm, bm, true, req)
+	if rec.Recommended || rec.Severity != severityNone {
+		t.Errorf("Expected no recommendation, got %v", rec)
+	}
+
+	// Storage isn't evaluated without metrics.
+	rec = rebalanceRecommendation(pm, bm, false, &pb.RebalanceRecommendationRequest{})
+	for _, m := range rec.Metrics {
+		if m.Name == "storage_free" {
+			t.Error("Unexpected storage_free metric")
+		}
+	}
+}
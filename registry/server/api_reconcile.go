@@ -0,0 +1,217 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+)
+
+// Topic action types.
+const (
+	actionCreate = "create"
+	actionUpdate = "update"
+	actionDelete = "delete"
+)
+
+var (
+	// ErrInvalidTopicAction error.
+	ErrInvalidTopicAction = errors.New("invalid topic action type")
+
+	// unmanagedTopicConfigs are topic configs set by other tooling (e.g.
+	// autothrottle) that are never planned for deletion.
+	unmanagedTopicConfigs = map[string]struct{}{
+		"leader.replication.throttled.replicas":   {},
+		"follower.replication.throttled.replicas": {},
+	}
+)
+
+// Plan takes a DesiredState and returns a ReconciliationPlan of the actions
+// required for the live topics to match the desired topics. Topics that
+// don't exist are created. Existing topics with differing configs or custom
+// tags are updated, where configs and tags absent from the desired topic are
+// deleted. Live topics absent from the desired topics are deleted only if
+// they match all of the DesiredState.PruneTag tags.
+func (s *Server) Plan(ctx context.Context, req *pb.DesiredState) (*pb.ReconciliationPlan, error) {
+	ctx, err := s.ValidateRequest(ctx, req, readRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	live, err := s.fetchTopicSet(&pb.TopicRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	var prune TopicSet
+	if len(req.PruneTag) > 0 {
+		if prune, err = s.fetchTopicSet(&pb.TopicRequest{Tag: req.PruneTag}); err != nil {
+			return nil, err
+		}
+	}
+
+	return reconciliationPlan(req.Topics, live, prune), nil
+}
+
+// Apply takes a ReconciliationPlan and applies each action in order. The
+// result of each action is returned in the ApplyResponse.
+func (s *Server) Apply(ctx context.Context, req *pb.ReconciliationPlan) (*pb.ApplyResponse, error) {
+	ctx, err := s.ValidateRequest(ctx, req, writeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.ApplyResponse{}
+
+	for _, a := range req.Actions {
+		r := &pb.TopicActionResult{Action: a, Applied: true}
+		if err := s.applyTopicAction(ctx, a); err != nil {
+			r.Applied, r.Error = false, err.Error()
+		}
+		resp.Results = append(resp.Results, r)
+	}
+
+	return resp, nil
+}
+
+// applyTopicAction applies a single *pb.TopicAction.
+func (s *Server) applyTopicAction(ctx context.Context, a *pb.TopicAction) error {
+	switch a.Type {
+	case actionCreate:
+		_, err := s.CreateTopic(ctx, &pb.CreateTopicRequest{Topic: a.Topic})
+		return err
+	case actionDelete:
+		_, err := s.DeleteTopic(ctx, &pb.TopicRequest{Name: a.Name})
+		return err
+	case actionUpdate:
+	default:
+		return ErrInvalidTopicAction
+	}
+
+	if a.Name == "" {
+		return ErrTopicNameEmpty
+	}
+
+	// Deleted configs are set to an empty value.
+	var configs []kafkazk.KafkaConfigKV
+	for k, v := range a.SetConfigs {
+		configs = append(configs, kafkazk.KafkaConfigKV{k, v})
+	}
+	for _, k := range a.DeleteConfigs {
+		configs = append(configs, kafkazk.KafkaConfigKV{k, ""})
+	}
+
+	if len(configs) > 0 {
+		if err := s.checkPolicy(ctx, "UpdateTopicConfig", a); err != nil {
+			return err
+		}
+
+		c := kafkazk.KafkaConfig{Type: "topic", Name: a.Name, Configs: configs}
+		if _, err := s.ZK.UpdateKafkaConfig(c); err != nil {
+			return err
+		}
+	}
+
+	if len(a.SetTags) > 0 {
+		req := &pb.TopicRequest{Name: a.Name, Tag: TagSet(a.SetTags).Tags()}
+		if _, err := s.TagTopic(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	if len(a.DeleteTags) > 0 {
+		req := &pb.TopicRequest{Name: a.Name, Tag: a.DeleteTags}
+		if _, err := s.DeleteTopicTags(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// reconciliationPlan takes the desired topics, the live TopicSet and the
+// TopicSet of live topics eligible for deletion and returns a
+// *pb.ReconciliationPlan. Actions are ordered by creates, updates and
+// deletes, each sorted by topic name.
+func reconciliationPlan(desired []*pb.Topic, live, prune TopicSet) *pb.ReconciliationPlan {
+	plan := &pb.ReconciliationPlan{}
+	var creates, updates, deletes []*pb.TopicAction
+
+	want := TopicSet{}
+	for _, t := range desired {
+		if t != nil {
+			want[t.Name] = t
+		}
+	}
+
+	for _, name := range want.Names() {
+		d := want[name]
+
+		l, exists := live[name]
+		if !exists {
+			creates = append(creates, &pb.TopicAction{Type: actionCreate, Name: name, Topic: d})
+			continue
+		}
+
+		if d.Partitions != l.Partitions {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: partition count differs (live %d, desired %d)",
+				name, l.Partitions, d.Partitions))
+		}
+
+		if d.Replication != l.Replication {
+			plan.Warnings = append(plan.Warnings, fmt.Sprintf("%s: replication factor differs (live %d, desired %d)",
+				name, l.Replication, d.Replication))
+		}
+
+		a := &pb.TopicAction{Type: actionUpdate, Name: name}
+		a.SetConfigs, a.DeleteConfigs = diffStringMaps(l.Configs, d.Configs, unmanagedTopicConfigs)
+		a.SetTags, a.DeleteTags = diffStringMaps(l.Tags, d.Tags, nil)
+
+		if len(a.SetConfigs)+len(a.DeleteConfigs)+len(a.SetTags)+len(a.DeleteTags) > 0 {
+			updates = append(updates, a)
+		}
+	}
+
+	for _, name := range prune.Names() {
+		if _, exists := want[name]; !exists {
+			deletes = append(deletes, &pb.TopicAction{Type: actionDelete, Name: name})
+		}
+	}
+
+	plan.Actions = append(append(creates, updates...), deletes...)
+
+	return plan
+}
+
+// diffStringMaps takes current and desired maps and returns the keys to set
+// to their desired values and the sorted keys to delete. Keys in the
+// ignoreDeletes set are never deleted.
+func diffStringMaps(current, desired map[string]string, ignoreDeletes map[string]struct{}) (map[string]string, []string) {
+	var set map[string]string
+	var del []string
+
+	for k, v := range desired {
+		if cv, exists := current[k]; !exists || cv != v {
+			if set == nil {
+				set = map[string]string{}
+			}
+			set[k] = v
+		}
+	}
+
+	for k := range current {
+		if _, exists := desired[k]; exists {
+			continue
+		}
+		if _, ignored := ignoreDeletes[k]; !ignored {
+			del = append(del, k)
+		}
+	}
+
+	sort.Strings(del)
+
+	return set, del
+}
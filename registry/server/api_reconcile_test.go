@@ -0,0 +1,172 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+)
+
+func TestPlan(t *testing.T) {
+	s := testServer()
+
+	s.Tags.Store.SetTags(
+		KafkaObject{Type: "topic", ID: "test_topic2"},
+		TagSet{"managed": "true"},
+	)
+
+	req := &pb.DesiredState{
+		Topics: []*pb.Topic{
+			// A changed topic. The stub topic has 5 partitions, a replication
+			// factor of 2 and a retention.ms of 172800000.
+			{
+				Name:        "test_topic",
+				Partitions:  5,
+				Replication: 2,
+				Configs:     map[string]string{"retention.ms": "86400000"},
+				Tags:        map[string]string{"team": "eng"},
+			},
+			// A new topic.
+			{Name: "new_topic", Partitions: 8, Replication: 3},
+		},
+		PruneTag: []string{"managed:true"},
+	}
+
+	plan, err := s.Plan(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(plan.Actions) != 3 {
+		t.Fatalf("Expected 3 actions, got %d: %v", len(plan.Actions), plan.Actions)
+	}
+
+	create, update, del := plan.Actions[0], plan.Actions[1], plan.Actions[2]
+
+	if create.Type != actionCreate || create.Name != "new_topic" || create.Topic.Partitions != 8 {
+		t.Errorf("Unexpected create action %v", create)
+	}
+
+	if update.Type != actionUpdate || update.Name != "test_topic" {
+		t.Errorf("Unexpected update action %v", update)
+	}
+
+	if len(update.SetConfigs) != 1 || update.SetConfigs["retention.ms"] != "86400000" {
+		t.Errorf("Expected retention.ms to be set, got %v", update.SetConfigs)
+	}
+
+	// Throttle configs aren't managed by the desired state.
+	if len(update.DeleteConfigs) != 0 {
+		t.Errorf("Unexpected config deletes %v", update.DeleteConfigs)
+	}
+
+	if len(update.SetTags) != 1 || update.SetTags["team"] != "eng" {
+		t.Errorf("Expected team tag to be set, got %v", update.SetTags)
+	}
+
+	if del.Type != actionDelete || del.Name != "test_topic2" {
+		t.Errorf("Unexpected delete action %v", del)
+	}
+
+	if len(plan.Warnings) != 0 {
+		t.Errorf("Unexpected warnings %v", plan.Warnings)
+	}
+
+	// Without prune tags, nothing is deleted.
+	req.PruneTag = nil
+	plan, err = s.Plan(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, a := range plan.Actions {
+		if a.Type == actionDelete {
+			t.Errorf("Unexpected delete action %v", a)
+		}
+	}
+}
+
+func TestReconciliationPlan(t *testing.T) {
+	live := TopicSet{
+		"test": &pb.Topic{
+			Name:        "test",
+			Partitions:  4,
+			Replication: 2,
+			Configs:     map[string]string{"retention.ms": "1000", "cleanup.policy": "compact"},
+			Tags:        map[string]string{"team": "eng", "owner": "alice"},
+		},
+	}
+
+	desired := []*pb.Topic{
+		{
+			Name:        "test",
+			Partitions:  8,
+			Replication: 2,
+			Configs:     map[string]string{"retention.ms": "1000"},
+			Tags:        map[string]string{"team": "eng"},
+		},
+	}
+
+	plan := reconciliationPlan(desired, live, nil)
+
+	if len(plan.Actions) != 1 {
+		t.Fatalf("Expected 1 action, got %d", len(plan.Actions))
+	}
+
+	a := plan.Actions[0]
+	if len(a.SetConfigs) != 0 || !stringsEqual(a.DeleteConfigs, []string{"cleanup.policy"}) {
+		t.Errorf("Unexpected config changes %v %v", a.SetConfigs, a.DeleteConfigs)
+	}
+
+	if len(a.SetTags) != 0 || !stringsEqual(a.DeleteTags, []string{"owner"}) {
+		t.Errorf("Unexpected tag changes %v %v", a.SetTags, a.DeleteTags)
+	}
+
+	// Partition count changes can't be reconciled.
+	if len(plan.Warnings) != 1 {
+		t.Errorf("Expected 1 warning, got %v", plan.Warnings)
+	}
+
+	// No differences.
+	live["test"].Configs = desired[0].Configs
+	live["test"].Tags = desired[0].Tags
+	live["test"].Partitions = desired[0].Partitions
+
+	plan = reconciliationPlan(desired, live, nil)
+	if len(plan.Actions) != 0 || len(plan.Warnings) != 0 {
+		t.Errorf("Expected an empty plan, got %v", plan)
+	}
+}
+
+func TestApply(t *testing.T) {
+	s := testServer()
+
+	req := &pb.ReconciliationPlan{
+		Actions: []*pb.TopicAction{
+			{Type: actionUpdate, Name: "test_topic", SetTags: map[string]string{"team": "eng"}},
+			{Type: "rename", Name: "test_topic"},
+		},
+	}
+
+	resp, err := s.Apply(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(resp.Results))
+	}
+
+	if r := resp.Results[0]; !r.Applied || r.Error != "" {
+		t.Errorf("Expected the update to be applied, got %v", r)
+	}
+
+	if r := resp.Results[1]; r.Applied || r.Error != ErrInvalidTopicAction.Error() {
+		t.Errorf("Expected an invalid action error, got %v", r)
+	}
+
+	tags, _ := s.Tags.Store.GetTags(KafkaObject{Type: "topic", ID: "test_topic"})
+	if tags["team"] != "eng" {
+		t.Errorf("Expected team tag to be set, got %v", tags)
+	}
+}
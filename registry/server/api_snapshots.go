@@ -0,0 +1,336 @@
+package server
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+
+	"github.com/golang/protobuf/proto"
+)
+
+var (
+	// ErrSnapshotIDEmpty error.
+	ErrSnapshotIDEmpty = errors.New("snapshot Id field must be specified")
+	// ErrSnapshotNotExist error.
+	ErrSnapshotNotExist = errors.New("snapshot does not exist")
+)
+
+// snapshotChunkSize is the maximum size of each snapshot chunk znode, well
+// under the ZooKeeper default jute.maxbuffer of 1MB.
+var snapshotChunkSize = 512 * 1024
+
+// Snapshot holds the broker and topic metadata of a cluster at a point in time.
+type Snapshot struct {
+	Timestamp int64
+	Brokers   BrokerSet
+	Topics    TopicSet
+}
+
+// CreateSnapshot captures the current broker and topic metadata, including
+// all tags, and persists it. The snapshot ID is returned in the
+// SnapshotResponse. If a snapshot retention is configured, the oldest
+// snapshots beyond it are deleted.
+func (s *Server) CreateSnapshot(ctx context.Context, req *pb.Empty) (*pb.SnapshotResponse, error) {
+	ctx, err := s.ValidateRequest(ctx, req, writeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	snap, err := s.currentSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	id := fmt.Sprintf("%d", time.Now().UnixNano())
+	if err := s.storeSnapshot(id, snap); err != nil {
+		return nil, err
+	}
+
+	if err := s.pruneSnapshots(); err != nil {
+		log.Printf("Error pruning snapshots: %s\n", err)
+	}
+
+	return &pb.SnapshotResponse{Id: id, Timestamp: snap.Timestamp}, nil
+}
+
+// DiffSnapshot compares the snapshot specified in the SnapshotRequest.Id
+// field against the current cluster state. Brokers and topics that were
+// added, removed, or had any metadata or tags changed are returned in the
+// SnapshotDiff.
+func (s *Server) DiffSnapshot(ctx context.Context, req *pb.SnapshotRequest) (*pb.SnapshotDiff, error) {
+	ctx, err := s.ValidateRequest(ctx, req, readRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Id == "" {
+		return nil, ErrSnapshotIDEmpty
+	}
+
+	prev, err := s.loadSnapshot(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	curr, err := s.currentSnapshot()
+	if err != nil {
+		return nil, err
+	}
+
+	return diffSnapshots(*prev, *curr), nil
+}
+
+// storeSnapshot writes the gzip compressed Snapshot under the snapshot ID
+// znode, split into chunk znodes of at most snapshotChunkSize bytes named by
+// their index. The number of chunks is written to the snapshot ID znode once
+// all chunks are stored; snapshots without it are incomplete.
+func (s *Server) storeSnapshot(id string, snap *Snapshot) error {
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return err
+	}
+
+	data = buf.Bytes()
+
+	p := s.snapshotPath(id)
+	if err := s.ZK.Create(p, ""); err != nil {
+		return err
+	}
+
+	var chunks int
+	for ; len(data) > 0; chunks++ {
+		n := snapshotChunkSize
+		if len(data) < n {
+			n = len(data)
+		}
+
+		if err := s.ZK.Create(fmt.Sprintf("%s/%d", p, chunks), string(data[:n])); err != nil {
+			return err
+		}
+
+		data = data[n:]
+	}
+
+	return s.ZK.Set(p, strconv.Itoa(chunks))
+}
+
+// loadSnapshot returns the *Snapshot stored with the snapshot ID.
+// ErrSnapshotNotExist is returned if the snapshot doesn't exist or is
+// incomplete. Snapshots stored as a single uncompressed znode are also read.
+func (s *Server) loadSnapshot(id string) (*Snapshot, error) {
+	p := s.snapshotPath(id)
+
+	data, err := s.ZK.Get(p)
+	if err != nil {
+		switch err.(type) {
+		case kafkazk.ErrNoNode:
+			return nil, ErrSnapshotNotExist
+		default:
+			return nil, err
+		}
+	}
+
+	if len(data) == 0 {
+		return nil, ErrSnapshotNotExist
+	}
+
+	// The znode holds the chunk count, otherwise the uncompressed snapshot.
+	if chunks, err := strconv.Atoi(string(data)); err == nil {
+		var compressed []byte
+		for i := 0; i < chunks; i++ {
+			chunk, err := s.ZK.Get(fmt.Sprintf("%s/%d", p, i))
+			if err != nil {
+				return nil, err
+			}
+			compressed = append(compressed, chunk...)
+		}
+
+		zr, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return nil, err
+		}
+
+		if data, err = ioutil.ReadAll(zr); err != nil {
+			return nil, err
+		}
+	}
+
+	var snap Snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return nil, err
+	}
+
+	return &snap, nil
+}
+
+// pruneSnapshots deletes the oldest snapshots beyond the snapshot retention.
+// Snapshots are retained indefinitely if the retention is 0.
+func (s *Server) pruneSnapshots() error {
+	if s.snapshotRetention <= 0 {
+		return nil
+	}
+
+	ids, err := s.ZK.Children(s.snapshotPath(""))
+	if err != nil {
+		return err
+	}
+
+	if len(ids) <= s.snapshotRetention {
+		return nil
+	}
+
+	// IDs are creation timestamps in nanoseconds.
+	sort.Slice(ids, func(i, j int) bool {
+		a, _ := strconv.ParseInt(ids[i], 10, 64)
+		b, _ := strconv.ParseInt(ids[j], 10, 64)
+		return a < b
+	})
+
+	for _, id := range ids[:len(ids)-s.snapshotRetention] {
+		if err := s.deleteSnapshot(id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteSnapshot deletes the snapshot ID znode along with its chunks.
+func (s *Server) deleteSnapshot(id string) error {
+	p := s.snapshotPath(id)
+
+	chunks, err := s.ZK.Children(p)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range chunks {
+		if err := s.ZK.Delete(fmt.Sprintf("%s/%s", p, c)); err != nil {
+			return err
+		}
+	}
+
+	return s.ZK.Delete(p)
+}
+
+// currentSnapshot returns a *Snapshot of the current cluster state.
+func (s *Server) currentSnapshot() (*Snapshot, error) {
+	brokers, err := s.fetchBrokerSet(&pb.BrokerRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := s.fetchTopicSet(&pb.TopicRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{
+		Timestamp: time.Now().Unix(),
+		Brokers:   brokers,
+		Topics:    topics,
+	}, nil
+}
+
+// diffSnapshots returns a *pb.SnapshotDiff describing the changes from
+// Snapshot a to Snapshot b.
+func diffSnapshots(a, b Snapshot) *pb.SnapshotDiff {
+	diff := &pb.SnapshotDiff{}
+
+	for id, broker := range b.Brokers {
+		prev, exists := a.Brokers[id]
+		switch {
+		case !exists:
+			diff.BrokersAdded = append(diff.BrokersAdded, id)
+		case !proto.Equal(prev, broker):
+			diff.BrokersChanged = append(diff.BrokersChanged, id)
+		}
+	}
+
+	for id := range a.Brokers {
+		if _, exists := b.Brokers[id]; !exists {
+			diff.BrokersRemoved = append(diff.BrokersRemoved, id)
+		}
+	}
+
+	for name, topic := range b.Topics {
+		prev, exists := a.Topics[name]
+		switch {
+		case !exists:
+			diff.TopicsAdded = append(diff.TopicsAdded, name)
+		case !proto.Equal(prev, topic):
+			diff.TopicsChanged = append(diff.TopicsChanged, name)
+		}
+	}
+
+	for name := range a.Topics {
+		if _, exists := b.Topics[name]; !exists {
+			diff.TopicsRemoved = append(diff.TopicsRemoved, name)
+		}
+	}
+
+	for _, ids := range [][]uint32{diff.BrokersAdded, diff.BrokersRemoved, diff.BrokersChanged} {
+		sort.Sort(idList(ids))
+	}
+
+	for _, names := range [][]string{diff.TopicsAdded, diff.TopicsRemoved, diff.TopicsChanged} {
+		sort.Strings(names)
+	}
+
+	return diff
+}
+
+// initSnapshots ensures that the snapshots znode exists.
+func (s *Server) initSnapshots() error {
+	p := s.snapshotPath("")
+
+	exist, err := s.ZK.Exists(p)
+	if err != nil {
+		return err
+	}
+
+	if !exist {
+		if err := s.ZK.Create(p, ""); err != nil {
+			switch err.(type) {
+			case kafkazk.ErrNoNode:
+				return fmt.Errorf("parent znode for %s does not exist", p)
+			default:
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// snapshotPath returns the znode path for the snapshot ID. If the ID is
+// empty, the parent snapshots znode path is returned.
+func (s *Server) snapshotPath(id string) string {
+	if id == "" {
+		return fmt.Sprintf("/%s/snapshots", s.zkPrefix)
+	}
+
+	return fmt.Sprintf("/%s/snapshots/%s", s.zkPrefix, id)
+}
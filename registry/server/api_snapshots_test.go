@@ -0,0 +1,185 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+)
+
+func TestDiffSnapshot(t *testing.T) {
+	s := testServer()
+
+	snap, err := s.CreateSnapshot(context.Background(), &pb.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// No changes.
+	diff, err := s.DiffSnapshot(context.Background(), &pb.SnapshotRequest{Id: snap.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diff.TopicsChanged) != 0 || len(diff.BrokersChanged) != 0 {
+		t.Errorf("Expected empty diff, got %v", diff)
+	}
+
+	// Mutate a tag.
+	_, err = s.TagTopic(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: []string{"team:eng"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err = s.DiffSnapshot(context.Background(), &pb.SnapshotRequest{Id: snap.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"test_topic"}
+	if !stringsEqual(diff.TopicsChanged, expected) {
+		t.Errorf("Expected changed topics %v, got %v", expected, diff.TopicsChanged)
+	}
+
+	for _, l := range [][]string{diff.TopicsAdded, diff.TopicsRemoved} {
+		if len(l) != 0 {
+			t.Errorf("Unexpected added/removed topics: %v", l)
+		}
+	}
+
+	if len(diff.BrokersAdded)+len(diff.BrokersRemoved)+len(diff.BrokersChanged) != 0 {
+		t.Errorf("Unexpected broker changes: %v", diff)
+	}
+}
+
+func TestDiffSnapshotNotExist(t *testing.T) {
+	s := testServer()
+
+	_, err := s.DiffSnapshot(context.Background(), &pb.SnapshotRequest{Id: "missing"})
+	if err != ErrSnapshotNotExist {
+		t.Errorf("Expected ErrSnapshotNotExist, got %v", err)
+	}
+
+	_, err = s.DiffSnapshot(context.Background(), &pb.SnapshotRequest{})
+	if err != ErrSnapshotIDEmpty {
+		t.Errorf("Expected ErrSnapshotIDEmpty, got %v", err)
+	}
+}
+
+// getErrHandler is a kafkazk.Handler where Get fails with err.
+type getErrHandler struct {
+	kafkazk.Handler
+	err error
+}
+
+func (h getErrHandler) Get(string) ([]byte, error) { return nil, h.err }
+
+func TestDiffSnapshotGetError(t *testing.T) {
+	s := testServer()
+
+	connErr := errors.New("zk: could not connect to a server")
+	s.ZK = getErrHandler{Handler: s.ZK, err: connErr}
+
+	_, err := s.DiffSnapshot(context.Background(), &pb.SnapshotRequest{Id: "1"})
+	if err != connErr {
+		t.Errorf("Expected error '%s', got '%v'", connErr, err)
+	}
+}
+
+func TestSnapshotChunks(t *testing.T) {
+	s := testServer()
+
+	defer func(n int) { snapshotChunkSize = n }(snapshotChunkSize)
+	snapshotChunkSize = 64
+
+	snap, err := s.CreateSnapshot(context.Background(), &pb.Empty{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := s.ZK.Children(s.snapshotPath(snap.Id))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(chunks) < 2 {
+		t.Fatalf("Expected multiple chunks, got %d", len(chunks))
+	}
+
+	for _, c := range chunks {
+		if data, _ := s.ZK.Get(s.snapshotPath(snap.Id) + "/" + c); len(data) > snapshotChunkSize {
+			t.Errorf("Chunk %s exceeds the chunk size: %d bytes", c, len(data))
+		}
+	}
+
+	diff, err := s.DiffSnapshot(context.Background(), &pb.SnapshotRequest{Id: snap.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diff.TopicsChanged)+len(diff.BrokersChanged) != 0 {
+		t.Errorf("Expected empty diff, got %v", diff)
+	}
+
+	// An incomplete snapshot, without a chunk count, doesn't exist.
+	s.ZK.Create(s.snapshotPath("1"), "")
+	s.ZK.Create(s.snapshotPath("1")+"/0", "data")
+
+	if _, err := s.DiffSnapshot(context.Background(), &pb.SnapshotRequest{Id: "1"}); err != ErrSnapshotNotExist {
+		t.Errorf("Expected ErrSnapshotNotExist, got %v", err)
+	}
+}
+
+func TestDiffSnapshotUncompressed(t *testing.T) {
+	s := testServer()
+
+	snap, err := s.currentSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := json.Marshal(snap)
+	s.ZK.Create(s.snapshotPath("1"), string(data))
+
+	diff, err := s.DiffSnapshot(context.Background(), &pb.SnapshotRequest{Id: "1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(diff.TopicsChanged)+len(diff.BrokersChanged) != 0 {
+		t.Errorf("Expected empty diff, got %v", diff)
+	}
+}
+
+func TestSnapshotRetention(t *testing.T) {
+	s := testServer()
+	s.snapshotRetention = 2
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		snap, err := s.CreateSnapshot(context.Background(), &pb.Empty{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, snap.Id)
+	}
+
+	// The oldest snapshot is deleted.
+	if _, err := s.DiffSnapshot(context.Background(), &pb.SnapshotRequest{Id: ids[0]}); err != ErrSnapshotNotExist {
+		t.Errorf("Expected ErrSnapshotNotExist, got %v", err)
+	}
+
+	for _, id := range ids[1:] {
+		if _, err := s.DiffSnapshot(context.Background(), &pb.SnapshotRequest{Id: id}); err != nil {
+			t.Errorf("Unexpected error for snapshot %s: %s", id, err)
+		}
+	}
+
+	remaining, _ := s.ZK.Children(s.snapshotPath(""))
+	if len(remaining) != 2 {
+		t.Errorf("Expected 2 snapshots, got %v", remaining)
+	}
+}
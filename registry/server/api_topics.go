@@ -2,10 +2,12 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"regexp"
 	"sort"
+	"time"
 
 	"github.com/DataDog/kafka-kit/v3/kafkaadmin"
 	"github.com/DataDog/kafka-kit/v3/kafkazk"
@@ -25,6 +27,10 @@ var (
 	ErrTopicAlreadyExists = errors.New("topic already exists")
 	// ErrInsufficientBrokers error.
 	ErrInsufficientBrokers = errors.New("insufficient number of brokers")
+	// ErrReplicationFactorInvalid error.
+	ErrReplicationFactorInvalid = errors.New("replication field must be greater than 0")
+	// ErrTopicReassigning error.
+	ErrTopicReassigning = errors.New("topic is undergoing a reassignment")
 	// Misc.
 	tregex = regexp.MustCompile(".*")
 )
@@ -113,6 +119,7 @@ func (s *Server) UnderReplicatedTopics(ctx context.Context, _ *pb.Empty) (*pb.To
 // CreateTopic creates a topic if it doesn't exist. Topic tags can optionally
 // be set at topic creation time. Additionally, topics can be created on
 // a target set of brokers by specifying the broker tag(s) in the request.
+// Brokers tagged for maintenance aren't assigned replicas.
 func (s *Server) CreateTopic(ctx context.Context, req *pb.CreateTopicRequest) (*pb.Empty, error) {
 	empty := &pb.Empty{}
 
@@ -141,10 +148,20 @@ func (s *Server) CreateTopic(ctx context.Context, req *pb.CreateTopicRequest) (*
 		return empty, ErrTopicAlreadyExists
 	}
 
-	// If we're targeting a specific set of brokers by tag, build
-	// a replica assignment.
+	if err := s.checkPolicy(ctx, "CreateTopic", req); err != nil {
+		return empty, err
+	}
+
+	// Get brokers in maintenance.
+	maintenance, err := s.maintenanceBrokers()
+	if err != nil {
+		return empty, err
+	}
+
+	// If we're targeting a specific set of brokers by tag or brokers must be
+	// avoided for maintenance, build a replica assignment.
 	var assignment kafkaadmin.ReplicaAssignment
-	if req.TargetBrokerTags != nil {
+	if req.TargetBrokerTags != nil || len(maintenance) > 0 {
 		// Create a stub map with the provided request dimensions.
 		opts := kafkazk.Populate(
 			req.Topic.Name,
@@ -153,18 +170,34 @@ func (s *Server) CreateTopic(ctx context.Context, req *pb.CreateTopicRequest) (*
 		)
 		pMap := kafkazk.NewPartitionMap(opts)
 
-		// Fetch brokers by tag.
-		reqParams := &pb.BrokerRequest{Tag: req.TargetBrokerTags}
-		resp, err := s.ListBrokers(ctx, reqParams)
-		if err != nil {
-			return empty, err
+		// Get the live broker metadata.
+		brokerState, errs := s.ZK.GetAllBrokerMeta(false)
+		if errs != nil {
+			return empty, ErrFetchingBrokers
 		}
 
-		targetBrokerIDs := make([]int, len(resp.Ids))
-		for i := range resp.Ids {
-			targetBrokerIDs[i] = int(resp.Ids[i])
+		// Fetch brokers by tag, otherwise target all brokers.
+		var targetBrokerIDs []int
+		if req.TargetBrokerTags != nil {
+			reqParams := &pb.BrokerRequest{Tag: req.TargetBrokerTags}
+			resp, err := s.ListBrokers(ctx, reqParams)
+			if err != nil {
+				return empty, err
+			}
+
+			for _, id := range resp.Ids {
+				targetBrokerIDs = append(targetBrokerIDs, int(id))
+			}
+		} else {
+			for id := range brokerState {
+				targetBrokerIDs = append(targetBrokerIDs, id)
+			}
+			sort.Ints(targetBrokerIDs)
 		}
 
+		// Exclude brokers in maintenance.
+		targetBrokerIDs = excludeBrokers(targetBrokerIDs, maintenance)
+
 		if len(targetBrokerIDs) < int(req.Topic.Replication) {
 			return empty, ErrInsufficientBrokers
 		}
@@ -172,12 +205,6 @@ func (s *Server) CreateTopic(ctx context.Context, req *pb.CreateTopicRequest) (*
 		// Create a stub BrokerMap.
 		bMap := kafkazk.NewBrokerMap()
 
-		// Get the live broker metadata.
-		brokerState, errs := s.ZK.GetAllBrokerMeta(false)
-		if errs != nil {
-			return empty, ErrFetchingBrokers
-		}
-
 		// Update the BrokerMap with the target broker list.
 		// XXX we don't catch any errors here, such as provided
 		// brokers being marked as missing. This is because we're
@@ -251,10 +278,143 @@ func (s *Server) DeleteTopic(ctx context.Context, req *pb.TopicRequest) (*pb.Emp
 		return empty, ErrTopicNotExist
 	}
 
+	if err := s.checkPolicy(ctx, "DeleteTopic", req); err != nil {
+		return empty, err
+	}
+
 	// Make the delete request.
 	return empty, s.kafkaadmin.DeleteTopic(ctx, req.Name)
 }
 
+// ChangeReplicationFactor builds a reassignment plan that sets the replication
+// factor of the topic specified in the req.Name field to req.Replication. New
+// replicas are placed with the same placement logic used by topicmappr, scoped
+// to brokers matching req.TargetBrokerTags if specified or all brokers
+// otherwise. Brokers tagged for maintenance are never targets; as with brokers
+// not matching req.TargetBrokerTags, replicas they hold are moved. The plan
+// only includes partitions that change. If req.Apply is true, the plan is
+// submitted as a reassignment.
+func (s *Server) ChangeReplicationFactor(ctx context.Context, req *pb.ReplicationFactorRequest) (*pb.ReplicationFactorResponse, error) {
+	ctx, err := s.ValidateRequest(ctx, req, writeRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, ErrTopicNameEmpty
+	}
+
+	if req.Replication == 0 {
+		return nil, ErrReplicationFactorInvalid
+	}
+
+	// Ensure that the topic exists.
+	resp, err := s.ListTopics(ctx, &pb.TopicRequest{Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Names) == 0 {
+		return nil, ErrTopicNotExist
+	}
+
+	// Don't stack changes on an in progress reassignment.
+	if _, reassigning := s.ZK.GetReassignments()[req.Name]; reassigning {
+		return nil, ErrTopicReassigning
+	}
+
+	if err := s.checkPolicy(ctx, "ChangeReplicationFactor", req); err != nil {
+		return nil, err
+	}
+
+	pm, err := s.ZK.GetPartitionMap(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the live broker metadata.
+	brokerState, errs := s.ZK.GetAllBrokerMeta(false)
+	if errs != nil {
+		return nil, ErrFetchingBrokers
+	}
+
+	// Get the target broker list.
+	var targetBrokerIDs []int
+	if len(req.TargetBrokerTags) > 0 {
+		resp, err := s.ListBrokers(ctx, &pb.BrokerRequest{Tag: req.TargetBrokerTags})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, id := range resp.Ids {
+			targetBrokerIDs = append(targetBrokerIDs, int(id))
+		}
+	} else {
+		for id := range brokerState {
+			targetBrokerIDs = append(targetBrokerIDs, id)
+		}
+	}
+
+	// Exclude brokers in maintenance.
+	maintenance, err := s.maintenanceBrokers()
+	if err != nil {
+		return nil, err
+	}
+
+	targetBrokerIDs = excludeBrokers(targetBrokerIDs, maintenance)
+
+	if len(targetBrokerIDs) < int(req.Replication) {
+		return nil, ErrInsufficientBrokers
+	}
+
+	sort.Ints(targetBrokerIDs)
+
+	// Get a BrokerMap from the current map and update it with the target
+	// broker list. As with CreateTopic, we're only using brokers fetched
+	// from the cluster state and don't need to handle Update errors.
+	bMap := kafkazk.BrokerMapFromPartitionMap(pm, brokerState, false)
+	bMap.Update(targetBrokerIDs, brokerState)
+
+	// Set the replication factor; this appends stub brokers to replica sets
+	// that are increasing, which are then replaced in the rebuild.
+	pmOut := pm.Copy()
+	pmOut.SetReplication(int(req.Replication))
+
+	rebuildParams := kafkazk.RebuildParams{
+		BM:       bMap,
+		Strategy: "count",
+	}
+
+	pmOut, errs = pmOut.Rebuild(rebuildParams)
+	if errs != nil {
+		return nil, fmt.Errorf("%s", errs)
+	}
+
+	// Only include partitions that change.
+	plan := kafkazk.NewPartitionMap()
+	for i := range pm.Partitions {
+		if !pm.Partitions[i].Equal(pmOut.Partitions[i]) {
+			plan.Partitions = append(plan.Partitions, pmOut.Partitions[i])
+		}
+	}
+
+	out, err := json.Marshal(plan)
+	if err != nil {
+		return nil, err
+	}
+
+	rfResp := &pb.ReplicationFactorResponse{Plan: string(out)}
+
+	if req.Apply && len(plan.Partitions) > 0 {
+		if err := s.ZK.SubmitReassignment(plan); err != nil {
+			return nil, err
+		}
+		rfResp.Applied = true
+	}
+
+	return rfResp, nil
+}
+
 // TopicMappings returns all broker IDs that hold at least one partition for
 // the requested topic. The topic is specified in the TopicRequest.Name
 // field.
@@ -337,7 +497,20 @@ func (s *Server) TagTopic(ctx context.Context, req *pb.TopicRequest) (*pb.TagRes
 	}
 
 	// Set the tags.
-	err = s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: req.Name}, ts)
+	o := KafkaObject{Type: "topic", ID: req.Name}
+
+	// Ensure that no immutable tags are being changed.
+	if err := s.Tags.CheckImmutable(o, ts, nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPolicy(ctx, "TagTopic", req); err != nil {
+		return nil, err
+	}
+
+	ttl := time.Duration(req.TtlSeconds) * time.Second
+
+	err = s.Tags.SetTags(o, ts, ttl)
 	if err != nil {
 		return nil, err
 	}
@@ -376,7 +549,18 @@ func (s *Server) DeleteTopicTags(ctx context.Context, req *pb.TopicRequest) (*pb
 	}
 
 	// Delete the tags.
-	err = s.Tags.Store.DeleteTags(KafkaObject{Type: "topic", ID: req.Name}, req.Tag)
+	o := KafkaObject{Type: "topic", ID: req.Name}
+
+	// Ensure that no immutable tags are being deleted.
+	if err := s.Tags.CheckImmutable(o, nil, req.Tag); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkPolicy(ctx, "DeleteTopicTags", req); err != nil {
+		return nil, err
+	}
+
+	err = s.Tags.Store.DeleteTags(o, req.Tag)
 	if err != nil {
 		return nil, err
 	}
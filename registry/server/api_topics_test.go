@@ -4,6 +4,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
 	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
 )
 
@@ -182,6 +183,57 @@ func TestTagTopic(t *testing.T) {
 	}
 }
 
+func TestTagTopicImmutable(t *testing.T) {
+	s := testServer()
+	s.Tags.ImmutableKeys = map[string]struct{}{"created_by": {}}
+
+	// Requests are applied in order.
+	tests := []*pb.TopicRequest{
+		// Initial set.
+		&pb.TopicRequest{Name: "test_topic", Tag: []string{"created_by:a", "k:v"}},
+		// Setting the current value.
+		&pb.TopicRequest{Name: "test_topic", Tag: []string{"created_by:a"}},
+		// Mutable tags can be changed.
+		&pb.TopicRequest{Name: "test_topic", Tag: []string{"k:v2"}},
+		// Modification.
+		&pb.TopicRequest{Name: "test_topic", Tag: []string{"created_by:b"}},
+		// Initial set on another topic.
+		&pb.TopicRequest{Name: "test_topic2", Tag: []string{"created_by:b"}},
+	}
+
+	expected := []error{
+		nil,
+		nil,
+		nil,
+		ErrImmutableTag{t: "created_by"},
+		nil,
+	}
+
+	for i, req := range tests {
+		_, err := s.TagTopic(context.Background(), req)
+		if err != expected[i] {
+			t.Errorf("[test %d] Expected err '%v', got '%v'", i, expected[i], err)
+		}
+	}
+
+	// Deletion.
+	req := &pb.TopicRequest{Name: "test_topic", Tag: []string{"created_by"}}
+	_, err := s.DeleteTopicTags(context.Background(), req)
+	if err != (ErrImmutableTag{t: "created_by"}) {
+		t.Errorf("Expected err '%v', got '%v'", ErrImmutableTag{t: "created_by"}, err)
+	}
+
+	// The original value is retained.
+	resp, err := s.GetTopics(context.Background(), &pb.TopicRequest{Name: "test_topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := resp.Topics["test_topic"].Tags["created_by"]; v != "a" {
+		t.Errorf("Expected created_by tag value 'a', got '%s'", v)
+	}
+}
+
 func TestDeleteTopicTags(t *testing.T) {
 	s := testServer()
 
@@ -280,3 +332,100 @@ func TestTopicMappings(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestChangeReplicationFactor(t *testing.T) {
+	s := testServer()
+
+	req := &pb.ReplicationFactorRequest{
+		Name:        "test_topic",
+		Replication: 3,
+	}
+
+	resp, err := s.ChangeReplicationFactor(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := kafkazk.PartitionMapFromString(resp.Plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	brokerState, _ := s.ZK.GetAllBrokerMeta(false)
+	current, _ := s.ZK.GetPartitionMap("test_topic")
+
+	// Partitions 0 and 1 are at RF=2; 2 and 3 are already at RF=3.
+	if len(plan.Partitions) != 2 {
+		t.Fatalf("Expected 2 partitions in plan, got %d", len(plan.Partitions))
+	}
+
+	for _, p := range plan.Partitions {
+		orig := current.Partitions[p.Partition].Replicas
+
+		if len(p.Replicas) != len(orig)+1 {
+			t.Errorf("Expected exactly one added replica for p%d, got %v", p.Partition, p.Replicas)
+			continue
+		}
+
+		// Existing replicas are retained.
+		racks := map[string]struct{}{}
+		for i, id := range orig {
+			if p.Replicas[i] != id {
+				t.Errorf("Expected existing replicas %v retained for p%d, got %v", orig, p.Partition, p.Replicas)
+			}
+			racks[brokerState[id].Rack] = struct{}{}
+		}
+
+		// The new replica is placed in a unique rack.
+		added := p.Replicas[len(p.Replicas)-1]
+		if _, exists := racks[brokerState[added].Rack]; exists {
+			t.Errorf("Added replica %d for p%d violates rack constraints", added, p.Partition)
+		}
+	}
+
+	// Apply the change.
+	req.Apply = true
+
+	resp, err = s.ChangeReplicationFactor(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !resp.Applied {
+		t.Error("Expected plan to be applied")
+	}
+
+	if _, err := s.ZK.Get("/admin/reassign_partitions"); err != nil {
+		t.Errorf("Expected reassignment to be submitted: %s", err)
+	}
+
+	// A reassignment is already in progress.
+	if _, err := s.ChangeReplicationFactor(context.Background(), req); err != kafkazk.ErrReassignmentInProgress {
+		t.Errorf("Expected error '%s', got '%v'", kafkazk.ErrReassignmentInProgress, err)
+	}
+}
+
+func TestChangeReplicationFactorFailures(t *testing.T) {
+	s := testServer()
+
+	testRequests := map[int]*pb.ReplicationFactorRequest{
+		0: &pb.ReplicationFactorRequest{Replication: 3},
+		1: &pb.ReplicationFactorRequest{Name: "test_topic"},
+		2: &pb.ReplicationFactorRequest{Name: "test_topic20", Replication: 3},
+		3: &pb.ReplicationFactorRequest{Name: "test_topic", Replication: 10},
+	}
+
+	expected := map[int]error{
+		0: ErrTopicNameEmpty,
+		1: ErrReplicationFactorInvalid,
+		2: ErrTopicNotExist,
+		3: ErrInsufficientBrokers,
+	}
+
+	for k := range testRequests {
+		_, err := s.ChangeReplicationFactor(context.Background(), testRequests[k])
+		if err != expected[k] {
+			t.Errorf("Unexpected error '%s', got '%s'", expected[k], err)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+)
+
+// auditSubscriberBuffer is the number of entries buffered per TailAuditLog
+// subscriber. Entries are dropped for subscribers with full buffers.
+const auditSubscriberBuffer = 64
+
+// auditedMethods maps each audited RPC to the type of entity it acts on.
+var auditedMethods = map[string]string{
+	"CreateTopic":             "topic",
+	"DeleteTopic":             "topic",
+	"ChangeReplicationFactor": "topic",
+	"TagTopic":                "topic",
+	"DeleteTopicTags":         "topic",
+	"TagBroker":               "broker",
+	"DeleteBrokerTags":        "broker",
+	"RemoveBroker":            "broker",
+	"CreateSnapshot":          "snapshot",
+	"Apply":                   "reconciliation",
+}
+
+// auditLog fans out audit entries to TailAuditLog subscribers.
+type auditLog struct {
+	mu          sync.Mutex
+	subscribers map[chan *pb.AuditEntry]struct{}
+}
+
+func newAuditLog() *auditLog {
+	return &auditLog{subscribers: map[chan *pb.AuditEntry]struct{}{}}
+}
+
+// subscribe returns a channel receiving all subsequently published entries.
+func (a *auditLog) subscribe() chan *pb.AuditEntry {
+	c := make(chan *pb.AuditEntry, auditSubscriberBuffer)
+
+	a.mu.Lock()
+	a.subscribers[c] = struct{}{}
+	a.mu.Unlock()
+
+	return c
+}
+
+// unsubscribe removes a channel returned by subscribe.
+func (a *auditLog) unsubscribe(c chan *pb.AuditEntry) {
+	a.mu.Lock()
+	delete(a.subscribers, c)
+	a.mu.Unlock()
+}
+
+// publish sends the entry to all subscribers without blocking.
+func (a *auditLog) publish(e *pb.AuditEntry) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for c := range a.subscribers {
+		select {
+		case c <- e:
+		default:
+			log.Printf("Audit log subscriber buffer full, dropping %s entry\n", e.Method)
+		}
+	}
+}
+
+// auditInterceptor is a grpc.UnaryServerInterceptor that publishes an audit
+// entry for each audited request that completes without error.
+func (s *Server) auditInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	// Methods are named /package.Service/Method.
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+
+	if e, ok := auditEntry(ctx, method, req); ok {
+		s.audit.publish(e)
+	}
+
+	return resp, err
+}
+
+// auditEntry takes a request context, RPC name and request and returns a
+// *pb.AuditEntry. False is returned if the request isn't audited, such as
+// non-audited methods and replication factor changes that aren't applied.
+func auditEntry(ctx context.Context, method string, req interface{}) (*pb.AuditEntry, bool) {
+	entityType, audited := auditedMethods[method]
+	if !audited {
+		return nil, false
+	}
+
+	e := &pb.AuditEntry{
+		Timestamp:  time.Now().Unix(),
+		Method:     method,
+		EntityType: entityType,
+	}
+
+	switch r := req.(type) {
+	case *pb.TopicRequest:
+		e.Entity = r.Name
+	case *pb.BrokerRequest:
+		e.Entity = fmt.Sprintf("%d", r.Id)
+	case *pb.CreateTopicRequest:
+		if r.Topic != nil {
+			e.Entity = r.Topic.Name
+		}
+	case *pb.ReplicationFactorRequest:
+		if !r.Apply {
+			return nil, false
+		}
+		e.Entity = r.Name
+	}
+
+	if p, ok := peer.FromContext(ctx); ok {
+		e.Requestor = p.Addr.String()
+	}
+
+	if b, err := json.Marshal(req); err == nil {
+		e.Request = string(b)
+	}
+
+	return e, true
+}
+
+// TailAuditLog streams a *pb.AuditEntry for each audited request completed
+// while subscribed. Entries are filtered by each non-empty *pb.AuditLogRequest
+// field. The stream ends when the client cancels the request.
+func (s *Server) TailAuditLog(req *pb.AuditLogRequest, stream pb.Registry_TailAuditLogServer) error {
+	// The derived context carries the request timeout; the stream runs until
+	// the client cancels.
+	if _, err := s.ValidateRequest(stream.Context(), req, readRequest); err != nil {
+		return err
+	}
+
+	entries := s.audit.subscribe()
+	defer s.audit.unsubscribe(entries)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e := <-entries:
+			if !auditEntryMatches(req, e) {
+				continue
+			}
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// auditEntryMatches returns whether the *pb.AuditEntry matches all non-empty
+// *pb.AuditLogRequest filters.
+func auditEntryMatches(req *pb.AuditLogRequest, e *pb.AuditEntry) bool {
+	switch {
+	case req.EntityType != "" && req.EntityType != e.EntityType:
+		return false
+	case req.Entity != "" && req.Entity != e.Entity:
+		return false
+	case req.Method != "" && req.Method != e.Method:
+		return false
+	}
+
+	return true
+}
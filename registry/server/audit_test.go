@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+
+	"google.golang.org/grpc"
+)
+
+// fakeAuditStream is a pb.Registry_TailAuditLogServer that forwards sent
+// entries to a channel.
+type fakeAuditStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	entries chan *pb.AuditEntry
+}
+
+func (f *fakeAuditStream) Context() context.Context { return f.ctx }
+
+func (f *fakeAuditStream) Send(e *pb.AuditEntry) error {
+	f.entries <- e
+	return nil
+}
+
+func TestTailAuditLog(t *testing.T) {
+	s := testServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeAuditStream{ctx: ctx, entries: make(chan *pb.AuditEntry, 10)}
+
+	done := make(chan error)
+	go func() { done <- s.TailAuditLog(&pb.AuditLogRequest{EntityType: "topic"}, stream) }()
+
+	// Wait for the subscription.
+	for i := 0; ; i++ {
+		s.audit.mu.Lock()
+		n := len(s.audit.subscribers)
+		s.audit.mu.Unlock()
+
+		if n == 1 {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Timed out waiting for the TailAuditLog subscription")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	tagTopic := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.TagTopic(ctx, req.(*pb.TopicRequest))
+	}
+
+	tagBroker := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.TagBroker(ctx, req.(*pb.BrokerRequest))
+	}
+
+	// The broker tag change is filtered by the subscriber.
+	_, err := s.auditInterceptor(context.Background(), &pb.BrokerRequest{Id: 1001, Tag: []string{"k:v"}},
+		&grpc.UnaryServerInfo{FullMethod: "/registry.Registry/TagBroker"}, tagBroker)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = s.auditInterceptor(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: []string{"k:v"}},
+		&grpc.UnaryServerInfo{FullMethod: "/registry.Registry/TagTopic"}, tagTopic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case e := <-stream.entries:
+		if e.Method != "TagTopic" || e.EntityType != "topic" || e.Entity != "test_topic" {
+			t.Errorf("Unexpected audit entry %v", e)
+		}
+		if e.Request == "" {
+			t.Error("Expected the request in the audit entry")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the audit entry")
+	}
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	if len(stream.entries) != 0 {
+		t.Errorf("Expected no further audit entries, got %d", len(stream.entries))
+	}
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+)
+
+var (
+	// Characters not permitted in Prometheus label names.
+	invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+)
+
+// inventoryHandler returns an http.Handler that serves the cluster inventory
+// in the Prometheus text exposition format.
+func (s *Server) inventoryHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Buffer the output so that errors can be returned as a 500.
+		var buf bytes.Buffer
+		if err := s.writeInventoryMetrics(&buf); err != nil {
+			log.Printf("Error generating inventory metrics: %s\n", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		buf.WriteTo(w)
+	})
+}
+
+// writeInventoryMetrics writes the registry's view of the cluster as
+// Prometheus gauges: the topic and broker counts, the partition count and
+// replication factor of each topic, and an info series for each broker.
+// Topic and broker series are additionally labeled with the values of the
+// configured inventory tag keys, bounding label cardinality to the keys that
+// operators opt into.
+func (s *Server) writeInventoryMetrics(w io.Writer) error {
+	topics, err := s.fetchTopicSet(&pb.TopicRequest{})
+	if err != nil {
+		return err
+	}
+
+	brokers, err := s.fetchBrokerSet(&pb.BrokerRequest{})
+	if err != nil {
+		return err
+	}
+
+	names := topics.Names()
+	sort.Strings(names)
+
+	topicLabels := map[string]string{}
+	for _, t := range names {
+		ts, err := s.Tags.TagSetFromObject(topics[t])
+		if err != nil {
+			return err
+		}
+		topicLabels[t] = formatLabels(
+			[]string{"topic"}, []string{t},
+			s.inventoryTopicTags, ts,
+		)
+	}
+
+	writeGauge(w, "kafka_topics", "Number of topics.")
+	fmt.Fprintf(w, "kafka_topics %d\n", len(names))
+
+	writeGauge(w, "kafka_topic_partitions", "Number of partitions per topic.")
+	for _, t := range names {
+		fmt.Fprintf(w, "kafka_topic_partitions%s %d\n", topicLabels[t], topics[t].Partitions)
+	}
+
+	writeGauge(w, "kafka_topic_replication_factor", "Replication factor per topic.")
+	for _, t := range names {
+		fmt.Fprintf(w, "kafka_topic_replication_factor%s %d\n", topicLabels[t], topics[t].Replication)
+	}
+
+	ids := brokers.IDs()
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	writeGauge(w, "kafka_brokers", "Number of brokers.")
+	fmt.Fprintf(w, "kafka_brokers %d\n", len(ids))
+
+	writeGauge(w, "kafka_broker_info", "Registered brokers, labeled by the configured broker tags.")
+	for _, id := range ids {
+		ts, err := s.Tags.TagSetFromObject(brokers[id])
+		if err != nil {
+			return err
+		}
+		labels := formatLabels(
+			[]string{"broker_id"}, []string{fmt.Sprint(id)},
+			s.inventoryBrokerTags, ts,
+		)
+		fmt.Fprintf(w, "kafka_broker_info%s 1\n", labels)
+	}
+
+	return nil
+}
+
+func writeGauge(w io.Writer, name, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+}
+
+// formatLabels takes the fixed label names and values along with the tag keys
+// to include as labels and the object's TagSet, returning a Prometheus label
+// set. Tag labels are named by the tag key, prefixed with tag_ and sanitized
+// into a valid label name (e.g. team becomes tag_team); tags that aren't set
+// have an empty value.
+func formatLabels(names, values []string, keys []string, ts TagSet) string {
+	var pairs []string
+
+	for i := range names {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, names[i], labelValueEscaper.Replace(values[i])))
+	}
+
+	for _, k := range keys {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, tagLabelName(k), labelValueEscaper.Replace(ts[k])))
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+// tagLabelName returns the label name for a tag key.
+func tagLabelName(k string) string {
+	return "tag_" + invalidLabelChars.ReplaceAllString(k, "_")
+}
+
+// validateInventoryTagKeys returns an error if any of the tag keys share a
+// label name once sanitized (e.g. a-b and a.b), since a series can't have two
+// labels of the same name.
+func validateInventoryTagKeys(keys []string) error {
+	seen := map[string]string{}
+
+	for _, k := range keys {
+		name := tagLabelName(k)
+		if prev, exists := seen[name]; exists {
+			return fmt.Errorf("inventory tag keys '%s' and '%s' both map to label %s", prev, k, name)
+		}
+		seen[name] = k
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestInventoryMetrics(t *testing.T) {
+	s := testServer()
+	s.inventoryTopicTags = []string{"team"}
+	s.inventoryBrokerTags = []string{"rack"}
+
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic"}, TagSet{"team": "eng"})
+
+	r := httptest.NewRecorder()
+	s.inventoryHandler().ServeHTTP(r, httptest.NewRequest(http.MethodGet, "/inventory/metrics", nil))
+
+	if r.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", r.Code)
+	}
+
+	body := r.Body.String()
+
+	// The stub has two topics with five partitions each.
+	expected := []string{
+		"# TYPE kafka_topic_partitions gauge",
+		`kafka_topic_partitions{topic="test_topic",tag_team="eng"} 5`,
+		`kafka_topic_partitions{topic="test_topic2",tag_team=""} 5`,
+		`kafka_topic_replication_factor{topic="test_topic",tag_team="eng"} 2`,
+		"kafka_topics 2",
+		`kafka_broker_info{broker_id="1001",tag_rack="a"} 1`,
+	}
+
+	for _, e := range expected {
+		if !strings.Contains(body, e+"\n") {
+			t.Errorf("Expected series '%s' in:\n%s", e, body)
+		}
+	}
+}
+
+func TestValidateInventoryTagKeys(t *testing.T) {
+	if err := validateInventoryTagKeys([]string{"team", "a-b", "ab"}); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	// Both sanitize to tag_a_b.
+	if err := validateInventoryTagKeys([]string{"team", "a-b", "a.b"}); err == nil {
+		t.Error("Expected an error for colliding tag keys")
+	}
+
+	_, err := NewServer(Config{
+		ReadReqRate:         10,
+		WriteReqRate:        10,
+		ZKTagsPrefix:        "registry",
+		InventoryBrokerTags: []string{"rack", "rack"},
+	})
+
+	if err == nil {
+		t.Error("Expected NewServer to reject colliding inventory tag keys")
+	}
+}
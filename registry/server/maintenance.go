@@ -0,0 +1,38 @@
+package server
+
+import (
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+)
+
+// maintenanceTag marks brokers going down for maintenance. Tagged brokers are
+// excluded from the candidate brokers of placement related RPCs so that data
+// isn't moved onto them.
+const maintenanceTag = "maintenance:true"
+
+// maintenanceBrokers returns the set of broker IDs tagged for maintenance.
+func (s *Server) maintenanceBrokers() (map[int]struct{}, error) {
+	brokers, err := s.fetchBrokerSet(&pb.BrokerRequest{Tag: []string{maintenanceTag}})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := map[int]struct{}{}
+	for id := range brokers {
+		ids[int(id)] = struct{}{}
+	}
+
+	return ids, nil
+}
+
+// excludeBrokers takes a []int of broker IDs and a set of broker IDs to
+// exclude and returns the remaining IDs, preserving order.
+func excludeBrokers(ids []int, exclude map[int]struct{}) []int {
+	var remaining []int
+	for _, id := range ids {
+		if _, excluded := exclude[id]; !excluded {
+			remaining = append(remaining, id)
+		}
+	}
+
+	return remaining
+}
@@ -0,0 +1,128 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PolicyRequest describes a mutating operation submitted to a PolicyEngine.
+type PolicyRequest struct {
+	// The RPC name, e.g. CreateTopic.
+	Operation string `json:"operation"`
+	// The RPC request message.
+	Request interface{} `json:"request"`
+}
+
+// PolicyDecision is a PolicyEngine decision. If Allow is false, Message
+// describes why the operation was denied.
+type PolicyDecision struct {
+	Allow   bool   `json:"allow"`
+	Message string `json:"message"`
+}
+
+// PolicyEngine decides whether mutating operations are permitted.
+type PolicyEngine interface {
+	Evaluate(context.Context, PolicyRequest) (PolicyDecision, error)
+}
+
+// PolicyFunc is a func that implements PolicyEngine, allowing a policy to be
+// embedded in the registry.
+type PolicyFunc func(context.Context, PolicyRequest) (PolicyDecision, error)
+
+// Evaluate calls f(ctx, req).
+func (f PolicyFunc) Evaluate(ctx context.Context, req PolicyRequest) (PolicyDecision, error) {
+	return f(ctx, req)
+}
+
+// HTTPPolicyEngine is a PolicyEngine that delegates decisions to an external
+// policy service. The PolicyRequest is POSTed as the input document
+// ({"input": {...}}) and a {"result": {"allow": bool, "message": string}}
+// response is expected. This is compatible with the OPA data API.
+type HTTPPolicyEngine struct {
+	URL    string
+	Client *http.Client
+}
+
+// NewHTTPPolicyEngine returns an *HTTPPolicyEngine for the policy URL.
+func NewHTTPPolicyEngine(url string) *HTTPPolicyEngine {
+	return &HTTPPolicyEngine{URL: url, Client: http.DefaultClient}
+}
+
+// Evaluate requests a decision from the policy service. The request is bound
+// to the context.
+func (p *HTTPPolicyEngine) Evaluate(ctx context.Context, req PolicyRequest) (PolicyDecision, error) {
+	var decision PolicyDecision
+
+	body, err := json.Marshal(struct {
+		Input PolicyRequest `json:"input"`
+	}{req})
+	if err != nil {
+		return decision, err
+	}
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return decision, err
+	}
+
+	r.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.Client.Do(r)
+	if err != nil {
+		return decision, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return decision, fmt.Errorf("policy service returned %s", resp.Status)
+	}
+
+	var result struct {
+		Result *PolicyDecision `json:"result"`
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return decision, err
+	}
+
+	// An undefined result, e.g. where the policy doesn't exist, is treated
+	// as an error rather than a deny.
+	if result.Result == nil {
+		return decision, fmt.Errorf("policy service returned no result")
+	}
+
+	return *result.Result, nil
+}
+
+// SetPolicyEngine sets the PolicyEngine consulted before mutating topic
+// operations. A nil PolicyEngine permits all operations.
+func (s *Server) SetPolicyEngine(p PolicyEngine) {
+	s.policy = p
+}
+
+// checkPolicy evaluates the operation and request against the configured
+// PolicyEngine, if any. A PermissionDenied status error carrying the policy
+// message is returned if the operation is denied. Operations are also denied
+// if the policy can't be evaluated.
+func (s *Server) checkPolicy(ctx context.Context, op string, req interface{}) error {
+	if s.policy == nil {
+		return nil
+	}
+
+	d, err := s.policy.Evaluate(ctx, PolicyRequest{Operation: op, Request: req})
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "policy evaluation failed: %s", err)
+	}
+
+	if !d.Allow {
+		return status.Errorf(codes.PermissionDenied, "denied by policy: %s", d.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCreateTopicPolicyDenied(t *testing.T) {
+	s := testServer()
+
+	var evaluated PolicyRequest
+	s.SetPolicyEngine(PolicyFunc(func(_ context.Context, req PolicyRequest) (PolicyDecision, error) {
+		evaluated = req
+		return PolicyDecision{Allow: false, Message: "topics must be owned by a team"}, nil
+	}))
+
+	req := &pb.CreateTopicRequest{
+		Topic: &pb.Topic{Name: "new_topic", Partitions: 1, Replication: 2},
+	}
+
+	_, err := s.CreateTopic(context.Background(), req)
+
+	st, _ := status.FromError(err)
+	if st.Code() != codes.PermissionDenied {
+		t.Fatalf("Expected a PermissionDenied error, got %v", err)
+	}
+
+	if expected := "denied by policy: topics must be owned by a team"; st.Message() != expected {
+		t.Errorf("Expected message '%s', got '%s'", expected, st.Message())
+	}
+
+	if evaluated.Operation != "CreateTopic" || evaluated.Request != req {
+		t.Errorf("Unexpected policy request %+v", evaluated)
+	}
+}
+
+// configRecorder is a kafkazk.Handler that records UpdateKafkaConfig calls.
+type configRecorder struct {
+	kafkazk.Handler
+	updates []kafkazk.KafkaConfig
+}
+
+func (zk *configRecorder) UpdateKafkaConfig(c kafkazk.KafkaConfig) ([]bool, error) {
+	zk.updates = append(zk.updates, c)
+	return nil, nil
+}
+
+func TestApplyPolicyDenied(t *testing.T) {
+	s := testServer()
+	zk := &configRecorder{Handler: s.ZK}
+	s.ZK = zk
+
+	var ops []string
+	s.SetPolicyEngine(PolicyFunc(func(_ context.Context, req PolicyRequest) (PolicyDecision, error) {
+		ops = append(ops, req.Operation)
+		return PolicyDecision{Allow: false, Message: "retention changes require review"}, nil
+	}))
+
+	req := &pb.ReconciliationPlan{
+		Actions: []*pb.TopicAction{
+			{Type: actionUpdate, Name: "test_topic", SetConfigs: map[string]string{"retention.ms": "1"}},
+			{Type: actionUpdate, Name: "test_topic", SetTags: map[string]string{"team": "eng"}},
+			{Type: actionUpdate, Name: "test_topic", DeleteTags: []string{"team"}},
+		},
+	}
+
+	resp, err := s.Apply(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, r := range resp.Results {
+		if r.Applied {
+			t.Errorf("[action %d] Expected the update to be denied", i)
+		}
+	}
+
+	if len(zk.updates) != 0 {
+		t.Errorf("Expected no config updates, got %v", zk.updates)
+	}
+
+	expected := []string{"UpdateTopicConfig", "TagTopic", "DeleteTopicTags"}
+	if !stringsEqual(ops, expected) {
+		t.Errorf("Expected policy operations %v, got %v", expected, ops)
+	}
+
+	tags, _ := s.Tags.Store.GetTags(KafkaObject{Type: "topic", ID: "test_topic"})
+	if _, exists := tags["team"]; exists {
+		t.Errorf("Expected no team tag, got %v", tags)
+	}
+}
+
+func TestHTTPPolicyEngine(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input struct {
+				Operation string `json:"operation"`
+			} `json:"input"`
+		}
+
+		json.NewDecoder(r.Body).Decode(&body)
+
+		// Deny deletes.
+		allow := body.Input.Operation != "DeleteTopic"
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"result": PolicyDecision{Allow: allow, Message: "deletes are disabled"},
+		})
+	}))
+	defer ts.Close()
+
+	p := NewHTTPPolicyEngine(ts.URL)
+
+	d, err := p.Evaluate(context.Background(), PolicyRequest{Operation: "CreateTopic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !d.Allow {
+		t.Error("Expected CreateTopic to be allowed")
+	}
+
+	d, _ = p.Evaluate(context.Background(), PolicyRequest{Operation: "DeleteTopic"})
+	if d.Allow || d.Message != "deletes are disabled" {
+		t.Errorf("Expected DeleteTopic to be denied, got %+v", d)
+	}
+}
@@ -0,0 +1,186 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc"
+)
+
+// readCacheMaxEntries is the maximum number of distinct requests with cached
+// responses. Responses for requests beyond this aren't cached.
+const readCacheMaxEntries = 1024
+
+// cachedReadMethods are the RPCs whose most recent successful response is
+// cached for serving while in read-only mode.
+var cachedReadMethods = map[string]struct{}{
+	"GetBrokers":            {},
+	"ListBrokers":           {},
+	"UnmappedBrokers":       {},
+	"GetTopics":             {},
+	"ListTopics":            {},
+	"ReassigningTopics":     {},
+	"UnderReplicatedTopics": {},
+	"RecommendTopicConfig":  {},
+	"TopicMappings":         {},
+	"BrokerMappings":        {},
+}
+
+// readCache holds the last-known good response for each distinct read
+// request.
+type readCache struct {
+	mu      sync.Mutex
+	entries map[string]proto.Message
+}
+
+func newReadCache() *readCache {
+	return &readCache{entries: map[string]proto.Message{}}
+}
+
+func (c *readCache) get(key string) (proto.Message, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m, ok := c.entries[key]
+	return m, ok
+}
+
+func (c *readCache) set(key string, m proto.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= readCacheMaxEntries {
+		return
+	}
+
+	c.entries[key] = proto.Clone(m)
+}
+
+// readCacheKey returns the readCache key for a method and request.
+func readCacheKey(method string, req proto.Message) (string, error) {
+	b, err := proto.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	return method + "/" + string(b), nil
+}
+
+// readCacheInterceptor is a grpc.UnaryServerInterceptor that caches the
+// response of each successful cached read request. If a cached read request
+// fails while the Server is in read-only mode, the last-known good response
+// is returned instead.
+func (s *Server) readCacheInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	// Methods are named /package.Service/Method.
+	method := info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]
+
+	if _, cached := cachedReadMethods[method]; !cached {
+		return handler(ctx, req)
+	}
+
+	r, ok := req.(proto.Message)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	key, err := readCacheKey(method, r)
+	if err != nil {
+		return handler(ctx, req)
+	}
+
+	resp, err := handler(ctx, req)
+	if err == nil {
+		if m, ok := resp.(proto.Message); ok {
+			s.readCache.set(key, m)
+		}
+		return resp, nil
+	}
+
+	if s.ReadOnly() {
+		if m, ok := s.readCache.get(key); ok {
+			log.Printf("Serving cached %s response in read-only mode: %s\n", method, err)
+			return m, nil
+		}
+	}
+
+	return resp, err
+}
+
+// RunZKWriteCheck starts a background process that periodically writes to a
+// probe znode. If a write fails or times out, the Server enters read-only
+// mode until a write succeeds. The check is disabled if the configured
+// interval is 0.
+func (s *Server) RunZKWriteCheck(ctx context.Context, wg *sync.WaitGroup, c Config) error {
+	if c.ZKWriteCheckSeconds <= 0 {
+		return nil
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		t := time.NewTicker(time.Duration(c.ZKWriteCheckSeconds) * time.Second)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				s.checkZKWritable(s.reqTimeout)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// checkZKWritable writes the current time to the probe znode, setting whether
+// ZooKeeper is unwritable according to the outcome. A write that doesn't
+// complete within the timeout is considered failed.
+func (s *Server) checkZKWritable(timeout time.Duration) {
+	path := fmt.Sprintf("/%s/write_check", s.zkPrefix)
+	data := fmt.Sprintf("%d", time.Now().Unix())
+
+	// Buffered so that a write completing after the timeout doesn't block.
+	done := make(chan error, 1)
+
+	go func() {
+		exists, err := s.ZK.Exists(path)
+		switch {
+		case err != nil:
+			done <- err
+		case !exists:
+			done <- s.ZK.Create(path, data)
+		default:
+			done <- s.ZK.Set(path, data)
+		}
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(timeout):
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+
+	var v int32
+	if err != nil {
+		v = 1
+	}
+
+	if atomic.SwapInt32(&s.zkUnwritable, v) != v {
+		if err != nil {
+			log.Printf("ZooKeeper write check failed, entering read-only mode: %s\n", err)
+		} else {
+			log.Println("ZooKeeper write check succeeded, leaving read-only mode")
+		}
+	}
+}
@@ -19,8 +19,10 @@ import (
 
 	"github.com/grpc-ecosystem/grpc-gateway/runtime"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -28,6 +30,12 @@ const (
 	writeRequest
 )
 
+var (
+	// ErrReadOnly is returned for write requests when the registry is in
+	// read-only mode. It maps to an HTTP 503 through the gateway.
+	ErrReadOnly = status.Error(codes.Unavailable, "registry is in read-only mode; mutating requests are unavailable")
+)
+
 // Server implements the registry APIs.
 type Server struct {
 	HTTPListen       string
@@ -40,6 +48,29 @@ type Server struct {
 	writeReqThrottle RequestThrottle
 	reqID            uint64
 	kafkaconsumer    *kafka.Consumer
+	zkPrefix         string
+	// Set to 1 when in read-only mode.
+	readOnly int32
+	// Set to 1 when the ZooKeeper write check fails.
+	zkUnwritable int32
+	// Last-known good read responses served in read-only mode.
+	readCache *readCache
+	// Whether gRPC requests are checked against field constraints.
+	requestValidation bool
+	// Consulted before mutating topic operations, if set.
+	policy PolicyEngine
+	// Tag keys included as inventory metric labels.
+	inventoryTopicTags  []string
+	inventoryBrokerTags []string
+	// Rules for RecommendTopicConfig.
+	topicConfigRules []TopicConfigRule
+	// The number of snapshots retained; 0 retains all snapshots.
+	snapshotRetention int
+	// Audit entries streamed by TailAuditLog.
+	audit *auditLog
+	// Under-replication events streamed by TailUnderReplicationEvents.
+	underReplication        *underReplicationFeed
+	underReplicationMonitor bool
 	// For tests.
 	test bool
 }
@@ -53,6 +84,23 @@ type Config struct {
 	ZKTagsPrefix               string
 	TagCleanupFrequencyMinutes int
 	TagAllowedStalenessMinutes int
+	ImmutableTagKeys           []string
+	ReadOnly                   bool
+	RequestValidation          bool
+	// Seconds between under-replicated topic checks; 0 disables.
+	UnderReplicationCheckSeconds int
+	// Seconds between ZooKeeper write checks; 0 disables.
+	ZKWriteCheckSeconds int
+	// The number of snapshots retained; 0 retains all snapshots.
+	SnapshotRetention int
+	// If set, topic mutations are checked against the policy service at
+	// this URL.
+	PolicyURL string
+	// Tag keys included as labels on topic and broker inventory metrics.
+	InventoryTopicTags  []string
+	InventoryBrokerTags []string
+	// Recommended topic configs by topic tag.
+	TopicConfigRules []TopicConfigRule
 
 	test bool
 }
@@ -66,6 +114,16 @@ func NewServer(c Config) (*Server, error) {
 		return nil, errors.New("invalid configuration parameter(s)")
 	}
 
+	if err := validateTopicConfigRules(c.TopicConfigRules); err != nil {
+		return nil, err
+	}
+
+	for _, keys := range [][]string{c.InventoryTopicTags, c.InventoryBrokerTags} {
+		if err := validateInventoryTagKeys(keys); err != nil {
+			return nil, err
+		}
+	}
+
 	rrt, _ := NewRequestThrottle(RequestThrottleConfig{
 		Capacity: 10,
 		Rate:     c.ReadReqRate,
@@ -77,22 +135,68 @@ func NewServer(c Config) (*Server, error) {
 	})
 
 	tcfg := TagHandlerConfig{
-		Prefix: c.ZKTagsPrefix,
+		Prefix:        c.ZKTagsPrefix,
+		ImmutableKeys: c.ImmutableTagKeys,
 	}
 
 	th, _ := NewTagHandler(tcfg)
 
+	var readOnly int32
+	if c.ReadOnly {
+		readOnly = 1
+	}
+
+	var policy PolicyEngine
+	if c.PolicyURL != "" {
+		policy = NewHTTPPolicyEngine(c.PolicyURL)
+	}
+
 	return &Server{
-		HTTPListen:       c.HTTPListen,
-		GRPCListen:       c.GRPCListen,
-		Tags:             th,
-		reqTimeout:       3000 * time.Millisecond,
-		readReqThrottle:  rrt,
-		writeReqThrottle: wrt,
-		test:             c.test,
+		HTTPListen:              c.HTTPListen,
+		GRPCListen:              c.GRPCListen,
+		Tags:                    th,
+		reqTimeout:              3000 * time.Millisecond,
+		readReqThrottle:         rrt,
+		writeReqThrottle:        wrt,
+		zkPrefix:                c.ZKTagsPrefix,
+		readOnly:                readOnly,
+		requestValidation:       c.RequestValidation,
+		policy:                  policy,
+		inventoryTopicTags:      c.InventoryTopicTags,
+		inventoryBrokerTags:     c.InventoryBrokerTags,
+		topicConfigRules:        c.TopicConfigRules,
+		snapshotRetention:       c.SnapshotRetention,
+		audit:                   newAuditLog(),
+		underReplication:        newUnderReplicationFeed(),
+		underReplicationMonitor: c.UnderReplicationCheckSeconds > 0,
+		readCache:               newReadCache(),
+		test:                    c.test,
 	}, nil
 }
 
+// SetReadOnly sets whether the Server is in read-only mode. While in
+// read-only mode, write requests are rejected with ErrReadOnly and background
+// tag cleanup is paused; read requests are served as usual, falling back to
+// the last-known good response if a read fails. This may be toggled at
+// runtime. The Server is also in read-only mode while the ZooKeeper write
+// check is failing, regardless of this setting.
+func (s *Server) SetReadOnly(ro bool) {
+	var v int32
+	if ro {
+		v = 1
+	}
+
+	if atomic.SwapInt32(&s.readOnly, v) != v {
+		log.Printf("Read-only mode set to %t\n", ro)
+	}
+}
+
+// ReadOnly returns whether the Server is in read-only mode, either as set
+// with SetReadOnly or due to a failing ZooKeeper write check.
+func (s *Server) ReadOnly() bool {
+	return atomic.LoadInt32(&s.readOnly) == 1 || atomic.LoadInt32(&s.zkUnwritable) == 1
+}
+
 // Run* methods take a Context for cancellation and WaitGroup
 // for blocking on graceful shutdowns in main. Each call will background
 // a listener (e.g. gRPC, HTTP) and a graceful shutdown procedure that's
@@ -109,7 +213,14 @@ func (s *Server) RunRPC(ctx context.Context, wg *sync.WaitGroup) error {
 		return err
 	}
 
-	srvr := grpc.NewServer()
+	var interceptors []grpc.UnaryServerInterceptor
+	if s.requestValidation {
+		interceptors = append(interceptors, validationInterceptor)
+	}
+
+	interceptors = append(interceptors, s.readCacheInterceptor, s.auditInterceptor)
+
+	srvr := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
 	pb.RegisterRegistryServer(srvr, s)
 
 	// Shutdown procedure.
@@ -149,9 +260,14 @@ func (s *Server) RunHTTP(ctx context.Context, wg *sync.WaitGroup) error {
 		return err
 	}
 
+	// Serve the inventory metrics alongside the gateway.
+	handler := http.NewServeMux()
+	handler.Handle("/inventory/metrics", s.inventoryHandler())
+	handler.Handle("/", mux)
+
 	srvr := &http.Server{
 		Addr:    s.HTTPListen,
-		Handler: mux,
+		Handler: handler,
 	}
 
 	// Shutdown procedure.
@@ -301,6 +417,11 @@ func (s *Server) DialZK(ctx context.Context, wg *sync.WaitGroup, c *kafkazk.Conf
 		return fmt.Errorf("failed to initialize ZooKeeper TagStorage backend")
 	}
 
+	// Ensure the snapshots znode exists.
+	if err := s.initSnapshots(); err != nil {
+		return fmt.Errorf("failed to initialize snapshots znode: %s", err)
+	}
+
 	// Shutdown procedure.
 	go func() {
 		<-ctx.Done()
@@ -330,6 +451,11 @@ func (s *Server) ValidateRequest(ctx context.Context, req interface{}, kind int)
 	// Log the request.
 	s.LogRequest(ctx, fmt.Sprintf("%v", req), reqID)
 
+	// Reject write requests in read-only mode.
+	if kind == writeRequest && s.ReadOnly() {
+		return nil, ErrReadOnly
+	}
+
 	var cCtx context.Context
 
 	// Check if the incoming context has a deadline set.
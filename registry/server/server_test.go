@@ -0,0 +1,167 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+
+	"google.golang.org/grpc"
+)
+
+func TestReadOnly(t *testing.T) {
+	s := testServer()
+	s.SetReadOnly(true)
+
+	if !s.ReadOnly() {
+		t.Fatal("Expected server to be in read-only mode")
+	}
+
+	// Mutations are rejected.
+	_, err := s.TagTopic(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: []string{"k:v"}})
+	if err != ErrReadOnly {
+		t.Errorf("Expected err '%v', got '%v'", ErrReadOnly, err)
+	}
+
+	_, err = s.TagBroker(context.Background(), &pb.BrokerRequest{Id: 1001, Tag: []string{"k:v"}})
+	if err != ErrReadOnly {
+		t.Errorf("Expected err '%v', got '%v'", ErrReadOnly, err)
+	}
+
+	_, err = s.DeleteTopic(context.Background(), &pb.TopicRequest{Name: "test_topic"})
+	if err != ErrReadOnly {
+		t.Errorf("Expected err '%v', got '%v'", ErrReadOnly, err)
+	}
+
+	// Reads still succeed.
+	resp, err := s.GetTopics(context.Background(), &pb.TopicRequest{Name: "test_topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists := resp.Topics["test_topic"]; !exists {
+		t.Error("Expected test_topic in response")
+	}
+
+	if _, err := s.ListBrokers(context.Background(), &pb.BrokerRequest{}); err != nil {
+		t.Error(err)
+	}
+
+	// Mutations succeed once read-only mode is disabled.
+	s.SetReadOnly(false)
+
+	_, err = s.TagTopic(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: []string{"k:v"}})
+	if err != nil {
+		t.Errorf("Expected nil error, got '%v'", err)
+	}
+}
+
+// unwritableZK is a kafkazk.Handler where writes fail. If reads is set,
+// topic reads also fail.
+type unwritableZK struct {
+	kafkazk.Handler
+	reads bool
+}
+
+var errZKUnavailable = errors.New("zk: could not connect to a server")
+
+func (z unwritableZK) Create(string, string) error { return errZKUnavailable }
+func (z unwritableZK) Set(string, string) error    { return errZKUnavailable }
+
+func (z unwritableZK) GetTopics(ts []*regexp.Regexp) ([]string, error) {
+	if z.reads {
+		return nil, errZKUnavailable
+	}
+	return z.Handler.GetTopics(ts)
+}
+
+func TestZKWriteCheck(t *testing.T) {
+	s := testServer()
+	zk := s.ZK
+
+	s.checkZKWritable(time.Second)
+	if s.ReadOnly() {
+		t.Fatal("Expected server to not be in read-only mode")
+	}
+
+	// Writes fail.
+	s.ZK = unwritableZK{Handler: zk}
+	s.checkZKWritable(time.Second)
+
+	if !s.ReadOnly() {
+		t.Fatal("Expected server to be in read-only mode")
+	}
+
+	_, err := s.TagTopic(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: []string{"k:v"}})
+	if err != ErrReadOnly {
+		t.Errorf("Expected err '%v', got '%v'", ErrReadOnly, err)
+	}
+
+	// Writes recover.
+	s.ZK = zk
+	s.checkZKWritable(time.Second)
+
+	if s.ReadOnly() {
+		t.Error("Expected server to leave read-only mode")
+	}
+
+	// Read-only mode set with SetReadOnly is retained.
+	s.SetReadOnly(true)
+	s.checkZKWritable(time.Second)
+
+	if !s.ReadOnly() {
+		t.Error("Expected server to be in read-only mode")
+	}
+}
+
+func TestReadCacheInterceptor(t *testing.T) {
+	s := testServer()
+	zk := s.ZK
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/registry.Registry/GetTopics"}
+	getTopics := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return s.GetTopics(ctx, req.(*pb.TopicRequest))
+	}
+
+	req := &pb.TopicRequest{Name: "test_topic"}
+
+	// Populate the cache.
+	if _, err := s.readCacheInterceptor(context.Background(), req, info, getTopics); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reads fail outside of read-only mode.
+	s.ZK = unwritableZK{Handler: zk, reads: true}
+
+	if _, err := s.readCacheInterceptor(context.Background(), req, info, getTopics); err == nil {
+		t.Error("Expected error")
+	}
+
+	// ZooKeeper becomes unwritable; mutations are rejected while reads are
+	// served from the cache.
+	s.checkZKWritable(time.Second)
+
+	_, err := s.TagTopic(context.Background(), &pb.TopicRequest{Name: "test_topic", Tag: []string{"k:v"}})
+	if err != ErrReadOnly {
+		t.Errorf("Expected err '%v', got '%v'", ErrReadOnly, err)
+	}
+
+	resp, err := s.readCacheInterceptor(context.Background(), req, info, getTopics)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists := resp.(*pb.TopicResponse).Topics["test_topic"]; !exists {
+		t.Error("Expected test_topic in cached response")
+	}
+
+	// Requests without a cached response fail.
+	other := &pb.TopicRequest{Name: "test_topic2"}
+	if _, err := s.readCacheInterceptor(context.Background(), other, info, getTopics); err == nil {
+		t.Error("Expected error")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"regexp"
 	"strings"
+	"time"
 
 	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
 )
@@ -32,10 +33,21 @@ func (e ErrReservedTag) Error() string {
 	return fmt.Sprintf("tag '%s' is a reserved tag", e.t)
 }
 
+// ErrImmutableTag error.
+type ErrImmutableTag struct {
+	t string
+}
+
+func (e ErrImmutableTag) Error() string {
+	return fmt.Sprintf("tag '%s' is immutable and can't be changed once set", e.t)
+}
+
 // TagHandler provides object filtering by tags
 // along with tag storage and retrieval.
 type TagHandler struct {
 	Store TagStorage
+	// Tag keys that can't be modified or deleted once set.
+	ImmutableKeys map[string]struct{}
 }
 
 // TagStorage handles tag persistence to stable storage.
@@ -60,16 +72,72 @@ func NewTagHandler(c TagHandlerConfig) (*TagHandler, error) {
 		return nil, err
 	}
 
+	immutable := map[string]struct{}{}
+	for _, k := range c.ImmutableKeys {
+		immutable[k] = struct{}{}
+	}
+
 	return &TagHandler{
 		// More sophisticated initialization/config passing
 		// if additional TagStorage backends are written.
-		Store: ts,
+		Store:         ts,
+		ImmutableKeys: immutable,
 	}, nil
 }
 
 // TagHandlerConfig holds TagHandler configuration.
 type TagHandlerConfig struct {
-	Prefix string
+	Prefix        string
+	ImmutableKeys []string
+}
+
+// CheckImmutable takes a KafkaObject, a TagSet to be set and a list of tag
+// keys to be deleted. An ErrImmutableTag is returned if the change would
+// modify or delete an immutable tag that's already set for the object.
+// Initially setting an immutable tag, or setting it to its current value,
+// is permitted.
+func (t *TagHandler) CheckImmutable(o KafkaObject, set TagSet, del []string) error {
+	if len(t.ImmutableKeys) == 0 {
+		return nil
+	}
+
+	current, err := t.Store.GetTags(o)
+	switch err {
+	case nil:
+	case ErrKafkaObjectDoesNotExist:
+		// No tags have been set.
+		return nil
+	default:
+		return err
+	}
+
+	// Expired tags are treated as unset.
+	current, err = withoutExpired(current, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for k, v := range set {
+		if _, immutable := t.ImmutableKeys[k]; !immutable {
+			continue
+		}
+
+		if cv, exists := current[k]; exists && cv != v {
+			return ErrImmutableTag{t: k}
+		}
+	}
+
+	for _, k := range del {
+		if _, immutable := t.ImmutableKeys[k]; !immutable {
+			continue
+		}
+
+		if _, exists := current[k]; exists {
+			return ErrImmutableTag{t: k}
+		}
+	}
+
+	return nil
 }
 
 // Tags is a []string of "key:value" pairs.
@@ -148,6 +216,12 @@ func (t *TagHandler) TagSetFromObject(o interface{}) (TagSet, error) {
 		}
 	}
 
+	// Exclude expired tags.
+	st, err = withoutExpired(st, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	// Merge stored tags with default tags.
 	for k, v := range st {
 		ts[k] = v
@@ -26,6 +26,12 @@ func (tc *TagCleaner) RunTagCleanup(s *Server, ctx context.Context, c Config) {
 
 	for tc.running {
 		<-t.C
+
+		// Tag cleanup writes to ZooKeeper; skip while in read-only mode.
+		if s.ReadOnly() {
+			continue
+		}
+
 		err := s.MarkForDeletion(time.Now)
 		if err != nil {
 			log.Println(err)
@@ -33,6 +39,10 @@ func (tc *TagCleaner) RunTagCleanup(s *Server, ctx context.Context, c Config) {
 		}
 
 		s.DeleteStaleTags(time.Now, c)
+
+		if err := s.DeleteExpiredTags(time.Now); err != nil {
+			log.Println(err)
+		}
 	}
 }
 
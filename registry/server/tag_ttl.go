@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// TagExpiryKey is the stored tag key holding the expiration times for tags
+// set with a TTL. The value is a JSON object of tag keys to Unix timestamps.
+var TagExpiryKey = "tagExpirations"
+
+// SetTags takes a KafkaObject, TagSet and TTL and sets the tag key:values for
+// the object. If the TTL is non-zero, the tags expire once it has elapsed;
+// expired tags are excluded from reads and deleted from storage by the
+// background tag cleanup. Setting a tag without a TTL clears any expiration
+// previously set for it.
+func (t *TagHandler) SetTags(o KafkaObject, ts TagSet, ttl time.Duration) error {
+	if _, exists := ts[TagExpiryKey]; exists {
+		return ErrReservedTag{t: TagExpiryKey}
+	}
+
+	current, err := t.Store.GetTags(o)
+	switch err {
+	case nil, ErrKafkaObjectDoesNotExist:
+	default:
+		return err
+	}
+
+	expiries, err := tagExpiries(current)
+	if err != nil {
+		return err
+	}
+
+	out := TagSet{}
+	for k, v := range ts {
+		out[k] = v
+
+		if ttl != 0 {
+			expiries[k] = time.Now().Add(ttl).Unix()
+		} else {
+			delete(expiries, k)
+		}
+	}
+
+	// Update the expirations if any exist or are being cleared.
+	if _, exists := current[TagExpiryKey]; exists || len(expiries) > 0 {
+		e, err := json.Marshal(expiries)
+		if err != nil {
+			return err
+		}
+		out[TagExpiryKey] = string(e)
+	}
+
+	return t.Store.SetTags(o, out)
+}
+
+// tagExpiries returns the tag key to expiration Unix timestamp mappings
+// stored in the TagSet.
+func tagExpiries(ts TagSet) (map[string]int64, error) {
+	expiries := map[string]int64{}
+
+	if e := ts[TagExpiryKey]; e != "" {
+		if err := json.Unmarshal([]byte(e), &expiries); err != nil {
+			return nil, err
+		}
+	}
+
+	return expiries, nil
+}
+
+// withoutExpired takes a TagSet and returns a copy excluding the stored
+// expirations and any tags expired as of now.
+func withoutExpired(ts TagSet, now time.Time) (TagSet, error) {
+	if ts == nil {
+		return nil, nil
+	}
+
+	expiries, err := tagExpiries(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	out := TagSet{}
+	for k, v := range ts {
+		if k == TagExpiryKey {
+			continue
+		}
+
+		if e, exists := expiries[k]; exists && now.Unix() >= e {
+			continue
+		}
+
+		out[k] = v
+	}
+
+	return out, nil
+}
+
+// DeleteExpiredTags deletes any tags with an elapsed TTL.
+func (s *Server) DeleteExpiredTags(now func() time.Time) error {
+	sweepTime := now().Unix()
+
+	allTags, err := s.Tags.Store.GetAllTags()
+	if err != nil {
+		return err
+	}
+
+	for kafkaObject, tags := range allTags {
+		expiries, err := tagExpiries(tags)
+		if err != nil {
+			log.Printf("Found invalid tag expirations for %s %s: %s\n", kafkaObject.Type, kafkaObject.ID, err)
+			continue
+		}
+
+		var expired []string
+		for k, e := range expiries {
+			if sweepTime >= e {
+				expired = append(expired, k)
+				delete(expiries, k)
+			}
+		}
+
+		if len(expired) == 0 {
+			continue
+		}
+
+		// Drop the expirations once none remain, otherwise persist those
+		// that haven't elapsed.
+		if len(expiries) == 0 {
+			expired = append(expired, TagExpiryKey)
+		} else {
+			e, err := json.Marshal(expiries)
+			if err != nil {
+				return err
+			}
+
+			if err := s.Tags.Store.SetTags(kafkaObject, TagSet{TagExpiryKey: string(e)}); err != nil {
+				return err
+			}
+		}
+
+		if err := s.Tags.Store.DeleteTags(kafkaObject, expired); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+)
+
+func TestTagTTL(t *testing.T) {
+	// GIVEN
+	th := testTagHandler()
+	s := Server{Tags: th, ZK: kafkazk.NewZooKeeperStub()}
+	topic := KafkaObject{Type: "topic", ID: "test_topic"}
+
+	// WHEN
+	th.SetTags(topic, TagSet{"team": "eng"}, 0)
+	th.SetTags(topic, TagSet{"owner": "alice"}, time.Hour)
+	// Already expired.
+	th.SetTags(topic, TagSet{"maintenance": "true"}, -time.Minute)
+
+	// THEN
+	ts, err := th.TagSetFromObject(&pb.Topic{Name: "test_topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists := ts["maintenance"]; exists {
+		t.Error("Expected expired tag to be excluded from reads")
+	}
+
+	if ts["team"] != "eng" || ts["owner"] != "alice" {
+		t.Errorf("Expected unexpired tags in reads, got %v", ts)
+	}
+
+	if _, exists := ts[TagExpiryKey]; exists {
+		t.Error("Expected tag expirations to be excluded from reads")
+	}
+
+	// Expired tags are purged from the store.
+	if err := s.DeleteExpiredTags(time.Now); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _ := th.Store.GetTags(topic)
+	if _, exists := stored["maintenance"]; exists {
+		t.Error("Expected expired tag to be deleted")
+	}
+
+	if stored["owner"] != "alice" {
+		t.Error("Expected unexpired tag to be retained")
+	}
+
+	// Once the remaining TTL elapses, only tags set without a TTL remain.
+	later := func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if err := s.DeleteExpiredTags(later); err != nil {
+		t.Fatal(err)
+	}
+
+	stored, _ = th.Store.GetTags(topic)
+	if !stored.Equal(TagSet{"team": "eng"}) {
+		t.Errorf("Expected only non-TTL tags to remain, got %v", stored)
+	}
+}
+
+func TestTagTTLCleared(t *testing.T) {
+	th := testTagHandler()
+	topic := KafkaObject{Type: "topic", ID: "test_topic"}
+
+	// Setting a tag without a TTL clears its expiration.
+	th.SetTags(topic, TagSet{"maintenance": "true"}, -time.Minute)
+	th.SetTags(topic, TagSet{"maintenance": "true"}, 0)
+
+	ts, _ := th.TagSetFromObject(&pb.Topic{Name: "test_topic"})
+	if ts["maintenance"] != "true" {
+		t.Error("Expected tag expiration to be cleared")
+	}
+
+	// The expirations key can't be set directly.
+	err := th.SetTags(topic, TagSet{TagExpiryKey: "{}"}, 0)
+	if _, ok := err.(ErrReservedTag); !ok {
+		t.Errorf("Expected ErrReservedTag, got %v", err)
+	}
+}
+
+func TestTagTopicTTL(t *testing.T) {
+	s := testServer()
+
+	req := &pb.TopicRequest{
+		Name:       "test_topic",
+		Tag:        []string{"maintenance:true"},
+		TtlSeconds: 60,
+	}
+
+	if _, err := s.TagTopic(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+
+	tags, _ := s.Tags.Store.GetTags(KafkaObject{Type: "topic", ID: "test_topic"})
+	expiries, err := tagExpiries(tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if e := expiries["maintenance"]; e <= time.Now().Unix() {
+		t.Errorf("Expected a future expiration, got %d", e)
+	}
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+)
+
+// TopicConfigRule recommends Configs for topics tagged with Tag, in
+// "key:value" form (e.g. "workload:log").
+type TopicConfigRule struct {
+	Tag     string            `json:"tag"`
+	Configs map[string]string `json:"configs"`
+}
+
+// RecommendTopicConfig returns a *pb.TopicConfigRecommendation for the topic
+// specified in the *pb.TopicRequest Name field. The configs of every rule
+// whose tag is set on the topic are merged in rule order; where rules
+// recommend the same config, the later rule takes precedence.
+func (s *Server) RecommendTopicConfig(ctx context.Context, req *pb.TopicRequest) (*pb.TopicConfigRecommendation, error) {
+	ctx, err := s.ValidateRequest(ctx, req, readRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name == "" {
+		return nil, ErrTopicNameEmpty
+	}
+
+	topics, err := s.fetchTopicSet(&pb.TopicRequest{Name: req.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	topic, exists := topics[req.Name]
+	if !exists {
+		return nil, ErrTopicNotExist
+	}
+
+	tags, err := s.Tags.TagSetFromObject(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	return topicConfigRecommendation(topic, tags, s.topicConfigRules), nil
+}
+
+// topicConfigRecommendation takes a *pb.Topic, the topic's TagSet and a
+// []TopicConfigRule and returns a *pb.TopicConfigRecommendation.
+func topicConfigRecommendation(topic *pb.Topic, tags TagSet, rules []TopicConfigRule) *pb.TopicConfigRecommendation {
+	rec := &pb.TopicConfigRecommendation{
+		Name:    topic.Name,
+		Configs: map[string]string{},
+		Changes: map[string]string{},
+	}
+
+	for _, r := range rules {
+		kv := strings.SplitN(r.Tag, ":", 2)
+		if len(kv) != 2 || tags[kv[0]] != kv[1] {
+			continue
+		}
+
+		rec.MatchedTags = append(rec.MatchedTags, r.Tag)
+		for k, v := range r.Configs {
+			rec.Configs[k] = v
+		}
+	}
+
+	for k, v := range rec.Configs {
+		if topic.Configs[k] != v {
+			rec.Changes[k] = v
+		}
+	}
+
+	return rec
+}
+
+// validateTopicConfigRules returns an error if any rule tag isn't in
+// "key:value" form.
+func validateTopicConfigRules(rules []TopicConfigRule) error {
+	for _, r := range rules {
+		if kv := strings.SplitN(r.Tag, ":", 2); len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid topic config rule tag '%s'", r.Tag)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+)
+
+func TestRecommendTopicConfig(t *testing.T) {
+	s := testServer()
+	s.topicConfigRules = []TopicConfigRule{
+		{Tag: "workload:log", Configs: map[string]string{"cleanup.policy": "delete", "retention.ms": "172800000"}},
+		{Tag: "workload:queue", Configs: map[string]string{"cleanup.policy": "delete", "retention.ms": "86400000"}},
+	}
+
+	s.Tags.Store.SetTags(KafkaObject{Type: "topic", ID: "test_topic"}, TagSet{"workload": "log"})
+
+	rec, err := s.RecommendTopicConfig(context.Background(), &pb.TopicRequest{Name: "test_topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.MatchedTags) != 1 || rec.MatchedTags[0] != "workload:log" {
+		t.Errorf("Expected matched tags [workload:log], got %v", rec.MatchedTags)
+	}
+
+	expected := map[string]string{"cleanup.policy": "delete", "retention.ms": "172800000"}
+	for k, v := range expected {
+		if rec.Configs[k] != v {
+			t.Errorf("Expected %s=%s, got '%s'", k, v, rec.Configs[k])
+		}
+	}
+
+	if len(rec.Configs) != len(expected) {
+		t.Errorf("Expected %d configs, got %d", len(expected), len(rec.Configs))
+	}
+
+	// The stub topic config already sets retention.ms to 172800000.
+	if len(rec.Changes) != 1 || rec.Changes["cleanup.policy"] != "delete" {
+		t.Errorf("Expected changes {cleanup.policy: delete}, got %v", rec.Changes)
+	}
+
+	// Untagged topics match no rules.
+	rec, err = s.RecommendTopicConfig(context.Background(), &pb.TopicRequest{Name: "test_topic2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rec.MatchedTags) != 0 || len(rec.Configs) != 0 {
+		t.Errorf("Expected no recommendation, got %v", rec)
+	}
+
+	if _, err := s.RecommendTopicConfig(context.Background(), &pb.TopicRequest{Name: "nonexistent"}); err != ErrTopicNotExist {
+		t.Errorf("Expected error '%s', got '%v'", ErrTopicNotExist, err)
+	}
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"context"
+	"log"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// ErrUnderReplicationMonitorDisabled error.
+	ErrUnderReplicationMonitorDisabled = status.Error(codes.FailedPrecondition, "under-replication monitor is disabled")
+)
+
+// underReplicationSubscriberBuffer is the number of events buffered per
+// TailUnderReplicationEvents subscriber. Events are dropped for subscribers
+// with full buffers.
+const underReplicationSubscriberBuffer = 64
+
+// Under-replication event types.
+const (
+	eventUnderReplicated = "under_replicated"
+	eventRecovered       = "recovered"
+)
+
+// UnderReplicationEvent describes a topic becoming or recovering from being
+// under-replicated.
+type UnderReplicationEvent struct {
+	Type      string
+	Topic     string
+	Timestamp time.Time
+}
+
+// UnderReplicationMonitor tracks the under-replicated state of topics,
+// emitting events on transitions.
+type UnderReplicationMonitor struct {
+	running bool
+	// Topics under-replicated as of the last check.
+	underReplicated map[string]struct{}
+	// Notify is called for each event. If nil, events are logged.
+	Notify func(UnderReplicationEvent)
+}
+
+// NewUnderReplicationMonitor initializes an *UnderReplicationMonitor.
+func NewUnderReplicationMonitor() *UnderReplicationMonitor {
+	return &UnderReplicationMonitor{
+		underReplicated: map[string]struct{}{},
+	}
+}
+
+// Check compares the currently under-replicated topics with those from the
+// previous check. An under_replicated event is emitted for each topic that
+// became under-replicated and a recovered event for each topic that's no
+// longer under-replicated; topics that were deleted don't emit events. The
+// events are returned sorted by topic.
+func (m *UnderReplicationMonitor) Check(s *Server, now func() time.Time) ([]UnderReplicationEvent, error) {
+	urp, err := s.ZK.GetUnderReplicated()
+	if err != nil {
+		return nil, err
+	}
+
+	topics, err := s.ZK.GetTopics([]*regexp.Regexp{topicRegex})
+	if err != nil {
+		return nil, ErrFetchingTopics
+	}
+
+	exists := TopicSetFromSlice(topics)
+	current := map[string]struct{}{}
+	ts := now()
+
+	var events []UnderReplicationEvent
+
+	for _, t := range urp {
+		current[t] = struct{}{}
+		if _, seen := m.underReplicated[t]; !seen {
+			events = append(events, UnderReplicationEvent{Type: eventUnderReplicated, Topic: t, Timestamp: ts})
+		}
+	}
+
+	for t := range m.underReplicated {
+		_, urp := current[t]
+		if _, ok := exists[t]; ok && !urp {
+			events = append(events, UnderReplicationEvent{Type: eventRecovered, Topic: t, Timestamp: ts})
+		}
+	}
+
+	m.underReplicated = current
+
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].Topic < events[j].Topic
+	})
+
+	for _, e := range events {
+		m.notify(e)
+	}
+
+	return events, nil
+}
+
+func (m *UnderReplicationMonitor) notify(e UnderReplicationEvent) {
+	if m.Notify != nil {
+		m.Notify(e)
+		return
+	}
+
+	logUnderReplicationEvent(e)
+}
+
+func logUnderReplicationEvent(e UnderReplicationEvent) {
+	switch e.Type {
+	case eventUnderReplicated:
+		log.Printf("Topic %s is under-replicated\n", e.Topic)
+	case eventRecovered:
+		log.Printf("Topic %s recovered from under-replication\n", e.Topic)
+	}
+}
+
+// toProto returns the event as a *pb.UnderReplicationEvent.
+func (e UnderReplicationEvent) toProto() *pb.UnderReplicationEvent {
+	return &pb.UnderReplicationEvent{
+		Type:      e.Type,
+		Topic:     e.Topic,
+		Timestamp: e.Timestamp.Unix(),
+	}
+}
+
+// underReplicationFeed fans out under-replication events to
+// TailUnderReplicationEvents subscribers.
+type underReplicationFeed struct {
+	mu          sync.Mutex
+	subscribers map[chan *pb.UnderReplicationEvent]struct{}
+}
+
+func newUnderReplicationFeed() *underReplicationFeed {
+	return &underReplicationFeed{subscribers: map[chan *pb.UnderReplicationEvent]struct{}{}}
+}
+
+// subscribe returns a channel receiving all subsequently published events.
+func (f *underReplicationFeed) subscribe() chan *pb.UnderReplicationEvent {
+	c := make(chan *pb.UnderReplicationEvent, underReplicationSubscriberBuffer)
+
+	f.mu.Lock()
+	f.subscribers[c] = struct{}{}
+	f.mu.Unlock()
+
+	return c
+}
+
+// unsubscribe removes a channel returned by subscribe.
+func (f *underReplicationFeed) unsubscribe(c chan *pb.UnderReplicationEvent) {
+	f.mu.Lock()
+	delete(f.subscribers, c)
+	f.mu.Unlock()
+}
+
+// publish sends the event to all subscribers without blocking.
+func (f *underReplicationFeed) publish(e *pb.UnderReplicationEvent) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for c := range f.subscribers {
+		select {
+		case c <- e:
+		default:
+			log.Printf("Under-replication event subscriber buffer full, dropping %s event for %s\n", e.Type, e.Topic)
+		}
+	}
+}
+
+// TailUnderReplicationEvents streams a *pb.UnderReplicationEvent for each
+// under-replication state change observed while subscribed. The stream ends
+// when the client cancels the request. ErrUnderReplicationMonitorDisabled is
+// returned if the under-replication monitor isn't enabled.
+func (s *Server) TailUnderReplicationEvents(req *pb.Empty, stream pb.Registry_TailUnderReplicationEventsServer) error {
+	if _, err := s.ValidateRequest(stream.Context(), req, readRequest); err != nil {
+		return err
+	}
+
+	if !s.underReplicationMonitor {
+		return ErrUnderReplicationMonitorDisabled
+	}
+
+	events := s.underReplication.subscribe()
+	defer s.underReplication.unsubscribe(events)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e := <-events:
+			if err := stream.Send(e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Run checks for under-replication state changes at the configured interval.
+func (m *UnderReplicationMonitor) Run(s *Server, c Config) {
+	m.running = true
+
+	t := time.NewTicker(time.Duration(c.UnderReplicationCheckSeconds) * time.Second)
+	defer t.Stop()
+
+	for m.running {
+		<-t.C
+
+		if _, err := m.Check(s, time.Now); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// RunUnderReplicationMonitor starts a background process emitting events as
+// topics become or recover from being under-replicated. Events are logged and
+// published to TailUnderReplicationEvents subscribers. The monitor is
+// disabled if the configured check interval is 0.
+func (s *Server) RunUnderReplicationMonitor(ctx context.Context, wg *sync.WaitGroup, c Config) error {
+	if c.UnderReplicationCheckSeconds <= 0 {
+		return nil
+	}
+
+	wg.Add(1)
+	m := NewUnderReplicationMonitor()
+	m.Notify = func(e UnderReplicationEvent) {
+		logUnderReplicationEvent(e)
+		s.underReplication.publish(e.toProto())
+	}
+
+	// Shutdown procedure.
+	go func() {
+		<-ctx.Done()
+		m.running = false
+		wg.Done()
+	}()
+
+	go m.Run(s, c)
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DataDog/kafka-kit/v3/kafkazk"
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+
+	"google.golang.org/grpc"
+)
+
+// urpStub wraps a kafkazk.Handler with configurable under-replicated topics.
+type urpStub struct {
+	kafkazk.Handler
+	urp []string
+}
+
+func (zk *urpStub) GetUnderReplicated() ([]string, error) {
+	return zk.urp, nil
+}
+
+func TestUnderReplicationMonitor(t *testing.T) {
+	zk := &urpStub{Handler: kafkazk.NewZooKeeperStub()}
+	s := Server{ZK: zk}
+
+	var notified []UnderReplicationEvent
+	m := NewUnderReplicationMonitor()
+	m.Notify = func(e UnderReplicationEvent) { notified = append(notified, e) }
+
+	// No under-replicated topics.
+	events, err := m.Check(&s, time.Now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+
+	// A partition drops out of the ISR.
+	zk.urp = []string{"test_topic"}
+
+	events, _ = m.Check(&s, time.Now)
+	if len(events) != 1 || events[0].Type != eventUnderReplicated || events[0].Topic != "test_topic" {
+		t.Errorf("Expected an under_replicated event for test_topic, got %v", events)
+	}
+
+	// No transitions.
+	events, _ = m.Check(&s, time.Now)
+	if len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+
+	// The ISR is restored.
+	zk.urp = nil
+
+	events, _ = m.Check(&s, time.Now)
+	if len(events) != 1 || events[0].Type != eventRecovered || events[0].Topic != "test_topic" {
+		t.Errorf("Expected a recovered event for test_topic, got %v", events)
+	}
+
+	if len(notified) != 2 {
+		t.Errorf("Expected 2 notifications, got %d", len(notified))
+	}
+
+	// Deleted topics don't emit recovered events.
+	zk.urp = []string{"deleted_topic"}
+	m.Check(&s, time.Now)
+	zk.urp = nil
+
+	events, _ = m.Check(&s, time.Now)
+	if len(events) != 0 {
+		t.Errorf("Expected no events, got %v", events)
+	}
+}
+
+// fakeUnderReplicationStream is a pb.Registry_TailUnderReplicationEventsServer
+// that forwards sent events to a channel.
+type fakeUnderReplicationStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	events chan *pb.UnderReplicationEvent
+}
+
+func (f *fakeUnderReplicationStream) Context() context.Context { return f.ctx }
+
+func (f *fakeUnderReplicationStream) Send(e *pb.UnderReplicationEvent) error {
+	f.events <- e
+	return nil
+}
+
+func TestTailUnderReplicationEvents(t *testing.T) {
+	s := testServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeUnderReplicationStream{ctx: ctx, events: make(chan *pb.UnderReplicationEvent, 10)}
+
+	// The monitor isn't enabled.
+	if err := s.TailUnderReplicationEvents(&pb.Empty{}, stream); err != ErrUnderReplicationMonitorDisabled {
+		t.Fatalf("Expected error '%s', got '%v'", ErrUnderReplicationMonitorDisabled, err)
+	}
+
+	s.underReplicationMonitor = true
+
+	done := make(chan error)
+	go func() { done <- s.TailUnderReplicationEvents(&pb.Empty{}, stream) }()
+
+	// Wait for the subscription.
+	for i := 0; ; i++ {
+		s.underReplication.mu.Lock()
+		n := len(s.underReplication.subscribers)
+		s.underReplication.mu.Unlock()
+
+		if n == 1 {
+			break
+		}
+		if i == 100 {
+			t.Fatal("Timed out waiting for the TailUnderReplicationEvents subscription")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ts := time.Unix(1577934245, 0)
+	s.underReplication.publish(UnderReplicationEvent{Type: eventUnderReplicated, Topic: "test_topic", Timestamp: ts}.toProto())
+
+	select {
+	case e := <-stream.events:
+		if e.Type != eventUnderReplicated || e.Topic != "test_topic" || e.Timestamp != ts.Unix() {
+			t.Errorf("Unexpected under-replication event %v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for the under-replication event")
+	}
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+
+	s.underReplication.mu.Lock()
+	defer s.underReplication.mu.Unlock()
+
+	if len(s.underReplication.subscribers) != 0 {
+		t.Error("Expected the subscription to be removed")
+	}
+}
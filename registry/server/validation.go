@@ -0,0 +1,157 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// Kafka topic names are limited to 249 ASCII alphanumerics, '.', '_'
+	// and '-'.
+	validTopicName = regexp.MustCompile(`^[a-zA-Z0-9._-]{1,249}$`)
+)
+
+// fieldViolations is a list of request field constraint violations.
+type fieldViolations []*errdetails.BadRequest_FieldViolation
+
+func (v *fieldViolations) add(field, desc string) {
+	*v = append(*v, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: desc,
+	})
+}
+
+// validationInterceptor is a grpc.UnaryServerInterceptor that checks requests
+// against field constraints before calling the handler. Requests with
+// violations are rejected with an InvalidArgument status carrying a
+// BadRequest detail that lists each violating field.
+func validationInterceptor(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if err := validateRequestFields(req); err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// validateRequestFields takes a request and returns an InvalidArgument status
+// error if any field constraints are violated.
+func validateRequestFields(req interface{}) error {
+	v := requestFieldViolations(req)
+	if len(v) == 0 {
+		return nil
+	}
+
+	var msgs []string
+	for _, fv := range v {
+		msgs = append(msgs, fmt.Sprintf("%s: %s", fv.Field, fv.Description))
+	}
+
+	st := status.New(codes.InvalidArgument, fmt.Sprintf("invalid request: %s", strings.Join(msgs, "; ")))
+	if std, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: v}); err == nil {
+		st = std
+	}
+
+	return st.Err()
+}
+
+// requestFieldViolations returns the field constraint violations for the
+// request. Fields are named by their protobuf field names.
+func requestFieldViolations(req interface{}) fieldViolations {
+	var v fieldViolations
+
+	switch r := req.(type) {
+	case *pb.CreateTopicRequest:
+		if r.Topic == nil {
+			v.add("topic", "must be specified")
+			break
+		}
+		validateTopicName(&v, "topic.name", r.Topic.Name)
+		if r.Topic.Partitions < 1 {
+			v.add("topic.partitions", "must be greater than 0")
+		}
+		if r.Topic.Replication < 1 {
+			v.add("topic.replication", "must be greater than 0")
+		}
+	case *pb.ReplicationFactorRequest:
+		validateTopicName(&v, "name", r.Name)
+		if r.Replication < 1 {
+			v.add("replication", "must be greater than 0")
+		}
+	case *pb.TranslateOffsetRequest:
+		if r.RemoteClusterAlias == "" {
+			v.add("remote_cluster_alias", "must be specified")
+		}
+		if r.GroupId == "" {
+			v.add("group_id", "must be specified")
+		}
+	case *pb.SnapshotRequest:
+		if r.Id == "" {
+			v.add("id", "must be specified")
+		}
+	case *pb.DesiredState:
+		seen := map[string]struct{}{}
+		for i, t := range r.Topics {
+			f := fmt.Sprintf("topics[%d]", i)
+			validateTopicName(&v, f+".name", t.GetName())
+			if _, dupe := seen[t.GetName()]; dupe {
+				v.add(f+".name", "must be unique")
+			}
+			seen[t.GetName()] = struct{}{}
+			if t.GetPartitions() < 1 {
+				v.add(f+".partitions", "must be greater than 0")
+			}
+			if t.GetReplication() < 1 {
+				v.add(f+".replication", "must be greater than 0")
+			}
+		}
+	case *pb.ReconciliationPlan:
+		for i, a := range r.Actions {
+			f := fmt.Sprintf("actions[%d]", i)
+			switch a.GetType() {
+			case actionCreate:
+				if a.GetTopic() == nil {
+					v.add(f+".topic", "must be specified")
+				}
+			case actionUpdate, actionDelete:
+				validateTopicName(&v, f+".name", a.GetName())
+			default:
+				v.add(f+".type", "must be one of create, update or delete")
+			}
+		}
+	case *pb.RebalanceRecommendationRequest:
+		thresholds := map[string]float64{
+			"partition_skew_threshold":  r.PartitionSkewThreshold,
+			"storage_skew_threshold":    r.StorageSkewThreshold,
+			"leadership_skew_threshold": r.LeadershipSkewThreshold,
+		}
+		for _, f := range []string{"partition_skew_threshold", "storage_skew_threshold", "leadership_skew_threshold"} {
+			if thresholds[f] < 0 {
+				v.add(f, "must not be negative")
+			}
+		}
+	}
+
+	return v
+}
+
+// validateTopicName adds a violation for field f if the topic name n isn't a
+// valid Kafka topic name.
+func validateTopicName(v *fieldViolations, f, n string) {
+	switch {
+	case n == "":
+		v.add(f, "must be specified")
+	case n == "." || n == "..":
+		v.add(f, "must not be '.' or '..'")
+	case !validTopicName.MatchString(n):
+		v.add(f, "must be at most 249 characters of [a-zA-Z0-9._-]")
+	}
+}
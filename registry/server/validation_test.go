@@ -0,0 +1,105 @@
+package server
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	pb "github.com/DataDog/kafka-kit/v3/registry/protos"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidationInterceptor(t *testing.T) {
+	var called bool
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		called = true
+		return &pb.Empty{}, nil
+	}
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/registry.Registry/CreateTopic"}
+
+	// Partition counts are unsigned; a non-positive count is zero.
+	req := &pb.CreateTopicRequest{
+		Topic: &pb.Topic{Name: "test", Partitions: 0, Replication: 2},
+	}
+
+	_, err := validationInterceptor(context.Background(), req, info, handler)
+	if called {
+		t.Error("Unexpected handler call for an invalid request")
+	}
+
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.InvalidArgument {
+		t.Fatalf("Expected an InvalidArgument error, got %v", err)
+	}
+
+	if !strings.Contains(st.Message(), "topic.partitions") {
+		t.Errorf("Expected error naming topic.partitions, got '%s'", st.Message())
+	}
+
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+		}
+	}
+
+	if len(fields) != 1 || fields[0] != "topic.partitions" {
+		t.Errorf("Expected a topic.partitions field violation, got %v", fields)
+	}
+
+	// Valid requests are passed to the handler.
+	req.Topic.Partitions = 6
+	if _, err := validationInterceptor(context.Background(), req, info, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	if !called {
+		t.Error("Expected handler call for a valid request")
+	}
+}
+
+func TestRequestFieldViolations(t *testing.T) {
+	tests := []struct {
+		req      interface{}
+		expected []string
+	}{
+		{&pb.CreateTopicRequest{}, []string{"topic"}},
+		{&pb.CreateTopicRequest{Topic: &pb.Topic{Name: "bad/name"}},
+			[]string{"topic.name", "topic.partitions", "topic.replication"}},
+		{&pb.CreateTopicRequest{Topic: &pb.Topic{Name: "..", Partitions: 1, Replication: 1}},
+			[]string{"topic.name"}},
+		{&pb.ReplicationFactorRequest{Name: "test"}, []string{"replication"}},
+		{&pb.TranslateOffsetRequest{}, []string{"remote_cluster_alias", "group_id"}},
+		{&pb.SnapshotRequest{}, []string{"id"}},
+		{&pb.RebalanceRecommendationRequest{StorageSkewThreshold: -1}, []string{"storage_skew_threshold"}},
+		{&pb.DesiredState{Topics: []*pb.Topic{
+			{Name: "test", Partitions: 1, Replication: 1},
+			{Name: "test", Partitions: 1}}},
+			[]string{"topics[1].name", "topics[1].replication"}},
+		{&pb.ReconciliationPlan{Actions: []*pb.TopicAction{
+			{Type: "create"}, {Type: "update", Name: "test"}, {Type: "rename"}}},
+			[]string{"actions[0].topic", "actions[2].type"}},
+		// Requests without constraints.
+		{&pb.TopicRequest{}, nil},
+	}
+
+	for _, tt := range tests {
+		v := requestFieldViolations(tt.req)
+
+		var fields []string
+		for _, fv := range v {
+			fields = append(fields, fv.Field)
+		}
+
+		if !stringsEqual(fields, tt.expected) {
+			t.Errorf("%T: expected violations %v, got %v", tt.req, tt.expected, fields)
+		}
+	}
+}